@@ -0,0 +1,11 @@
+// Package assets bundles the SonarQube API specifications the client is generated
+// against. They are refreshed from a running server with "make api".
+package assets
+
+import _ "embed" // for go:embed
+
+// APISpec is the Web API specification (api/webservices/list with internal actions)
+// of the SonarQube Community Build the client targets.
+//
+//go:embed api.json
+var APISpec []byte
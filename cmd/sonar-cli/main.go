@@ -20,6 +20,6 @@ import (
 func main() {
 	err := cli.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
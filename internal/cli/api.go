@@ -0,0 +1,458 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+const (
+	// apiV2Prefix identifies V2 API paths passed to the api command.
+	apiV2Prefix = "v2/"
+	// apiPathPrefix is stripped from api command paths, which are relative to the API base URL.
+	apiPathPrefix = "api/"
+	// apiArgCount is the number of positional arguments of the api command (method and path).
+	apiArgCount = 2
+	// maxPaginatePages bounds --paginate so a server that misreports totals cannot loop forever.
+	maxPaginatePages = 10000
+)
+
+// apiPageStyle describes the query parameters and paging object of a paginated API flavour.
+type apiPageStyle struct {
+	// pageParam is the query parameter carrying the 1-based page index.
+	pageParam string
+	// sizeParam is the query parameter carrying the page size.
+	sizeParam string
+	// pagingKey is the response object holding the "total" count.
+	pagingKey string
+}
+
+//nolint:gochecknoglobals // constant pagination conventions
+var (
+	// apiPageStyleV1 is the p/ps pagination used by V1 endpoints.
+	apiPageStyleV1 = apiPageStyle{pageParam: "p", sizeParam: "ps", pagingKey: "paging"}
+	// apiPageStyleV2 is the pageIndex/pageSize pagination used by V2 endpoints.
+	apiPageStyleV2 = apiPageStyle{pageParam: "pageIndex", sizeParam: "pageSize", pagingKey: "page"}
+)
+
+// apiRequest holds the parsed arguments of an api command invocation.
+//
+//nolint:govet // fieldalignment: keeping logical field grouping for readability
+type apiRequest struct {
+	// Method is the upper-cased HTTP method.
+	Method string
+	// Path is the endpoint path relative to the API base URL (e.g. "issues/search" or "v2/users-management/users").
+	Path string
+	// Fields are the -f key=value parameters.
+	Fields url.Values
+	// Headers are the -H "Name: value" headers.
+	Headers map[string]string
+	// Body is the raw JSON request body read from --input, if any.
+	Body json.RawMessage
+	// Paginate fetches and merges every page of the response.
+	Paginate bool
+}
+
+// apiFlags holds the raw flag values of the api command.
+type apiFlags struct {
+	fields   []string
+	headers  []string
+	input    string
+	paginate bool
+}
+
+// newAPICommand creates the "api" command, a raw passthrough to any SonarQube endpoint.
+// It reuses the client built by initClient, so authentication, base URL, retry and
+// middleware all apply exactly as they do for generated commands.
+func newAPICommand(format *OutputFormat) *cobra.Command {
+	flags := &apiFlags{} //nolint:exhaustruct // fields are set by Cobra flag binding
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the api command
+		Use:   "api <method> <path>",
+		Short: "Send a raw request to any SonarQube API endpoint",
+		Long: `Send a raw HTTP request to any SonarQube API endpoint, including endpoints the SDK
+does not model yet. The path is relative to the API base URL; prefix it with "v2/"
+for V2 endpoints.
+
+Fields passed with -f are sent as query parameters. For V2 POST, PUT and PATCH
+requests without --input, they are sent as a JSON body instead.`,
+		Example: `  sonar-cli api GET issues/search -f projects=foo -f ps=500
+  sonar-cli api GET projects/search --paginate
+  sonar-cli api POST v2/users-management/users --input body.json
+  cat body.json | sonar-cli api PATCH v2/users-management/users/abc --input -`,
+		Args: cobra.ExactArgs(apiArgCount),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAPICommand(cmd, args, flags, format)
+		},
+	}
+
+	cmdFlags := cmd.Flags()
+	cmdFlags.StringArrayVarP(&flags.fields, "field", "f", nil, "Request parameter as key=value (repeatable)")
+	cmdFlags.StringArrayVarP(&flags.headers, "header", "H", nil, `Additional HTTP header as "Name: value" (repeatable)`)
+	cmdFlags.StringVar(&flags.input, "input", "", `File containing the JSON request body ("-" for stdin)`)
+	cmdFlags.BoolVar(&flags.paginate, "paginate", false, "Fetch all pages (p/ps for V1, pageIndex/pageSize for V2) and merge the results")
+
+	return cmd
+}
+
+// runAPICommand parses the api command arguments, sends the request and prints the response.
+func runAPICommand(cmd *cobra.Command, args []string, flags *apiFlags, format *OutputFormat) error {
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return err
+	}
+
+	req, err := parseAPIRequest(args, flags, cmd.InOrStdin())
+	if err != nil {
+		Logger().Error("invalid api request", zap.Error(err))
+
+		return err
+	}
+
+	result, err := executeAPIRequest(cmd.Context(), client, req)
+	if isDryRun(err) {
+		return nil
+	}
+
+	if err != nil {
+		Logger().Error("api request failed",
+			zap.String("method", req.Method),
+			zap.String("path", req.Path),
+			zap.Error(err))
+
+		return err
+	}
+
+	return writeResult(cmd, result, *format)
+}
+
+// parseAPIRequest validates the positional arguments and flags of the api command.
+func parseAPIRequest(args []string, flags *apiFlags, stdin io.Reader) (*apiRequest, error) {
+	method := strings.ToUpper(args[0])
+
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil, fmt.Errorf("unsupported HTTP method %q: must be one of GET, POST, PUT, PATCH, DELETE", args[0])
+	}
+
+	path := strings.TrimPrefix(strings.TrimPrefix(args[1], "/"), apiPathPrefix)
+	if path == "" {
+		return nil, errors.New("endpoint path must not be empty")
+	}
+
+	fields, err := parseAPIFields(flags.fields)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := parseAPIHeaders(flags.headers)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &apiRequest{
+		Method:   method,
+		Path:     path,
+		Fields:   fields,
+		Headers:  headers,
+		Body:     nil,
+		Paginate: flags.paginate,
+	}
+
+	if flags.input != "" {
+		req.Body, err = readAPIInput(flags.input, stdin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// parseAPIFields parses repeated key=value fields; repeated keys accumulate values.
+func parseAPIFields(raw []string) (url.Values, error) {
+	fields := url.Values{}
+
+	for _, field := range raw {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid field %q (expected key=value)", field)
+		}
+
+		fields.Add(key, value)
+	}
+
+	return fields, nil
+}
+
+// parseAPIHeaders parses repeated "Name: value" headers.
+func parseAPIHeaders(raw []string) (map[string]string, error) {
+	headers := make(map[string]string, len(raw))
+
+	for _, header := range raw {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q (expected \"Name: value\")", header)
+		}
+
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return headers, nil
+}
+
+// readAPIInput reads a JSON request body from a file or, for "-", from stdin.
+func readAPIInput(path string, stdin io.Reader) (json.RawMessage, error) {
+	data, err := readInputFile(path, stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("request body from %q is not valid JSON", path)
+	}
+
+	return json.RawMessage(data), nil
+}
+
+// isV2Path reports whether the path targets a V2 endpoint.
+func isV2Path(path string) bool {
+	return strings.HasPrefix(path, apiV2Prefix)
+}
+
+// sendsFieldsAsBody reports whether -f fields should be encoded as a JSON body
+// rather than query parameters: only V2 endpoints accept JSON bodies, and only when
+// no explicit --input body was given.
+func (r *apiRequest) sendsFieldsAsBody() bool {
+	if !isV2Path(r.Path) || r.Body != nil {
+		return false
+	}
+
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	default:
+		return false
+	}
+}
+
+// requestParameters builds the SDK request parameters for a single call.
+func (r *apiRequest) requestParameters(query url.Values) sonar.SonarAPIRequestParameters {
+	params := sonar.SonarAPIRequestParameters{ //nolint:exhaustruct // RootPath is never needed for API paths
+		Method:  r.Method,
+		Path:    r.Path,
+		Headers: r.Headers,
+	}
+
+	switch {
+	case r.Body != nil:
+		params.Body = r.Body
+		params.RawQuery = query
+	case r.sendsFieldsAsBody():
+		params.Body = fieldsToJSONBody(query)
+	default:
+		params.RawQuery = query
+	}
+
+	return params
+}
+
+// fieldsToJSONBody converts fields to a JSON object; repeated keys become arrays.
+func fieldsToJSONBody(fields url.Values) map[string]any {
+	body := make(map[string]any, len(fields))
+
+	for key, values := range fields {
+		if len(values) == 1 {
+			body[key] = values[0]
+
+			continue
+		}
+
+		body[key] = values
+	}
+
+	return body
+}
+
+// executeAPIRequest sends the request (every page of it with --paginate) and returns
+// the decoded JSON response, or the raw body when the response is not JSON.
+func executeAPIRequest(ctx context.Context, client *sonar.Client, req *apiRequest) (any, error) {
+	if req.Paginate {
+		return paginateAPIRequest(ctx, client, req)
+	}
+
+	body, err := sendAPIRequest(ctx, client, req, req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAPIBody(body), nil
+}
+
+// sendAPIRequest sends one request through the client and returns the raw response body.
+func sendAPIRequest(ctx context.Context, client *sonar.Client, req *apiRequest, query url.Values) ([]byte, error) {
+	httpReq, err := client.NewSonarQubeAPIRequest(ctx, req.requestParameters(query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	var body bytes.Buffer
+
+	resp, err := client.Do(httpReq, &body)
+	CloseBody(resp)
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // ResponseError already carries method, endpoint and status
+	}
+
+	return body.Bytes(), nil
+}
+
+// decodeAPIBody decodes a JSON body, preserving number precision. Non-JSON bodies
+// (e.g. protobuf or plain text endpoints) are returned as raw bytes.
+func decodeAPIBody(body []byte) any {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var decoded any
+
+	err := decoder.Decode(&decoded)
+	if err != nil {
+		return body
+	}
+
+	return decoded
+}
+
+// paginateAPIRequest fetches every page of a paginated endpoint and merges the item
+// arrays into the first page's response object.
+func paginateAPIRequest(ctx context.Context, client *sonar.Client, req *apiRequest) (any, error) {
+	style := apiPageStyleV1
+	if isV2Path(req.Path) {
+		style = apiPageStyleV2
+	}
+
+	query := cloneValues(req.Fields)
+	if query.Get(style.sizeParam) == "" {
+		query.Set(style.sizeParam, strconv.Itoa(sonar.MaxPageSize))
+	}
+
+	var (
+		merged   map[string]any
+		itemsKey string
+		items    []any
+	)
+
+	for page := 1; page <= maxPaginatePages; page++ {
+		query.Set(style.pageParam, strconv.Itoa(page))
+
+		body, err := sendAPIRequest(ctx, client, req, query)
+		if err != nil {
+			return nil, err
+		}
+
+		object, ok := decodeAPIBody(body).(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot paginate %s: response is not a JSON object", req.Path)
+		}
+
+		if page == 1 {
+			merged = object
+			itemsKey = largestArrayKey(object)
+
+			if itemsKey == "" {
+				return merged, nil
+			}
+		}
+
+		pageItems, _ := object[itemsKey].([]any)
+		items = append(items, pageItems...)
+
+		total, hasTotal := pagingTotal(object, style.pagingKey)
+		if len(pageItems) == 0 || !hasTotal || int64(len(items)) >= total {
+			break
+		}
+	}
+
+	merged[itemsKey] = items
+
+	// Both V1 "paging" and V2 "page" objects use pageIndex/pageSize keys.
+	if paging, ok := merged[style.pagingKey].(map[string]any); ok {
+		paging["pageIndex"] = json.Number("1")
+		paging["pageSize"] = json.Number(strconv.Itoa(len(items)))
+	}
+
+	return merged, nil
+}
+
+// largestArrayKey returns the key of the longest array in a response object, which is
+// the paginated item list. Ties are broken by key name for deterministic behaviour.
+func largestArrayKey(object map[string]any) string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	best, bestLen := "", -1
+
+	for _, key := range keys {
+		if arr, ok := object[key].([]any); ok && len(arr) > bestLen {
+			best, bestLen = key, len(arr)
+		}
+	}
+
+	return best
+}
+
+// pagingTotal reads the total item count from the paging object, falling back to a
+// top-level "total" as returned by some legacy V1 endpoints.
+func pagingTotal(object map[string]any, pagingKey string) (int64, bool) {
+	source := object
+	if paging, ok := object[pagingKey].(map[string]any); ok {
+		source = paging
+	}
+
+	number, ok := source["total"].(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	total, err := number.Int64()
+	if err != nil {
+		return 0, false
+	}
+
+	return total, true
+}
+
+// cloneValues returns a deep copy of url.Values.
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for key, vals := range values {
+		clone[key] = slices.Clone(vals)
+	}
+
+	return clone
+}
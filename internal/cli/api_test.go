@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// newTestClient creates a sonar.Client pointing at a test server.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *sonar.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	serverURL := server.URL + "/api/"
+	token := "test-token"
+
+	client, err := sonar.NewClient(&sonar.ClientCreateOptions{URL: &serverURL, Token: &token})
+	require.NoError(t, err)
+
+	return client
+}
+
+// TestParseAPIRequest tests argument and flag parsing for the api command.
+func TestParseAPIRequest(t *testing.T) {
+	flags := &apiFlags{
+		fields:  []string{"projects=foo", "ps=500", "projects=bar"},
+		headers: []string{"X-Test: yes"},
+	}
+
+	req, err := parseAPIRequest([]string{"get", "/api/issues/search"}, flags, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodGet, req.Method)
+	assert.Equal(t, "issues/search", req.Path)
+	assert.Equal(t, []string{"foo", "bar"}, req.Fields["projects"])
+	assert.Equal(t, "yes", req.Headers["X-Test"])
+}
+
+// TestParseAPIRequest_Errors tests invalid api command input.
+func TestParseAPIRequest_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		flags apiFlags
+	}{
+		{name: "bad method", args: []string{"FETCH", "issues/search"}},
+		{name: "empty path", args: []string{"GET", "/api/"}},
+		{name: "bad field", args: []string{"GET", "x"}, flags: apiFlags{fields: []string{"novalue"}}},
+		{name: "bad header", args: []string{"GET", "x"}, flags: apiFlags{headers: []string{"nocolon"}}},
+		{name: "invalid json input", args: []string{"POST", "v2/x"}, flags: apiFlags{input: "-"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseAPIRequest(tc.args, &tc.flags, strings.NewReader("{not json"))
+			require.Error(t, err)
+		})
+	}
+}
+
+// TestExecuteAPIRequest_V1Query tests that V1 fields are sent as query parameters.
+func TestExecuteAPIRequest_V1Query(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/issues/search", r.URL.Path)
+		assert.Equal(t, "foo", r.URL.Query().Get("projects"))
+		assert.Equal(t, "yes", r.Header.Get("X-Test"))
+
+		_, _ = io.WriteString(w, `{"total": 1, "issues": [{"key": "AX1"}]}`)
+	})
+
+	req := &apiRequest{
+		Method:  http.MethodGet,
+		Path:    "issues/search",
+		Fields:  map[string][]string{"projects": {"foo"}},
+		Headers: map[string]string{"X-Test": "yes"},
+	}
+
+	result, err := executeAPIRequest(context.Background(), client, req)
+	require.NoError(t, err)
+
+	object, ok := result.(map[string]any)
+	require.True(t, ok)
+	assert.Len(t, object["issues"], 1)
+}
+
+// TestExecuteAPIRequest_V2Body tests V2 body handling for --input and -f fields.
+func TestExecuteAPIRequest_V2Body(t *testing.T) {
+	var gotBody map[string]any
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/users-management/users", r.URL.Path)
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"id": "u1"}`)
+	})
+
+	req := &apiRequest{
+		Method: http.MethodPost,
+		Path:   "v2/users-management/users",
+		Fields: map[string][]string{"login": {"bob"}},
+	}
+
+	_, err := executeAPIRequest(context.Background(), client, req)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", gotBody["login"])
+
+	req.Body = json.RawMessage(`{"login": "alice"}`)
+
+	_, err = executeAPIRequest(context.Background(), client, req)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", gotBody["login"])
+}
+
+// TestExecuteAPIRequest_RawBody tests that non-JSON responses are returned verbatim.
+func TestExecuteAPIRequest_RawBody(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "plain text")
+	})
+
+	result, err := executeAPIRequest(context.Background(), client, &apiRequest{Method: http.MethodGet, Path: "server/version"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("plain text"), result)
+}
+
+// TestExecuteAPIRequest_Error tests that API errors are surfaced.
+func TestExecuteAPIRequest_Error(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"errors":[{"msg":"not found"}]}`)
+	})
+
+	_, err := executeAPIRequest(context.Background(), client, &apiRequest{Method: http.MethodGet, Path: "projects/show"})
+	require.Error(t, err)
+	assert.True(t, sonar.IsNotFound(err))
+}
+
+// TestExecuteAPIRequest_Paginate tests V1 and V2 pagination merging.
+func TestExecuteAPIRequest_Paginate(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		pageParam string
+		sizeParam string
+		pagingKey string
+	}{
+		{name: "v1", path: "projects/search", pageParam: "p", sizeParam: "ps", pagingKey: "paging"},
+		{name: "v2", path: "v2/users-management/users", pageParam: "pageIndex", sizeParam: "pageSize", pagingKey: "page"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+
+				assert.Equal(t, "2", r.URL.Query().Get(tc.sizeParam))
+
+				page, _ := strconv.Atoi(r.URL.Query().Get(tc.pageParam))
+				items := []map[string]string{{"key": "p" + strconv.Itoa(page) + "a"}}
+
+				if page < 3 {
+					items = append(items, map[string]string{"key": "p" + strconv.Itoa(page) + "b"})
+				}
+
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"items":      items,
+					tc.pagingKey: map[string]int{"pageIndex": page, "pageSize": 2, "total": 5},
+				})
+			})
+
+			req := &apiRequest{
+				Method:   http.MethodGet,
+				Path:     tc.path,
+				Fields:   map[string][]string{tc.sizeParam: {"2"}},
+				Paginate: true,
+			}
+
+			result, err := executeAPIRequest(context.Background(), client, req)
+			require.NoError(t, err)
+			assert.Equal(t, 3, calls)
+
+			object, ok := result.(map[string]any)
+			require.True(t, ok)
+			assert.Len(t, object["items"], 5)
+
+			var buf bytes.Buffer
+
+			require.NoError(t, FormatOutput(&buf, result, OutputJSON))
+			assert.Contains(t, buf.String(), `"p3a"`)
+		})
+	}
+}
@@ -0,0 +1,434 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/assets"
+)
+
+const (
+	// enumAnnotation lists the values the API accepts for a flag.
+	enumAnnotation = "sonar-cli_enum"
+	// deprecatedAnnotation holds the version since which a command or flag is deprecated.
+	deprecatedAnnotation = "sonar-cli_deprecated"
+	// maxSuggestionDistance is the largest edit distance of a "did you mean" suggestion.
+	maxSuggestionDistance = 2
+)
+
+// errInvalidFlagValue is returned when a flag value is not one the API accepts.
+var errInvalidFlagValue = errors.New("invalid flag value")
+
+// endpointOverrides maps "ServiceName.MethodName" to the API action of methods whose name
+// does not follow the action key. Other methods map to <service>/<method> in snake_case.
+//
+//nolint:gochecknoglobals // constant configuration set
+var endpointOverrides = map[string]string{
+	"AlmIntegrations.ImportBitbucketCloudRepo":     "alm_integrations/import_bitbucketcloud_repo",
+	"AlmIntegrations.ImportBitbucketServerProject": "alm_integrations/import_bitbucketserver_project",
+	"AlmIntegrations.ListBitbucketServerProjects":  "alm_integrations/list_bitbucketserver_projects",
+	"AlmIntegrations.SearchBitbucketCloudRepos":    "alm_integrations/search_bitbucketcloud_repos",
+	"AlmIntegrations.SearchBitbucketServerRepos":   "alm_integrations/search_bitbucketserver_repos",
+	"AlmSettings.CreateBitbucketCloud":             "alm_settings/create_bitbucketcloud",
+	"AlmSettings.SetBitbucketCloudBinding":         "alm_settings/set_bitbucketcloud_binding",
+	"AlmSettings.UpdateBitbucketCloud":             "alm_settings/update_bitbucketcloud",
+	"AnalysisReports.QueueStatus":                  "analysis_reports/is_queue_empty",
+	"Batch.GetFile":                                "batch/file",
+	"Batch.GetIndex":                               "batch/index",
+	"Batch.GetProject":                             "batch/project",
+	"Editions.Get":                                 "editions/show_license",
+	"Editions.Set":                                 "editions/set_license",
+	"L10N.GetIndex":                                "l10n/index",
+	"Qualitygates.Assign":                          "qualitygates/select",
+	"Qualitygates.Delete":                          "qualitygates/destroy",
+	"Qualitygates.SetDefault":                      "qualitygates/set_as_default",
+	"Qualitygates.Unassign":                        "qualitygates/deselect",
+	"Views.SubPortfolios":                          "views/portfolios",
+}
+
+var (
+	// htmlBreakPattern matches the HTML tags that start a new line in API descriptions.
+	htmlBreakPattern = regexp.MustCompile(`(?i)<(br\s*/?|/p|p|/ul|/ol)>`)
+	// htmlItemPattern matches list items in API descriptions.
+	htmlItemPattern = regexp.MustCompile(`(?i)<li>`)
+	// htmlTagPattern matches any other HTML tag in API descriptions.
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+)
+
+// apiSpec is the part of the bundled api/webservices/list response used by the CLI.
+type apiSpec struct {
+	WebServices []apiWebService `json:"webServices"`
+}
+
+// apiWebService is a web service (an API controller) of the specification.
+type apiWebService struct {
+	Path    string      `json:"path"`
+	Actions []apiAction `json:"actions"`
+}
+
+// apiAction is an action (an endpoint) of a web service.
+//
+//nolint:govet // fieldalignment: keeping logical field grouping for readability
+type apiAction struct {
+	Key             string     `json:"key"`
+	Description     string     `json:"description"`
+	Since           string     `json:"since"`
+	DeprecatedSince string     `json:"deprecatedSince"`
+	Post            bool       `json:"post"`
+	Params          []apiParam `json:"params"`
+}
+
+// apiParam is a parameter of an action.
+//
+//nolint:govet // fieldalignment: keeping logical field grouping for readability
+type apiParam struct {
+	Key             string   `json:"key"`
+	Description     string   `json:"description"`
+	Required        bool     `json:"required"`
+	PossibleValues  []string `json:"possibleValues"`
+	DefaultValue    string   `json:"defaultValue"`
+	ExampleValue    string   `json:"exampleValue"`
+	Since           string   `json:"since"`
+	DeprecatedSince string   `json:"deprecatedSince"`
+	MaximumLength   int      `json:"maximumLength"`
+}
+
+// apiActions returns the actions of the bundled specification keyed by path, e.g.
+// "projects/delete". The specification is parsed once, on first use.
+//
+//nolint:gochecknoglobals // parsed once from the embedded specification
+var apiActions = sync.OnceValue(func() map[string]*apiAction {
+	var spec apiSpec
+
+	err := json.Unmarshal(assets.APISpec, &spec)
+	if err != nil {
+		Logger().Error("failed to parse the bundled API specification", zap.Error(err))
+
+		return nil
+	}
+
+	actions := make(map[string]*apiAction)
+
+	for _, service := range spec.WebServices {
+		for idx := range service.Actions {
+			action := &service.Actions[idx]
+			actions[strings.TrimPrefix(service.Path, "api/")+"/"+action.Key] = action
+		}
+	}
+
+	return actions
+})
+
+// apiEndpoint returns the API action path of a service method, e.g. "projects/bulk_delete".
+func apiEndpoint(serviceName, methodName string) string {
+	if endpoint, ok := endpointOverrides[serviceName+"."+methodName]; ok {
+		return endpoint
+	}
+
+	return pascalToSnake(serviceName) + "/" + pascalToSnake(methodName)
+}
+
+// pascalToSnake converts a PascalCase string to snake_case.
+func pascalToSnake(input string) string {
+	return strings.ReplaceAll(pascalToKebab(input), "-", "_")
+}
+
+// applyAPISpec enriches a method command with the bundled specification of its action:
+// descriptions in --help, enum completion, and the annotations checkAPISpec validates
+// flag values and warns about deprecations with. Methods missing from the specification
+// (such as Enterprise-only ones) are left as they are.
+func applyAPISpec(cmd *cobra.Command, serviceName, methodName string, optValue reflect.Value) {
+	endpoint := apiEndpoint(serviceName, methodName)
+
+	action := apiActions()[endpoint]
+	if action == nil {
+		return
+	}
+
+	cmd.Long += "\n\n" + actionHelp(endpoint, action)
+
+	if action.DeprecatedSince != "" {
+		cmd.Annotations = map[string]string{deprecatedAnnotation: action.DeprecatedSince}
+	}
+
+	if !optValue.IsValid() {
+		return
+	}
+
+	params := make(map[string]*apiParam, len(action.Params))
+	for idx := range action.Params {
+		params[action.Params[idx].Key] = &action.Params[idx]
+	}
+
+	for _, field := range collectOptionFields(optValue.Elem()) {
+		param := params[field.apiName]
+		flag := cmd.Flags().Lookup(field.flagName)
+
+		if param == nil || flag == nil {
+			continue
+		}
+
+		applyParamSpec(cmd, flag, param)
+	}
+}
+
+// actionHelp describes an action for the long help of its command.
+func actionHelp(endpoint string, action *apiAction) string {
+	method := "GET"
+	if action.Post {
+		method = "POST"
+	}
+
+	help := apiDescription(action.Description, true) + "\n\nEndpoint: " + method + " api/" + endpoint
+
+	if action.Since != "" {
+		help += " (since " + action.Since + ")"
+	}
+
+	if action.DeprecatedSince != "" {
+		help += "\nDeprecated since " + action.DeprecatedSince + "."
+	}
+
+	return help
+}
+
+// applyParamSpec describes a flag with its API parameter and records the accepted values
+// and deprecation of the parameter.
+func applyParamSpec(cmd *cobra.Command, flag *pflag.Flag, param *apiParam) {
+	required := strings.HasSuffix(flag.Usage, " (required)")
+
+	usage := apiDescription(param.Description, false)
+	if usage == "" {
+		usage = strings.TrimSuffix(flag.Usage, " (required)")
+	}
+
+	if details := paramDetails(flag, param); len(details) > 0 {
+		usage += " [" + strings.Join(details, "; ") + "]"
+	}
+
+	if required {
+		usage += " (required)"
+	}
+
+	flag.Usage = usage
+
+	if param.DeprecatedSince != "" {
+		_ = cmd.Flags().SetAnnotation(flag.Name, deprecatedAnnotation, []string{param.DeprecatedSince})
+	}
+
+	if len(param.PossibleValues) == 0 || !isEnumFlag(flag) {
+		return
+	}
+
+	_ = cmd.Flags().SetAnnotation(flag.Name, enumAnnotation, param.PossibleValues)
+
+	if _, ok := cmd.GetFlagCompletionFunc(flag.Name); !ok {
+		_ = cmd.RegisterFlagCompletionFunc(flag.Name, completeEnumValues(param.PossibleValues))
+	}
+}
+
+// paramDetails lists the constraints and metadata of a parameter shown in flag help.
+// Boolean flags do not list their possible values.
+func paramDetails(flag *pflag.Flag, param *apiParam) []string {
+	var details []string
+
+	if len(param.PossibleValues) > 0 && flag.Value.Type() != "bool" && flag.Value.Type() != "tristate" {
+		details = append(details, "one of: "+strings.Join(param.PossibleValues, ", "))
+	}
+
+	if param.DefaultValue != "" {
+		details = append(details, "server default: "+param.DefaultValue)
+	}
+
+	if param.ExampleValue != "" {
+		details = append(details, "example: "+param.ExampleValue)
+	}
+
+	if param.MaximumLength > 0 {
+		details = append(details, "max length: "+strconv.Itoa(param.MaximumLength))
+	}
+
+	if param.Since != "" {
+		details = append(details, "since "+param.Since)
+	}
+
+	if param.DeprecatedSince != "" {
+		details = append(details, "deprecated since "+param.DeprecatedSince)
+	}
+
+	return details
+}
+
+// isEnumFlag reports whether a flag holds free text that enum values can be checked against.
+func isEnumFlag(flag *pflag.Flag) bool {
+	return flag.Value.Type() == "string" || isListFlag(flag)
+}
+
+// apiDescription converts an HTML description of the specification to plain text. With
+// keepLines, line breaks and list items start new lines; otherwise the text is one line.
+func apiDescription(text string, keepLines bool) string {
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlItemPattern.ReplaceAllString(text, "\n- ")
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, ""))
+
+	if !keepLines {
+		return strings.Join(strings.Fields(text), " ")
+	}
+
+	var lines []string
+
+	for line := range strings.SplitSeq(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// completeEnumValues returns a completion function for the accepted values of a flag.
+// Slice flags are completed one comma-separated element at a time.
+func completeEnumValues(values []string) cobra.CompletionFunc {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		prefix, word := splitCompletionWord(toComplete)
+
+		var matches []cobra.Completion
+
+		for _, value := range values {
+			if strings.HasPrefix(strings.ToLower(value), strings.ToLower(word)) {
+				matches = append(matches, prefix+value)
+			}
+		}
+
+		directive := cobra.ShellCompDirectiveNoFileComp
+		if prefix != "" {
+			directive |= cobra.ShellCompDirectiveNoSpace
+		}
+
+		return matches, directive
+	}
+}
+
+// checkAPISpec warns about deprecated commands and flags in use, and rejects flag values
+// the API does not accept, suggesting the closest accepted value.
+func checkAPISpec(cmd *cobra.Command) error {
+	if since, ok := cmd.Annotations[deprecatedAnnotation]; ok {
+		Logger().Warn("command is deprecated",
+			zap.String("command", cmd.CommandPath()),
+			zap.String("since", since))
+	}
+
+	var errs []error
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Changed && (flag.Value.String() == "" || flag.Value.String() == "[]") {
+			return
+		}
+
+		if since, ok := flag.Annotations[deprecatedAnnotation]; ok {
+			Logger().Warn("flag is deprecated",
+				zap.String("flag", "--"+flag.Name),
+				zap.String("since", since[0]))
+		}
+
+		if allowed, ok := flag.Annotations[enumAnnotation]; ok {
+			for _, value := range flagValues(flag, allowed) {
+				if !slices.Contains(allowed, value) {
+					errs = append(errs, invalidValueError(flag.Name, value, allowed))
+				}
+			}
+		}
+	})
+
+	err := errors.Join(errs...)
+	if err != nil {
+		Logger().Error("invalid flag values", zap.Error(err))
+	}
+
+	return err
+}
+
+// flagValues returns the values of a flag to check. A string flag holding a
+// comma-separated list is split, unless its whole value is itself accepted.
+func flagValues(flag *pflag.Flag, allowed []string) []string {
+	if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+		return sliceValue.GetSlice()
+	}
+
+	value := flag.Value.String()
+	if value == "" {
+		return nil
+	}
+
+	if slices.Contains(allowed, value) {
+		return []string{value}
+	}
+
+	return strings.Split(value, ",")
+}
+
+// invalidValueError describes a rejected value, with a suggestion when one is close.
+func invalidValueError(flagName, value string, allowed []string) error {
+	err := fmt.Errorf("%w %q for --%s: must be one of %s", errInvalidFlagValue, value, flagName, strings.Join(allowed, ", "))
+
+	if suggestion := closestValue(value, allowed); suggestion != "" {
+		err = fmt.Errorf("%w; did you mean %q?", err, suggestion)
+	}
+
+	return err
+}
+
+// closestValue returns the allowed value closest to value, ignoring case, or "" when none
+// is within maxSuggestionDistance edits.
+func closestValue(value string, allowed []string) string {
+	best, bestDistance := "", maxSuggestionDistance+1
+
+	for _, candidate := range allowed {
+		distance := editDistance(strings.ToLower(value), strings.ToLower(candidate))
+		if distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(left, right string) int {
+	leftRunes, rightRunes := []rune(left), []rune(right)
+
+	previous := make([]int, len(rightRunes)+1)
+	for idx := range previous {
+		previous[idx] = idx
+	}
+
+	for i, leftRune := range leftRunes {
+		current := make([]int, len(rightRunes)+1)
+		current[0] = i + 1
+
+		for j, rightRune := range rightRunes {
+			cost := 1
+			if leftRune == rightRune {
+				cost = 0
+			}
+
+			current[j+1] = min(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(rightRunes)]
+}
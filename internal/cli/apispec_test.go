@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAPIEndpoint tests mapping service methods to API actions.
+func TestAPIEndpoint(t *testing.T) {
+	assert.Equal(t, "projects/bulk_delete", apiEndpoint("Projects", "BulkDelete"))
+	assert.Equal(t, "l10n/index", apiEndpoint("L10N", "GetIndex"))
+	assert.Equal(t, "qualitygates/destroy", apiEndpoint("Qualitygates", "Delete"))
+
+	// Actions of commercial editions are not in the bundled Community Build specification.
+	commercial := map[string]bool{
+		"AlmSettings.SetBitbucketCloudBinding": true,
+		"Editions.Get":                         true,
+		"Editions.Set":                         true,
+		"Views.SubPortfolios":                  true,
+	}
+
+	for key, endpoint := range endpointOverrides {
+		if commercial[key] {
+			continue
+		}
+
+		assert.NotNil(t, apiActions()[endpoint], key)
+	}
+}
+
+// TestApplyAPISpec tests the help, annotations and completion taken from the specification.
+func TestApplyAPISpec(t *testing.T) {
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test"}
+	RegisterAllCommands(rootCmd, &format)
+
+	cmd, _, err := rootCmd.Find([]string{"issues", "search"})
+	require.NoError(t, err)
+	assert.Contains(t, cmd.Long, "Endpoint: GET api/issues/search (since 3.6)")
+
+	flag := cmd.Flags().Lookup("impact-severities")
+	assert.Contains(t, flag.Usage, "Comma-separated list of Software Quality Severities [one of: INFO, LOW, MEDIUM, HIGH, BLOCKER;")
+	assert.Equal(t, []string{"INFO", "LOW", "MEDIUM", "HIGH", "BLOCKER"}, flag.Annotations[enumAnnotation])
+	assert.NotContains(t, cmd.Flags().Lookup("resolved").Usage, "one of")
+	assert.Equal(t, []string{"10.0"}, cmd.Flags().Lookup("sans-top25").Annotations[deprecatedAnnotation])
+
+	complete, ok := cmd.GetFlagCompletionFunc("impact-severities")
+	require.True(t, ok)
+
+	matches, directive := complete(cmd, nil, "HIGH,m")
+	assert.Equal(t, []cobra.Completion{"HIGH,MEDIUM"}, matches)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace, directive)
+
+	cmd, _, err = rootCmd.Find([]string{"users", "deactivate"})
+	require.NoError(t, err)
+	assert.Contains(t, cmd.Flags().Lookup("login").Usage, "User login [example: myuser] (required)")
+}
+
+// TestCheckAPISpec_Enums tests client-side validation of enum flags.
+func TestCheckAPISpec_Enums(t *testing.T) {
+	sent := 0
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		sent++
+
+		_, _ = w.Write([]byte(`{}`))
+	}
+
+	_, err := runPositionalCLI(t, handler, "issues", "search", "--impact-severities", "high,LOW", "--severities", "MAJR")
+	require.ErrorIs(t, err, errInvalidFlagValue)
+	assert.Contains(t, err.Error(), `invalid flag value "high" for --impact-severities: must be one of INFO, LOW, MEDIUM, HIGH, BLOCKER; did you mean "HIGH"?`)
+	assert.Contains(t, err.Error(), `"MAJR" for --severities`)
+	assert.Zero(t, sent)
+
+	_, err = runPositionalCLI(t, handler, "issues", "search", "--impact-severities", "HIGH", "--facets", "nonsense")
+	require.ErrorIs(t, err, errInvalidFlagValue)
+	assert.NotContains(t, err.Error(), "did you mean")
+
+	_, err = runPositionalCLI(t, handler, "issues", "search", "--impact-severities", "HIGH,LOW")
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+}
+
+// TestAPIDescription tests converting HTML descriptions to plain text.
+func TestAPIDescription(t *testing.T) {
+	text := "Search for issues.<br/>Requires the &#39;Browse&#39; permission:<ul><li>on <code>projects</code></li><li>on apps</li></ul>"
+
+	assert.Equal(t, "Search for issues. Requires the 'Browse' permission: - on projects - on apps", apiDescription(text, false))
+	assert.Equal(t, "Search for issues.\nRequires the 'Browse' permission:\n- on projects\n- on apps", apiDescription(text, true))
+}
+
+// TestClosestValue tests "did you mean" suggestions.
+func TestClosestValue(t *testing.T) {
+	allowed := []string{"INFO", "MINOR", "MAJOR", "CRITICAL", "BLOCKER"}
+
+	assert.Equal(t, "MAJOR", closestValue("majr", allowed))
+	assert.Equal(t, "CRITICAL", closestValue("critcal", allowed))
+	assert.Empty(t, closestValue("whatever", allowed))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
@@ -0,0 +1,708 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// batchStatusSucceeded marks a batch entry that ran successfully.
+	batchStatusSucceeded = "succeeded"
+	// batchStatusFailed marks a batch entry that returned an error.
+	batchStatusFailed = "failed"
+	// batchStatusSkipped marks a batch entry that did not run.
+	batchStatusSkipped = "skipped"
+	// templateMarker identifies plan values that contain template actions.
+	templateMarker = "{{"
+)
+
+// errBatchFailed is returned when at least one batch entry failed.
+var errBatchFailed = errors.New("batch failed")
+
+// ndjsonExtensions lists plan file extensions read as one JSON entry per line.
+//
+//nolint:gochecknoglobals // constant configuration set
+var ndjsonExtensions = map[string]struct{}{
+	".ndjson": {},
+	".jsonl":  {},
+}
+
+// BatchReport is the result of a batch run: a summary and one result per entry.
+type BatchReport struct {
+	// Summary counts the entries by status.
+	Summary BatchSummary `json:"summary"`
+	// Results lists the entries in plan order (row by row when a matrix is used).
+	Results []BatchEntryResult `json:"results"`
+}
+
+// BatchSummary counts the entries of a batch run by status.
+type BatchSummary struct {
+	Total      int   `json:"total"`
+	Succeeded  int   `json:"succeeded"`
+	Failed     int   `json:"failed"`
+	Skipped    int   `json:"skipped"`
+	DurationMs int64 `json:"durationMs"`
+}
+
+// BatchEntryResult is the outcome of a single batch entry.
+//
+//nolint:govet // fieldalignment: keeping logical field grouping for readability
+type BatchEntryResult struct {
+	// Index is the 1-based position of the entry in the plan.
+	Index int `json:"index"`
+	// Row is the 1-based matrix row the entry was rendered with, if any.
+	Row        int    `json:"row,omitempty"`
+	Name       string `json:"name"`
+	Service    string `json:"service"`
+	Method     string `json:"method"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+	// Result is the value returned by the method, if any.
+	Result any `json:"result,omitempty"`
+}
+
+// batchEntry is one operation of a batch plan.
+type batchEntry struct {
+	Name    string    `yaml:"name"`
+	Service string    `yaml:"service"`
+	Method  string    `yaml:"method"`
+	Options yaml.Node `yaml:"options"`
+}
+
+// batchJob is a batch entry resolved to a service method and decoded options.
+type batchJob struct {
+	result   *BatchEntryResult
+	service  reflect.Value
+	method   string
+	pattern  MethodReturnPattern
+	optValue reflect.Value
+}
+
+// batchFlags holds the flags of the batch run command.
+type batchFlags struct {
+	matrix          string
+	parallel        int
+	continueOnError bool
+	failFast        bool
+}
+
+// newBatchCommand creates the batch command and its run subcommand.
+func newBatchCommand(format *OutputFormat) *cobra.Command {
+	batchCmd := &cobra.Command{ //nolint:exhaustruct // only Use/Short/Long are needed
+		Use:   "batch",
+		Short: "Run many sonar-cli operations from a plan file",
+		Long:  "Commands for running sonar-cli operations in bulk from YAML or NDJSON plan files.",
+	}
+
+	batchCmd.AddCommand(newBatchRunCommand(format))
+
+	return batchCmd
+}
+
+// newBatchRunCommand creates the batch run command.
+func newBatchRunCommand(format *OutputFormat) *cobra.Command {
+	flags := &batchFlags{} //nolint:exhaustruct // fields are set by Cobra flag binding
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the batch run command
+		Use:   "run <plan.yaml|plan.ndjson>",
+		Short: "Run the operations of a plan file",
+		Long: `Run the operations listed in a plan file and print a report with the outcome,
+duration and result of every entry.
+
+A plan is a YAML list of entries (or a mapping with an "entries" list), or an NDJSON
+file (.ndjson, .jsonl) with one entry per line. Each entry names a service and a
+method, as on the command line, and their options, keyed like --from-file documents:
+
+  - name: create {{.key}}
+    service: projects
+    method: create
+    options:
+      project: "{{.key}}"
+      name: "{{.name}}"
+  - service: project-tags
+    method: set
+    options: {project: "{{.key}}", tags: [team-a]}
+
+With --matrix, the plan runs once for each row of a CSV file whose header names the
+template variables ({{.column}}). Within a row, entries run in order and a failure
+skips the rest of the row. Without a matrix, each entry stands alone.
+
+--parallel runs up to N rows (or entries, without a matrix) concurrently. By default,
+or with --fail-fast, the run stops starting new work at the first failure; with
+--continue-on-error every row is attempted. The command exits with an error when any
+entry failed.`,
+		Example: `  sonar-cli batch run onboarding.yaml --matrix projects.csv --parallel 8 --continue-on-error
+  sonar-cli -o table batch run plan.ndjson`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBatchCommand(cmd, args[0], flags, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.matrix, "matrix", "", "CSV file whose rows provide template values; the plan runs once per row")
+	cmd.Flags().IntVar(&flags.parallel, "parallel", 1, "Number of rows (or entries) to run concurrently")
+	cmd.Flags().BoolVar(&flags.continueOnError, "continue-on-error", false, "Keep running other rows after a failure")
+	cmd.Flags().BoolVar(&flags.failFast, "fail-fast", false, "Stop starting new rows at the first failure (the default)")
+	cmd.MarkFlagsMutuallyExclusive("continue-on-error", "fail-fast")
+	cmd.Flags().Bool(yesFlag, false, "Run destructive entries without asking for confirmation")
+	_ = cmd.MarkFlagFilename("matrix", "csv")
+
+	return cmd
+}
+
+// runBatchCommand loads a plan and matrix, runs them and prints the report.
+func runBatchCommand(cmd *cobra.Command, planPath string, flags *batchFlags, format *OutputFormat) error {
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return err
+	}
+
+	if flags.parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1, got %d", flags.parallel)
+	}
+
+	units, err := loadBatch(client, cmd.InOrStdin(), planPath, flags.matrix)
+	if err != nil {
+		Logger().Error("invalid batch plan", zap.String("plan", planPath), zap.Error(err))
+
+		return err
+	}
+
+	err = confirmDestructive(cmd, plannedBatchRequests(client, units))
+	if err != nil {
+		return err
+	}
+
+	report := runBatch(cmd.Context(), units, flags.parallel, flags.continueOnError)
+
+	if isInterrupted(cmd) {
+		return writePartialResult(cmd, report, *format, fmt.Errorf("batch stopped after %d of %d entries: %w",
+			report.Summary.Succeeded+report.Summary.Failed, report.Summary.Total, context.Cause(cmd.Context())))
+	}
+
+	err = writeResult(cmd, report, *format)
+	if err != nil {
+		return err
+	}
+
+	if report.Summary.Failed > 0 {
+		return fmt.Errorf("%w: %d of %d entries failed", errBatchFailed, report.Summary.Failed, report.Summary.Total)
+	}
+
+	return nil
+}
+
+// plannedBatchRequests returns the requests of the destructive jobs of a batch, in run order.
+func plannedBatchRequests(client *sonar.Client, units [][]batchJob) []*plannedRequest {
+	var requests []*plannedRequest
+
+	for _, unit := range units {
+		for _, job := range unit {
+			if planned := plannedWrite(client, job.service, job.method, job.optValue, job.pattern); planned != nil {
+				requests = append(requests, planned)
+			}
+		}
+	}
+
+	return requests
+}
+
+// loadBatch reads a plan and an optional matrix and resolves every entry before
+// anything runs, so that a typo in the last entry does not leave a half-applied plan.
+// It returns the units of work: one per matrix row, or one per entry without a matrix.
+func loadBatch(client *sonar.Client, stdin io.Reader, planPath, matrixPath string) ([][]batchJob, error) {
+	data, err := readInputFile(planPath, stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	entries, err := parseBatchPlan(data, planPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if matrixPath == "" {
+		units := make([][]batchJob, 0, len(entries))
+
+		for idx, entry := range entries {
+			job, jobErr := newBatchJob(client, entry, idx+1, 0)
+			if jobErr != nil {
+				return nil, jobErr
+			}
+
+			units = append(units, []batchJob{job})
+		}
+
+		return units, nil
+	}
+
+	rows, err := readBatchMatrix(matrixPath, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	units := make([][]batchJob, 0, len(rows))
+
+	for rowIdx, row := range rows {
+		unit := make([]batchJob, 0, len(entries))
+
+		for idx, entry := range entries {
+			rendered, renderErr := renderBatchEntry(entry, row)
+			if renderErr != nil {
+				return nil, fmt.Errorf("entry %d, matrix row %d: %w", idx+1, rowIdx+1, renderErr)
+			}
+
+			job, jobErr := newBatchJob(client, rendered, idx+1, rowIdx+1)
+			if jobErr != nil {
+				return nil, jobErr
+			}
+
+			unit = append(unit, job)
+		}
+
+		units = append(units, unit)
+	}
+
+	return units, nil
+}
+
+// parseBatchPlan parses a YAML plan, or an NDJSON plan when the file extension says so
+// or when the first line is a complete JSON entry (e.g. a plan piped on stdin).
+func parseBatchPlan(data []byte, planPath string) ([]batchEntry, error) {
+	_, ndjson := ndjsonExtensions[strings.ToLower(filepath.Ext(planPath))]
+	if ndjson || isNDJSONEntry(data) {
+		return parseNDJSONPlan(data)
+	}
+
+	var root yaml.Node
+
+	err := yaml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan: %w", err)
+	}
+
+	if len(root.Content) == 0 {
+		return nil, errors.New("invalid plan: no entries")
+	}
+
+	list := root.Content[0]
+
+	// Accept either a bare list of entries or a mapping with an "entries" list.
+	if list.Kind == yaml.MappingNode {
+		var wrapper struct {
+			Entries yaml.Node `yaml:"entries"`
+		}
+
+		err = list.Decode(&wrapper)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plan: %w", err)
+		}
+
+		list = &wrapper.Entries
+	}
+
+	if list.Kind != yaml.SequenceNode {
+		return nil, errors.New("invalid plan: expected a list of entries")
+	}
+
+	entries := make([]batchEntry, 0, len(list.Content))
+
+	for idx, node := range list.Content {
+		var entry batchEntry
+
+		err = node.Decode(&entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plan entry %d: %w", idx+1, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// isNDJSONEntry reports whether the first non-blank line of data is a JSON object
+// naming a service, i.e. a single NDJSON plan entry.
+func isNDJSONEntry(data []byte) bool {
+	for line := range bytes.SplitSeq(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry map[string]json.RawMessage
+
+		if json.Unmarshal(line, &entry) != nil {
+			return false
+		}
+
+		_, ok := entry["service"]
+
+		return ok
+	}
+
+	return false
+}
+
+// parseNDJSONPlan parses a plan with one JSON entry per line; blank lines are ignored.
+func parseNDJSONPlan(data []byte) ([]batchEntry, error) {
+	var entries []batchEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry batchEntry
+
+		err := yaml.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plan line %d: %w", lineNumber, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("invalid plan: no entries")
+	}
+
+	return entries, nil
+}
+
+// readBatchMatrix reads a CSV file into rows keyed by the header columns.
+func readBatchMatrix(path string, stdin io.Reader) ([]map[string]string, error) {
+	data, err := readInputFile(path, stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read matrix: %w", err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid matrix %q: %w", path, err)
+	}
+
+	if len(records) < 2 { //nolint:mnd // a header and at least one row
+		return nil, fmt.Errorf("invalid matrix %q: expected a header row and at least one data row", path)
+	}
+
+	header := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for idx, column := range header {
+			row[strings.TrimSpace(column)] = record[idx]
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// renderBatchEntry returns a copy of the entry with the templates in its name and
+// option values rendered for a matrix row.
+func renderBatchEntry(entry batchEntry, row map[string]string) (batchEntry, error) {
+	name, err := renderBatchTemplate(entry.Name, row)
+	if err != nil {
+		return batchEntry{}, err
+	}
+
+	entry.Name = name
+	entry.Options = *cloneYAMLNode(&entry.Options)
+
+	err = renderYAMLNode(&entry.Options, row)
+	if err != nil {
+		return batchEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// renderYAMLNode renders the templates of every scalar in a YAML tree.
+func renderYAMLNode(node *yaml.Node, row map[string]string) error {
+	if node.Kind == yaml.ScalarNode {
+		if !strings.Contains(node.Value, templateMarker) {
+			return nil
+		}
+
+		value, err := renderBatchTemplate(node.Value, row)
+		if err != nil {
+			return err
+		}
+
+		// Resolve the rendered value afresh: quoting a template (required in YAML when it
+		// starts with "{{") must not force a string where the option expects a number.
+		node.Value = value
+		node.Tag = ""
+		node.Style = 0
+
+		return nil
+	}
+
+	for _, child := range node.Content {
+		err := renderYAMLNode(child, row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderBatchTemplate renders a text/template with a matrix row. Unknown columns are errors.
+func renderBatchTemplate(text string, row map[string]string) (string, error) {
+	if !strings.Contains(text, templateMarker) {
+		return text, nil
+	}
+
+	tmpl, err := template.New("entry").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %q: %w", text, err)
+	}
+
+	var rendered strings.Builder
+
+	err = tmpl.Execute(&rendered, row)
+	if err != nil {
+		return "", fmt.Errorf("failed to render %q: %w", text, err)
+	}
+
+	return rendered.String(), nil
+}
+
+// cloneYAMLNode deep-copies a YAML node tree.
+func cloneYAMLNode(node *yaml.Node) *yaml.Node {
+	clone := *node
+	clone.Content = make([]*yaml.Node, len(node.Content))
+
+	for idx, child := range node.Content {
+		clone.Content[idx] = cloneYAMLNode(child)
+	}
+
+	return &clone
+}
+
+// newBatchJob resolves an entry to a service method and decodes its options.
+func newBatchJob(client *sonar.Client, entry batchEntry, index, row int) (batchJob, error) {
+	service, method, err := resolveServiceMethod(client, entry.Service, entry.Method)
+	if err != nil {
+		return batchJob{}, fmt.Errorf("entry %d: %w", index, err)
+	}
+
+	name := entry.Name
+	if name == "" {
+		name = entry.Service + " " + entry.Method
+	}
+
+	job := batchJob{
+		result: &BatchEntryResult{ //nolint:exhaustruct // outcome fields are set when the job runs
+			Index:   index,
+			Row:     row,
+			Name:    name,
+			Service: entry.Service,
+			Method:  entry.Method,
+			Status:  batchStatusSkipped,
+		},
+		service:  service,
+		method:   method.Name,
+		pattern:  ClassifyMethod(method),
+		optValue: reflect.Value{},
+	}
+
+	if entry.Options.Kind != 0 && entry.Options.Kind != yaml.MappingNode && entry.Options.Tag != "!!null" {
+		return batchJob{}, fmt.Errorf("entry %d: options must be a mapping", index)
+	}
+
+	hasOpt := method.Type.NumIn() == 3 //nolint:mnd // 3 = receiver + ctx + option param
+	hasOptions := entry.Options.Kind == yaml.MappingNode && len(entry.Options.Content) > 0
+
+	if !hasOpt {
+		if hasOptions {
+			return batchJob{}, fmt.Errorf("entry %d: %s %s takes no options", index, entry.Service, entry.Method)
+		}
+
+		return job, nil
+	}
+
+	job.optValue = reflect.New(method.Type.In(2).Elem()) //nolint:mnd // 2 = option param index
+
+	if hasOptions {
+		var document map[string]yaml.Node
+
+		err = entry.Options.Decode(&document)
+		if err != nil {
+			return batchJob{}, fmt.Errorf("entry %d: invalid options: %w", index, err)
+		}
+
+		err = applyOptionNodes(nil, document, job.optValue)
+		if err != nil {
+			return batchJob{}, fmt.Errorf("entry %d: %w", index, err)
+		}
+	}
+
+	return job, nil
+}
+
+// resolveServiceMethod finds a client service and method by command name ("project-tags",
+// "set") or Go name ("ProjectTags", "Set").
+func resolveServiceMethod(client *sonar.Client, serviceName, methodName string) (reflect.Value, reflect.Method, error) {
+	clientVal := reflect.ValueOf(client).Elem()
+
+	for field := range clientVal.Type().Fields() {
+		if !field.IsExported() || field.Type.Kind() != reflect.Pointer || field.Type.Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		if !matchesCommandName(field.Name, serviceName) {
+			continue
+		}
+
+		for method := range field.Type.Methods() {
+			if shouldSkipMethod(method.Name) || !matchesCommandName(method.Name, methodName) {
+				continue
+			}
+
+			if _, streaming := streamingMethods[field.Name+"."+method.Name]; streaming {
+				return reflect.Value{}, reflect.Method{}, fmt.Errorf("%s %s streams its response and cannot run in a batch", serviceName, methodName)
+			}
+
+			return clientVal.FieldByIndex(field.Index), method, nil
+		}
+
+		return reflect.Value{}, reflect.Method{}, fmt.Errorf("unknown method %q for service %q", methodName, serviceName)
+	}
+
+	return reflect.Value{}, reflect.Method{}, fmt.Errorf("unknown service %q", serviceName)
+}
+
+// matchesCommandName reports whether name is the command name or Go name of goName.
+func matchesCommandName(goName, name string) bool {
+	return name == pascalToKebab(goName) || strings.EqualFold(name, goName)
+}
+
+// runBatch runs the units with up to parallel workers and builds the report. Jobs within
+// a unit run in order and a failure skips the rest of the unit. Unless continueOnError
+// is set, the first failure stops workers from starting further units. Once ctx ends, the
+// remaining units are skipped.
+func runBatch(ctx context.Context, units [][]batchJob, parallel int, continueOnError bool) *BatchReport {
+	start := time.Now()
+
+	var (
+		stopped atomic.Bool
+		waiter  sync.WaitGroup
+	)
+
+	queue := make(chan []batchJob)
+
+	for range min(parallel, max(len(units), 1)) {
+		waiter.Go(func() {
+			for unit := range queue {
+				if stopped.Load() || ctx.Err() != nil {
+					continue
+				}
+
+				if !runBatchUnit(ctx, unit) && !continueOnError {
+					stopped.Store(true)
+				}
+			}
+		})
+	}
+
+	for _, unit := range units {
+		queue <- unit
+	}
+
+	close(queue)
+	waiter.Wait()
+
+	report := &BatchReport{} //nolint:exhaustruct // filled below
+
+	for _, unit := range units {
+		for _, job := range unit {
+			report.Results = append(report.Results, *job.result)
+
+			switch job.result.Status {
+			case batchStatusSucceeded:
+				report.Summary.Succeeded++
+			case batchStatusFailed:
+				report.Summary.Failed++
+			default:
+				report.Summary.Skipped++
+			}
+		}
+	}
+
+	report.Summary.Total = len(report.Results)
+	report.Summary.DurationMs = time.Since(start).Milliseconds()
+
+	return report
+}
+
+// runBatchUnit runs the jobs of a unit in order, stopping at the first failure.
+func runBatchUnit(ctx context.Context, unit []batchJob) bool {
+	for _, job := range unit {
+		if !runBatchJob(ctx, job) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runBatchJob invokes a job's method and records its outcome.
+func runBatchJob(ctx context.Context, job batchJob) bool {
+	start := time.Now()
+
+	result, resp, err := InvokeMethod(ctx, job.service, job.method, job.optValue, job.pattern, job.optValue.IsValid())
+	CloseBody(resp)
+
+	job.result.DurationMs = time.Since(start).Milliseconds()
+
+	if err != nil && !isDryRun(err) {
+		job.result.Status = batchStatusFailed
+		job.result.Error = err.Error()
+
+		Logger().Warn("batch entry failed",
+			zap.Int("index", job.result.Index),
+			zap.String("name", job.result.Name),
+			zap.Error(err))
+
+		return false
+	}
+
+	job.result.Status = batchStatusSucceeded
+	job.result.Result = result
+
+	return true
+}
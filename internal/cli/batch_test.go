@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// onboardingPlan creates a project and tags it, for every matrix row.
+const onboardingPlan = `
+- name: create {{.key}}
+  service: projects
+  method: create
+  options:
+    project: "{{.key}}"
+    name: "{{.name}}"
+- service: ProjectTags
+  method: Set
+  options: {project: "{{.key}}", tags: [team-a]}
+`
+
+// writeBatchFile writes a file into a temporary directory.
+func writeBatchFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+// runBatchCLI runs "batch run" with a test client and returns the decoded report.
+func runBatchCLI(t *testing.T, handler http.HandlerFunc, args ...string) (*BatchReport, error) {
+	t.Helper()
+
+	client := newTestClient(t, handler)
+
+	var out bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.AddCommand(newBatchCommand(&format))
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"batch", "run"}, args...))
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+	if out.Len() == 0 {
+		return nil, err
+	}
+
+	var report BatchReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+
+	return &report, err
+}
+
+// TestBatchRun_MatrixContinueOnError tests matrix expansion, row ordering and the
+// continue-on-error policy.
+func TestBatchRun_MatrixContinueOnError(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		requests []string
+	)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		mutex.Lock()
+		requests = append(requests, r.URL.Path+" "+r.Form.Get("project")+" "+r.Form.Get("name")+r.Form.Get("tags"))
+		mutex.Unlock()
+
+		if r.Form.Get("project") == "beta" && strings.HasSuffix(r.URL.Path, "/create") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": [{"msg": "already exists"}]}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"project": {"key": "` + r.Form.Get("project") + `"}}`))
+	}
+
+	plan := writeBatchFile(t, "plan.yaml", onboardingPlan)
+	matrix := writeBatchFile(t, "projects.csv", "key,name\nalpha,Alpha App\nbeta,Beta App\ngamma,Gamma App\n")
+
+	report, err := runBatchCLI(t, handler, plan, "--matrix", matrix, "--parallel", "2", "--continue-on-error")
+	require.ErrorIs(t, err, errBatchFailed)
+	require.NotNil(t, report)
+
+	assert.Equal(t, BatchSummary{Total: 6, Succeeded: 4, Failed: 1, Skipped: 1, DurationMs: report.Summary.DurationMs}, report.Summary)
+	assert.Equal(t, "create beta", report.Results[2].Name)
+	assert.Equal(t, batchStatusFailed, report.Results[2].Status)
+	assert.Contains(t, report.Results[2].Error, "already exists")
+	assert.Equal(t, batchStatusSkipped, report.Results[3].Status)
+	assert.Equal(t, 2, report.Results[3].Row)
+	assert.NotNil(t, report.Results[0].Result)
+
+	assert.ElementsMatch(t, []string{
+		"/api/projects/create alpha Alpha App",
+		"/api/project_tags/set alpha team-a",
+		"/api/projects/create beta Beta App",
+		"/api/projects/create gamma Gamma App",
+		"/api/project_tags/set gamma team-a",
+	}, requests)
+}
+
+// TestBatchRun_FailFast tests that the first failure stops further entries, by default
+// and with --fail-fast, and that --fail-fast excludes --continue-on-error.
+func TestBatchRun_FailFast(t *testing.T) {
+	plan := writeBatchFile(t, "plan.ndjson", strings.Join([]string{
+		`{"service": "project-tags", "method": "set", "options": {"project": "a", "tags": "x,y"}}`,
+		``,
+		`{"service": "project-tags", "method": "set", "options": {"project": "b", "tags": ["x"]}}`,
+		`{"service": "project-tags", "method": "set", "options": {"project": "c", "tags": ["x"]}}`,
+	}, "\n"))
+
+	for name, args := range map[string][]string{"default": {plan}, "flag": {plan, "--fail-fast"}} {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+
+			handler := func(w http.ResponseWriter, _ *http.Request) {
+				calls++
+
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+
+			report, err := runBatchCLI(t, handler, args...)
+			require.ErrorIs(t, err, errBatchFailed)
+
+			assert.Equal(t, 1, calls)
+			assert.Equal(t, 1, report.Summary.Failed)
+			assert.Equal(t, 2, report.Summary.Skipped)
+			assert.Equal(t, "project-tags set", report.Results[0].Name)
+		})
+	}
+
+	_, err := runBatchCLI(t, func(http.ResponseWriter, *http.Request) {}, plan, "--fail-fast", "--continue-on-error")
+	require.ErrorContains(t, err, "none of the others can be")
+}
+
+// TestBatchRun_ValidatesPlanFirst tests that plan errors are reported before any request.
+func TestBatchRun_ValidatesPlanFirst(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     string
+		matrix   string
+		contains string
+	}{
+		{name: "unknown service", plan: "- {service: nope, method: list}", contains: `unknown service "nope"`},
+		{name: "unknown method", plan: "- {service: projects, method: nope}", contains: `unknown method "nope"`},
+		{name: "unknown option", plan: "- {service: projects, method: search, options: {projet: x}}", contains: `unknown option "projet"`},
+		{name: "options not a mapping", plan: "- {service: projects, method: search, options: [x]}", contains: "must be a mapping"},
+		{name: "options on optionless method", plan: "- {service: analysis-reports, method: queue-status, options: {q: x}}", contains: "takes no options"},
+		{name: "streaming method", plan: "- {service: push, method: sonarlint-events}", contains: "streams its response"},
+		{name: "not a list", plan: "service: projects", contains: "expected a list"},
+		{name: "missing column", plan: `- {service: projects, method: search, options: {q: "{{.nope}}"}}`, matrix: "key\nalpha\n", contains: "nope"},
+		{name: "empty matrix", plan: "- {service: projects, method: search}", matrix: "key\n", contains: "at least one data row"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []string{writeBatchFile(t, "plan.yaml", tc.plan)}
+			if tc.matrix != "" {
+				args = append(args, "--matrix", writeBatchFile(t, "matrix.csv", tc.matrix))
+			}
+
+			_, err := runBatchCLI(t, func(_ http.ResponseWriter, _ *http.Request) {
+				t.Error("no request expected")
+			}, args...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.contains)
+		})
+	}
+}
+
+// TestParseBatchPlan tests the accepted plan layouts.
+func TestParseBatchPlan(t *testing.T) {
+	wrapped, err := parseBatchPlan([]byte("entries:\n  - {service: projects, method: search}\n"), "plan.yml")
+	require.NoError(t, err)
+	require.Len(t, wrapped, 1)
+	assert.Equal(t, "projects", wrapped[0].Service)
+
+	// NDJSON read from stdin is detected from its content.
+	ndjson, err := parseBatchPlan([]byte("{\"service\": \"a\", \"method\": \"b\"}\n{\"service\": \"c\", \"method\": \"d\"}\n"), stdinPath)
+	require.NoError(t, err)
+	require.Len(t, ndjson, 2)
+	assert.Equal(t, "d", ndjson[1].Method)
+
+	_, err = parseBatchPlan([]byte(""), "plan.yaml")
+	require.Error(t, err)
+}
+
+// TestRenderBatchEntry tests template rendering of names and option values.
+func TestRenderBatchEntry(t *testing.T) {
+	entries, err := parseBatchPlan([]byte(`
+- name: "search {{.q}}"
+  service: projects
+  method: search
+  options: {q: "{{.q}}", ps: "{{.size}}", projects: ["{{.q}}", other]}
+`), "plan.yaml")
+	require.NoError(t, err)
+
+	rendered, err := renderBatchEntry(entries[0], map[string]string{"q": "alpha", "size": "50"})
+	require.NoError(t, err)
+	assert.Equal(t, "search alpha", rendered.Name)
+
+	job, err := newBatchJob(newTestClient(t, nil), rendered, 1, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "alpha", job.optValue.Elem().FieldByName("Query").String())
+	assert.Equal(t, []string{"alpha", "other"}, job.optValue.Elem().FieldByName("Projects").Interface())
+
+	// The original entry is not modified by rendering.
+	assert.Contains(t, entries[0].Name, "{{.q}}")
+}
@@ -0,0 +1,386 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+const (
+	// completionCacheTTL is how long fetched completion values are reused.
+	completionCacheTTL = 2 * time.Minute
+	// completionTimeout bounds the server round-trips made while completing, so a slow
+	// or unreachable server never hangs the shell.
+	completionTimeout = 5 * time.Second
+	// completionPageSize is the number of server-side suggestions fetched per request.
+	completionPageSize = 100
+	// completionCacheDirName is the sub-directory of the user cache dir holding completion values.
+	completionCacheDirName = "sonar-cli/completion"
+	// completionCacheFileMode restricts cached values (project keys, logins) to the current user.
+	completionCacheFileMode = 0o600
+	// completionCacheDirMode restricts the cache directory to the current user.
+	completionCacheDirMode = 0o700
+	// completionValueSeparator separates multiple values in slice flags.
+	completionValueSeparator = ","
+)
+
+// completionSource fetches completion candidates of one kind from the server.
+type completionSource struct {
+	// scope returns the part of the cache key that varies per request (e.g. the
+	// server-side search text or the selected project). Sources that list a small,
+	// fixed collection return "" and are filtered locally.
+	scope func(cmd *cobra.Command, word string) string
+	// fetch returns "value\tdescription" candidates.
+	fetch func(ctx context.Context, client *sonar.Client, scope string) ([]string, error)
+	// serverFiltered reports that fetch already filters by the typed word (which may
+	// match names as well as keys), so candidates are not filtered again locally.
+	serverFiltered bool
+}
+
+// completionFlagKinds maps flag names to the kind of server-side value they hold.
+//
+//nolint:gochecknoglobals // static completion registry
+var completionFlagKinds = map[string]string{
+	"project":         "projects",
+	"projects":        "projects",
+	"project-key":     "projects",
+	"component":       "projects",
+	"gate-name":       "gates",
+	"quality-profile": "profiles",
+	"login":           "users",
+	"logins":          "users",
+	"branch":          "branches",
+	"language":        "languages",
+	"languages":       "languages",
+	"rule":            "rules",
+	"rule-key":        "rules",
+	"rules":           "rules",
+}
+
+// completionSources maps each completion kind to its source.
+//
+//nolint:gochecknoglobals // static completion registry
+var completionSources = map[string]completionSource{
+	"projects":  {scope: wordScope, fetch: fetchProjectCompletions, serverFiltered: true},
+	"gates":     {scope: noScope, fetch: fetchGateCompletions, serverFiltered: false},
+	"profiles":  {scope: noScope, fetch: fetchProfileCompletions, serverFiltered: false},
+	"users":     {scope: wordScope, fetch: fetchUserCompletions, serverFiltered: true},
+	"branches":  {scope: projectScope, fetch: fetchBranchCompletions, serverFiltered: false},
+	"languages": {scope: noScope, fetch: fetchLanguageCompletions, serverFiltered: false},
+	"rules":     {scope: wordScope, fetch: fetchRuleCompletions, serverFiltered: true},
+}
+
+// registerDynamicCompletions attaches server-backed completion functions to every
+// flag of cmd whose name identifies a server-side value (project keys, gate names...).
+func registerDynamicCompletions(cmd *cobra.Command) {
+	for name, kind := range completionFlagKinds {
+		if cmd.Flags().Lookup(name) == nil {
+			continue
+		}
+
+		_ = cmd.RegisterFlagCompletionFunc(name, completeServerValues(kind))
+	}
+}
+
+// completeServerValues returns a cobra completion function for a kind of server value.
+// Slice flags are completed one comma-separated element at a time.
+func completeServerValues(kind string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		prefix, word := splitCompletionWord(toComplete)
+		source := completionSources[kind]
+
+		candidates, ok := loadCompletions(cmd, kind, source, word)
+		if !ok {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		matches := make([]cobra.Completion, 0, len(candidates))
+
+		for _, candidate := range candidates {
+			value, _, _ := strings.Cut(candidate, "\t")
+			if source.serverFiltered || strings.HasPrefix(strings.ToLower(value), strings.ToLower(word)) {
+				matches = append(matches, prefix+candidate)
+			}
+		}
+
+		directive := cobra.ShellCompDirectiveNoFileComp
+		if prefix != "" {
+			// Let the user append further comma-separated values.
+			directive |= cobra.ShellCompDirectiveNoSpace
+		}
+
+		return matches, directive
+	}
+}
+
+// splitCompletionWord splits "a,b,c" into the already-typed prefix "a,b," and the word "c".
+func splitCompletionWord(toComplete string) (string, string) {
+	idx := strings.LastIndex(toComplete, completionValueSeparator)
+	if idx < 0 {
+		return "", toComplete
+	}
+
+	return toComplete[:idx+1], toComplete[idx+1:]
+}
+
+// loadCompletions returns cached candidates when fresh, otherwise fetches them from the
+// server and refreshes the cache. Any failure yields no candidates rather than an error,
+// since completion must never break the shell.
+func loadCompletions(cmd *cobra.Command, kind string, source completionSource, word string) ([]string, bool) {
+	flags := completionConnectionFlags(cmd)
+	if flags.url == "" {
+		return nil, false
+	}
+
+	scope := source.scope(cmd, word)
+	cachePath := completionCachePath(flags, kind, scope)
+
+	if cached, ok := readCompletionCache(cachePath); ok {
+		return cached, true
+	}
+
+	client, err := newClient(flags)
+	if err != nil {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	candidates, err := source.fetch(ctx, client, scope)
+	if err != nil {
+		return nil, false
+	}
+
+	writeCompletionCache(cachePath, candidates)
+
+	return candidates, true
+}
+
+// completionConnectionFlags reads the connection flags of a command being completed.
+// Only connection settings are kept: --dry-run and --explain never apply to completion.
+func completionConnectionFlags(cmd *cobra.Command) *globalFlags {
+	flags := &globalFlags{} //nolint:exhaustruct // only connection fields are needed
+	flags.url, _ = cmd.Flags().GetString("url")
+	flags.token, _ = cmd.Flags().GetString("token")
+	flags.username, _ = cmd.Flags().GetString("username")
+	flags.password, _ = cmd.Flags().GetString("password")
+	flags.timeout = completionTimeout
+
+	return flags
+}
+
+// completionCachePath returns the cache file for a server identity, kind and scope.
+// The credentials are part of the (hashed) key because different users may see
+// different projects.
+func completionCachePath(flags *globalFlags, kind, scope string) string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+
+	key := strings.Join([]string{flags.url, flags.token, flags.username, kind, scope}, "\x00")
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(cacheDir, completionCacheDirName, hex.EncodeToString(sum[:])+".json")
+}
+
+// readCompletionCache returns the cached candidates if the cache file is fresh.
+func readCompletionCache(path string) ([]string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > completionCacheTTL {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is derived from a hash under the user cache dir
+	if err != nil {
+		return nil, false
+	}
+
+	var candidates []string
+
+	err = json.Unmarshal(data, &candidates)
+	if err != nil {
+		return nil, false
+	}
+
+	return candidates, true
+}
+
+// writeCompletionCache stores candidates; failures are ignored since the cache is an optimization.
+func writeCompletionCache(path string, candidates []string) {
+	data, err := json.Marshal(candidates)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), completionCacheDirMode)
+	if err != nil {
+		return
+	}
+
+	_ = os.WriteFile(path, data, completionCacheFileMode)
+}
+
+// noScope is the scope of sources that list a fixed collection.
+func noScope(*cobra.Command, string) string {
+	return ""
+}
+
+// wordScope scopes a source by the word being completed (server-side search).
+func wordScope(_ *cobra.Command, word string) string {
+	return word
+}
+
+// projectScope scopes a source by the --project flag of the command being completed.
+func projectScope(cmd *cobra.Command, _ string) string {
+	project, _ := cmd.Flags().GetString("project")
+
+	return project
+}
+
+// fetchProjectCompletions suggests project keys matching the typed text.
+func fetchProjectCompletions(ctx context.Context, client *sonar.Client, word string) ([]string, error) {
+	result, resp, err := client.Components.Suggestions(ctx, &sonar.ComponentsSuggestionsOptions{Search: word}) //nolint:exhaustruct // only the search text is needed
+	CloseBody(resp)
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // completion errors are swallowed by the caller
+	}
+
+	var candidates []string
+
+	for _, group := range result.Results {
+		for _, item := range group.Items {
+			candidates = append(candidates, item.Key+"\t"+item.Name)
+		}
+	}
+
+	return candidates, nil
+}
+
+// fetchGateCompletions lists quality gate names.
+func fetchGateCompletions(ctx context.Context, client *sonar.Client, _ string) ([]string, error) {
+	result, resp, err := client.Qualitygates.List(ctx)
+	CloseBody(resp)
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // completion errors are swallowed by the caller
+	}
+
+	candidates := make([]string, 0, len(result.Qualitygates))
+	for _, gate := range result.Qualitygates {
+		candidates = append(candidates, gate.Name)
+	}
+
+	return candidates, nil
+}
+
+// fetchProfileCompletions lists quality profile names with their language.
+func fetchProfileCompletions(ctx context.Context, client *sonar.Client, _ string) ([]string, error) {
+	result, resp, err := client.Qualityprofiles.Search(ctx, &sonar.QualityprofilesSearchOptions{}) //nolint:exhaustruct // list every profile
+	CloseBody(resp)
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // completion errors are swallowed by the caller
+	}
+
+	candidates := make([]string, 0, len(result.Profiles))
+	for _, profile := range result.Profiles {
+		candidates = append(candidates, profile.Name+"\t"+profile.LanguageName)
+	}
+
+	return candidates, nil
+}
+
+// fetchUserCompletions suggests user logins matching the typed text.
+func fetchUserCompletions(ctx context.Context, client *sonar.Client, word string) ([]string, error) {
+	opt := &sonar.UsersSearchOptions{Query: word} //nolint:exhaustruct // only the search text is needed
+	opt.PageSize = completionPageSize
+
+	result, resp, err := client.Users.Search(ctx, opt)
+	CloseBody(resp)
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // completion errors are swallowed by the caller
+	}
+
+	candidates := make([]string, 0, len(result.Users))
+	for _, user := range result.Users {
+		candidates = append(candidates, user.Login+"\t"+user.Name)
+	}
+
+	return candidates, nil
+}
+
+// fetchBranchCompletions lists the branches of the project selected with --project.
+func fetchBranchCompletions(ctx context.Context, client *sonar.Client, project string) ([]string, error) {
+	if project == "" {
+		return nil, nil
+	}
+
+	result, resp, err := client.ProjectBranches.List(ctx, &sonar.ProjectBranchesListOptions{Project: project})
+	CloseBody(resp)
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // completion errors are swallowed by the caller
+	}
+
+	candidates := make([]string, 0, len(result.Branches))
+	for _, branch := range result.Branches {
+		candidates = append(candidates, branch.Name)
+	}
+
+	return candidates, nil
+}
+
+// fetchLanguageCompletions lists language keys.
+func fetchLanguageCompletions(ctx context.Context, client *sonar.Client, _ string) ([]string, error) {
+	result, resp, err := client.Languages.List(ctx, &sonar.LanguagesListOptions{}) //nolint:exhaustruct // list every language
+	CloseBody(resp)
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // completion errors are swallowed by the caller
+	}
+
+	candidates := make([]string, 0, len(result.Languages))
+	for _, language := range result.Languages {
+		candidates = append(candidates, language.Key+"\t"+language.Name)
+	}
+
+	return candidates, nil
+}
+
+// fetchRuleCompletions suggests rule keys matching the typed text. The rules search
+// requires at least two characters, so shorter input yields no candidates.
+func fetchRuleCompletions(ctx context.Context, client *sonar.Client, word string) ([]string, error) {
+	if len(word) < 2 { //nolint:mnd // minimum query length of api/rules/search
+		return nil, nil
+	}
+
+	opt := &sonar.RulesSearchOptions{Query: word} //nolint:exhaustruct // only the search text is needed
+	opt.PageSize = completionPageSize
+
+	result, resp, err := client.Rules.Search(ctx, opt)
+	CloseBody(resp)
+
+	if err != nil {
+		return nil, err //nolint:wrapcheck // completion errors are swallowed by the caller
+	}
+
+	candidates := make([]string, 0, len(result.Rules))
+	for _, rule := range result.Rules {
+		candidates = append(candidates, rule.Key+"\t"+rule.Name)
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCompletionCommand builds a command with connection flags pointing at a test server.
+func newCompletionCommand(t *testing.T, handler http.HandlerFunc) (*cobra.Command, *int) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String("url", server.URL+"/api/", "")
+	cmd.Flags().String("token", "tok", "")
+	cmd.Flags().String("username", "", "")
+	cmd.Flags().String("password", "", "")
+	cmd.Flags().String("project", "", "")
+
+	return cmd, &calls
+}
+
+// TestCompleteServerValues_Gates tests local prefix filtering and caching.
+func TestCompleteServerValues_Gates(t *testing.T) {
+	cmd, calls := newCompletionCommand(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/qualitygates/list", r.URL.Path)
+
+		_, _ = io.WriteString(w, `{"qualitygates":[{"name":"Sonar way"},{"name":"Strict"},{"name":"Legacy"}]}`)
+	})
+
+	complete := completeServerValues("gates")
+
+	values, directive := complete(cmd, nil, "s")
+	assert.Equal(t, []cobra.Completion{"Sonar way", "Strict"}, values)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	// A second completion within the TTL is served from the cache.
+	values, _ = complete(cmd, nil, "L")
+	assert.Equal(t, []cobra.Completion{"Legacy"}, values)
+	assert.Equal(t, 1, *calls)
+}
+
+// TestCompleteServerValues_ProjectsSlice tests server-side search and comma-separated values.
+func TestCompleteServerValues_ProjectsSlice(t *testing.T) {
+	cmd, _ := newCompletionCommand(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/components/suggestions", r.URL.Path)
+		assert.Equal(t, "my", r.URL.Query().Get("s"))
+
+		_, _ = io.WriteString(w, `{"results":[{"q":"TRK","items":[{"key":"my-app","name":"My App"}]}]}`)
+	})
+
+	values, directive := completeServerValues("projects")(cmd, nil, "first,my")
+	assert.Equal(t, []cobra.Completion{"first,my-app\tMy App"}, values)
+	assert.NotZero(t, directive&cobra.ShellCompDirectiveNoSpace)
+}
+
+// TestCompleteServerValues_Branches tests completion scoped by the --project flag.
+func TestCompleteServerValues_Branches(t *testing.T) {
+	cmd, _ := newCompletionCommand(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "my-app", r.URL.Query().Get("project"))
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"branches": []map[string]string{{"name": "main"}, {"name": "feature/x"}}})
+	})
+	require.NoError(t, cmd.Flags().Set("project", "my-app"))
+
+	values, _ := completeServerValues("branches")(cmd, nil, "fea")
+	assert.Equal(t, []cobra.Completion{"feature/x"}, values)
+}
+
+// TestCompleteServerValues_NoServer tests that completion degrades silently.
+func TestCompleteServerValues_NoServer(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+
+	values, directive := completeServerValues("gates")(cmd, nil, "")
+	assert.Empty(t, values)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	failing, _ := newCompletionCommand(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	values, _ = completeServerValues("users")(failing, nil, "bo")
+	assert.Empty(t, values)
+}
+
+// TestRegisterDynamicCompletions tests that generated commands get completion functions.
+func TestRegisterDynamicCompletions(t *testing.T) {
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test"}
+
+	RegisterAllCommands(rootCmd, &format)
+
+	deleteCmd, _, err := rootCmd.Find([]string{"projects", "delete"})
+	require.NoError(t, err)
+
+	_, found := deleteCmd.GetFlagCompletionFunc("project")
+	assert.True(t, found)
+}
+
+// TestSplitCompletionWord tests splitting of comma-separated flag values.
+func TestSplitCompletionWord(t *testing.T) {
+	prefix, word := splitCompletionWord("a,b,c")
+	assert.Equal(t, "a,b,", prefix)
+	assert.Equal(t, "c", word)
+
+	prefix, word = splitCompletionWord("abc")
+	assert.Empty(t, prefix)
+	assert.Equal(t, "abc", word)
+}
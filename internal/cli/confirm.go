@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// yesFlag is the flag skipping the confirmation of destructive commands.
+const yesFlag = "yes"
+
+// destructiveVerbs lists the method name prefixes of operations that delete, revoke or
+// overwrite server state. A method is destructive when its name starts with one of
+// them (as a whole word) and it sends a non-GET request.
+//
+//nolint:gochecknoglobals // constant configuration set
+var destructiveVerbs = []string{
+	"Anonymize",
+	"BulkApplyTemplate",
+	"BulkDelete",
+	"CancelAll",
+	"Deactivate",
+	"Delete",
+	"MigrateDb",
+	"Remove",
+	"Reset",
+	"Restart",
+	"Restore",
+	"Revoke",
+	"Uninstall",
+	"Unset",
+}
+
+// destructiveMethods lists method names that overwrite or remove server state without
+// starting with a destructive verb, matched exactly so that, for instance, ApplyTemplate
+// does not catch other Apply methods. Ce.Pause is not listed: it only suspends the
+// processing of new analyses, which Ce.Resume restores, and removes nothing.
+//
+//nolint:gochecknoglobals // constant configuration set
+var destructiveMethods = []string{
+	"ApplyTemplate",
+	"Clear",
+	"Disable",
+}
+
+var (
+	// errConfirmationRequired is returned when a destructive command cannot be confirmed
+	// interactively and --yes was not given.
+	errConfirmationRequired = errors.New("confirmation required")
+	// errAborted is returned when the user declines a confirmation prompt.
+	errAborted = errors.New("aborted")
+	// errProbe aborts the request built by a probe client before it is sent.
+	errProbe = errors.New("probe: request not sent")
+)
+
+// stdinIsTerminal reports whether reader is an interactive terminal.
+//
+//nolint:gochecknoglobals // replaced in tests, which have no terminal
+var stdinIsTerminal = func(reader io.Reader) bool {
+	file, ok := reader.(*os.File)
+
+	return ok && term.IsTerminal(int(file.Fd())) //nolint:gosec // file descriptors fit in an int
+}
+
+// plannedRequest is a request a command would send, built without sending it.
+type plannedRequest struct {
+	// operation names the method, e.g. "Projects.Delete".
+	operation string
+	req       *http.Request
+	body      []byte
+}
+
+// isDestructiveName reports whether a method name starts with a destructive verb, or is
+// one of the destructive methods.
+func isDestructiveName(methodName string) bool {
+	if slices.Contains(destructiveMethods, methodName) {
+		return true
+	}
+
+	for _, verb := range destructiveVerbs {
+		rest, ok := strings.CutPrefix(methodName, verb)
+		if ok && (rest == "" || unicode.IsUpper([]rune(rest)[0])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addYesFlag registers --yes on a destructive command.
+func addYesFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(yesFlag, false, "Run this destructive command without asking for confirmation")
+}
+
+// probeRequest builds the request a service method call would send, by calling the
+// method on a client whose transport captures the request instead of sending it.
+// It returns nil when the call fails before a request is built (e.g. on validation).
+func probeRequest(client *sonar.Client, service reflect.Value, methodName string, optValue reflect.Value, pattern MethodReturnPattern) *plannedRequest {
+	planned := &plannedRequest{operation: "", req: nil, body: nil}
+
+	capture := func(_ http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := readRequestBody(req)
+			if err != nil {
+				return nil, err
+			}
+
+			planned.req = req
+			planned.body = body
+
+			return nil, errProbe
+		})
+	}
+
+	probeClient, err := sonar.NewClient(nil, sonar.WithBaseURL(client.BaseURL().String()), sonar.WithMiddleware(capture))
+	if err != nil {
+		return nil
+	}
+
+	probeService, serviceName := sameTypedService(probeClient, service.Type())
+	if !probeService.IsValid() {
+		return nil
+	}
+
+	planned.operation = serviceName + "." + methodName
+
+	_, resp, _ := InvokeMethod(context.Background(), probeService, methodName, optValue, pattern, optValue.IsValid())
+	CloseBody(resp)
+
+	if planned.req == nil {
+		return nil
+	}
+
+	return planned
+}
+
+// sameTypedService returns the service field of client with the given type, and its name.
+func sameTypedService(client *sonar.Client, serviceType reflect.Type) (reflect.Value, string) {
+	clientVal := reflect.ValueOf(client).Elem()
+
+	for field := range clientVal.Type().Fields() {
+		if field.IsExported() && field.Type == serviceType {
+			return clientVal.FieldByIndex(field.Index), field.Name
+		}
+	}
+
+	return reflect.Value{}, ""
+}
+
+// plannedWrite returns the request of a destructive method call, or nil when the method
+// name is not destructive or the call would not send a write request.
+func plannedWrite(client *sonar.Client, service reflect.Value, methodName string, optValue reflect.Value, pattern MethodReturnPattern) *plannedRequest {
+	if !isDestructiveName(methodName) {
+		return nil
+	}
+
+	planned := probeRequest(client, service, methodName, optValue, pattern)
+	if planned == nil || planned.req.Method == http.MethodGet {
+		return nil
+	}
+
+	return planned
+}
+
+// confirmMethodCall asks for confirmation before a destructive method call.
+func confirmMethodCall(cmd *cobra.Command, client *sonar.Client, service reflect.Value, methodName string, optValue reflect.Value, pattern MethodReturnPattern) error {
+	planned := plannedWrite(client, service, methodName, optValue, pattern)
+	if planned == nil {
+		return nil
+	}
+
+	return confirmDestructive(cmd, []*plannedRequest{planned})
+}
+
+// confirmFanOut asks once for confirmation before a destructive method is called for
+// each target of the positional flag. It leaves the flag set to an arbitrary target.
+func confirmFanOut(
+	cmd *cobra.Command,
+	client *sonar.Client,
+	service reflect.Value,
+	methodName string,
+	optValue reflect.Value,
+	pattern MethodReturnPattern,
+	flagName string,
+	targets []string,
+) error {
+	if !isDestructiveName(methodName) {
+		return nil
+	}
+
+	var requests []*plannedRequest
+
+	for _, target := range targets {
+		_ = cmd.Flags().Set(flagName, target)
+
+		if planned := plannedWrite(client, service, methodName, optValue, pattern); planned != nil {
+			requests = append(requests, planned)
+		}
+	}
+
+	return confirmDestructive(cmd, requests)
+}
+
+// confirmDestructive asks the user to confirm destructive requests, showing each
+// request with its target parameters. It passes with --yes or --dry-run (nothing is
+// sent), and fails without a terminal to ask on.
+func confirmDestructive(cmd *cobra.Command, requests []*plannedRequest) error {
+	if len(requests) == 0 || flagIsTrue(cmd, yesFlag) || flagIsTrue(cmd, "dry-run") {
+		return nil
+	}
+
+	operations := make([]string, 0, len(requests))
+	for _, planned := range requests {
+		operations = append(operations, planned.operation)
+	}
+
+	if !stdinIsTerminal(cmd.InOrStdin()) {
+		err := fmt.Errorf("%w: %s is destructive; pass --yes to run it without a prompt",
+			errConfirmationRequired, strings.Join(uniqueStrings(operations), ", "))
+		Logger().Error("refusing to run a destructive command without confirmation", zap.Error(err))
+
+		return err
+	}
+
+	errOut := cmd.ErrOrStderr()
+
+	_, _ = fmt.Fprintf(errOut, "The following %d destructive request(s) will be sent:\n", len(requests))
+
+	for _, planned := range requests {
+		_, _ = fmt.Fprintf(errOut, "\n%s: ", planned.operation)
+		writeRequestDescription(errOut, planned.req, planned.body)
+	}
+
+	answer, err := promptLine(cmd, "\nContinue? [y/N] ")
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return nil
+	default:
+		_, _ = fmt.Fprintln(errOut, "Aborted.")
+
+		return errAborted
+	}
+}
+
+// promptRequiredFlags asks for the values of required flags that were not set, when
+// stdin is a terminal. An empty answer leaves the flag unset, so Cobra reports it.
+func promptRequiredFlags(cmd *cobra.Command) error {
+	if !stdinIsTerminal(cmd.InOrStdin()) {
+		return nil
+	}
+
+	var missing []*pflag.Flag
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		required := flag.Annotations[cobra.BashCompOneRequiredFlag]
+		if len(required) > 0 && required[0] == "true" && !flag.Changed {
+			missing = append(missing, flag)
+		}
+	})
+
+	for _, flag := range missing {
+		usage := strings.Replace(flag.Usage, " (required)", "", 1)
+
+		answer, err := promptLine(cmd, fmt.Sprintf("--%s (%s): ", flag.Name, usage))
+		if err != nil {
+			return err
+		}
+
+		if answer == "" {
+			continue
+		}
+
+		err = cmd.Flags().Set(flag.Name, answer)
+		if err != nil {
+			return fmt.Errorf("invalid value for --%s: %w", flag.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// promptLine prints a prompt to stderr and reads one line from stdin. Input is read a
+// byte at a time so that nothing beyond the line is consumed.
+func promptLine(cmd *cobra.Command, prompt string) (string, error) {
+	_, _ = fmt.Fprint(cmd.ErrOrStderr(), prompt)
+
+	var (
+		line []byte
+		char = make([]byte, 1)
+	)
+
+	for {
+		n, err := cmd.InOrStdin().Read(char)
+		if n > 0 {
+			if char[0] == '\n' {
+				break
+			}
+
+			line = append(line, char[0])
+		}
+
+		if errors.Is(err, io.EOF) {
+			if len(line) == 0 {
+				return "", errAborted
+			}
+
+			break
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("failed to read answer: %w", err)
+		}
+	}
+
+	return strings.TrimSpace(string(line)), nil
+}
+
+// flagIsTrue reports whether a boolean flag is defined on cmd and set to true.
+func flagIsTrue(cmd *cobra.Command, name string) bool {
+	flag := cmd.Flag(name)
+
+	return flag != nil && flag.Value.String() == "true"
+}
+
+// uniqueStrings returns values without duplicates, in first-seen order.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
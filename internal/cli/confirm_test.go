@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTerminal makes stdinIsTerminal report terminal for the duration of the test.
+func fakeTerminal(t *testing.T, terminal bool) {
+	t.Helper()
+
+	original := stdinIsTerminal
+	stdinIsTerminal = func(io.Reader) bool { return terminal }
+
+	t.Cleanup(func() { stdinIsTerminal = original })
+}
+
+// runConfirmCLI runs a generated command with stdin and returns stderr.
+func runConfirmCLI(t *testing.T, handler http.HandlerFunc, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	client := newTestClient(t, handler)
+
+	var errOut bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().Bool("dry-run", false, "")
+	RegisterAllCommands(rootCmd, &format)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(&errOut)
+	rootCmd.SetArgs(args)
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+
+	return errOut.String(), err
+}
+
+// TestIsDestructiveName tests classifying method names.
+func TestIsDestructiveName(t *testing.T) {
+	for _, name := range []string{"Delete", "BulkDelete", "RemoveGroup", "DeactivateRule", "Restore", "Unset",
+		"ApplyTemplate", "BulkApplyTemplate", "Clear", "Disable"} {
+		assert.True(t, isDestructiveName(name), name)
+	}
+
+	for _, name := range []string{"Search", "Create", "Deleted", "Resettle", "Show", "AddGroup", "ClearCache", "Pause"} {
+		assert.False(t, isDestructiveName(name), name)
+	}
+}
+
+// TestConfirm_RequiresYes tests that destructive commands refuse to run without a terminal.
+func TestConfirm_RequiresYes(t *testing.T) {
+	fakeTerminal(t, false)
+
+	sent := 0
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		sent++
+
+		_, _ = w.Write([]byte(`{}`))
+	}
+
+	_, err := runConfirmCLI(t, handler, "", "projects", "delete", "--project", "my-app")
+	require.ErrorIs(t, err, errConfirmationRequired)
+	assert.Contains(t, err.Error(), "Projects.Delete is destructive; pass --yes")
+	assert.Zero(t, sent)
+
+	_, err = runConfirmCLI(t, handler, "", "projects", "delete", "--project", "my-app", "--yes")
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	// Read-only commands never ask.
+	_, err = runConfirmCLI(t, handler, "", "projects", "search")
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+}
+
+// TestConfirm_Prompt tests the interactive confirmation showing the target resources.
+func TestConfirm_Prompt(t *testing.T) {
+	fakeTerminal(t, true)
+
+	sent := 0
+	handler := func(_ http.ResponseWriter, r *http.Request) {
+		sent++
+
+		assert.Equal(t, "/api/projects/bulk_delete", r.URL.Path)
+	}
+
+	errOut, err := runConfirmCLI(t, handler, "n\n", "projects", "bulk-delete", "--projects", "a,b")
+	require.ErrorIs(t, err, errAborted)
+	assert.Zero(t, sent)
+	assert.Contains(t, errOut, "Projects.BulkDelete: POST http://")
+	assert.Contains(t, errOut, "projects=a,b")
+	assert.Contains(t, errOut, "Continue? [y/N]")
+
+	_, err = runConfirmCLI(t, handler, "yes\n", "projects", "bulk-delete", "--projects", "a,b")
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+}
+
+// TestConfirm_OverwritingMethods tests that methods overwriting or removing state without
+// a destructive verb in their name are confirmed too.
+func TestConfirm_OverwritingMethods(t *testing.T) {
+	fakeTerminal(t, false)
+
+	sent := 0
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		sent++
+
+		_, _ = w.Write([]byte(`{}`))
+	}
+
+	for _, args := range [][]string{
+		{"permissions", "apply-template", "--project-key", "my-app", "--template-name", "Default"},
+		{"analysis-cache", "clear"},
+		{"scim-management", "disable"},
+	} {
+		_, err := runConfirmCLI(t, handler, "", args...)
+		require.ErrorIs(t, err, errConfirmationRequired, args)
+		assert.Zero(t, sent, args)
+
+		_, err = runConfirmCLI(t, handler, "", append(args, "--yes")...)
+		require.NoError(t, err, args)
+		assert.Equal(t, 1, sent, args)
+
+		sent = 0
+	}
+}
+
+// TestPromptRequiredFlags tests asking for missing required flags on a terminal.
+func TestPromptRequiredFlags(t *testing.T) {
+	fakeTerminal(t, true)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "my-app", query.Get("application")+query.Get("component"))
+
+		_, _ = w.Write([]byte(`{}`))
+	}
+
+	errOut, err := runConfirmCLI(t, handler, "my-app\n", "components", "show")
+	require.NoError(t, err)
+	assert.Contains(t, errOut, "--component (")
+
+	errOut, err = runConfirmCLI(t, handler, "my-app\ny\n", "applications", "delete")
+	require.NoError(t, err)
+	assert.Contains(t, errOut, "--application (")
+	assert.Contains(t, errOut, "Continue? [y/N]")
+
+	// An empty answer leaves the flag missing.
+	_, err = runConfirmCLI(t, handler, "\n", "applications", "delete")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `required flag(s) "application" not set`)
+}
+
+// TestBatchRun_Confirm tests that destructive batch entries are confirmed once, up front.
+func TestBatchRun_Confirm(t *testing.T) {
+	fakeTerminal(t, false)
+
+	plan := writeBatchFile(t, "plan.yaml", `
+- service: projects
+  method: create
+  options: {project: a, name: A}
+- service: projects
+  method: delete
+  options: {project: b}
+`)
+
+	sent := 0
+
+	_, err := runBatchCLI(t, func(w http.ResponseWriter, _ *http.Request) {
+		sent++
+
+		_, _ = w.Write([]byte(`{}`))
+	}, plan)
+	require.ErrorIs(t, err, errConfirmationRequired)
+	assert.Zero(t, sent)
+
+	_, err = runBatchCLI(t, func(w http.ResponseWriter, _ *http.Request) {
+		sent++
+
+		_, _ = w.Write([]byte(`{}`))
+	}, plan, "--yes")
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+}
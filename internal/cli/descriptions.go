@@ -370,15 +370,16 @@ var methodDescriptions = map[string]string{ //nolint:gosec // G101 false positiv
 	"Qualityprofiles.Show":            "Shows profile details",
 
 	// Rules
-	"Rules.App":          "Gets Coding Rules page data",
-	"Rules.Create":       "Creates a custom coding rule",
-	"Rules.Delete":       "Deletes a custom coding rule",
-	"Rules.List":         "Lists rules excluding external ones",
-	"Rules.Repositories": "Lists rule repositories",
-	"Rules.Search":       "Searches for coding rules",
-	"Rules.Show":         "Shows details of a rule",
-	"Rules.Tags":         "Lists available rule tags",
-	"Rules.Update":       "Updates a coding rule",
+	"Rules.App":               "Gets Coding Rules page data",
+	"Rules.Create":            "Creates a custom coding rule",
+	"Rules.Delete":            "Deletes a custom coding rule",
+	"Rules.List":              "Lists rules excluding external ones",
+	"Rules.Repositories":      "Lists rule repositories",
+	"Rules.Search":            "Searches for coding rules",
+	"Rules.SearchActivations": "Lists the rule activations of a quality profile",
+	"Rules.Show":              "Shows details of a rule",
+	"Rules.Tags":              "Lists available rule tags",
+	"Rules.Update":            "Updates a coding rule",
 
 	// Server
 	"Server.Version": "Returns the SonarQube server version",
@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar/snapshot"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	// contextPrefix introduces the name of a server context in diff --from and --to.
+	contextPrefix = "context:"
+	// diffFormatText prints the differences as an indented list.
+	diffFormatText = "text"
+	// diffFormatMarkdown prints the differences as Markdown tables.
+	diffFormatMarkdown = "markdown"
+	// diffFormatJSON prints the differences as a JSON document.
+	diffFormatJSON = "json"
+)
+
+var (
+	// errDifferences is returned by diff --detailed-exitcode when the configurations differ.
+	errDifferences = errors.New("the configurations differ")
+	// errUnknownContext is returned for a server context whose URL is not set.
+	errUnknownContext = errors.New("unknown server context")
+)
+
+// diffSymbols prefixes the differences by type, as plan does.
+//
+//nolint:gochecknoglobals // constant lookup table
+var diffSymbols = map[snapshot.DifferenceType]string{
+	snapshot.DifferenceAdded:   "+",
+	snapshot.DifferenceChanged: "~",
+	snapshot.DifferenceRemoved: "-",
+}
+
+// DiffReport is the result of the diff command.
+type DiffReport struct {
+	// From describes the first configuration.
+	From string `json:"from"`
+	// To describes the second configuration.
+	To string `json:"to"`
+	// Differences are the differences from the first configuration to the second.
+	Differences []snapshot.Difference `json:"differences"`
+}
+
+// newDiffCommand creates the diff command.
+func newDiffCommand(flags *globalFlags) *cobra.Command {
+	var (
+		from, to, diffFormat string
+		detailedExitCode     bool
+	)
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the diff command
+		Use:   "diff --from <source> [--to <source>]",
+		Short: "Compare the configuration of two instances, or of an instance and an export",
+		Long: `Compare the configuration of two instances, or of an instance and a directory
+written by "sonar-cli export", and print the differences: quality gate conditions,
+quality profile rule activations and parameters, permission templates, global
+permissions, global settings and webhooks.
+
+A source is either context:<name>, a server whose connection is read from the
+SONAR_CLI_<NAME>_URL, SONAR_CLI_<NAME>_TOKEN, SONAR_CLI_<NAME>_USERNAME and
+SONAR_CLI_<NAME>_PASSWORD environment variables (the name in upper case, dashes
+replaced by underscores), or an export directory. Without --to, the server given by
+--url is compared.
+
+The differences are printed as text, Markdown (--format markdown) or JSON
+(--format json, or --output). With --detailed-exitcode, the command exits with
+status 2 when there are differences, and 0 when there are none.`,
+		Example: `  sonar-cli diff --from context:prod --to context:staging
+  sonar-cli diff --from backup/ --to context:prod --format markdown
+  sonar-cli diff --from context:prod --detailed-exitcode`,
+		Args: cobra.NoArgs,
+		// Each source builds its own client, if it needs one: override the root hook so
+		// that no server URL is required to compare two exports.
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			applyErrorFormat(flags.errorFormat)
+			applyDeadline(cmd, flags.deadline)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			report, err := runDiff(cmd, flags, from, to, diffFormat)
+			if err != nil {
+				return err
+			}
+
+			if detailedExitCode && len(report.Differences) > 0 {
+				return withExitCode(exitPlanChanges, errDifferences)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "First configuration: context:<name> or an export directory")
+	cmd.Flags().StringVar(&to, "to", "", "Second configuration: context:<name> or an export directory (default: the server given by --url)")
+	cmd.Flags().StringVar(&diffFormat, "format", diffFormatText, "Format of the differences: text, markdown or json")
+	cmd.Flags().BoolVar(&detailedExitCode, "detailed-exitcode", false, "Exit with status 2 when there are differences")
+
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{diffFormatText, diffFormatMarkdown, diffFormatJSON}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// runDiff compares the two configurations and prints the differences.
+func runDiff(cmd *cobra.Command, flags *globalFlags, from, to, diffFormat string) (*DiffReport, error) {
+	if diffFormat != diffFormatText && diffFormat != diffFormatMarkdown && diffFormat != diffFormatJSON {
+		err := fmt.Errorf("%w %q for --format: must be one of %s, %s, %s",
+			errInvalidFlagValue, diffFormat, diffFormatText, diffFormatMarkdown, diffFormatJSON)
+		Logger().Error("invalid diff format", zap.Error(err))
+
+		return nil, err
+	}
+
+	fromSnapshot, fromLabel, err := loadDiffSource(cmd, flags, "from", from)
+	if err != nil {
+		return nil, err
+	}
+
+	toSnapshot, toLabel, err := loadDiffSource(cmd, flags, "to", to)
+	if err != nil {
+		return nil, err
+	}
+
+	differences, err := snapshot.Compare(fromSnapshot, toSnapshot)
+	if err != nil {
+		Logger().Error("failed to compare the configurations", zap.Error(err))
+
+		return nil, fmt.Errorf("failed to compare the configurations: %w", err)
+	}
+
+	report := &DiffReport{From: fromLabel, To: toLabel, Differences: differences}
+
+	switch {
+	case cmd.Flags().Changed("output"):
+		err = writeResult(cmd, report, flags.output)
+	case diffFormat == diffFormatJSON:
+		err = writeResult(cmd, report, OutputJSON)
+	case diffFormat == diffFormatMarkdown:
+		recordResult(cmd.Context(), report)
+		writeDiffMarkdown(cmd.OutOrStdout(), report)
+	default:
+		recordResult(cmd.Context(), report)
+		writeDiffText(cmd.OutOrStdout(), report)
+	}
+
+	return report, err
+}
+
+// loadDiffSource reads the configuration a --from or --to value names, and returns it
+// with a label describing it.
+func loadDiffSource(cmd *cobra.Command, flags *globalFlags, flagName, source string) (*snapshot.Snapshot, string, error) {
+	if source != "" && !strings.HasPrefix(source, contextPrefix) {
+		snap, err := snapshot.ReadDir(source)
+		if err != nil {
+			Logger().Error("failed to read the export", zap.String("directory", source), zap.Error(err))
+
+			return nil, "", fmt.Errorf("failed to read the export %s: %w", source, err)
+		}
+
+		return snap, source, nil
+	}
+
+	client, label, err := diffSourceClient(cmd, flags, flagName, source)
+	if err != nil {
+		return nil, "", err
+	}
+
+	snap, err := snapshot.Export(cmd.Context(), client)
+	if err != nil {
+		Logger().Error("failed to export the configuration", zap.String("source", label), zap.Error(err))
+
+		return nil, "", fmt.Errorf("failed to export the configuration of %s: %w", label, err)
+	}
+
+	return snap, label, nil
+}
+
+// diffSourceClient returns the client of a server named by context:<name>, or of the
+// server given by the global flags when source is empty.
+func diffSourceClient(cmd *cobra.Command, flags *globalFlags, flagName, source string) (*sonar.Client, string, error) {
+	if source == "" {
+		label := cmp.Or(flags.url, "the server")
+
+		// The interactive shell provides its client.
+		if client, ok := cmd.Context().Value(clientContextKey).(*sonar.Client); ok && !clientFlagsChanged(cmd) {
+			return client, label, nil
+		}
+
+		client, err := newClient(flags)
+
+		return client, label, err
+	}
+
+	name := strings.TrimPrefix(source, contextPrefix)
+
+	contextFlags, err := serverContextFlags(name, flags)
+	if err != nil {
+		err = fmt.Errorf("%w %q for --%s: %w", errInvalidFlagValue, source, flagName, err)
+		Logger().Error("unknown server context", zap.Error(err))
+
+		return nil, "", err
+	}
+
+	client, err := newClient(contextFlags)
+
+	return client, source, err
+}
+
+// serverContextFlags returns the global flags with the connection of the named server
+// context, read from the SONAR_CLI_<NAME>_* environment variables.
+func serverContextFlags(name string, flags *globalFlags) (*globalFlags, error) {
+	prefix := "SONAR_CLI_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+	contextFlags := *flags
+	contextFlags.url = os.Getenv(prefix + "URL")
+	contextFlags.token = os.Getenv(prefix + "TOKEN")
+	contextFlags.username = os.Getenv(prefix + "USERNAME")
+	contextFlags.password = os.Getenv(prefix + "PASSWORD")
+
+	if name == "" || contextFlags.url == "" {
+		return nil, fmt.Errorf("%w: %sURL is not set", errUnknownContext, prefix)
+	}
+
+	return &contextFlags, nil
+}
+
+// writeDiffText prints the differences grouped by section and object.
+func writeDiffText(writer io.Writer, report *DiffReport) {
+	if len(report.Differences) == 0 {
+		_, _ = fmt.Fprintf(writer, "No differences between %s and %s.\n", report.From, report.To)
+
+		return
+	}
+
+	_, _ = fmt.Fprintf(writer, "Differences from %s to %s:\n", report.From, report.To)
+
+	section, object := "", ""
+
+	for _, difference := range report.Differences {
+		if difference.Section != section {
+			section, object = difference.Section, ""
+			_, _ = fmt.Fprintf(writer, "\n%s\n", section)
+		}
+
+		symbol := diffSymbols[difference.Type]
+
+		if difference.Field == "" {
+			object = ""
+			_, _ = fmt.Fprintf(writer, "  %s %s%s\n", symbol, difference.Object, diffValues(difference, ": "))
+
+			continue
+		}
+
+		if difference.Object != object {
+			object = difference.Object
+			_, _ = fmt.Fprintf(writer, "  ~ %s\n", object)
+		}
+
+		_, _ = fmt.Fprintf(writer, "      %s %s%s\n", symbol, difference.Field, diffValues(difference, ": "))
+	}
+
+	_, _ = fmt.Fprintf(writer, "\n%s\n", diffCounts(report.Differences))
+}
+
+// diffValues returns the values of a difference after sep, or nothing if it has none.
+func diffValues(difference snapshot.Difference, sep string) string {
+	switch {
+	case difference.From == "" && difference.To == "":
+		return ""
+	case difference.Type == snapshot.DifferenceAdded:
+		return sep + difference.To
+	case difference.Type == snapshot.DifferenceRemoved:
+		return sep + difference.From
+	default:
+		return sep + difference.From + " -> " + difference.To
+	}
+}
+
+// diffCounts returns the number of differences of each type.
+func diffCounts(differences []snapshot.Difference) string {
+	counts := map[snapshot.DifferenceType]int{}
+
+	for _, difference := range differences {
+		counts[difference.Type]++
+	}
+
+	return fmt.Sprintf("%d difference(s): %d added, %d changed, %d removed.", len(differences),
+		counts[snapshot.DifferenceAdded], counts[snapshot.DifferenceChanged], counts[snapshot.DifferenceRemoved])
+}
+
+// writeDiffMarkdown prints the differences as one Markdown table per section.
+func writeDiffMarkdown(writer io.Writer, report *DiffReport) {
+	_, _ = fmt.Fprintf(writer, "## Differences from `%s` to `%s`\n", report.From, report.To)
+
+	if len(report.Differences) == 0 {
+		_, _ = fmt.Fprintln(writer, "\nNo differences.")
+
+		return
+	}
+
+	section := ""
+
+	for _, difference := range report.Differences {
+		if difference.Section != section {
+			section = difference.Section
+			_, _ = fmt.Fprintf(writer, "\n### %s\n\n| | Object | Field | From | To |\n|---|---|---|---|---|\n", section)
+		}
+
+		_, _ = fmt.Fprintf(writer, "| %s | %s | %s | %s | %s |\n", diffSymbols[difference.Type],
+			markdownCell(difference.Object), markdownCell(difference.Field),
+			markdownCell(difference.From), markdownCell(difference.To))
+	}
+
+	_, _ = fmt.Fprintf(writer, "\n%s\n", diffCounts(report.Differences))
+}
+
+// markdownCell escapes a value for a Markdown table cell.
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar/snapshot"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDiffSnapshots writes two exports differing in a gate condition and a setting,
+// and returns their directories.
+func writeDiffSnapshots(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	prod, staging := filepath.Join(dir, "prod"), filepath.Join(dir, "staging")
+
+	snap := &snapshot.Snapshot{ //nolint:exhaustruct // other sections are empty
+		Instance: snapshot.Instance{Version: "2025.1"}, //nolint:exhaustruct // version only
+		QualityGates: []snapshot.QualityGate{{Name: "Strict", Conditions: []snapshot.Condition{ //nolint:exhaustruct // custom gate
+			{Metric: "new_coverage", Op: "LT", Error: "80"},
+		}}},
+		Settings: []snapshot.Setting{{Key: "sonar.exclusions", Value: "**/gen/**"}}, //nolint:exhaustruct // single value
+	}
+	require.NoError(t, snap.WriteDir(prod, snapshot.FormatYAML))
+
+	snap.QualityGates[0].Conditions[0].Error = "90"
+	snap.Settings = nil
+	require.NoError(t, snap.WriteDir(staging, snapshot.FormatYAML))
+
+	return prod, staging
+}
+
+// runDiffCLI runs the diff command and returns its output.
+func runDiffCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+
+	flags := &globalFlags{output: OutputJSON} //nolint:exhaustruct // output only
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().VarP(&outputFormatFlag{target: &flags.output}, "output", "o", "")
+	rootCmd.AddCommand(newDiffCommand(flags))
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"diff"}, args...))
+
+	err := rootCmd.ExecuteContext(context.Background())
+
+	return out.String(), err
+}
+
+// TestDiff_Text tests the text output and --detailed-exitcode.
+func TestDiff_Text(t *testing.T) {
+	prod, staging := writeDiffSnapshots(t)
+
+	out, err := runDiffCLI(t, "--from", prod, "--to", staging, "--detailed-exitcode")
+	require.ErrorIs(t, err, errDifferences)
+	assert.Equal(t, exitPlanChanges, ExitCode(err))
+	assert.Equal(t, "Differences from "+prod+" to "+staging+":\n"+
+		"\nquality-gates\n"+
+		"  ~ Strict\n"+
+		"      ~ condition new_coverage: LT 80 -> LT 90\n"+
+		"\nsettings\n"+
+		"  - sonar.exclusions: **/gen/**\n"+
+		"\n2 difference(s): 0 added, 1 changed, 1 removed.\n", out)
+
+	out, err = runDiffCLI(t, "--from", prod, "--to", prod, "--detailed-exitcode")
+	require.NoError(t, err)
+	assert.Equal(t, "No differences between "+prod+" and "+prod+".\n", out)
+}
+
+// TestDiff_MarkdownAndJSON tests the Markdown and JSON outputs.
+func TestDiff_MarkdownAndJSON(t *testing.T) {
+	prod, staging := writeDiffSnapshots(t)
+
+	out, err := runDiffCLI(t, "--from", prod, "--to", staging, "--format", "markdown")
+	require.NoError(t, err)
+	assert.Contains(t, out, "### quality-gates\n\n| | Object | Field | From | To |\n|---|---|---|---|---|\n"+
+		"| ~ | Strict | condition new_coverage | LT 80 | LT 90 |\n")
+	assert.Contains(t, out, "| - | sonar.exclusions |  | **/gen/** |  |\n")
+
+	out, err = runDiffCLI(t, "--from", prod, "--to", staging, "--format", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"field": "condition new_coverage"`)
+	assert.Contains(t, out, `"type": "removed"`)
+
+	_, err = runDiffCLI(t, "--from", prod, "--to", staging, "--format", "html")
+	require.ErrorIs(t, err, errInvalidFlagValue)
+}
+
+// TestDiff_Context tests that context:<name> reads the connection of the server from
+// the environment.
+func TestDiff_Context(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/navigation/global":
+			_, _ = w.Write([]byte(`{"version": "2025.1", "qualifiers": ["TRK"]}`))
+		case "/api/qualitygates/list":
+			_, _ = w.Write([]byte(`{"qualitygates": [{"name": "Strict"}]}`))
+		case "/api/qualitygates/show":
+			_, _ = w.Write([]byte(`{"name": "Strict", "conditions": [{"metric": "new_coverage", "op": "LT", "error": "80"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("SONAR_CLI_STAGING_EU_URL", server.URL+"/api/")
+
+	prod, _ := writeDiffSnapshots(t)
+
+	out, err := runDiffCLI(t, "--from", prod, "--to", "context:staging-eu")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Differences from "+prod+" to context:staging-eu:\n")
+	assert.Contains(t, out, "  - sonar.exclusions: **/gen/**\n")
+	assert.NotContains(t, out, "quality-gates")
+
+	_, err = runDiffCLI(t, "--from", "context:unknown", "--to", prod)
+	require.ErrorIs(t, err, errUnknownContext)
+	assert.Equal(t, exitValidation, ExitCode(err))
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// errorFormatFlag is the global flag choosing how command errors are reported.
+	errorFormatFlag = "error-format"
+	// errorFormatText reports errors as log lines on stderr.
+	errorFormatText = "text"
+	// errorFormatJSON reports errors as a single JSON document on stderr.
+	errorFormatJSON = "json"
+)
+
+// errorReport is the JSON document printed to stderr for a failed command with
+// --error-format json.
+//
+//nolint:govet // fieldalignment: fields are in the documented output order
+type errorReport struct {
+	// Code is the exit status of the process.
+	Code int `json:"code"`
+	// Status is the HTTP status of the failed request, if the server answered.
+	Status int `json:"status"`
+	// Endpoint is the method and path of the failed request, if the server answered.
+	Endpoint string `json:"endpoint"`
+	// Messages are the error messages, one per error reported by the server or the CLI.
+	Messages []string `json:"messages"`
+}
+
+// sonarErrorBody is the body of a SonarQube error response.
+type sonarErrorBody struct {
+	Errors []struct {
+		Msg string `json:"msg"`
+	} `json:"errors"`
+}
+
+// errorFormatValue implements pflag.Value for the --error-format flag with validation.
+type errorFormatValue struct {
+	target *string
+}
+
+// String returns the current error format.
+func (f *errorFormatValue) String() string {
+	if f.target == nil || *f.target == "" {
+		return errorFormatText
+	}
+
+	return *f.target
+}
+
+// Set validates and sets the error format.
+func (f *errorFormatValue) Set(val string) error {
+	switch val {
+	case errorFormatText, errorFormatJSON:
+		*f.target = val
+
+		return nil
+	default:
+		return fmt.Errorf("invalid error format %q: must be one of text, json", val)
+	}
+}
+
+// Type returns the type name for help text.
+func (f *errorFormatValue) Type() string {
+	return "format"
+}
+
+// applyErrorFormat silences the logger when errors are reported as JSON, so that stderr
+// only holds the error document.
+func applyErrorFormat(format string) {
+	if format == errorFormatJSON {
+		logLevel.SetLevel(zapcore.FatalLevel)
+	} else {
+		logLevel.SetLevel(zapcore.InfoLevel)
+	}
+}
+
+// reportsJSONErrors reports whether the command line run by rootCmd asked for JSON errors.
+func reportsJSONErrors(rootCmd *cobra.Command) bool {
+	flag := rootCmd.PersistentFlags().Lookup(errorFormatFlag)
+
+	return flag != nil && flag.Value.String() == errorFormatJSON
+}
+
+// writeErrorReport writes the JSON error document for err.
+func writeErrorReport(writer io.Writer, err error) {
+	report := newErrorReport(err)
+
+	data, marshalErr := json.Marshal(report)
+	if marshalErr != nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(writer, "%s\n", data)
+}
+
+// newErrorReport describes err with its exit status and, for an error response of the
+// server, the request and the messages the server gave.
+func newErrorReport(err error) *errorReport {
+	report := &errorReport{Code: ExitCode(err), Status: 0, Endpoint: "", Messages: nil}
+
+	var responseErr *sonar.ResponseError
+	if errors.As(err, &responseErr) {
+		report.Status = responseErr.StatusCode
+
+		if responseErr.Response != nil && responseErr.Response.Request != nil {
+			req := responseErr.Response.Request
+			report.Endpoint = req.Method + " " + req.URL.Path
+		}
+
+		var body sonarErrorBody
+		if json.Unmarshal(responseErr.Body, &body) == nil {
+			for _, item := range body.Errors {
+				report.Messages = append(report.Messages, item.Msg)
+			}
+		}
+
+		if len(report.Messages) == 0 {
+			report.Messages = []string{responseErr.Message}
+		}
+
+		return report
+	}
+
+	// Joined errors, such as several invalid flag values, are reported one by one.
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, item := range joined.Unwrap() {
+			report.Messages = append(report.Messages, item.Error())
+		}
+
+		return report
+	}
+
+	report.Messages = []string{err.Error()}
+
+	return report
+}
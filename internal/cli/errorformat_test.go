@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestExitCode_APIErrors tests the exit status for each kind of API error.
+func TestExitCode_APIErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		code   int
+	}{
+		{status: http.StatusBadRequest, code: exitValidation},
+		{status: http.StatusUnauthorized, code: exitUnauthorized},
+		{status: http.StatusForbidden, code: exitForbidden},
+		{status: http.StatusNotFound, code: exitNotFound},
+		{status: http.StatusConflict, code: exitConflict},
+		{status: http.StatusTooManyRequests, code: exitRateLimited},
+		{status: http.StatusBadGateway, code: exitServerError},
+		{status: http.StatusTeapot, code: exitFailure},
+	}
+
+	for _, tc := range tests {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			_, err := runPositionalCLI(t, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+			}, "components", "show", "my-app")
+			require.Error(t, err)
+			assert.Equal(t, tc.code, ExitCode(err))
+		})
+	}
+
+	assert.Equal(t, exitValidation, ExitCode(fmt.Errorf("call: %w", sonar.NewValidationError("Component", "is required", sonar.ErrMissingRequired))))
+	assert.Equal(t, exitValidation, ExitCode(errors.Join(fmt.Errorf("%w: nope", errInvalidFlagValue))))
+}
+
+// TestErrorReport tests the JSON error document of a server error response.
+func TestErrorReport(t *testing.T) {
+	_, err := runPositionalCLI(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors": [{"msg": "Component key 'my-app' not found"}, {"msg": "second"}]}`))
+	}, "components", "show", "my-app")
+	require.Error(t, err)
+
+	data, marshalErr := json.Marshal(newErrorReport(err))
+	require.NoError(t, marshalErr)
+	assert.JSONEq(t, `{
+		"code": 6,
+		"status": 404,
+		"endpoint": "GET /api/components/show",
+		"messages": ["Component key 'my-app' not found", "second"]
+	}`, string(data))
+
+	report := newErrorReport(errors.Join(errors.New("first"), errors.New("second")))
+	assert.Equal(t, &errorReport{Code: exitFailure, Messages: []string{"first", "second"}}, report)
+}
+
+// TestErrorFormat tests the --error-format flag and the logger it silences.
+func TestErrorFormat(t *testing.T) {
+	t.Cleanup(func() { applyErrorFormat(errorFormatText) })
+
+	rootCmd := newCommandTree()
+	rootCmd.SetArgs([]string{"--error-format", "xml", "issues", "search"})
+	require.ErrorContains(t, rootCmd.Execute(), `invalid error format "xml"`)
+
+	applyErrorFormat(errorFormatJSON)
+	assert.False(t, Logger().Core().Enabled(zapcore.ErrorLevel))
+
+	applyErrorFormat(errorFormatText)
+	assert.True(t, Logger().Core().Enabled(zapcore.InfoLevel))
+
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	session, _, errOut := newTestShellSession(t, client)
+	runScript(t, session, "components show my-app --error-format json")
+
+	var report errorReport
+	require.NoError(t, json.Unmarshal(errOut.Bytes(), &report))
+	assert.Equal(t, exitForbidden, report.Code)
+	assert.Equal(t, http.StatusForbidden, report.Status)
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os/exec"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+const (
+	// exitFailure is the exit status of any failed command without a more specific status.
+	exitFailure = 1
+	// exitValidation is the exit status of a request with invalid parameters, rejected by the
+	// CLI or the SDK before sending it, or by the server with 400 Bad Request.
+	exitValidation = 3
+	// exitUnauthorized is the exit status of a request rejected for missing or invalid credentials (401).
+	exitUnauthorized = 4
+	// exitForbidden is the exit status of a request rejected for lack of permissions (403).
+	exitForbidden = 5
+	// exitNotFound is the exit status of a request for something that does not exist (404).
+	exitNotFound = 6
+	// exitConflict is the exit status of a request conflicting with the server state, such as
+	// creating something that exists (409).
+	exitConflict = 7
+	// exitRateLimited is the exit status of a request rejected by rate limiting (429).
+	exitRateLimited = 8
+	// exitServerError is the exit status of a request the server failed (5xx) or that could not
+	// reach the server.
+	exitServerError = 9
+	// exitDeadlineExceeded is the exit status of a command stopped by --deadline, as timeout(1) uses.
+	exitDeadlineExceeded = 124
+	// exitInterrupted is the exit status of a command stopped by SIGINT (128 + SIGINT).
+	exitInterrupted = 130
+	// exitTerminated is the exit status of a command stopped by SIGTERM (128 + SIGTERM).
+	exitTerminated = 143
+)
+
+// exitStatusError is an error carrying the process exit status it should produce.
+type exitStatusError struct {
+	code int
+	err  error
+}
+
+// Error returns the message of the wrapped error.
+func (e *exitStatusError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *exitStatusError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode wraps err so that the process exits with code.
+func withExitCode(code int, err error) error {
+	return &exitStatusError{code: code, err: err}
+}
+
+// ExitCode returns the process exit status for an error returned by Execute: 0 for nil,
+// the status chosen by the command (such as a failed quality gate) or by a plugin
+// process, a status for the kind of API error (see the exit constants), and 1 otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var statusErr *exitStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code
+	}
+
+	// A failing plugin's exit status is passed on unchanged.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+
+	return apiErrorExitCode(err)
+}
+
+// apiErrorExitCode returns the exit status for the kind of API error err is.
+func apiErrorExitCode(err error) int {
+	var (
+		validationErr *sonar.ValidationError
+		responseErr   *sonar.ResponseError
+		netErr        net.Error
+	)
+
+	switch {
+	case errors.As(err, &validationErr), errors.Is(err, errInvalidFlagValue):
+		return exitValidation
+	case errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusBadRequest:
+		return exitValidation
+	case sonar.IsUnauthorized(err):
+		return exitUnauthorized
+	case sonar.IsForbidden(err):
+		return exitForbidden
+	case sonar.IsNotFound(err):
+		return exitNotFound
+	case sonar.IsConflict(err):
+		return exitConflict
+	case sonar.IsRateLimited(err):
+		return exitRateLimited
+	case sonar.IsServerError(err), errors.As(err, &netErr):
+		return exitServerError
+	default:
+		return exitFailure
+	}
+}
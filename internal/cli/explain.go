@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// redactedValue replaces credentials in printed requests.
+const redactedValue = "<redacted>"
+
+// errDryRun is returned by the dry-run transport instead of sending a request.
+// Commands treat it as success: the request has been printed and nothing was sent.
+var errDryRun = errors.New("dry run: request not sent")
+
+// sensitiveHeaders lists the request headers whose values are redacted when printed.
+//
+//nolint:gochecknoglobals // constant configuration set
+var sensitiveHeaders = map[string]struct{}{
+	"Authorization": {},
+	"Cookie":        {},
+	"X-Auth-Token":  {},
+}
+
+// isDryRun reports whether err is the dry-run sentinel (possibly wrapped by net/http).
+func isDryRun(err error) bool {
+	return errors.Is(err, errDryRun)
+}
+
+// dryRunMiddleware returns a middleware that prints each request to writer and aborts
+// it with errDryRun before anything is sent. Because it sits in the client transport,
+// requests are built by the service methods themselves, after option validation.
+func dryRunMiddleware(writer io.Writer, curl bool) sonar.Middleware {
+	return func(_ http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := readRequestBody(req)
+			if err != nil {
+				return nil, err
+			}
+
+			writeRequestDescription(writer, req, body)
+
+			if curl {
+				_, _ = fmt.Fprintln(writer, renderCurl(req, body))
+			}
+
+			return nil, errDryRun
+		})
+	}
+}
+
+// explainMiddleware returns a middleware that logs each request before sending it and
+// its status and duration afterwards, optionally with a curl equivalent.
+func explainMiddleware(curl bool) sonar.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := readRequestBody(req)
+			if err != nil {
+				return nil, err
+			}
+
+			fields := []zap.Field{
+				zap.String("method", req.Method),
+				zap.String("url", req.URL.String()),
+			}
+
+			if len(body) > 0 {
+				fields = append(fields, zap.ByteString("body", body))
+			}
+
+			if curl {
+				fields = append(fields, zap.String("curl", renderCurl(req, body)))
+			}
+
+			Logger().Info("sending request", fields...)
+
+			start := time.Now()
+
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				Logger().Warn("request failed", zap.String("method", req.Method), zap.Duration("duration", time.Since(start)), zap.Error(err))
+
+				return nil, err //nolint:wrapcheck // pass-through transport
+			}
+
+			Logger().Info("received response",
+				zap.String("method", req.Method),
+				zap.Int("status", resp.StatusCode),
+				zap.Duration("duration", time.Since(start)))
+
+			return resp, nil
+		})
+	}
+}
+
+// roundTripperFunc adapts a function to the http.RoundTripper interface.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls the function.
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// readRequestBody reads the request body and restores it so the request can still be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// writeRequestDescription prints the method, URL, decoded query parameters and body of a request.
+func writeRequestDescription(writer io.Writer, req *http.Request, body []byte) {
+	endpoint := *req.URL
+	endpoint.RawQuery = ""
+
+	_, _ = fmt.Fprintf(writer, "%s %s\n", req.Method, endpoint.String())
+
+	query := req.URL.Query()
+	if len(query) > 0 {
+		_, _ = fmt.Fprintln(writer, "Query:")
+
+		for _, key := range sortedKeys(query) {
+			for _, value := range query[key] {
+				_, _ = fmt.Fprintf(writer, "  %s=%s\n", key, value)
+			}
+		}
+	}
+
+	if len(body) > 0 {
+		_, _ = fmt.Fprintln(writer, "Body:")
+		_, _ = fmt.Fprintf(writer, "  %s\n", body)
+	}
+}
+
+// renderCurl renders a request as an equivalent curl command with credentials redacted.
+func renderCurl(req *http.Request, body []byte) string {
+	parts := []string{"curl", "-X", req.Method}
+
+	for _, name := range sortedKeys(req.Header) {
+		for _, value := range req.Header[name] {
+			parts = append(parts, "-H", shellQuote(name+": "+redactHeader(name, value)))
+		}
+	}
+
+	if len(body) > 0 {
+		parts = append(parts, "--data", shellQuote(string(body)))
+	}
+
+	parts = append(parts, shellQuote(req.URL.String()))
+
+	return strings.Join(parts, " ")
+}
+
+// redactHeader hides credential header values, keeping the auth scheme for context.
+func redactHeader(name, value string) string {
+	if _, sensitive := sensitiveHeaders[http.CanonicalHeaderKey(name)]; !sensitive {
+		return value
+	}
+
+	if scheme, _, found := strings.Cut(value, " "); found {
+		return scheme + " " + redactedValue
+	}
+
+	return redactedValue
+}
+
+// shellQuote wraps a value in single quotes for POSIX shells.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// sortedKeys returns the keys of a string-slice map in sorted order.
+func sortedKeys[M ~map[string][]string](values M) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
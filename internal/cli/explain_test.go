@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// newInspectedClient creates a client against a test server with the given middleware.
+func newInspectedClient(t *testing.T, handler http.HandlerFunc, middleware sonar.Middleware) *sonar.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := sonar.NewClient(nil,
+		sonar.WithBaseURL(server.URL+"/api/"),
+		sonar.WithToken("secret-token"),
+		sonar.WithMiddleware(middleware),
+	)
+	require.NoError(t, err)
+
+	return client
+}
+
+// TestDryRunMiddleware tests that dry-run prints the request and never sends it.
+func TestDryRunMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+
+	client := newInspectedClient(t, func(http.ResponseWriter, *http.Request) {
+		t.Fatal("dry run must not send the request")
+	}, dryRunMiddleware(&buf, true))
+
+	_, err := client.Projects.BulkDelete(context.Background(), &sonar.ProjectsBulkDeleteOptions{Projects: []string{"a", "b"}})
+	require.Error(t, err)
+	assert.True(t, isDryRun(err))
+
+	output := buf.String()
+	assert.Contains(t, output, "POST http://")
+	assert.Contains(t, output, "/api/projects/bulk_delete")
+	assert.Contains(t, output, "projects=a,b")
+	assert.Contains(t, output, "curl -X POST")
+	assert.Contains(t, output, "Authorization: Basic <redacted>")
+	assert.NotContains(t, output, "c2VjcmV0LXRva2Vu") // base64("secret-token")
+}
+
+// TestDryRunMiddleware_ValidationFirst tests that invalid options fail before the dry run.
+func TestDryRunMiddleware_ValidationFirst(t *testing.T) {
+	var buf bytes.Buffer
+
+	client := newInspectedClient(t, func(http.ResponseWriter, *http.Request) {}, dryRunMiddleware(&buf, false))
+
+	_, err := client.Projects.Delete(context.Background(), &sonar.ProjectsDeleteOptions{})
+	require.Error(t, err)
+	assert.False(t, isDryRun(err))
+	assert.Empty(t, buf.String())
+}
+
+// TestDryRunMiddleware_Body tests that request bodies are printed.
+func TestDryRunMiddleware_Body(t *testing.T) {
+	var buf bytes.Buffer
+
+	client := newInspectedClient(t, func(http.ResponseWriter, *http.Request) {}, dryRunMiddleware(&buf, true))
+
+	req := &apiRequest{Method: http.MethodPost, Path: "v2/users-management/users", Body: json.RawMessage(`{"login":"it's"}`)}
+
+	_, err := executeAPIRequest(context.Background(), client, req)
+	require.True(t, isDryRun(err))
+	assert.Contains(t, buf.String(), `Body:`)
+	assert.Contains(t, buf.String(), `--data '{"login":"it'\''s"}'`)
+}
+
+// TestExplainMiddleware tests that explain still sends the request and preserves the body.
+func TestExplainMiddleware(t *testing.T) {
+	client := newInspectedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "bob", body["login"])
+		w.WriteHeader(http.StatusCreated)
+	}, explainMiddleware(true))
+
+	req := &apiRequest{Method: http.MethodPost, Path: "v2/users-management/users", Body: json.RawMessage(`{"login":"bob"}`)}
+
+	_, err := executeAPIRequest(context.Background(), client, req)
+	require.NoError(t, err)
+}
+
+// TestRedactHeader tests credential redaction in printed headers.
+func TestRedactHeader(t *testing.T) {
+	assert.Equal(t, "Basic <redacted>", redactHeader("authorization", "Basic abc"))
+	assert.Equal(t, "<redacted>", redactHeader("X-Auth-Token", "abc"))
+	assert.Equal(t, "application/json", redactHeader("Accept", "application/json"))
+}
+
+// TestRequestInspectionOptions tests client option wiring for the global flags.
+func TestRequestInspectionOptions(t *testing.T) {
+	assert.Empty(t, requestInspectionOptions(&globalFlags{}))
+	assert.Len(t, requestInspectionOptions(&globalFlags{dryRun: true, explain: true}), 1)
+	assert.True(t, strings.HasPrefix(shellQuote("a'b"), "'a"))
+}
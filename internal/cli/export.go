@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar/snapshot"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// ExportSummary describes an exported snapshot.
+type ExportSummary struct {
+	// Directory is the directory the snapshot was written to.
+	Directory string `json:"directory"`
+	// Format is the format of the snapshot files.
+	Format snapshot.Format `json:"format"`
+	// Version is the version of the exported server.
+	Version string `json:"version"`
+	// Edition is the edition of the exported server.
+	Edition string `json:"edition,omitempty"`
+	// Counts are the numbers of exported objects, by section.
+	Counts []ExportCount `json:"counts"`
+	// Skipped are the sections the edition of the server does not support.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ExportCount is the number of objects exported in a section.
+type ExportCount struct {
+	// Section is the name of the section.
+	Section string `json:"section"`
+	// Count is the number of objects.
+	Count int `json:"count"`
+}
+
+// newExportCommand creates the export command.
+func newExportCommand(format *OutputFormat) *cobra.Command {
+	var fileFormat string
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the export command
+		Use:   "export <directory>",
+		Short: "Export the configuration of the instance to a directory",
+		Long: `Export the configuration of the instance to a directory of YAML or JSON files:
+quality gates, quality profiles, permission templates, global permissions, groups
+and their members, global settings, webhooks, ALM settings, portfolios,
+applications and new code periods.
+
+Each custom quality profile is also backed up to
+quality-profiles/<language>/<name>.xml, in the format "qualityprofiles restore"
+accepts. Secrets are never exported: secured settings, webhook secrets and ALM
+credentials are written as "(redacted)".
+
+The files of a previous export in the directory are replaced. The export needs the
+Administer System permission.`,
+		Example: `  sonar-cli export backup/
+  sonar-cli export backup/ --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExport(cmd, args[0], snapshot.Format(fileFormat), *format)
+		},
+	}
+
+	cmd.Flags().StringVar(&fileFormat, "format", string(snapshot.FormatYAML), "Format of the files: yaml or json")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{string(snapshot.FormatYAML), string(snapshot.FormatJSON)}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// runExport exports the configuration of the instance to dir and prints a summary.
+func runExport(cmd *cobra.Command, dir string, fileFormat snapshot.Format, format OutputFormat) error {
+	if fileFormat != snapshot.FormatYAML && fileFormat != snapshot.FormatJSON {
+		err := fmt.Errorf("%w %q for --format: must be one of %s, %s", errInvalidFlagValue, fileFormat, snapshot.FormatYAML, snapshot.FormatJSON)
+		Logger().Error("invalid export format", zap.Error(err))
+
+		return err
+	}
+
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return err
+	}
+
+	snap, err := snapshot.Export(cmd.Context(), client)
+	if err != nil {
+		Logger().Error("failed to export the configuration", zap.Error(err))
+
+		return fmt.Errorf("failed to export the configuration: %w", err)
+	}
+
+	err = snap.WriteDir(dir, fileFormat)
+	if err != nil {
+		Logger().Error("failed to write the snapshot", zap.String("directory", dir), zap.Error(err))
+
+		return fmt.Errorf("failed to write the snapshot: %w", err)
+	}
+
+	summary := summarizeExport(snap, dir, fileFormat)
+
+	if cmd.Flags().Changed("output") {
+		return writeResult(cmd, summary, format)
+	}
+
+	recordResult(cmd.Context(), summary)
+	writeExportSummary(cmd.OutOrStdout(), summary)
+
+	return nil
+}
+
+// summarizeExport counts the objects of a snapshot.
+func summarizeExport(snap *snapshot.Snapshot, dir string, fileFormat snapshot.Format) *ExportSummary {
+	backups := 0
+
+	for _, profile := range snap.QualityProfiles {
+		if profile.Backup != "" {
+			backups++
+		}
+	}
+
+	return &ExportSummary{
+		Directory: dir,
+		Format:    fileFormat,
+		Version:   snap.Instance.Version,
+		Edition:   snap.Instance.Edition,
+		Counts: []ExportCount{
+			{Section: "quality gates", Count: len(snap.QualityGates)},
+			{Section: "quality profiles", Count: len(snap.QualityProfiles)},
+			{Section: "profile backups", Count: backups},
+			{Section: "permission templates", Count: len(snap.PermissionTemplates)},
+			{Section: "global permission grants", Count: len(snap.GlobalPermissions.Groups) + len(snap.GlobalPermissions.Users)},
+			{Section: "groups", Count: len(snap.Groups)},
+			{Section: "settings", Count: len(snap.Settings)},
+			{Section: "webhooks", Count: len(snap.Webhooks)},
+			{Section: "ALM settings", Count: len(snap.AlmSettings)},
+			{Section: "portfolios", Count: len(snap.Portfolios)},
+			{Section: "applications", Count: len(snap.Applications)},
+			{Section: "new code periods", Count: len(snap.NewCodePeriods)},
+		},
+		Skipped: snap.Instance.Skipped,
+	}
+}
+
+// writeExportSummary prints the counts of an export.
+func writeExportSummary(writer io.Writer, summary *ExportSummary) {
+	server := "SonarQube " + summary.Version
+	if summary.Edition != "" {
+		server += " (" + summary.Edition + ")"
+	}
+
+	_, _ = fmt.Fprintf(writer, "Exported %s to %s:\n", server, summary.Directory)
+
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+	for _, count := range summary.Counts {
+		_, _ = fmt.Fprintf(table, "  %s\t%d\n", count.Section, count.Count)
+	}
+
+	_ = table.Flush()
+
+	if len(summary.Skipped) > 0 {
+		_, _ = fmt.Fprintf(writer, "Not available in this edition: %s\n", strings.Join(summary.Skipped, ", "))
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runExportCLI runs the export command against a server with one quality gate and
+// otherwise empty sections, and returns its output.
+func runExportCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/navigation/global":
+			_, _ = w.Write([]byte(`{"version": "2025.1", "edition": "community", "qualifiers": ["TRK"]}`))
+		case "/api/qualitygates/list":
+			_, _ = w.Write([]byte(`{"qualitygates": [{"name": "Sonar way", "isBuiltIn": true, "isDefault": true}]}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	var out bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().VarP(&outputFormatFlag{target: &format}, "output", "o", "")
+	rootCmd.AddCommand(newExportCommand(&format))
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"export"}, args...))
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+
+	return out.String(), err
+}
+
+// TestExport_WritesSnapshot tests that export writes the snapshot and prints the counts.
+func TestExport_WritesSnapshot(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	out, err := runExportCLI(t, dir, "--format", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Exported SonarQube 2025.1 (community) to "+dir+":\n")
+	assert.Contains(t, out, "  quality gates             1\n")
+	assert.Contains(t, out, "Not available in this edition: portfolios, applications\n")
+	assert.FileExists(t, filepath.Join(dir, "quality-gates.json"))
+
+	out, err = runExportCLI(t, dir, "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"format": "yaml"`)
+	assert.FileExists(t, filepath.Join(dir, "quality-gates.yaml"))
+}
+
+// TestExport_InvalidFormat tests that an unknown file format is rejected before exporting.
+func TestExport_InvalidFormat(t *testing.T) {
+	_, err := runExportCLI(t, t.TempDir(), "--format", "xml")
+	require.ErrorIs(t, err, errInvalidFlagValue)
+	assert.Equal(t, exitValidation, ExitCode(err))
+}
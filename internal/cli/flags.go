@@ -10,10 +10,15 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// positionalAnnotation marks the flag bound to a field tagged `cli:"positional"`.
+const positionalAnnotation = "sonar-cli_positional"
+
 // BindFlags binds Cobra flags to a struct's fields based on reflection.
 // It reads `url` struct tags to derive flag names and detects required fields
 // (those without "omitempty" in their url tag).
 // Embedded structs with `url:",inline"` or anonymous fields are recursively bound.
+// The flag of a field tagged `cli:"positional"` is annotated so that the command can
+// also take its value as positional arguments.
 func BindFlags(cmd *cobra.Command, opt any) {
 	bindFlagsRecursive(cmd.Flags(), reflect.ValueOf(opt).Elem(), "")
 }
@@ -53,9 +58,27 @@ func bindFlagsRecursive(flags *pflag.FlagSet, structVal reflect.Value, prefix st
 		}
 
 		bindField(flags, fieldVal, field, flagName, required)
+
+		if field.Tag.Get("cli") == "positional" && flags.Lookup(flagName) != nil {
+			_ = flags.SetAnnotation(flagName, positionalAnnotation, []string{"true"})
+		}
 	}
 }
 
+// positionalFlag returns the flag whose value a command takes as positional arguments,
+// or nil when the command has none.
+func positionalFlag(cmd *cobra.Command) *pflag.Flag {
+	var positional *pflag.Flag
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if _, ok := flag.Annotations[positionalAnnotation]; ok {
+			positional = flag
+		}
+	})
+
+	return positional
+}
+
 // parseFlagMeta extracts flag name and required status from a struct field.
 // Flag name is derived from the Go field name (PascalCase→kebab-case).
 // A field is required if its url tag does not contain "omitempty".
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// fromFileFlag is the per-command flag reading option values from a file.
+const fromFileFlag = "from-file"
+
+// stdinPath is the special file path that reads from standard input.
+const stdinPath = "-"
+
+// errInvalidOptionsFile is returned when an options file cannot be applied.
+var errInvalidOptionsFile = errors.New("invalid options file")
+
+// optionField describes a bindable option struct field and the keys accepted for it
+// in an options file.
+type optionField struct {
+	// flagName is the kebab-case flag name, e.g. "set-severity".
+	flagName string
+	// apiName is the API parameter name from the url tag, e.g. "set_severity".
+	apiName string
+	// goName is the Go field name, e.g. "SetSeverity".
+	goName string
+	// value is the settable struct field.
+	value reflect.Value
+}
+
+// matches reports whether an options file key refers to this field.
+// Flag and API names match exactly; Go names match case-insensitively.
+func (f optionField) matches(key string) bool {
+	return key == f.flagName || key == f.apiName || strings.EqualFold(key, f.goName)
+}
+
+// addFromFileFlag registers --from-file on a method command and loads the file in
+// PreRunE, which runs before Cobra checks required flags.
+func addFromFileFlag(cmd *cobra.Command, optValue reflect.Value) {
+	cmd.Flags().String(fromFileFlag, "",
+		"Read options from a JSON or YAML file (\"-\" for stdin); explicitly set flags take precedence")
+	_ = cmd.MarkFlagFilename(fromFileFlag, "json", "yaml", "yml")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return applyOptionsFile(cmd, optValue)
+	}
+}
+
+// applyOptionsFile reads the --from-file document, if any, and decodes it into the
+// option struct. Values for flags set on the command line are left untouched, and
+// flags filled from the file no longer count as missing required flags. The values
+// are validated by the service method's Validate*Opt function like any flag value.
+func applyOptionsFile(cmd *cobra.Command, optValue reflect.Value) error {
+	path, _ := cmd.Flags().GetString(fromFileFlag)
+	if path == "" {
+		return nil
+	}
+
+	data, err := readInputFile(path, cmd.InOrStdin())
+	if err != nil {
+		return fmt.Errorf("failed to read options file: %w", err)
+	}
+
+	return decodeOptions(cmd.Flags(), data, optValue)
+}
+
+// readInputFile reads a user-supplied file, or stdin when path is "-".
+func readInputFile(path string, stdin io.Reader) ([]byte, error) {
+	if path == stdinPath {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+
+		return data, nil
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // reading a user-supplied file is the purpose of the calling flag
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+
+	return data, nil
+}
+
+// decodeOptions decodes a JSON or YAML mapping into the option struct behind optValue.
+// Keys may be flag names, API parameter names or Go field names.
+func decodeOptions(flags *pflag.FlagSet, data []byte, optValue reflect.Value) error {
+	var document map[string]yaml.Node
+
+	err := yaml.Unmarshal(data, &document)
+	if err != nil {
+		return fmt.Errorf("%w: expected a JSON or YAML mapping: %w", errInvalidOptionsFile, err)
+	}
+
+	err = applyOptionNodes(flags, document, optValue)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidOptionsFile, err)
+	}
+
+	return nil
+}
+
+// applyOptionNodes decodes YAML values keyed by option name into the option struct.
+// When flags is non-nil, options whose flag was set explicitly are skipped and flags
+// filled from the document no longer count as missing required flags.
+func applyOptionNodes(flags *pflag.FlagSet, document map[string]yaml.Node, optValue reflect.Value) error {
+	fields := collectOptionFields(optValue.Elem())
+
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		idx := slices.IndexFunc(fields, func(field optionField) bool { return field.matches(key) })
+		if idx < 0 {
+			return fmt.Errorf("unknown option %q", key)
+		}
+
+		field := fields[idx]
+
+		var flag *pflag.Flag
+		if flags != nil {
+			flag = flags.Lookup(field.flagName)
+		}
+
+		if flag != nil && flag.Changed {
+			continue
+		}
+
+		node := document[key]
+
+		err := decodeOptionValue(&node, field.value)
+		if err != nil {
+			return fmt.Errorf("option %q: %w", key, err)
+		}
+
+		if flag != nil {
+			// The document supplies the value, so the flag no longer has to be set explicitly.
+			if _, required := flag.Annotations[cobra.BashCompOneRequiredFlag]; required {
+				flag.Annotations[cobra.BashCompOneRequiredFlag] = []string{"false"}
+			}
+		}
+	}
+
+	return nil
+}
+
+// decodeOptionValue decodes a single YAML value into a struct field. A scalar given
+// for a []string field is split on commas, matching the flag syntax.
+func decodeOptionValue(node *yaml.Node, field reflect.Value) error {
+	if node.Kind == yaml.ScalarNode && field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String {
+		values := strings.Split(node.Value, ",")
+		field.Set(reflect.ValueOf(values).Convert(field.Type()))
+
+		return nil
+	}
+
+	err := node.Decode(field.Addr().Interface())
+	if err != nil {
+		return fmt.Errorf("cannot decode into %s: %w", field.Type(), err)
+	}
+
+	return nil
+}
+
+// collectOptionFields lists the option struct fields bound as flags, following the same
+// rules as BindFlags (embedded structs are flattened, untagged fields are skipped).
+func collectOptionFields(structVal reflect.Value) []optionField {
+	var fields []optionField
+
+	for field := range structVal.Type().Fields() {
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldVal := structVal.FieldByIndex(field.Index)
+		urlTag := field.Tag.Get("url")
+
+		if (field.Anonymous || urlTag == ",inline") && fieldVal.Kind() == reflect.Struct {
+			fields = append(fields, collectOptionFields(fieldVal)...)
+
+			continue
+		}
+
+		if urlTag == "" {
+			continue
+		}
+
+		flagName, _ := parseFlagMeta(field)
+		apiName, _, _ := strings.Cut(urlTag, ",")
+
+		fields = append(fields, optionField{
+			flagName: flagName,
+			apiName:  apiName,
+			goName:   field.Name,
+			value:    fieldVal,
+		})
+	}
+
+	return fields
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeOptions tests decoding JSON and YAML documents into option structs.
+func TestDecodeOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     mockOptionStruct
+	}{
+		{
+			name:     "yaml with flag names",
+			document: "project: my-app\nseverities: [MAJOR, MINOR]\nactive: true\np: 3\n",
+			want:     mockOptionStruct{Project: "my-app", Severities: []string{"MAJOR", "MINOR"}, Active: true, Page: 3},
+		},
+		{
+			name:     "json with go names",
+			document: `{"Project": "my-app", "Params": {"max": "10"}, "Resolved": false}`,
+			want:     mockOptionStruct{Project: "my-app", Params: map[string]string{"max": "10"}, Resolved: new(false)},
+		},
+		{
+			name:     "comma separated slice",
+			document: "severities: MAJOR,MINOR\nproject: 42\n",
+			want:     mockOptionStruct{Project: "42", Severities: []string{"MAJOR", "MINOR"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &mockOptionStruct{}
+
+			err := decodeOptions(pflag.NewFlagSet("test", pflag.ContinueOnError), []byte(tc.document), reflect.ValueOf(opt))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, *opt)
+		})
+	}
+}
+
+// TestDecodeOptions_Errors tests rejection of malformed documents and unknown keys.
+func TestDecodeOptions_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		contains string
+	}{
+		{name: "not a mapping", document: "- a\n- b\n", contains: "mapping"},
+		{name: "unknown key", document: "projet: foo\n", contains: `unknown option "projet"`},
+		{name: "type mismatch", document: "p: many\n", contains: `option "p"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := decodeOptions(pflag.NewFlagSet("test", pflag.ContinueOnError), []byte(tc.document), reflect.ValueOf(&mockOptionStruct{}))
+			require.ErrorIs(t, err, errInvalidOptionsFile)
+			assert.Contains(t, err.Error(), tc.contains)
+		})
+	}
+}
+
+// TestApplyOptionsFile_FlagsOverride tests that explicitly set flags win over file values
+// and that file values satisfy required flags.
+func TestApplyOptionsFile_FlagsOverride(t *testing.T) {
+	opt := &mockOptionStruct{}
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, opt)
+	addFromFileFlag(cmd, reflect.ValueOf(opt))
+
+	cmd.SetIn(strings.NewReader("project: from-file\nseverities: [BLOCKER]\n"))
+	require.NoError(t, cmd.Flags().Set(fromFileFlag, stdinPath))
+	require.NoError(t, cmd.Flags().Set("severities", "MAJOR"))
+
+	require.NoError(t, cmd.PreRunE(cmd, nil))
+	require.NoError(t, cmd.ValidateRequiredFlags())
+
+	assert.Equal(t, "from-file", opt.Project)
+	assert.Equal(t, []string{"MAJOR"}, opt.Severities)
+}
+
+// TestFromFile_MethodCommand tests --from-file end to end on a generated command.
+func TestFromFile_MethodCommand(t *testing.T) {
+	var query string
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		query = r.Form.Encode()
+
+		_, _ = w.Write([]byte(`{"total": 2, "success": 2, "ignored": 0, "failures": 0}`))
+	})
+
+	path := filepath.Join(t.TempDir(), "opts.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("issues: [AX-1, AX-2]\nset_severity: MAJOR\nadd-tags: a,b\n"), 0o600))
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test"}
+	RegisterAllCommands(rootCmd, &format)
+	rootCmd.SetArgs([]string{"issues", "bulk-change", "--from-file", path, "--set-severity", "MINOR"})
+
+	ctx := context.WithValue(context.Background(), clientContextKey, client)
+	require.NoError(t, rootCmd.ExecuteContext(ctx))
+
+	assert.Contains(t, query, "issues=AX-1%2CAX-2")
+	assert.Contains(t, query, "add_tags=a%2Cb")
+	assert.Contains(t, query, "set_severity=MINOR")
+}
+
+// TestFromFile_ValidationFirst tests that file values go through the service validation.
+func TestFromFile_ValidationFirst(t *testing.T) {
+	client := newTestClient(t, func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("request must not be sent")
+	})
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test"}
+	RegisterAllCommands(rootCmd, &format)
+	rootCmd.SetIn(strings.NewReader(`{"issues": ["AX-1"], "set_severity": "NOPE"}`))
+	rootCmd.SetArgs([]string{"issues", "bulk-change", "--from-file", "-"})
+	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
+
+	ctx := context.WithValue(context.Background(), clientContextKey, client)
+	require.Error(t, rootCmd.ExecuteContext(ctx))
+}
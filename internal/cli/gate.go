@@ -0,0 +1,484 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	// exitGateFailed is the exit status of gate check when the quality gate failed.
+	exitGateFailed = 1
+	// exitGateNone is the exit status of gate check when no quality gate was computed.
+	exitGateNone = 2
+	// defaultGateWaitTimeout bounds the wait for a Compute Engine task.
+	defaultGateWaitTimeout = 5 * time.Minute
+	// defaultGatePollInterval caps the delay between two polls of a Compute Engine task.
+	defaultGatePollInterval = sonar.DefaultWaitMaxInterval
+	// gateReportFileMode is the permission of the JUnit and Markdown report files.
+	gateReportFileMode = 0o644
+)
+
+var (
+	// errGateFailed is returned by gate check when the quality gate failed.
+	errGateFailed = errors.New("quality gate failed")
+	// errGateNone is returned by gate check when the project has no quality gate status.
+	errGateNone = errors.New("no quality gate status")
+)
+
+// comparatorSymbols renders quality gate condition comparators.
+//
+//nolint:gochecknoglobals // constant lookup table
+var comparatorSymbols = map[string]string{
+	"GT": ">",
+	"LT": "<",
+	"EQ": "=",
+	"NE": "!=",
+}
+
+// GateCheckResult is the outcome of a quality gate check.
+type GateCheckResult struct {
+	// Status is the quality gate status (OK, WARN, ERROR or NONE).
+	Status string `json:"status"`
+	// Project is the checked project key, when known.
+	Project string `json:"project,omitempty"`
+	// Branch is the checked branch, if any.
+	Branch string `json:"branch,omitempty"`
+	// PullRequest is the checked pull request, if any.
+	PullRequest string `json:"pullRequest,omitempty"`
+	// AnalysisID is the checked analysis, if one was given or resolved from a task.
+	AnalysisID string `json:"analysisId,omitempty"`
+	// TaskID is the Compute Engine task waited for, if any.
+	TaskID string `json:"taskId,omitempty"`
+	// Conditions are the evaluated quality gate conditions.
+	Conditions []sonar.QualityGateConditionStatus `json:"conditions"`
+}
+
+// failedConditions returns the conditions in error.
+func (r *GateCheckResult) failedConditions() []sonar.QualityGateConditionStatus {
+	var failed []sonar.QualityGateConditionStatus
+
+	for _, condition := range r.Conditions {
+		if condition.Status == sonar.QualityGateStatusError {
+			failed = append(failed, condition)
+		}
+	}
+
+	return failed
+}
+
+// target describes the checked project, branch or pull request for summaries.
+func (r *GateCheckResult) target() string {
+	var target string
+
+	switch {
+	case r.Project != "":
+		target = "project " + r.Project
+	case r.AnalysisID != "":
+		target = "analysis " + r.AnalysisID
+	}
+
+	switch {
+	case r.Branch != "":
+		target += ", branch " + r.Branch
+	case r.PullRequest != "":
+		target += ", pull request " + r.PullRequest
+	}
+
+	return target
+}
+
+// gateCheckFlags holds the flags of the gate check command.
+type gateCheckFlags struct {
+	project        string
+	branch         string
+	pullRequest    string
+	analysisID     string
+	ceTaskID       string
+	waitTimeout    time.Duration
+	pollInterval   time.Duration
+	failOnWarnings bool
+	junit          string
+	markdown       string
+}
+
+// newGateCommand creates the gate command and its check subcommand.
+func newGateCommand(format *OutputFormat) *cobra.Command {
+	gateCmd := &cobra.Command{ //nolint:exhaustruct // only Use/Short/Long are needed
+		Use:   "gate",
+		Short: "Quality gate commands for CI pipelines",
+		Long:  "Commands for checking quality gate results in CI pipelines.",
+	}
+
+	gateCmd.AddCommand(newGateCheckCommand(format))
+
+	return gateCmd
+}
+
+// newGateCheckCommand creates the gate check command.
+func newGateCheckCommand(format *OutputFormat) *cobra.Command {
+	flags := &gateCheckFlags{} //nolint:exhaustruct // fields are set by Cobra flag binding
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the gate check command
+		Use:   "check",
+		Short: "Check the quality gate of a project, branch, pull request or analysis",
+		Long: `Check the quality gate status and exit with a status CI pipelines can act on:
+
+  0    the quality gate passed (OK, or WARN on servers that still report it)
+  1    the quality gate failed (ERROR), the Compute Engine task failed or was
+       canceled, or, with --fail-on-warnings, the analysis reported warnings
+  2    no quality gate was computed (NONE)
+  3-9  the check could not be made because a request failed: 3 invalid
+       parameters, 4 unauthorized, 5 forbidden, 6 not found, 7 conflict,
+       8 rate limited, 9 server error or server unreachable
+  124  --wait-timeout elapsed or --deadline stopped the check
+  130  interrupted by SIGINT (143 for SIGTERM)
+
+Any other failure of the check itself, such as failing to write --junit, also exits
+with status 1; its error, logged to stderr, tells it apart from a failed gate.
+
+With --ce-task-id (the ceTaskId of the scanner's report-task.txt), the command first
+waits for the Compute Engine task to finish, then checks the analysis it produced.
+
+A summary of the failed conditions is printed, unless --output is given, in which
+case the full result is printed in that format. --junit and --markdown also write
+the result as a JUnit XML report and a Markdown summary (e.g. $GITHUB_STEP_SUMMARY).`,
+		Example: `  sonar-cli gate check --project my-app --branch main
+  sonar-cli gate check --project my-app --ce-task-id "$CE_TASK_ID" --junit gate.xml
+  sonar-cli gate check --project my-app --pull-request 42 --markdown "$GITHUB_STEP_SUMMARY"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runGateCheck(cmd, flags, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.project, "project", "", "Project key")
+	cmd.Flags().StringVar(&flags.branch, "branch", "", "Branch key")
+	cmd.Flags().StringVar(&flags.pullRequest, "pull-request", "", "Pull request id")
+	cmd.Flags().StringVar(&flags.analysisID, "analysis-id", "", "Analysis id to check instead of the latest analysis")
+	cmd.Flags().StringVar(&flags.ceTaskID, "ce-task-id", "", "Compute Engine task to wait for; its analysis is checked")
+	cmd.Flags().DurationVar(&flags.waitTimeout, "wait-timeout", defaultGateWaitTimeout, "Maximum time to wait for the Compute Engine task")
+	cmd.Flags().DurationVar(&flags.pollInterval, "poll-interval", defaultGatePollInterval, "Maximum delay between two polls of the Compute Engine task (polling backs off up to it)")
+	cmd.Flags().BoolVar(&flags.failOnWarnings, "fail-on-warnings", false, "Fail the check when the analysis reported warnings")
+	cmd.Flags().StringVar(&flags.junit, "junit", "", "Write the result as a JUnit XML report to this file")
+	cmd.Flags().StringVar(&flags.markdown, "markdown", "", "Write a Markdown summary of the result to this file")
+	cmd.MarkFlagsMutuallyExclusive("branch", "pull-request")
+	cmd.MarkFlagsMutuallyExclusive("analysis-id", "ce-task-id")
+	cmd.MarkFlagsOneRequired("project", "analysis-id", "ce-task-id")
+	_ = cmd.MarkFlagFilename("junit", "xml")
+	_ = cmd.MarkFlagFilename("markdown", "md")
+
+	registerDynamicCompletions(cmd)
+
+	return cmd
+}
+
+// runGateCheck waits for the analysis if requested, fetches the quality gate status,
+// writes the reports and summary, and returns an error carrying the exit status.
+func runGateCheck(cmd *cobra.Command, flags *gateCheckFlags, format *OutputFormat) error {
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return err
+	}
+
+	result := &GateCheckResult{ //nolint:exhaustruct // status and conditions are set below
+		Project:     flags.project,
+		Branch:      flags.branch,
+		PullRequest: flags.pullRequest,
+		AnalysisID:  flags.analysisID,
+		TaskID:      flags.ceTaskID,
+	}
+
+	status, err := fetchGateStatus(cmd.Context(), client, flags, result)
+	if err != nil {
+		return err
+	}
+
+	result.Status = status.Status
+	result.Conditions = status.Conditions
+
+	err = writeGateReports(result, flags)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("output") {
+		err = writeResult(cmd, result, *format)
+	} else {
+		recordResult(cmd.Context(), result)
+		err = writeGateSummary(cmd.OutOrStdout(), result)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return gateExitError(result)
+}
+
+// fetchGateStatus returns the quality gate status to check. With a Compute Engine task,
+// it waits for the task and fills in the analysis, project and branch the task reports.
+func fetchGateStatus(ctx context.Context, client *sonar.Client, flags *gateCheckFlags, result *GateCheckResult) (*sonar.QualityGateProjectStatus, error) {
+	if flags.ceTaskID == "" {
+		status, _, err := client.Qualitygates.ProjectStatus(ctx, gateStatusOptions(result))
+		if err != nil {
+			Logger().Error("failed to get the quality gate status", zap.Error(err))
+
+			return nil, fmt.Errorf("failed to get the quality gate status: %w", err)
+		}
+
+		return &status.ProjectStatus, nil
+	}
+
+	waited, _, err := client.Qualitygates.WaitForQualityGate(ctx, &sonar.QualitygatesWaitForQualityGateOptions{
+		WaitOptions: sonar.WaitOptions{ //nolint:exhaustruct // the initial interval keeps its default
+			MaxInterval:    flags.pollInterval,
+			Timeout:        flags.waitTimeout,
+			FailOnWarnings: flags.failOnWarnings,
+		},
+		TaskID: flags.ceTaskID,
+	})
+	if err != nil {
+		Logger().Error("failed to wait for the analysis", zap.String("task", flags.ceTaskID), zap.Error(err))
+
+		err = fmt.Errorf("failed to wait for the analysis: %w", err)
+
+		// The wait timed out before --deadline, if any: exit as --deadline does.
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil, withExitCode(exitDeadlineExceeded, err)
+		}
+
+		return nil, err
+	}
+
+	result.AnalysisID = waited.Task.AnalysisID
+
+	if result.Project == "" {
+		result.Project = waited.Task.ComponentKey
+	}
+
+	if result.Branch == "" && result.PullRequest == "" {
+		result.Branch = waited.Task.Branch
+		result.PullRequest = waited.Task.PullRequest
+	}
+
+	return &waited.ProjectStatus, nil
+}
+
+// gateStatusOptions selects the analysis when one is known, and the project, branch or
+// pull request otherwise.
+func gateStatusOptions(result *GateCheckResult) *sonar.QualitygatesProjectStatusOptions {
+	if result.AnalysisID != "" {
+		return &sonar.QualitygatesProjectStatusOptions{AnalysisID: result.AnalysisID} //nolint:exhaustruct // the analysis identifies the gate status
+	}
+
+	return &sonar.QualitygatesProjectStatusOptions{ //nolint:exhaustruct // the project key identifies the gate status
+		ProjectKey:  result.Project,
+		Branch:      result.Branch,
+		PullRequest: result.PullRequest,
+	}
+}
+
+// gateExitError maps a quality gate status to the error, and exit status, of gate check.
+func gateExitError(result *GateCheckResult) error {
+	switch result.Status {
+	case sonar.QualityGateStatusOK, sonar.QualityGateStatusWarn:
+		return nil
+	case sonar.QualityGateStatusError:
+		return withExitCode(exitGateFailed, fmt.Errorf("%w for %s", errGateFailed, result.target()))
+	default:
+		return withExitCode(exitGateNone, fmt.Errorf("%w for %s", errGateNone, result.target()))
+	}
+}
+
+// writeGateSummary prints a human summary of the check and its failed conditions.
+func writeGateSummary(writer io.Writer, result *GateCheckResult) error {
+	var headline string
+
+	switch result.Status {
+	case sonar.QualityGateStatusOK, sonar.QualityGateStatusWarn:
+		headline = "Quality gate passed"
+	case sonar.QualityGateStatusError:
+		headline = "Quality gate failed"
+	default:
+		headline = "No quality gate computed"
+	}
+
+	var buf bytes.Buffer
+
+	_, _ = fmt.Fprintf(&buf, "%s (%s) for %s\n", headline, displayGateStatus(result.Status), result.target())
+
+	failed := result.failedConditions()
+	if len(failed) > 0 {
+		_, _ = fmt.Fprintf(&buf, "%d of %d conditions failed:\n", len(failed), len(result.Conditions))
+
+		table := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+		_, _ = fmt.Fprintln(table, "  METRIC\tFAILS WHEN\tACTUAL")
+
+		for _, condition := range failed {
+			_, _ = fmt.Fprintf(table, "  %s\t%s\t%s\n", condition.MetricKey, conditionThreshold(condition), condition.ActualValue)
+		}
+
+		_ = table.Flush()
+	}
+
+	_, err := writer.Write(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+
+	return nil
+}
+
+// displayGateStatus returns the status shown for a check, NONE when the server gave none.
+func displayGateStatus(status string) string {
+	if status == "" {
+		return sonar.QualityGateStatusNone
+	}
+
+	return status
+}
+
+// conditionThreshold renders the failing side of a condition, e.g. "< 80".
+func conditionThreshold(condition sonar.QualityGateConditionStatus) string {
+	symbol, ok := comparatorSymbols[condition.Comparator]
+	if !ok {
+		symbol = condition.Comparator
+	}
+
+	return strings.TrimSpace(symbol + " " + condition.ErrorThreshold)
+}
+
+// writeGateReports writes the JUnit and Markdown reports that were requested.
+func writeGateReports(result *GateCheckResult, flags *gateCheckFlags) error {
+	reports := []struct {
+		path   string
+		render func(*GateCheckResult) ([]byte, error)
+	}{
+		{path: flags.junit, render: renderGateJUnit},
+		{path: flags.markdown, render: renderGateMarkdown},
+	}
+
+	for _, report := range reports {
+		if report.path == "" {
+			continue
+		}
+
+		data, err := report.render(result)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(report.path, data, gateReportFileMode) //nolint:gosec // reports are meant to be read by CI tooling
+		if err != nil {
+			Logger().Error("failed to write report", zap.String("path", report.path), zap.Error(err))
+
+			return fmt.Errorf("failed to write report %q: %w", report.path, err)
+		}
+	}
+
+	return nil
+}
+
+// junitTestSuite is the root element of a JUnit XML report.
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is one quality gate condition in a JUnit XML report.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+// junitMessage is the failure or skipped element of a JUnit test case.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+// renderGateJUnit renders the check as a JUnit test suite with one test case per
+// condition. Without a computed gate, the suite holds a single skipped test case.
+func renderGateJUnit(result *GateCheckResult) ([]byte, error) {
+	suite := junitTestSuite{ //nolint:exhaustruct // counters and test cases are set below
+		Name: "Quality gate: " + result.target(),
+	}
+
+	className := "qualitygate." + result.Project
+
+	for _, condition := range result.Conditions {
+		testCase := junitTestCase{Name: condition.MetricKey, ClassName: className} //nolint:exhaustruct // failure is set below
+
+		if condition.Status == sonar.QualityGateStatusError {
+			testCase.Failure = &junitMessage{
+				Message: fmt.Sprintf("%s is %s, fails when %s", condition.MetricKey, condition.ActualValue, conditionThreshold(condition)),
+				Type:    condition.Status,
+			}
+			suite.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	if result.Status != sonar.QualityGateStatusOK && result.Status != sonar.QualityGateStatusWarn && result.Status != sonar.QualityGateStatusError {
+		suite.TestCases = append(suite.TestCases, junitTestCase{ //nolint:exhaustruct // a skipped test case has no failure
+			Name:      "quality gate",
+			ClassName: className,
+			Skipped:   &junitMessage{Message: "no quality gate computed", Type: ""},
+		})
+		suite.Skipped++
+	}
+
+	suite.Tests = len(suite.TestCases)
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render JUnit report: %w", err)
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// renderGateMarkdown renders the check as a Markdown summary with a table of conditions.
+func renderGateMarkdown(result *GateCheckResult) ([]byte, error) {
+	var buf bytes.Buffer
+
+	_, _ = fmt.Fprintf(&buf, "### Quality gate: %s\n\n", displayGateStatus(result.Status))
+	_, _ = fmt.Fprintf(&buf, "Checked %s.\n\n", result.target())
+
+	if len(result.Conditions) == 0 {
+		buf.WriteString("No conditions were evaluated.\n")
+
+		return buf.Bytes(), nil
+	}
+
+	buf.WriteString("| Metric | Fails when | Actual | Status |\n")
+	buf.WriteString("|--------|------------|--------|--------|\n")
+
+	for _, condition := range result.Conditions {
+		_, _ = fmt.Fprintf(&buf, "| %s | %s | %s | %s |\n",
+			condition.MetricKey, conditionThreshold(condition), condition.ActualValue, condition.Status)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failedGateStatus is a project status with one failed and one passed condition.
+const failedGateStatus = `{"projectStatus": {"status": "ERROR", "conditions": [
+	{"status": "ERROR", "metricKey": "new_coverage", "comparator": "LT", "errorThreshold": "80", "actualValue": "45.2"},
+	{"status": "OK", "metricKey": "new_violations", "comparator": "GT", "errorThreshold": "0", "actualValue": "0"}
+]}}`
+
+// runGateCLI runs "gate check" with a test client and returns its output.
+func runGateCLI(t *testing.T, handler http.HandlerFunc, args ...string) (string, error) {
+	t.Helper()
+
+	client := newTestClient(t, handler)
+
+	var out bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().VarP(&outputFormatFlag{target: &format}, "output", "o", "")
+	rootCmd.AddCommand(newGateCommand(&format))
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"gate", "check"}, args...))
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+
+	return out.String(), err
+}
+
+// TestGateCheck_ExitCodes tests the summary and exit status for each gate status.
+func TestGateCheck_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		code     int
+		contains string
+	}{
+		{name: "ok", response: `{"projectStatus": {"status": "OK"}}`, code: 0, contains: "Quality gate passed (OK) for project my-app, branch main\n"},
+		{name: "error", response: failedGateStatus, code: exitGateFailed, contains: "1 of 2 conditions failed:\n  METRIC        FAILS WHEN  ACTUAL\n  new_coverage  < 80        45.2\n"},
+		{name: "none", response: `{"projectStatus": {"status": "NONE"}}`, code: exitGateNone, contains: "No quality gate computed (NONE)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := runGateCLI(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/qualitygates/project_status", r.URL.Path)
+				assert.Equal(t, "my-app", r.URL.Query().Get("projectKey"))
+				assert.Equal(t, "main", r.URL.Query().Get("branch"))
+
+				_, _ = w.Write([]byte(tc.response))
+			}, "--project", "my-app", "--branch", "main")
+
+			assert.Equal(t, tc.code, ExitCode(err))
+			assert.Contains(t, out, tc.contains)
+		})
+	}
+}
+
+// TestGateCheck_WaitsForTask tests waiting for the CE task and checking its analysis.
+func TestGateCheck_WaitsForTask(t *testing.T) {
+	polls := 0
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/ce/task":
+			polls++
+
+			status := "IN_PROGRESS"
+			if polls == 2 {
+				status = "SUCCESS"
+			}
+
+			_, _ = w.Write([]byte(`{"task": {"id": "T1", "status": "` + status + `", "analysisId": "A1", "componentKey": "my-app", "pullRequest": "42"}}`))
+		case "/api/qualitygates/project_status":
+			assert.Equal(t, "A1", r.URL.Query().Get("analysisId"))
+
+			_, _ = w.Write([]byte(`{"projectStatus": {"status": "OK"}}`))
+		}
+	}
+
+	out, err := runGateCLI(t, handler, "--ce-task-id", "T1", "--poll-interval", "1ms", "-o", "json")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, polls)
+	assert.JSONEq(t, `{"status": "OK", "project": "my-app", "pullRequest": "42", "analysisId": "A1", "taskId": "T1", "conditions": null}`, out)
+}
+
+// TestGateCheck_TaskFailures tests failed tasks, analysis warnings and wait timeouts.
+func TestGateCheck_TaskFailures(t *testing.T) {
+	_, err := runGateCLI(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"task": {"id": "T1", "status": "FAILED", "errorMessage": "boom"}}`))
+	}, "--ce-task-id", "T1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ended with status FAILED: boom")
+	assert.Equal(t, exitFailure, ExitCode(err))
+
+	_, err = runGateCLI(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"task": {"id": "T1", "status": "SUCCESS", "warningCount": 2}}`))
+	}, "--ce-task-id", "T1", "--fail-on-warnings")
+	require.ErrorIs(t, err, sonar.ErrTaskWarnings)
+
+	_, err = runGateCLI(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"task": {"id": "T1", "status": "PENDING"}}`))
+	}, "--ce-task-id", "T1", "--poll-interval", "1ms", "--wait-timeout", "20ms")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, exitDeadlineExceeded, ExitCode(err))
+}
+
+// TestGateCheck_APIErrors tests that a request failing during the check exits with the
+// status of the API error, not the status of a failed gate.
+func TestGateCheck_APIErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		code   int
+	}{
+		{name: "unauthorized", status: http.StatusUnauthorized, code: exitUnauthorized},
+		{name: "not found", status: http.StatusNotFound, code: exitNotFound},
+		{name: "server error", status: http.StatusInternalServerError, code: exitServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := runGateCLI(t, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(`{"errors": [{"msg": "failed"}]}`))
+			}, "--project", "my-app")
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, errGateFailed)
+			assert.Equal(t, tc.code, ExitCode(err))
+			assert.NotEqual(t, exitGateFailed, ExitCode(err))
+		})
+	}
+}
+
+// TestGateCheck_Reports tests the JUnit and Markdown report files.
+func TestGateCheck_Reports(t *testing.T) {
+	dir := t.TempDir()
+	junit := filepath.Join(dir, "gate.xml")
+	markdown := filepath.Join(dir, "gate.md")
+
+	_, err := runGateCLI(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(failedGateStatus))
+	}, "--project", "my-app", "--junit", junit, "--markdown", markdown)
+	require.ErrorIs(t, err, errGateFailed)
+
+	data, err := os.ReadFile(junit)
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="Quality gate: project my-app" tests="2" failures="1" skipped="0">
+  <testcase name="new_coverage" classname="qualitygate.my-app">
+    <failure message="new_coverage is 45.2, fails when &lt; 80" type="ERROR"></failure>
+  </testcase>
+  <testcase name="new_violations" classname="qualitygate.my-app"></testcase>
+</testsuite>
+`, string(data))
+
+	data, err = os.ReadFile(markdown)
+	require.NoError(t, err)
+	assert.Equal(t, `### Quality gate: ERROR
+
+Checked project my-app.
+
+| Metric | Fails when | Actual | Status |
+|--------|------------|--------|--------|
+| new_coverage | < 80 | 45.2 | ERROR |
+| new_violations | > 0 | 0 | OK |
+`, string(data))
+}
+
+// TestRenderGateJUnit_None tests the JUnit report of a project without a gate status.
+func TestRenderGateJUnit_None(t *testing.T) {
+	data, err := renderGateJUnit(&GateCheckResult{Status: sonar.QualityGateStatusNone, Project: "p"})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `tests="1" failures="0" skipped="1"`)
+	assert.Contains(t, string(data), `<skipped message="no quality gate computed"></skipped>`)
+}
+
+// TestExitCode tests exit statuses derived from errors.
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, exitFailure, ExitCode(errors.New("boom")))
+	assert.Equal(t, exitGateNone, ExitCode(withExitCode(exitGateNone, errGateNone)))
+	assert.Equal(t, exitGateNone, ExitCode(errors.Join(errors.New("context"), withExitCode(exitGateNone, errGateNone))))
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// deadlineFlag is the global flag bounding the run time of a whole command.
+	deadlineFlag = "deadline"
+	// sessionAnnotation marks commands that run other commands, such as the shell, which
+	// pass --deadline on to each command instead of applying it to themselves.
+	sessionAnnotation = "sonar-cli_session"
+)
+
+var (
+	// errSignaled is the cause of a command context canceled by SIGINT or SIGTERM.
+	errSignaled = errors.New("interrupted by signal")
+	// errDeadlineExceeded is the cause of a command context canceled by --deadline.
+	errDeadlineExceeded = errors.New("command deadline exceeded")
+)
+
+// signalError is the cause of a command context canceled by a signal.
+type signalError struct {
+	signal os.Signal
+}
+
+// Error names the signal.
+func (e *signalError) Error() string {
+	return fmt.Sprintf("%s %s", errSignaled, e.signal)
+}
+
+// Unwrap returns errSignaled.
+func (e *signalError) Unwrap() error {
+	return errSignaled
+}
+
+// notifyContext returns a context canceled with errSignaled on the first SIGINT or SIGTERM,
+// and a function releasing the signals. After the first signal, the signals get their
+// default behavior back, so that a second Ctrl-C stops a command that is slow to wind down.
+func notifyContext(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			cancel(&signalError{signal: sig})
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel(nil)
+	}
+}
+
+// applyDeadline bounds the command context by --deadline, so that every request and page
+// of the command shares one time budget, unlike --timeout which applies per HTTP request.
+func applyDeadline(cmd *cobra.Command, deadline time.Duration) {
+	if deadline <= 0 || cmd.Annotations[sessionAnnotation] != "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeoutCause(cmd.Context(), deadline, fmt.Errorf("%w after %s", errDeadlineExceeded, deadline))
+
+	// Release the timer with the command context, which ends when the command returns.
+	context.AfterFunc(cmd.Context(), cancel)
+
+	cmd.SetContext(ctx)
+}
+
+// interruptionError explains an error of a command whose context ended, naming the signal
+// or deadline that ended it and setting the matching exit status. Other errors are
+// returned unchanged.
+func interruptionError(ctx context.Context, err error) error {
+	if err == nil || ctx == nil || ctx.Err() == nil {
+		return err
+	}
+
+	cause := context.Cause(ctx)
+
+	var code int
+
+	var signaled *signalError
+
+	switch {
+	case errors.As(cause, &signaled) && signaled.signal == syscall.SIGTERM:
+		code = exitTerminated
+	case errors.Is(cause, errSignaled):
+		code = exitInterrupted
+	case errors.Is(cause, errDeadlineExceeded):
+		code = exitDeadlineExceeded
+	default:
+		return err
+	}
+
+	// Requests report the cause themselves; other errors get it prepended.
+	if !errors.Is(err, cause) {
+		err = fmt.Errorf("%w: %w", cause, err)
+	}
+
+	return withExitCode(code, err)
+}
+
+// isInterrupted reports whether a command's context has ended, making its output partial.
+func isInterrupted(cmd *cobra.Command) bool {
+	return cmd.Context() != nil && cmd.Context().Err() != nil
+}
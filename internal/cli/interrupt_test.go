@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// interruptedService mocks a paginated method whose context ends during the second page.
+type interruptedService struct {
+	cancel context.CancelFunc
+}
+
+// Search returns the first page, then cancels the context and fails like an aborted request.
+func (s *interruptedService) Search(ctx context.Context, opt *paginatedOptions) (*paginatedResponse, *http.Response, error) {
+	if opt.Page == 1 {
+		return &paginatedResponse{
+			Items:  []fakeResponse{{Name: "a"}, {Name: "b"}},
+			Paging: testPaging{PageIndex: 1, PageSize: 2, Total: 6},
+		}, nil, nil
+	}
+
+	s.cancel()
+
+	return nil, nil, fmt.Errorf("request failed: %w", ctx.Err())
+}
+
+// TestPaginateAll_Interrupted tests that the pages fetched before cancellation are returned.
+func TestPaginateAll_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	optValue := reflect.ValueOf(&paginatedOptions{Query: "test"})
+
+	result, err := PaginateAll(ctx, reflect.ValueOf(&interruptedService{cancel: cancel}), "Search",
+		optValue, PatternResponseBody, reflect.TypeOf(&paginatedResponse{}))
+	require.ErrorIs(t, err, context.Canceled)
+
+	resp, ok := result.(*paginatedResponse)
+	require.True(t, ok)
+	assert.Equal(t, []fakeResponse{{Name: "a"}, {Name: "b"}}, resp.Items)
+}
+
+// TestDeadline tests that --deadline stops a command and flushes the pages fetched so far.
+func TestDeadline(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("p") != "1" {
+			<-r.Context().Done()
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"paging": {"pageIndex": 1, "pageSize": 500, "total": 1000}, "issues": [{"key": "first"}]}`))
+	})
+
+	var out bytes.Buffer
+
+	rootCmd := newCommandTree()
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--deadline", "200ms", "issues", "search", "--all"})
+
+	cmd, err := rootCmd.ExecuteContextC(context.WithValue(t.Context(), clientContextKey, client))
+	err = interruptionError(cmd.Context(), err)
+	require.ErrorIs(t, err, errDeadlineExceeded)
+	assert.Equal(t, exitDeadlineExceeded, ExitCode(err))
+	assert.Contains(t, out.String(), `"key": "first"`)
+}
+
+// TestInterruptionError tests the exit status of commands ended by a signal or deadline.
+func TestInterruptionError(t *testing.T) {
+	failure := errors.New("request failed")
+
+	assert.Equal(t, failure, interruptionError(t.Context(), failure))
+	assert.NoError(t, interruptionError(t.Context(), nil))
+
+	ctx, cancel := context.WithCancelCause(t.Context())
+	cancel(fmt.Errorf("%w interrupt", errSignaled))
+
+	err := interruptionError(ctx, failure)
+	require.ErrorIs(t, err, failure)
+	assert.Equal(t, exitInterrupted, ExitCode(err))
+	assert.Equal(t, "interrupted by signal interrupt: request failed", err.Error())
+
+	ctx, cancel = context.WithCancelCause(t.Context())
+	cancel(&signalError{signal: syscall.SIGTERM})
+
+	err = interruptionError(ctx, failure)
+	require.ErrorIs(t, err, errSignaled)
+	assert.Equal(t, exitTerminated, ExitCode(err))
+	assert.Equal(t, "interrupted by signal terminated: request failed", err.Error())
+
+	ctx, cancel = context.WithCancelCause(t.Context())
+	cancel(errors.New("other"))
+	assert.Equal(t, failure, interruptionError(ctx, failure))
+}
@@ -66,15 +66,15 @@ func ClassifyMethod(method reflect.Method) MethodReturnPattern {
 
 // InvokeMethod calls a service method via reflection and returns the result.
 // It handles all four return patterns, extracting the response value and error.
-// context.Background() is automatically prepended when the method's first parameter
-// is context.Context.
-func InvokeMethod(service reflect.Value, methodName string, opt reflect.Value, pattern MethodReturnPattern, hasOpt bool) (any, *http.Response, error) {
+// ctx is passed as the method's first, context.Context parameter, so that canceling it
+// aborts the request.
+func InvokeMethod(ctx context.Context, service reflect.Value, methodName string, opt reflect.Value, pattern MethodReturnPattern, hasOpt bool) (any, *http.Response, error) {
 	method := service.MethodByName(methodName)
 	if !method.IsValid() {
 		return nil, nil, fmt.Errorf("method %q not found on service", methodName)
 	}
 
-	ctxVal := reflect.ValueOf(context.Background())
+	ctxVal := reflect.ValueOf(ctx)
 
 	var results []reflect.Value
 
@@ -102,14 +102,14 @@ func InvokeMethod(service reflect.Value, methodName string, opt reflect.Value, p
 
 // InvokeStreamingMethod calls a streaming service method (like Push.SonarlintEvents)
 // and pipes the response body to the writer. The response body is left open for streaming.
-// context.Background() is automatically prepended as the first argument.
-func InvokeStreamingMethod(service reflect.Value, methodName string, opt reflect.Value, writer io.Writer) error {
+// ctx is passed as the first argument, so that canceling it ends the stream.
+func InvokeStreamingMethod(ctx context.Context, service reflect.Value, methodName string, opt reflect.Value, writer io.Writer) error {
 	method := service.MethodByName(methodName)
 	if !method.IsValid() {
 		return fmt.Errorf("method %q not found on service", methodName)
 	}
 
-	ctxVal := reflect.ValueOf(context.Background())
+	ctxVal := reflect.ValueOf(ctx)
 
 	results := method.Call([]reflect.Value{ctxVal, opt})
 
@@ -83,7 +83,7 @@ func TestInvokeMethod_ResponseBody(t *testing.T) {
 	svc := reflect.ValueOf(&fakeService{})
 	opt := reflect.New(reflect.TypeOf(struct{}{}))
 
-	result, _, err := InvokeMethod(svc, "ResponseBodyMethod", opt, PatternResponseBody, true)
+	result, _, err := InvokeMethod(t.Context(), svc, "ResponseBodyMethod", opt, PatternResponseBody, true)
 	require.NoError(t, err)
 
 	resp, ok := result.(*fakeResponse)
@@ -96,7 +96,7 @@ func TestInvokeMethod_NoBody(t *testing.T) {
 	svc := reflect.ValueOf(&fakeService{})
 	opt := reflect.Value{}
 
-	result, _, err := InvokeMethod(svc, "NoBodyMethod", opt, PatternNoBody, false)
+	result, _, err := InvokeMethod(t.Context(), svc, "NoBodyMethod", opt, PatternNoBody, false)
 	require.NoError(t, err)
 	assert.Nil(t, result)
 }
@@ -106,7 +106,7 @@ func TestInvokeMethod_RawBytes(t *testing.T) {
 	svc := reflect.ValueOf(&fakeService{})
 	opt := reflect.New(reflect.TypeOf(struct{}{}))
 
-	result, _, err := InvokeMethod(svc, "RawBytesMethod", opt, PatternRawBytes, true)
+	result, _, err := InvokeMethod(t.Context(), svc, "RawBytesMethod", opt, PatternRawBytes, true)
 	require.NoError(t, err)
 	assert.Equal(t, []byte("hello"), result)
 }
@@ -116,7 +116,7 @@ func TestInvokeMethod_RawString(t *testing.T) {
 	svc := reflect.ValueOf(&fakeService{})
 	opt := reflect.New(reflect.TypeOf(struct{}{}))
 
-	result, _, err := InvokeMethod(svc, "RawStringMethod", opt, PatternRawString, true)
+	result, _, err := InvokeMethod(t.Context(), svc, "RawStringMethod", opt, PatternRawString, true)
 	require.NoError(t, err)
 
 	s, ok := result.(*string)
@@ -129,7 +129,7 @@ func TestInvokeMethod_Slice(t *testing.T) {
 	svc := reflect.ValueOf(&fakeService{})
 	opt := reflect.New(reflect.TypeOf(struct{}{}))
 
-	result, _, err := InvokeMethod(svc, "SliceMethod", opt, PatternSlice, true)
+	result, _, err := InvokeMethod(t.Context(), svc, "SliceMethod", opt, PatternSlice, true)
 	require.NoError(t, err)
 
 	items, ok := result.([]fakeResponse)
@@ -143,7 +143,7 @@ func TestInvokeMethod_Error(t *testing.T) {
 	svc := reflect.ValueOf(&fakeService{})
 	opt := reflect.New(reflect.TypeOf(struct{}{}))
 
-	_, _, err := InvokeMethod(svc, "ErrorMethod", opt, PatternResponseBody, true)
+	_, _, err := InvokeMethod(t.Context(), svc, "ErrorMethod", opt, PatternResponseBody, true)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "something failed")
 }
@@ -153,7 +153,7 @@ func TestInvokeMethod_NotFound(t *testing.T) {
 	svc := reflect.ValueOf(&fakeService{})
 	opt := reflect.Value{}
 
-	_, _, err := InvokeMethod(svc, "DoesNotExist", opt, PatternNoBody, false)
+	_, _, err := InvokeMethod(t.Context(), svc, "DoesNotExist", opt, PatternNoBody, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
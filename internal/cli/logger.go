@@ -9,10 +9,13 @@ import (
 
 var globalLogger *zap.Logger //nolint:gochecknoglobals // global logger is a standard pattern
 
+// logLevel is the level of the global logger, raised to silence it with --error-format json.
+var logLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel) //nolint:gochecknoglobals // shared with the global logger
+
 // initLogger creates a development-friendly logger suitable for CLI output.
 func initLogger() {
 	config := zap.NewDevelopmentConfig()
-	config.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	config.Level = logLevel
 
 	// Write to stderr instead of stdout
 	config.OutputPaths = []string{"stderr"}
@@ -0,0 +1,341 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar/access"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	// matrixFormatCSV writes the permission matrix as CSV.
+	matrixFormatCSV = "csv"
+	// matrixFormatJSON writes the permission matrix as JSON.
+	matrixFormatJSON = "json"
+
+	// defaultMatrixParallel is the default number of projects read concurrently.
+	defaultMatrixParallel = 4
+	// matrixFileMode is the permission of the permission matrix file.
+	matrixFileMode = 0o644
+)
+
+// errPermissionChanges is returned by permissions matrix --detailed-exitcode when
+// permissions were granted or revoked since the previous matrix.
+var errPermissionChanges = errors.New("permissions changed since the previous matrix")
+
+// PermissionMatrixDiff is the result of the permissions matrix command given --previous.
+type PermissionMatrixDiff struct {
+	// Previous is the file of the previous matrix.
+	Previous string `json:"previous"`
+	// Granted is the number of permissions granted since the previous matrix.
+	Granted int `json:"granted"`
+	// Revoked is the number of permissions revoked since the previous matrix.
+	Revoked int `json:"revoked"`
+	// Changes are the permissions granted and revoked.
+	Changes []access.Change `json:"changes"`
+}
+
+// matrixFlags holds the flags of the permissions matrix command.
+type matrixFlags struct {
+	projects         []string
+	permissions      []string
+	format           string
+	file             string
+	previous         string
+	parallel         int
+	detailedExitCode bool
+}
+
+// newMatrixCommand creates the permissions matrix command.
+func newMatrixCommand(format *OutputFormat) *cobra.Command {
+	flags := &matrixFlags{} //nolint:exhaustruct // fields are set by Cobra flag binding
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the matrix command
+		Use:   "matrix [--project <key>]... [--permission <permission>]... [--file <path>] [--previous <path>]",
+		Short: "Report the effective permissions of every user on every project",
+		Long: `Read the global permissions and the permissions of every project, or of the
+--project ones, and report them for each user: the members of the groups the
+permissions are granted to are listed, with the group a permission comes from.
+Permissions of the Anyone group and of public projects are reported for the user *.
+
+The matrix is written as CSV, or JSON with --format json, to --file or to the
+standard output. Given the file of a previous run with --previous, the permissions
+granted and revoked since are printed instead, and the current matrix is still
+written to --file. With --detailed-exitcode, the command exits with status 2 when
+permissions changed.`,
+		Example: `  sonar-cli permissions matrix --permission admin --file admins.csv
+  sonar-cli permissions matrix --previous last-quarter.csv --file this-quarter.csv
+  sonar-cli permissions matrix --project app --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			diff, err := runMatrix(cmd, flags, *format)
+			if err != nil {
+				return err
+			}
+
+			if flags.detailedExitCode && diff != nil && len(diff.Changes) > 0 {
+				return withExitCode(exitPlanChanges, errPermissionChanges)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&flags.projects, "project", nil, "Project to report instead of every project (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.permissions, "permission", nil, "Permission to report, such as admin (repeatable)")
+	cmd.Flags().StringVar(&flags.format, "format", matrixFormatCSV, "Format of the matrix: csv or json")
+	cmd.Flags().StringVar(&flags.file, "file", "", "Write the matrix to this file")
+	cmd.Flags().StringVar(&flags.previous, "previous", "", "Matrix of a previous run to report the changes since")
+	cmd.Flags().IntVar(&flags.parallel, "parallel", defaultMatrixParallel, "Number of projects read concurrently")
+	cmd.Flags().BoolVar(&flags.detailedExitCode, "detailed-exitcode", false, "Exit with status 2 when permissions changed since --previous")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{matrixFormatCSV, matrixFormatJSON}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// runMatrix builds the permission matrix and writes it, or the changes since the
+// previous matrix, which it returns.
+func runMatrix(cmd *cobra.Command, flags *matrixFlags, format OutputFormat) (*PermissionMatrixDiff, error) {
+	err := validateMatrixFlags(flags)
+	if err != nil {
+		Logger().Error("invalid permission matrix flags", zap.Error(err))
+
+		return nil, err
+	}
+
+	var previous *access.Matrix
+
+	if flags.previous != "" {
+		previous, err = readMatrix(flags.previous)
+		if err != nil {
+			Logger().Error("failed to read the previous permission matrix", zap.String("file", flags.previous), zap.Error(err))
+
+			return nil, err
+		}
+
+		filterMatrix(previous, flags.permissions)
+	}
+
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return nil, err
+	}
+
+	matrix, err := access.Build(cmd.Context(), client, access.Options{Projects: flags.projects, Parallel: flags.parallel})
+	if err != nil {
+		Logger().Error("failed to build the permission matrix", zap.Error(err))
+
+		return nil, err
+	}
+
+	filterMatrix(matrix, flags.permissions)
+
+	if flags.file != "" {
+		err = writeMatrixFile(flags.file, matrix, flags.format)
+		if err != nil {
+			Logger().Error("failed to write the permission matrix", zap.String("file", flags.file), zap.Error(err))
+
+			return nil, err
+		}
+	}
+
+	if previous == nil {
+		return nil, outputMatrix(cmd, matrix, flags, format)
+	}
+
+	diff := comparedMatrix(flags.previous, previous, matrix)
+
+	if cmd.Flags().Changed("output") {
+		return diff, writeResult(cmd, diff, format)
+	}
+
+	recordResult(cmd.Context(), diff)
+	writeMatrixDiff(cmd.OutOrStdout(), diff)
+
+	return diff, nil
+}
+
+// validateMatrixFlags checks the format and the parallelism.
+func validateMatrixFlags(flags *matrixFlags) error {
+	if flags.format != matrixFormatCSV && flags.format != matrixFormatJSON {
+		return fmt.Errorf("%w %q for --format: must be one of %s, %s", errInvalidFlagValue, flags.format, matrixFormatCSV, matrixFormatJSON)
+	}
+
+	if flags.parallel < 1 {
+		return fmt.Errorf("%w %d for --parallel: must be at least 1", errInvalidFlagValue, flags.parallel)
+	}
+
+	return nil
+}
+
+// outputMatrix writes the matrix to the standard output, unless it was written to
+// --file and no --output format is requested.
+func outputMatrix(cmd *cobra.Command, matrix *access.Matrix, flags *matrixFlags, format OutputFormat) error {
+	if cmd.Flags().Changed("output") {
+		return writeResult(cmd, matrix, format)
+	}
+
+	recordResult(cmd.Context(), matrix)
+
+	if flags.file != "" {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d permissions to %s.\n", len(matrix.Entries), flags.file)
+
+		return nil
+	}
+
+	err := encodeMatrix(cmd.OutOrStdout(), matrix, flags.format)
+	if err != nil {
+		Logger().Error("failed to print the permission matrix", zap.Error(err))
+
+		return err
+	}
+
+	return nil
+}
+
+// filterMatrix keeps the entries of the given permissions, or every entry when none
+// is given.
+func filterMatrix(matrix *access.Matrix, permissions []string) {
+	if len(permissions) == 0 {
+		return
+	}
+
+	matrix.Entries = slices.DeleteFunc(matrix.Entries, func(entry access.Entry) bool {
+		return !slices.Contains(permissions, entry.Permission)
+	})
+}
+
+// comparedMatrix returns the changes from the previous matrix to the current one.
+func comparedMatrix(file string, previous, current *access.Matrix) *PermissionMatrixDiff {
+	diff := &PermissionMatrixDiff{
+		Previous: file,
+		Granted:  0,
+		Revoked:  0,
+		Changes:  access.Compare(previous, current),
+	}
+
+	if diff.Changes == nil {
+		diff.Changes = []access.Change{}
+	}
+
+	for _, change := range diff.Changes {
+		if change.Type == access.ChangeGranted {
+			diff.Granted++
+		} else {
+			diff.Revoked++
+		}
+	}
+
+	return diff
+}
+
+// readMatrix reads a matrix file: JSON when its extension is .json, CSV otherwise.
+func readMatrix(path string) (*access.Matrix, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // reading a user-supplied file is the purpose of --previous
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		matrix, err := access.ReadCSV(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		return matrix, nil
+	}
+
+	var matrix access.Matrix
+
+	err = json.Unmarshal(data, &matrix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return &matrix, nil
+}
+
+// writeMatrixFile writes the matrix to a file in the given format.
+func writeMatrixFile(path string, matrix *access.Matrix, matrixFormat string) error {
+	var data bytes.Buffer
+
+	err := encodeMatrix(&data, matrix, matrixFormat)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, data.Bytes(), matrixFileMode) //nolint:gosec // the matrix is meant to be read by auditors
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// encodeMatrix writes the matrix as CSV or as indented JSON.
+func encodeMatrix(writer io.Writer, matrix *access.Matrix, matrixFormat string) error {
+	if matrixFormat == matrixFormatCSV {
+		return matrix.WriteCSV(writer)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(matrix)
+	if err != nil {
+		return fmt.Errorf("failed to write the permission matrix: %w", err)
+	}
+
+	return nil
+}
+
+// writeMatrixDiff prints the permissions granted (+) and revoked (-) and a summary.
+func writeMatrixDiff(writer io.Writer, diff *PermissionMatrixDiff) {
+	if len(diff.Changes) > 0 {
+		table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+		_, _ = fmt.Fprintln(table, "\tUSER\tPROJECT\tPERMISSION\tGROUP")
+
+		for _, change := range diff.Changes {
+			sign := "+"
+			if change.Type == access.ChangeRevoked {
+				sign = "-"
+			}
+
+			_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", sign, change.User,
+				cmp.Or(change.Project, "(global)"), change.Permission, matrixSource(change.Entry))
+		}
+
+		_ = table.Flush()
+	}
+
+	_, _ = fmt.Fprintf(writer, "%d permissions granted, %d revoked since %s.\n", diff.Granted, diff.Revoked, diff.Previous)
+}
+
+// matrixSource describes where the permission of an entry comes from: its group, the
+// visibility of a public project or a direct grant.
+func matrixSource(entry access.Entry) string {
+	switch {
+	case entry.Group != "":
+		return entry.Group
+	case entry.User == access.Everyone:
+		return "(public)"
+	default:
+		return "(direct)"
+	}
+}
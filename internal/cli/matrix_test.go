@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/testutil"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// matrixResponses are the responses of the permission matrix test server, by path and
+// project or group: root administers the instance, and developers browse app.
+//
+//nolint:gochecknoglobals // test fixture
+var matrixResponses = map[string]string{
+	"projects/search":                  `{"paging": {"total": 1}, "components": [{"key": "app", "visibility": "private"}]}`,
+	"permissions/users":                `{"paging": {"total": 1}, "users": [{"login": "root", "permissions": ["admin"]}]}`,
+	"permissions/groups":               `{"paging": {"total": 0}, "groups": []}`,
+	"permissions/users?projectKey=app": `{"paging": {"total": 0}, "users": []}`,
+	"permissions/groups?projectKey=app": `{"paging": {"total": 1}, "groups": [
+		{"name": "developers", "permissions": ["user"]}]}`,
+	"user_groups/users?name=developers": `{"paging": {"total": 2}, "users": [{"login": "alice"}, {"login": "bob"}]}`,
+}
+
+// runMatrixCLI runs permissions matrix against the permission matrix test server, and
+// returns its output.
+func runMatrixCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	client := newTestClient(t, testutil.Handler(matrixResponses, "projectKey", "name"))
+
+	var out bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().VarP(&outputFormatFlag{target: &format}, "output", "o", "")
+	rootCmd.AddCommand(&cobra.Command{Use: "permissions"}) //nolint:exhaustruct // stands for the generated command
+	addServiceSubcommand(rootCmd, "permissions", newMatrixCommand(&format))
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"permissions", "matrix"}, args...))
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+
+	return out.String(), err
+}
+
+// TestMatrix_CSV tests that the matrix lists the members of the groups permissions
+// are granted to, and can be restricted to some permissions.
+func TestMatrix_CSV(t *testing.T) {
+	out, err := runMatrixCLI(t)
+	require.NoError(t, err)
+	assert.Equal(t, "user,project,permission,group\nalice,app,user,developers\nbob,app,user,developers\nroot,,admin,\n", out)
+
+	out, err = runMatrixCLI(t, "--permission", "admin", "--format", "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"entries": [{"user": "root", "permission": "admin"}]}`, out)
+}
+
+// TestMatrix_Previous tests that the changes since a previous matrix are reported, and
+// that the current matrix is written to --file.
+func TestMatrix_Previous(t *testing.T) {
+	dir := t.TempDir()
+	previous := filepath.Join(dir, "previous.csv")
+	current := filepath.Join(dir, "current.json")
+
+	require.NoError(t, os.WriteFile(previous, []byte("user,project,permission,group\nalice,app,user,developers\ncarol,app,admin,\n"), 0o600))
+
+	out, err := runMatrixCLI(t, "--previous", previous, "--file", current, "--format", "json", "--detailed-exitcode")
+	require.ErrorIs(t, err, errPermissionChanges)
+	assert.Equal(t, exitPlanChanges, ExitCode(err))
+	assert.Equal(t, `   USER   PROJECT   PERMISSION  GROUP
++  bob    app       user        developers
+-  carol  app       admin       (direct)
++  root   (global)  admin       (direct)
+2 permissions granted, 1 revoked since `+previous+".\n", out)
+
+	out, err = runMatrixCLI(t, "--previous", current, "--detailed-exitcode", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"changes": []`)
+}
+
+// TestMatrix_InvalidFlags tests that an unknown format or a malformed previous matrix
+// is rejected.
+func TestMatrix_InvalidFlags(t *testing.T) {
+	_, err := runMatrixCLI(t, "--format", "xlsx")
+	require.ErrorIs(t, err, errInvalidFlagValue)
+	assert.Equal(t, exitValidation, ExitCode(err))
+
+	previous := filepath.Join(t.TempDir(), "previous.csv")
+	require.NoError(t, os.WriteFile(previous, []byte("login,permission\n"), 0o600))
+
+	_, err = runMatrixCLI(t, "--previous", previous)
+	require.ErrorContains(t, err, "invalid permission matrix CSV")
+}
@@ -20,6 +20,8 @@ const (
 	OutputTable OutputFormat = "table"
 	// OutputYAML formats output as YAML.
 	OutputYAML OutputFormat = "yaml"
+	// OutputWide formats output as an ASCII table showing every field.
+	OutputWide OutputFormat = "wide"
 
 	// tablePadding is the padding added to each column in table output.
 	tablePadding = 2
@@ -28,6 +30,12 @@ const (
 // FormatOutput writes the given value to the writer in the specified format.
 // It handles nil values, raw bytes, raw strings, and struct/slice types.
 func FormatOutput(writer io.Writer, val any, format OutputFormat) error {
+	return FormatOutputWithOptions(writer, val, format, TableOptions{}) //nolint:exhaustruct // default table rendering
+}
+
+// FormatOutputWithOptions writes the given value to the writer in the specified format,
+// applying the table options (columns, sorting, headers) to table and wide output.
+func FormatOutputWithOptions(writer io.Writer, val any, format OutputFormat, opts TableOptions) error {
 	if val == nil {
 		return nil
 	}
@@ -43,7 +51,11 @@ func FormatOutput(writer io.Writer, val any, format OutputFormat) error {
 	case OutputYAML:
 		return formatYAML(writer, val)
 	case OutputTable:
-		return formatTable(writer, val)
+		return formatTable(writer, val, opts)
+	case OutputWide:
+		opts.Wide = true
+
+		return formatTable(writer, val, opts)
 	default:
 		return formatJSON(writer, val)
 	}
@@ -104,9 +116,9 @@ func formatYAML(writer io.Writer, data any) error {
 }
 
 // formatTable outputs the value as an ASCII table.
-// For slices of structs, each struct field becomes a column.
+// For slices of structs, each selected struct field becomes a column.
 // For single structs, output as a key-value table.
-func formatTable(writer io.Writer, data any) error {
+func formatTable(writer io.Writer, data any, opts TableOptions) error {
 	rval := reflect.ValueOf(data)
 
 	// Dereference pointers.
@@ -121,9 +133,9 @@ func formatTable(writer io.Writer, data any) error {
 	//nolint:exhaustive // only struct and slice are renderable as tables
 	switch rval.Kind() {
 	case reflect.Slice:
-		return formatSliceTable(writer, rval)
+		return formatSliceTable(writer, rval, opts)
 	case reflect.Struct:
-		return formatStructTable(writer, rval)
+		return formatStructTable(writer, rval, opts)
 	default:
 		// Fallback to JSON for unsupported types.
 		return formatJSON(writer, data)
@@ -131,7 +143,8 @@ func formatTable(writer io.Writer, data any) error {
 }
 
 // formatSliceTable formats a slice of structs as a table with columns.
-func formatSliceTable(writer io.Writer, sliceVal reflect.Value) error {
+// Columns come from opts (or the type's default view), and rows are sorted by opts.SortBy.
+func formatSliceTable(writer io.Writer, sliceVal reflect.Value, opts TableOptions) error {
 	if sliceVal.Len() == 0 {
 		_, err := fmt.Fprintln(writer, "(no results)")
 		if err != nil {
@@ -151,40 +164,34 @@ func formatSliceTable(writer io.Writer, sliceVal reflect.Value) error {
 		return formatJSON(writer, sliceVal.Interface())
 	}
 
-	// Extract headers from struct fields.
-	headers := extractHeaders(elemType)
-
-	// Extract rows.
-	rows := make([][]string, sliceVal.Len())
-	for rowIdx := range sliceVal.Len() {
-		elem := sliceVal.Index(rowIdx)
-
-		for elem.Kind() == reflect.Pointer {
-			elem = elem.Elem()
-		}
-
-		rows[rowIdx] = extractRow(elem, elemType)
-	}
+	columns := tableColumns(elemType, opts)
+	rows := buildTableRows(sliceVal, columns)
+	sortTableRows(sliceVal, rows, opts.SortBy)
 
-	renderTable(writer, headers, rows)
+	renderTableWithOptions(writer, columnHeaders(columns), rows, opts.NoHeaders)
 
 	return nil
 }
 
 // formatStructTable formats a struct as a table.
-// If the struct contains an exported slice-of-structs field (typical of paginated API responses
+// Response types with a default table view render the slice pinned by that view.
+// Otherwise, if the struct contains an exported slice-of-structs field (typical of paginated API responses
 // such as ProjectsSearchResponse{Components []..., Paging ...}), that slice is rendered as a
 // proper multi-column table using the slice element fields as columns.
 // When multiple slice fields are present, the one with the most elements is chosen.
 // If no slice-of-structs field exists, output falls back to a key-value table.
-func formatStructTable(writer io.Writer, structVal reflect.Value) error {
+func formatStructTable(writer io.Writer, structVal reflect.Value, opts TableOptions) error {
+	if sliceField, ok := primarySliceFieldFor(structVal); ok {
+		return formatSliceTable(writer, sliceField, opts)
+	}
+
 	// Try to find the primary data slice inside the response struct.
 	if sliceField, ok := findPrimarySliceField(structVal); ok {
-		return formatSliceTable(writer, sliceField)
+		return formatSliceTable(writer, sliceField, opts)
 	}
 
 	// Fallback: render the struct itself as a two-column key-value table.
-	return formatStructKeyValueTable(writer, structVal)
+	return formatStructKeyValueTable(writer, structVal, opts)
 }
 
 // findPrimarySliceField scans the exported fields of a struct and returns the slice-of-structs
@@ -239,75 +246,23 @@ func findPrimarySliceField(structVal reflect.Value) (reflect.Value, bool) {
 }
 
 // formatStructKeyValueTable renders a struct as a two-column FIELD | VALUE table.
-func formatStructKeyValueTable(writer io.Writer, structVal reflect.Value) error {
+// When opts.Columns is set, only those paths are rendered, in the given order.
+func formatStructKeyValueTable(writer io.Writer, structVal reflect.Value, opts TableOptions) error {
 	structType := structVal.Type()
 	headers := []string{"FIELD", "VALUE"}
 	rows := make([][]string, 0, structType.NumField())
 
-	for fieldIdx := range structType.NumField() {
-		field := structType.Field(fieldIdx)
-		if !field.IsExported() {
-			continue
-		}
-
-		fieldVal := structVal.Field(fieldIdx)
-
-		// Skip zero values.
-		if fieldVal.IsZero() {
-			continue
-		}
-
-		var valueStr string
-
-		//nolint:exhaustive // only struct/slice/map need JSON inline formatting
-		switch fieldVal.Kind() {
-		case reflect.Struct, reflect.Slice, reflect.Map:
-			data, err := json.Marshal(fieldVal.Interface())
-			if err != nil {
-				valueStr = fmt.Sprintf("%v", fieldVal.Interface())
-			} else {
-				valueStr = string(data)
-			}
-		default:
-			valueStr = fmt.Sprintf("%v", fieldVal.Interface())
-		}
-
-		rows = append(rows, []string{field.Name, valueStr})
-	}
-
-	renderTable(writer, headers, rows)
-
-	return nil
-}
-
-// extractHeaders returns the column headers from a struct type.
-// Uses the JSON tag name if available, otherwise the field name.
-func extractHeaders(structType reflect.Type) []string {
-	headers := make([]string, 0, structType.NumField())
-
-	for field := range structType.Fields() {
-		if !field.IsExported() {
-			continue
+	if len(opts.Columns) > 0 {
+		for _, column := range opts.Columns {
+			cell, _ := resolvePath(structVal, column)
+			rows = append(rows, []string{column, formatCell(cell)})
 		}
 
-		name := field.Name
-		if jsonTag := field.Tag.Get("json"); jsonTag != "" {
-			parts := strings.Split(jsonTag, ",")
-			if parts[0] != "" {
-				name = parts[0]
-			}
-		}
+		renderTableWithOptions(writer, headers, rows, opts.NoHeaders)
 
-		headers = append(headers, strings.ToUpper(name))
+		return nil
 	}
 
-	return headers
-}
-
-// extractRow returns the column values from a struct value.
-func extractRow(structVal reflect.Value, structType reflect.Type) []string {
-	row := make([]string, 0, structType.NumField())
-
 	for fieldIdx := range structType.NumField() {
 		field := structType.Field(fieldIdx)
 		if !field.IsExported() {
@@ -316,37 +271,28 @@ func extractRow(structVal reflect.Value, structType reflect.Type) []string {
 
 		fieldVal := structVal.Field(fieldIdx)
 
-		var cellStr string
-
-		//nolint:exhaustive // only struct/slice/map need JSON inline formatting
-		switch fieldVal.Kind() {
-		case reflect.Struct, reflect.Slice, reflect.Map:
-			if fieldVal.IsZero() {
-				cellStr = ""
-			} else {
-				data, err := json.Marshal(fieldVal.Interface())
-				if err != nil {
-					cellStr = fmt.Sprintf("%v", fieldVal.Interface())
-				} else {
-					cellStr = string(data)
-				}
-			}
-		default:
-			if fieldVal.IsZero() {
-				cellStr = ""
-			} else {
-				cellStr = fmt.Sprintf("%v", fieldVal.Interface())
-			}
+		// Skip zero values.
+		if fieldVal.IsZero() {
+			continue
 		}
 
-		row = append(row, cellStr)
+		rows = append(rows, []string{field.Name, formatCell(fieldVal)})
 	}
 
-	return row
+	renderTableWithOptions(writer, headers, rows, opts.NoHeaders)
+
+	return nil
 }
 
 // renderTable renders headers and rows as a simple ASCII table.
 func renderTable(writer io.Writer, headers []string, rows [][]string) {
+	renderTableWithOptions(writer, headers, rows, false)
+}
+
+// renderTableWithOptions renders rows as a simple ASCII table, optionally without
+// the header and separator rows. Headers still size the columns so output stays
+// aligned whether or not they are printed.
+func renderTableWithOptions(writer io.Writer, headers []string, rows [][]string, noHeaders bool) {
 	if len(headers) == 0 {
 		return
 	}
@@ -365,16 +311,18 @@ func renderTable(writer io.Writer, headers []string, rows [][]string) {
 		}
 	}
 
-	// Print header row.
-	printTableRow(writer, headers, widths)
+	if !noHeaders {
+		// Print header row.
+		printTableRow(writer, headers, widths)
 
-	// Print separator.
-	sep := make([]string, len(widths))
-	for colIdx, width := range widths {
-		sep[colIdx] = strings.Repeat("-", width+tablePadding)
-	}
+		// Print separator.
+		sep := make([]string, len(widths))
+		for colIdx, width := range widths {
+			sep[colIdx] = strings.Repeat("-", width+tablePadding)
+		}
 
-	_, _ = fmt.Fprintln(writer, strings.Join(sep, "+"))
+		_, _ = fmt.Fprintln(writer, strings.Join(sep, "+"))
+	}
 
 	// Print data rows.
 	for _, row := range rows {
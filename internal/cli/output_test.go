@@ -119,26 +119,51 @@ func TestFormatOutput_DefaultFormat(t *testing.T) {
 	assert.Contains(t, buf.String(), `"name": "test"`)
 }
 
-// TestExtractHeaders tests header extraction from struct types.
-func TestExtractHeaders(t *testing.T) {
+// TestTableColumns_JSONTags tests that column names use JSON tag names.
+func TestTableColumns_JSONTags(t *testing.T) {
 	type withJSON struct {
 		ID   int    `json:"id"`
 		Name string `json:"name"`
 	}
 
-	headers := extractHeaders(reflect.TypeOf(withJSON{}))
-	assert.Equal(t, []string{"ID", "NAME"}, headers)
+	columns := tableColumns(reflect.TypeOf(withJSON{}), TableOptions{})
+	assert.Equal(t, []string{"id", "name"}, columns)
+	assert.Equal(t, []string{"ID", "NAME"}, columnHeaders(columns))
 }
 
-// TestExtractHeaders_NoJSONTag tests header extraction without json tags.
-func TestExtractHeaders_NoJSONTag(t *testing.T) {
+// TestTableColumns_NoJSONTag tests that column names fall back to field names.
+func TestTableColumns_NoJSONTag(t *testing.T) {
 	type noTag struct {
 		First  string
 		Second int
 	}
 
-	headers := extractHeaders(reflect.TypeOf(noTag{}))
-	assert.Equal(t, []string{"FIRST", "SECOND"}, headers)
+	columns := tableColumns(reflect.TypeOf(noTag{}), TableOptions{})
+	assert.Equal(t, []string{"FIRST", "SECOND"}, columnHeaders(columns))
+}
+
+// TestFormatOutput_Wide tests that wide output renders nested fields.
+func TestFormatOutput_Wide(t *testing.T) {
+	type nested struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+		Meta struct {
+			Owner string `json:"owner"`
+		} `json:"meta"`
+	}
+
+	row := nested{Name: "alpha", Tags: []string{"a", "b"}}
+	row.Meta.Owner = "bob"
+
+	var table, wide bytes.Buffer
+
+	require.NoError(t, FormatOutput(&table, []nested{row}, OutputTable))
+	require.NoError(t, FormatOutput(&wide, []nested{row}, OutputWide))
+
+	assert.Contains(t, table.String(), "a,b")
+	assert.NotContains(t, table.String(), "META")
+	assert.Contains(t, wide.String(), "META")
+	assert.Contains(t, wide.String(), `{"owner":"bob"}`)
 }
 
 // TestRenderTable tests the ASCII table renderer.
@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"go.uber.org/zap"
 )
 
 const (
@@ -68,10 +70,13 @@ func findSliceField(responseType reflect.Type) (string, bool) {
 
 // PaginateAll calls a paginated service method repeatedly until all results are collected.
 // It sets Page=1 and PageSize=MaxPageSize, then increments page until Total is reached.
-// The results are merged by concatenating the slice field across pages.
+// The results are merged by concatenating the slice field across pages. When ctx ends
+// after some pages were fetched, those pages are returned along with the error, as
+// sonar's allPages does, so that interrupted commands can still print them.
 //
-//nolint:funlen // unavoidable length for multi-page merging with reflection
+//nolint:funlen,cyclop // unavoidable length for multi-page merging with reflection
 func PaginateAll(
+	ctx context.Context,
 	service reflect.Value,
 	methodName string,
 	opt reflect.Value,
@@ -81,7 +86,7 @@ func PaginateAll(
 	sliceFieldName, hasSlice := findSliceField(responseType)
 	if !hasSlice {
 		// No slice field to paginate - just call once.
-		result, resp, err := InvokeMethod(service, methodName, opt, pattern, true)
+		result, resp, err := InvokeMethod(ctx, service, methodName, opt, pattern, true)
 		CloseBody(resp)
 
 		return result, err
@@ -101,9 +106,20 @@ func PaginateAll(
 	for {
 		setPageField(optElem, paginationPageField, pageNum)
 
-		result, resp, err := InvokeMethod(service, methodName, opt, pattern, true)
+		result, resp, err := InvokeMethod(ctx, service, methodName, opt, pattern, true)
 		CloseBody(resp)
 
+		if err != nil && ctx.Err() != nil && firstResult != nil {
+			setPaginatedResult(firstResult, allItems, sliceFieldName)
+			Logger().Warn("pagination interrupted, returning the pages fetched so far",
+				zap.String("method", methodName),
+				zap.Int64("pages", pageNum-1),
+				zap.Int("items", allItems.Len()),
+				zap.Error(context.Cause(ctx)))
+
+			return firstResult, err
+		}
+
 		if err != nil {
 			return nil, err
 		}
@@ -174,7 +174,7 @@ func TestPaginateAll(t *testing.T) {
 	svcValue := reflect.ValueOf(svc)
 	responseType := reflect.TypeOf(&paginatedResponse{})
 
-	result, err := PaginateAll(svcValue, "Search", optValue, PatternResponseBody, responseType)
+	result, err := PaginateAll(t.Context(), svcValue, "Search", optValue, PatternResponseBody, responseType)
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 
@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar/config"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// exitPlanChanges is the exit status of plan --detailed-exitcode when the server does
+// not match the configuration, and of diff --detailed-exitcode when the configurations
+// differ.
+const exitPlanChanges = 2
+
+// errPlanChanges is returned by plan --detailed-exitcode when the plan has changes.
+var errPlanChanges = errors.New("the server does not match the configuration")
+
+// planSymbols prefixes the changes of a plan by action, as Terraform does.
+//
+//nolint:gochecknoglobals // constant lookup table
+var planSymbols = map[config.Action]string{
+	config.ActionCreate: "+",
+	config.ActionUpdate: "~",
+	config.ActionDelete: "-",
+}
+
+// newPlanCommand creates the plan command.
+func newPlanCommand(format *OutputFormat) *cobra.Command {
+	var detailedExitCode bool
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the plan command
+		Use:   "plan <file|directory>...",
+		Short: "Show the changes that would make the server match configuration files",
+		Long: `Compare configuration files with the server and print the changes apply would make,
+without changing anything.
+
+The files declare quality gates, quality profiles, permission templates, settings,
+webhooks and new code periods in YAML or JSON; a directory stands for the .yaml, .yml
+and .json files it contains. Objects the files do not declare are left alone.
+
+The plan is printed as a summary, unless --output is given, in which case the changes
+are printed in that format. With --detailed-exitcode, the command exits with status 2
+when there are changes, and 0 when the server already matches.`,
+		Example: `  sonar-cli plan sonar.yaml
+  sonar-cli plan config/ --detailed-exitcode
+  sonar-cli plan sonar.yaml -o json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, err := buildPlan(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = writePlan(cmd, plan, *format)
+			if err != nil {
+				return err
+			}
+
+			if detailedExitCode && !plan.Empty() {
+				return withExitCode(exitPlanChanges, errPlanChanges)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&detailedExitCode, "detailed-exitcode", false, "Exit with status 2 when there are changes")
+
+	return cmd
+}
+
+// newApplyCommand creates the apply command.
+func newApplyCommand(format *OutputFormat) *cobra.Command {
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the apply command
+		Use:   "apply <file|directory>...",
+		Short: "Change the server to match configuration files",
+		Long: `Compare configuration files with the server, print the plan and, once confirmed,
+make the changes.
+
+Changes are made in order: quality profiles (parents first), quality gates,
+permission templates, global settings, webhooks and new code period, then projects.
+If a change fails, the changes already made are undone in reverse order and the
+command fails.
+
+The plan is confirmed on a terminal; --yes applies it without asking, and is
+required when stdin is not a terminal.`,
+		Example: `  sonar-cli apply sonar.yaml
+  sonar-cli apply config/ --yes`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runApply(cmd, args, *format)
+		},
+	}
+
+	addYesFlag(cmd)
+
+	return cmd
+}
+
+// buildPlan loads the configuration files and compares them with the server. It rejects
+// --dry-run, which would stop the requests reading the server: plan is the dry run of apply.
+func buildPlan(cmd *cobra.Command, paths []string) (*config.Plan, error) {
+	if flagIsTrue(cmd, "dry-run") {
+		err := fmt.Errorf("%w --dry-run for %s: plan already shows the changes apply would make without making them",
+			errInvalidFlagValue, cmd.Name())
+		Logger().Error("--dry-run is not supported", zap.Error(err))
+
+		return nil, err
+	}
+
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return nil, err
+	}
+
+	desired, err := config.LoadFiles(paths...)
+	if err != nil {
+		Logger().Error("failed to load the configuration", zap.Error(err))
+
+		return nil, fmt.Errorf("failed to load the configuration: %w", err)
+	}
+
+	plan, err := config.NewPlan(cmd.Context(), client, desired)
+	if err != nil {
+		Logger().Error("failed to plan the changes", zap.Error(err))
+
+		return nil, fmt.Errorf("failed to plan the changes: %w", err)
+	}
+
+	return plan, nil
+}
+
+// runApply prints the plan, confirms it and applies it.
+func runApply(cmd *cobra.Command, paths []string, format OutputFormat) error {
+	plan, err := buildPlan(cmd, paths)
+	if err != nil {
+		return err
+	}
+
+	structured := cmd.Flags().Changed("output")
+
+	if !structured {
+		writePlanSummary(cmd.OutOrStdout(), plan)
+	}
+
+	if plan.Empty() {
+		if structured {
+			return writeResult(cmd, &config.ApplyResult{Applied: nil, Failed: nil, RolledBack: nil}, format)
+		}
+
+		return nil
+	}
+
+	err = confirmPlan(cmd, plan)
+	if err != nil {
+		return err
+	}
+
+	result, applyErr := plan.Apply(cmd.Context())
+	if applyErr != nil {
+		Logger().Error("failed to apply the plan", zap.Int("rolledBack", len(result.RolledBack)), zap.Error(applyErr))
+	}
+
+	if structured {
+		err = writeResult(cmd, result, format)
+	} else {
+		writeApplySummary(cmd.OutOrStdout(), result)
+	}
+
+	return errors.Join(applyErr, err)
+}
+
+// confirmPlan asks the user to confirm a plan, unless --yes was given.
+func confirmPlan(cmd *cobra.Command, plan *config.Plan) error {
+	return confirmChanges(cmd, "apply", fmt.Sprintf("\nApply these %d change(s)? [y/N] ", len(plan.Changes)))
+}
+
+// confirmChanges asks the user to confirm the changes a command is about to make,
+// unless --yes was given. command names the command in the error without a terminal.
+func confirmChanges(cmd *cobra.Command, command, prompt string) error {
+	if flagIsTrue(cmd, yesFlag) {
+		return nil
+	}
+
+	if !stdinIsTerminal(cmd.InOrStdin()) {
+		err := fmt.Errorf("%w: %s changes the server; pass --yes to apply without a prompt", errConfirmationRequired, command)
+		Logger().Error("refusing to apply without confirmation", zap.Error(err))
+
+		return err
+	}
+
+	answer, err := promptLine(cmd, prompt)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return nil
+	default:
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Aborted.")
+
+		return errAborted
+	}
+}
+
+// writePlan prints a plan as a summary, or in the output format given with --output.
+func writePlan(cmd *cobra.Command, plan *config.Plan, format OutputFormat) error {
+	if cmd.Flags().Changed("output") {
+		return writeResult(cmd, plan, format)
+	}
+
+	recordResult(cmd.Context(), plan)
+	writePlanSummary(cmd.OutOrStdout(), plan)
+
+	return nil
+}
+
+// writePlanSummary prints the changes of a plan and their attributes, then the counts.
+func writePlanSummary(writer io.Writer, plan *config.Plan) {
+	if plan.Empty() {
+		_, _ = fmt.Fprintln(writer, "No changes. The server matches the configuration.")
+
+		return
+	}
+
+	for _, change := range plan.Changes {
+		_, _ = fmt.Fprintf(writer, "  %s %s %q\n", planSymbols[change.Action], change.Kind, change.Address)
+
+		for _, diff := range change.Diffs {
+			switch change.Action {
+			case config.ActionCreate:
+				_, _ = fmt.Fprintf(writer, "      %s = %q\n", diff.Name, diff.After)
+			case config.ActionDelete:
+				_, _ = fmt.Fprintf(writer, "      %s = %q\n", diff.Name, diff.Before)
+			default:
+				_, _ = fmt.Fprintf(writer, "      %s: %q -> %q\n", diff.Name, diff.Before, diff.After)
+			}
+		}
+	}
+
+	summary := plan.Summary()
+
+	_, _ = fmt.Fprintf(writer, "\nPlan: %d to add, %d to change, %d to destroy.\n", summary.Create, summary.Update, summary.Delete)
+}
+
+// writeApplySummary prints the outcome of an apply.
+func writeApplySummary(writer io.Writer, result *config.ApplyResult) {
+	if result.Failed == nil {
+		_, _ = fmt.Fprintf(writer, "\nApply complete: %d change(s) made.\n", len(result.Applied))
+
+		return
+	}
+
+	_, _ = fmt.Fprintf(writer, "\nApply failed at %s.\n", result.Failed)
+	_, _ = fmt.Fprintf(writer, "Rolled back %d change(s).\n", len(result.RolledBack))
+
+	if len(result.Applied) > 0 {
+		_, _ = fmt.Fprintf(writer, "%d change(s) could not be rolled back:\n", len(result.Applied))
+
+		for _, change := range result.Applied {
+			_, _ = fmt.Fprintf(writer, "  %s\n", change)
+		}
+	}
+}
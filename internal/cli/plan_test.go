@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settingsServer serves the settings endpoints used by plan and apply from a map of
+// global settings, counting the writes.
+type settingsServer struct {
+	values map[string]string
+	writes int
+	fail   bool
+}
+
+// handle serves settings/values and settings/set.
+func (s *settingsServer) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/settings/values":
+		settings := []map[string]any{}
+
+		for _, key := range strings.Split(r.URL.Query().Get("keys"), ",") {
+			if value, ok := s.values[key]; ok {
+				settings = append(settings, map[string]any{"key": key, "value": value})
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"settings": settings})
+	case "/api/settings/set":
+		if s.fail {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": [{"msg": "refused"}]}`))
+
+			return
+		}
+
+		s.writes++
+		s.values[r.URL.Query().Get("key")] = r.URL.Query().Get("value")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// writeConfigFile writes a configuration file to a temporary directory and returns its path.
+func writeConfigFile(t *testing.T, data string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "sonar.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	return path
+}
+
+// runPlanCLI runs a plan or apply command with a test client and returns its output.
+func runPlanCLI(t *testing.T, server *settingsServer, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	client := newTestClient(t, server.handle)
+
+	var out bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().VarP(&outputFormatFlag{target: &format}, "output", "o", "")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "")
+	rootCmd.AddCommand(newPlanCommand(&format), newApplyCommand(&format))
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetArgs(args)
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+
+	return out.String(), err
+}
+
+// planConfig declares two global settings.
+const planConfig = `
+settings:
+  - {key: sonar.exclusions, value: "**/gen/**"}
+  - {key: sonar.scm.disabled, value: "true"}
+`
+
+// TestPlan_Summary tests the plan summary and --detailed-exitcode.
+func TestPlan_Summary(t *testing.T) {
+	server := &settingsServer{values: map[string]string{"sonar.scm.disabled": "false"}}
+	path := writeConfigFile(t, planConfig)
+
+	out, err := runPlanCLI(t, server, "", "plan", path)
+	require.NoError(t, err)
+	assert.Equal(t, `  + setting "sonar.exclusions"
+      value = "**/gen/**"
+  ~ setting "sonar.scm.disabled"
+      value: "false" -> "true"
+
+Plan: 1 to add, 1 to change, 0 to destroy.
+`, out)
+
+	_, err = runPlanCLI(t, server, "", "plan", path, "--detailed-exitcode")
+	require.ErrorIs(t, err, errPlanChanges)
+	assert.Equal(t, exitPlanChanges, ExitCode(err))
+	assert.Zero(t, server.writes, "plan must not change the server")
+
+	server.values = map[string]string{"sonar.exclusions": "**/gen/**", "sonar.scm.disabled": "true"}
+
+	out, err = runPlanCLI(t, server, "", "plan", path, "--detailed-exitcode")
+	require.NoError(t, err)
+	assert.Contains(t, out, "No changes.")
+}
+
+// TestPlan_DryRun tests that plan and apply reject --dry-run, which would stop the
+// requests reading the server.
+func TestPlan_DryRun(t *testing.T) {
+	server := &settingsServer{values: map[string]string{}}
+	path := writeConfigFile(t, planConfig)
+
+	for _, command := range []string{"plan", "apply"} {
+		_, err := runPlanCLI(t, server, "", command, path, "--dry-run")
+		require.ErrorIs(t, err, errInvalidFlagValue, command)
+		assert.Contains(t, err.Error(), "plan already shows the changes", command)
+		assert.Equal(t, exitValidation, ExitCode(err), command)
+	}
+
+	assert.Zero(t, server.writes)
+}
+
+// TestApply tests that apply requires confirmation, and makes the changes once given.
+func TestApply(t *testing.T) {
+	server := &settingsServer{values: map[string]string{}}
+	path := writeConfigFile(t, planConfig)
+
+	_, err := runPlanCLI(t, server, "", "apply", path)
+	require.ErrorIs(t, err, errConfirmationRequired)
+
+	fakeTerminal(t, true)
+
+	_, err = runPlanCLI(t, server, "n\n", "apply", path)
+	require.ErrorIs(t, err, errAborted)
+	assert.Zero(t, server.writes)
+
+	out, err := runPlanCLI(t, server, "y\n", "apply", path)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Apply complete: 2 change(s) made.")
+	assert.Equal(t, map[string]string{"sonar.exclusions": "**/gen/**", "sonar.scm.disabled": "true"}, server.values)
+
+	out, err = runPlanCLI(t, server, "", "apply", path)
+	require.NoError(t, err)
+	assert.Contains(t, out, "No changes.")
+}
+
+// TestApply_Failure tests the summary and exit status of a failed apply.
+func TestApply_Failure(t *testing.T) {
+	server := &settingsServer{values: map[string]string{}, fail: true}
+
+	out, err := runPlanCLI(t, server, "", "apply", writeConfigFile(t, planConfig), "--yes")
+	require.Error(t, err)
+	assert.Equal(t, exitValidation, ExitCode(err), "a 400 response is a validation error")
+	assert.Contains(t, out, "Apply failed at create setting \"sonar.exclusions\".\nRolled back 0 change(s).\n")
+}
@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+const (
+	// pluginPrefix is the file name prefix of plugin executables on PATH.
+	pluginPrefix = "sonar-cli-"
+	// pluginAnnotation marks plugin commands, holding the path of their executable.
+	pluginAnnotation = "sonar-cli-plugin"
+	// windowsExecutableSuffix is stripped from plugin names on Windows.
+	windowsExecutableSuffix = ".exe"
+	// envOutput passes the resolved output format to plugins.
+	envOutput = "SONAR_CLI_OUTPUT"
+)
+
+// reservedPluginNames lists command names Cobra or Execute add after plugins are
+// registered, which plugins therefore cannot take.
+//
+//nolint:gochecknoglobals // constant configuration set
+var reservedPluginNames = []string{"completion", "help", "shell", completeDirective, completeNoDescDirective}
+
+// plugin is an external sonar-cli-<name> executable found on PATH.
+type plugin struct {
+	// name is the subcommand name, the file name without prefix (and .exe suffix on Windows).
+	name string
+	// path is the absolute path of the executable.
+	path string
+}
+
+// discoverPlugins lists the plugin executables in the directories of a PATH value.
+// As with command lookup, the first directory providing a name wins.
+func discoverPlugins(pathList string) []plugin {
+	var plugins []plugin
+
+	seen := make(map[string]bool)
+
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			seen[name] = true
+
+			plugins = append(plugins, plugin{name: name, path: path})
+		}
+	}
+
+	return plugins
+}
+
+// pluginName returns the subcommand name of a plugin file, if the file name is one.
+func pluginName(fileName string) (string, bool) {
+	name, ok := strings.CutPrefix(fileName, pluginPrefix)
+	if !ok {
+		return "", false
+	}
+
+	if runtime.GOOS == "windows" {
+		name, ok = strings.CutSuffix(strings.ToLower(name), windowsExecutableSuffix)
+		if !ok {
+			return "", false
+		}
+	}
+
+	return name, name != "" && !strings.ContainsAny(name, " \t")
+}
+
+// isExecutable reports whether path is a regular file the current user may execute.
+// On Windows, where there are no execute bits, the .exe suffix is what counts.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// pluginCache holds the plugins found on PATH, so that the command trees the shell builds
+// for each line and each completion do not scan PATH again.
+type pluginCache struct {
+	mu sync.Mutex
+	// pathList is the PATH value plugins were discovered in.
+	pathList string
+	plugins  []plugin
+	valid    bool
+}
+
+// discoveredPlugins caches plugin discovery for the process.
+//
+//nolint:gochecknoglobals // process-wide cache of PATH discovery
+var discoveredPlugins = &pluginCache{} //nolint:exhaustruct // zero value is an empty cache
+
+// get returns the plugins of pathList, discovering them only when PATH changed since the
+// previous call.
+func (c *pluginCache) get(pathList string) []plugin {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.valid || c.pathList != pathList {
+		c.plugins = discoverPlugins(pathList)
+		c.pathList = pathList
+		c.valid = true
+	}
+
+	return c.plugins
+}
+
+// registerPlugins adds a command for each plugin on PATH. Built-in commands take
+// precedence: a plugin named like one of them is ignored.
+func registerPlugins(rootCmd *cobra.Command, flags *globalFlags) {
+	for _, found := range discoveredPlugins.get(os.Getenv("PATH")) {
+		existing, _, err := rootCmd.Find([]string{found.name})
+		if (err == nil && existing != rootCmd) || slices.Contains(reservedPluginNames, found.name) {
+			Logger().Debug("plugin shadowed by built-in command", zap.String("plugin", found.path))
+
+			continue
+		}
+
+		rootCmd.AddCommand(newPluginCommand(found, flags))
+	}
+}
+
+// newPluginCommand creates the command dispatching to a plugin. Arguments are passed
+// through unparsed, except for sonar-cli global flags given before the first plugin
+// argument: the connection flags and --output are passed to the plugin, while
+// --deadline and --error-format apply to the plugin run as to any command.
+func newPluginCommand(found plugin, flags *globalFlags) *cobra.Command {
+	return &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to plugin commands
+		Use:                found.name,
+		Short:              "Plugin command (" + found.path + ")",
+		Annotations:        map[string]string{pluginAnnotation: found.path},
+		DisableFlagParsing: true,
+		// Plugins build their own client from the environment; override the root hook so
+		// no client is initialized (and no server URL required) here. Global flags are
+		// parsed, and applied, once RunE has split them from the plugin arguments.
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			return nil
+		},
+		ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pluginArgs, err := parseLeadingGlobalFlags(cmd.Root(), args)
+			if err != nil {
+				return err
+			}
+
+			applyErrorFormat(flags.errorFormat)
+			applyDeadline(cmd, flags.deadline)
+
+			return runPlugin(cmd, found, pluginArgs, flags)
+		},
+	}
+}
+
+// parseLeadingGlobalFlags sets the root persistent flags found at the start of args and
+// returns the remaining arguments. Parsing stops at the first argument that is not a
+// global flag, or after a "--" terminator.
+func parseLeadingGlobalFlags(rootCmd *cobra.Command, args []string) ([]string, error) {
+	persistentFlags := rootCmd.PersistentFlags()
+
+	for len(args) > 0 {
+		arg := args[0]
+		if arg == "--" {
+			return args[1:], nil
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+
+		var flag *pflag.Flag
+
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			flag = persistentFlags.Lookup(name)
+		case strings.HasPrefix(arg, "-") && len(name) == 1:
+			flag = persistentFlags.ShorthandLookup(name)
+		}
+
+		if flag == nil {
+			return args, nil
+		}
+
+		consumed := 1
+
+		if !hasValue {
+			if flag.NoOptDefVal != "" {
+				value = flag.NoOptDefVal
+			} else {
+				if len(args) < 2 { //nolint:mnd // the flag and its value
+					return nil, fmt.Errorf("flag needs an argument: %s", arg)
+				}
+
+				value = args[1]
+				consumed = 2
+			}
+		}
+
+		err := persistentFlags.Set(flag.Name, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %q for %q flag: %w", value, arg, err)
+		}
+
+		args = args[consumed:]
+	}
+
+	return args, nil
+}
+
+// runPlugin executes a plugin with the resolved connection context in its environment.
+// A plugin exiting with a non-zero status yields an *exec.ExitError carrying that status;
+// the plugin reports its own errors, so only failures to start it are logged here.
+func runPlugin(cmd *cobra.Command, found plugin, args []string, flags *globalFlags) error {
+	process := exec.CommandContext(cmd.Context(), found.path, args...)
+	process.Stdin = cmd.InOrStdin()
+	process.Stdout = cmd.OutOrStdout()
+	process.Stderr = cmd.ErrOrStderr()
+	process.Env = append(os.Environ(), pluginEnv(flags)...)
+
+	err := process.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			Logger().Error("failed to run plugin", zap.String("plugin", found.path), zap.Error(err))
+		}
+
+		return fmt.Errorf("plugin %s: %w", found.name, err)
+	}
+
+	return nil
+}
+
+// pluginEnv returns the environment describing the resolved connection context, as read
+// by sonar.NewClientFromEnv. Every variable is set, empty when unset, so values inherited
+// from the parent environment cannot contradict the flags sonar-cli resolved.
+func pluginEnv(flags *globalFlags) []string {
+	return []string{
+		sonar.EnvURL + "=" + flags.url,
+		sonar.EnvToken + "=" + flags.token,
+		sonar.EnvUsername + "=" + flags.username,
+		sonar.EnvPassword + "=" + flags.password,
+		sonar.EnvTimeout + "=" + flags.timeout.String(),
+		envOutput + "=" + string(flags.output),
+		// The variables sonar-cli reads, so that a plugin running sonar-cli inherits the connection.
+		"SONAR_CLI_URL=" + flags.url,
+		"SONAR_CLI_TOKEN=" + flags.token,
+		"SONAR_CLI_USERNAME=" + flags.username,
+		"SONAR_CLI_PASSWORD=" + flags.password,
+	}
+}
+
+// isPluginCommand reports whether cmd dispatches to a plugin.
+func isPluginCommand(cmd *cobra.Command) bool {
+	_, ok := cmd.Annotations[pluginAnnotation]
+
+	return ok
+}
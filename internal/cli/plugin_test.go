@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// pluginScript prints its arguments and the connection context it received.
+const pluginScript = `#!/bin/sh
+[ -n "$PLUGIN_SLEEP" ] && exec /bin/sleep "$PLUGIN_SLEEP"
+echo "args: $*"
+echo "url: $SONAR_URL token: $SONAR_TOKEN timeout: $SONAR_TIMEOUT output: $SONAR_CLI_OUTPUT"
+exit ${PLUGIN_EXIT:-0}
+`
+
+// writePlugin writes an executable plugin script named sonar-cli-<name> into dir.
+func writePlugin(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+
+	path := filepath.Join(dir, pluginPrefix+name)
+	require.NoError(t, os.WriteFile(path, []byte(pluginScript), 0o700)) //nolint:gosec // test plugin must be executable
+
+	return path
+}
+
+// TestDiscoverPlugins tests PATH order, executable checks and name extraction.
+func TestDiscoverPlugins(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+
+	reportPath := writePlugin(t, first, "report")
+	writePlugin(t, second, "report")
+	onboardPath := writePlugin(t, second, "onboard")
+	require.NoError(t, os.WriteFile(filepath.Join(first, pluginPrefix+"notes"), []byte("text"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(first, "sonar-cli"), []byte(pluginScript), 0o700)) //nolint:gosec // executable on purpose
+
+	plugins := discoverPlugins(strings.Join([]string{first, "", filepath.Join(first, "missing"), second}, string(os.PathListSeparator)))
+
+	assert.Equal(t, []plugin{{name: "report", path: reportPath}, {name: "onboard", path: onboardPath}}, plugins)
+}
+
+// TestRegisterPlugins tests that plugins appear in the command tree, help and
+// completion, and that built-in names win.
+func TestRegisterPlugins(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "report")
+	writePlugin(t, dir, "issues")
+	writePlugin(t, dir, "shell")
+	t.Setenv("PATH", dir)
+
+	rootCmd := newCommandTree()
+
+	report, _, err := rootCmd.Find([]string{"report"})
+	require.NoError(t, err)
+	assert.True(t, isPluginCommand(report))
+
+	issues, _, err := rootCmd.Find([]string{"issues"})
+	require.NoError(t, err)
+	assert.False(t, isPluginCommand(issues))
+
+	// "shell" is added by Execute after plugin registration, so a plugin cannot take it.
+	_, _, err = rootCmd.Find([]string{"shell"})
+	require.Error(t, err)
+
+	var help bytes.Buffer
+
+	rootCmd.SetOut(&help)
+	rootCmd.SetArgs([]string{"--help"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, help.String(), "report")
+
+	var completions bytes.Buffer
+
+	rootCmd = newCommandTree()
+	rootCmd.SetOut(&completions)
+	rootCmd.SetArgs([]string{completeNoDescDirective, "rep"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, completions.String(), "report\n")
+}
+
+// TestPluginCommand_Run tests argument passthrough, global flag handling and the
+// environment passed to plugins.
+func TestPluginCommand_Run(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "report")
+	t.Setenv("PATH", dir)
+	t.Setenv("SONAR_CLI_URL", "http://from-env")
+	t.Setenv("SONAR_CLI_TOKEN", "")
+
+	var out bytes.Buffer
+
+	rootCmd := newCommandTree()
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--token", "secret", "report", "-o", "yaml", "--timeout=5s", "--project", "foo", "--help"})
+	require.NoError(t, rootCmd.Execute())
+
+	assert.Equal(t, "args: --project foo --help\nurl: http://from-env token: secret timeout: 5s output: yaml\n", out.String())
+
+	// A non-zero exit status is returned as an *exec.ExitError.
+	t.Setenv("PLUGIN_EXIT", "3")
+
+	rootCmd = newCommandTree()
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"report"})
+
+	err := rootCmd.Execute()
+
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.ExitCode())
+}
+
+// TestPluginCommand_GlobalFlags tests that --deadline and --error-format given before the
+// plugin name apply to the plugin run.
+func TestPluginCommand_GlobalFlags(t *testing.T) {
+	t.Cleanup(func() { applyErrorFormat(errorFormatText) })
+
+	dir := t.TempDir()
+	writePlugin(t, dir, "report")
+	t.Setenv("PATH", dir)
+	t.Setenv("PLUGIN_SLEEP", "10")
+
+	rootCmd := newCommandTree()
+	rootCmd.SetArgs([]string{"--error-format", "json", "--deadline", "200ms", "report"})
+
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteContextC(t.Context())
+	err = interruptionError(cmd.Context(), err)
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, exitDeadlineExceeded, ExitCode(err))
+	assert.True(t, reportsJSONErrors(rootCmd))
+	assert.False(t, Logger().Core().Enabled(zapcore.ErrorLevel))
+}
+
+// TestPluginCache tests that PATH is scanned again only when it changes.
+func TestPluginCache(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+	writePlugin(t, first, "report")
+
+	cache := &pluginCache{}
+	assert.Len(t, cache.get(first), 1)
+
+	writePlugin(t, first, "audit")
+	assert.Len(t, cache.get(first), 1)
+
+	pathList := strings.Join([]string{first, second}, string(os.PathListSeparator))
+	assert.Len(t, cache.get(pathList), 2)
+}
+
+// TestParseLeadingGlobalFlags tests which leading arguments are taken as global flags.
+func TestParseLeadingGlobalFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "none", args: []string{"--project", "a"}, want: []string{"--project", "a"}},
+		{name: "separate value", args: []string{"--url", "http://x", "a"}, want: []string{"a"}},
+		{name: "shorthand and bool", args: []string{"-o", "table", "--no-headers", "a"}, want: []string{"a"}},
+		{name: "terminator", args: []string{"--url=http://x", "--", "--token", "t"}, want: []string{"--token", "t"}},
+		{name: "stops at first argument", args: []string{"a", "--url", "http://x"}, want: []string{"a", "--url", "http://x"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseLeadingGlobalFlags(newCommandTree(), tc.args)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := parseLeadingGlobalFlags(newCommandTree(), []string{"--url"})
+	require.Error(t, err)
+
+	_, err = parseLeadingGlobalFlags(newCommandTree(), []string{"-o", "xml"})
+	require.Error(t, err)
+}
+
+// TestShellSession_Plugin tests that the shell passes its connection and variables to plugins.
+func TestShellSession_Plugin(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "report")
+	t.Setenv("PATH", dir)
+
+	session, out, errOut := newTestShellSession(t, nil)
+	session.connection["url"] = "http://sonar"
+
+	runScript(t, session, "set output=table project=foo", "report --all")
+
+	assert.Empty(t, errOut.String())
+	assert.Contains(t, out.String(), "args: --all\nurl: http://sonar token:")
+	assert.Contains(t, out.String(), "output: table\n")
+	assert.NotContains(t, out.String(), "project")
+}
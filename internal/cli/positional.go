@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// errPositionalConflict is returned when a command's main key is given both as a flag
+// and as positional arguments.
+var errPositionalConflict = errors.New("conflicting positional arguments")
+
+// addPositionalArgs lets a method command take the value of its positional flag as
+// arguments: "projects delete foo" is "projects delete --project foo". Several
+// arguments fill a list flag in one call, or fan out into one call per argument.
+func addPositionalArgs(cmd *cobra.Command, flag *pflag.Flag) {
+	cmd.Use += fmt.Sprintf(" [%s...]", flag.Name)
+	cmd.Args = cobra.ArbitraryArgs
+	flag.Usage += " (or pass as positional arguments)"
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		complete, ok := cmd.GetFlagCompletionFunc(flag.Name)
+		if !ok {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// applyPositionalArgs sets the positional flag from the command arguments, so that Cobra's
+// required flag check sees it. A list flag takes all arguments; a single-valued flag takes
+// the first one, and runMethodCommand sets the others in turn.
+func applyPositionalArgs(cmd *cobra.Command, args []string) error {
+	flag := positionalFlag(cmd)
+	if flag == nil || len(args) == 0 {
+		return nil
+	}
+
+	if flag.Changed {
+		return fmt.Errorf("%w: pass %s either with --%s or as arguments, not both", errPositionalConflict, flag.Name, flag.Name)
+	}
+
+	value := args[0]
+	if isListFlag(flag) {
+		value = strings.Join(args, ",")
+	}
+
+	err := cmd.Flags().Set(flag.Name, value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", flag.Name, err)
+	}
+
+	return nil
+}
+
+// fanOutTargets returns the arguments a command runs once for each, or nil when it runs a
+// single time.
+func fanOutTargets(cmd *cobra.Command, args []string) []string {
+	flag := positionalFlag(cmd)
+	if flag == nil || isListFlag(flag) || len(args) < 2 { //nolint:mnd // a single argument needs no fan-out
+		return nil
+	}
+
+	return args
+}
+
+// isListFlag reports whether a flag holds a list of values.
+func isListFlag(flag *pflag.Flag) bool {
+	return flag.Value.Type() == "stringSlice"
+}
+
+// positionalArgCount counts the positional arguments among a command's arguments, skipping
+// flags and their values.
+func positionalArgCount(cmd *cobra.Command, args []string) int {
+	count := 0
+
+	for idx := 0; idx < len(args); idx++ {
+		word := args[idx]
+
+		switch {
+		case word == "--":
+			return count + len(args) - idx - 1
+		case strings.HasPrefix(word, "--"):
+			name, _, hasValue := strings.Cut(word[2:], "=")
+			if flag := cmd.Flag(name); !hasValue && flag != nil && flag.NoOptDefVal == "" {
+				idx++
+			}
+		case strings.HasPrefix(word, "-") && len(word) > 1:
+			flag := cmd.Flags().ShorthandLookup(word[1:2])
+			if flag == nil {
+				flag = cmd.InheritedFlags().ShorthandLookup(word[1:2])
+			}
+
+			if len(word) == 2 && flag != nil && flag.NoOptDefVal == "" { //nolint:mnd // "-x" with its value in the next word
+				idx++
+			}
+		default:
+			count++
+		}
+	}
+
+	return count
+}
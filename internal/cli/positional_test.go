@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runPositionalCLI runs a generated command with a test client and returns its output.
+func runPositionalCLI(t *testing.T, handler http.HandlerFunc, args ...string) (string, error) {
+	t.Helper()
+
+	fakeTerminal(t, false)
+
+	client := newTestClient(t, handler)
+
+	var out bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	RegisterAllCommands(rootCmd, &format)
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(args)
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+
+	return out.String(), err
+}
+
+// TestBindFlags_Positional tests that the flag of a positional field is annotated.
+func TestBindFlags_Positional(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+
+	BindFlags(cmd, &struct {
+		Key  string `cli:"positional" url:"key"`
+		Name string `url:"name,omitempty"`
+	}{})
+
+	flag := positionalFlag(cmd)
+	require.NotNil(t, flag)
+	assert.Equal(t, "key", flag.Name)
+
+	cmd = &cobra.Command{Use: "test"}
+	BindFlags(cmd, &mockOptionStruct{})
+	assert.Nil(t, positionalFlag(cmd))
+}
+
+// TestPositional_Single tests passing a command's main key as an argument.
+func TestPositional_Single(t *testing.T) {
+	out, err := runPositionalCLI(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "my-app", r.URL.Query().Get("component"))
+
+		_, _ = w.Write([]byte(`{"component": {"key": "my-app"}}`))
+	}, "components", "show", "my-app")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"key": "my-app"`)
+
+	_, err = runPositionalCLI(t, nil, "components", "show", "a", "--component", "b")
+	require.ErrorIs(t, err, errPositionalConflict)
+}
+
+// TestPositional_FanOut tests that several arguments run the method once each.
+func TestPositional_FanOut(t *testing.T) {
+	var components []string
+
+	out, err := runPositionalCLI(t, func(w http.ResponseWriter, r *http.Request) {
+		components = append(components, r.URL.Query().Get("component"))
+
+		_, _ = w.Write([]byte(`{"component": {"key": "` + r.URL.Query().Get("component") + `"}}`))
+	}, "components", "show", "a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, components)
+	assert.JSONEq(t, `[{"component": {"key": "a"}}, {"component": {"key": "b"}}]`, out)
+
+	// Destructive fan-outs are confirmed once for all targets.
+	var deleted []string
+
+	handler := func(_ http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		deleted = append(deleted, r.Form.Get("application"))
+	}
+
+	_, err = runPositionalCLI(t, handler, "applications", "delete", "a", "b", "c")
+	require.ErrorIs(t, err, errConfirmationRequired)
+	assert.Empty(t, deleted)
+
+	_, err = runPositionalCLI(t, handler, "applications", "delete", "a", "b", "c", "--yes")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, deleted)
+}
+
+// TestPositional_FanOutFailure tests that a failed target does not hide the results of
+// the others, and is reported.
+func TestPositional_FanOutFailure(t *testing.T) {
+	var components []string
+
+	out, err := runPositionalCLI(t, func(w http.ResponseWriter, r *http.Request) {
+		component := r.URL.Query().Get("component")
+		components = append(components, component)
+
+		if component == "b" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": [{"msg": "Component key 'b' not found"}]}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"component": {"key": "` + component + `"}}`))
+	}, "components", "show", "a", "b", "c")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "component b: ")
+	assert.Equal(t, exitNotFound, ExitCode(err))
+	assert.Equal(t, []string{"a", "b", "c"}, components)
+	assert.JSONEq(t, `[{"component": {"key": "a"}}, {"component": {"key": "c"}}]`, out)
+}
+
+// TestPositional_ListFlag tests that arguments fill a list flag in a single call.
+func TestPositional_ListFlag(t *testing.T) {
+	calls := 0
+
+	_, err := runPositionalCLI(t, func(_ http.ResponseWriter, r *http.Request) {
+		calls++
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "sonar.a,sonar.b", r.Form.Get("keys"))
+	}, "settings", "reset", "sonar.a", "sonar.b", "--yes")
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+// TestPositionalArgCount tests counting positional arguments among flags.
+func TestPositionalArgCount(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String("project", "", "")
+	cmd.Flags().BoolP("yes", "y", false, "")
+	cmd.Flags().StringP("branch", "b", "", "")
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{args: nil, want: 0},
+		{args: []string{"--project", "a"}, want: 0},
+		{args: []string{"--project=a", "b"}, want: 1},
+		{args: []string{"a", "--yes", "b", "-b", "main", "-y"}, want: 2},
+		{args: []string{"-bmain", "a", "--", "-c", "d"}, want: 3},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, positionalArgCount(cmd, tc.args), tc.args)
+	}
+}
+
+// TestShellSession_Positional tests that positional arguments override session variables.
+func TestShellSession_Positional(t *testing.T) {
+	var components []string
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		components = append(components, r.URL.Query().Get("component"))
+
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	session, _, errOut := newTestShellSession(t, client)
+
+	runScript(t, session,
+		"set component=my-app",
+		"components show",
+		"components show other",
+	)
+
+	assert.Empty(t, errOut.String())
+	assert.Equal(t, []string{"my-app", "other"}, components)
+}
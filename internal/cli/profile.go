@@ -0,0 +1,307 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar/inheritance"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	// treeFormatTree prints the inheritance forests as indented trees.
+	treeFormatTree = "tree"
+	// treeFormatDOT prints the inheritance forests as a Graphviz graph.
+	treeFormatDOT = "dot"
+	// treeFormatMermaid prints the inheritance forests as a Mermaid flowchart.
+	treeFormatMermaid = "mermaid"
+)
+
+// errProfileNotFound is returned when the quality profile to explain does not exist.
+var errProfileNotFound = errors.New("quality profile not found")
+
+// ProfileRulesReport is the result of the profile rules command.
+type ProfileRulesReport struct {
+	// Profile is the name of the quality profile.
+	Profile string `json:"profile"`
+	// Language is the language of the quality profile.
+	Language string `json:"language"`
+	// Project is the project whose quality profile was resolved, if any.
+	Project string `json:"project,omitempty"`
+	// Ancestors are the names of the profiles it inherits from, its parent first.
+	Ancestors []string `json:"ancestors,omitempty"`
+	// Rules are the active rules, the inherited ones included.
+	Rules []inheritance.Activation `json:"rules"`
+}
+
+// newProfileCommand creates the profile command and its subcommands.
+func newProfileCommand(format *OutputFormat) *cobra.Command {
+	profileCmd := &cobra.Command{ //nolint:exhaustruct // only Use/Short/Long are needed
+		Use:   "profile",
+		Short: "Explore quality profile inheritance",
+		Long:  "Commands for exploring the inheritance of quality profiles and the rules it activates.",
+	}
+
+	profileCmd.AddCommand(newProfileTreeCommand(format))
+	profileCmd.AddCommand(newProfileRulesCommand(format))
+
+	return profileCmd
+}
+
+// newProfileTreeCommand creates the profile tree command.
+func newProfileTreeCommand(format *OutputFormat) *cobra.Command {
+	var language, treeFormat string
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the profile tree command
+		Use:   "tree",
+		Short: "Print the inheritance tree of the quality profiles",
+		Long: `Print the inheritance of the quality profiles of a language, or of every
+language, with the number of active rules and of projects of each profile.
+
+The forests are printed as indented trees, a Graphviz graph (--format dot) or a
+Mermaid flowchart (--format mermaid), or in the format given by --output.`,
+		Example: `  sonar-cli profile tree --language java
+  sonar-cli profile tree --format dot | dot -Tsvg > profiles.svg`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runProfileTree(cmd, language, treeFormat, *format)
+		},
+	}
+
+	cmd.Flags().StringVar(&language, "language", "", "Language of the quality profiles (default: every language)")
+	cmd.Flags().StringVar(&treeFormat, "format", treeFormatTree, "Format of the tree: tree, dot or mermaid")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{treeFormatTree, treeFormatDOT, treeFormatMermaid}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// runProfileTree builds the inheritance forests and prints them.
+func runProfileTree(cmd *cobra.Command, language, treeFormat string, format OutputFormat) error {
+	write, found := map[string]func(io.Writer, []*inheritance.Forest) error{
+		treeFormatTree:    inheritance.WriteTree,
+		treeFormatDOT:     inheritance.WriteDOT,
+		treeFormatMermaid: inheritance.WriteMermaid,
+	}[treeFormat]
+	if !found {
+		err := fmt.Errorf("%w %q for --format: must be one of %s, %s, %s",
+			errInvalidFlagValue, treeFormat, treeFormatTree, treeFormatDOT, treeFormatMermaid)
+		Logger().Error("invalid tree format", zap.Error(err))
+
+		return err
+	}
+
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return err
+	}
+
+	forests, err := inheritance.Build(cmd.Context(), client, language)
+	if err != nil {
+		Logger().Error("failed to build the inheritance tree", zap.Error(err))
+
+		return err
+	}
+
+	if cmd.Flags().Changed("output") {
+		return writeResult(cmd, forests, format)
+	}
+
+	recordResult(cmd.Context(), forests)
+
+	err = write(cmd.OutOrStdout(), forests)
+	if err != nil {
+		Logger().Error("failed to print the inheritance tree", zap.Error(err))
+
+		return err
+	}
+
+	return nil
+}
+
+// profileRulesFlags holds the flags of the profile rules command.
+type profileRulesFlags struct {
+	language string
+	project  string
+	rule     string
+}
+
+// newProfileRulesCommand creates the profile rules command.
+func newProfileRulesCommand(format *OutputFormat) *cobra.Command {
+	flags := &profileRulesFlags{} //nolint:exhaustruct // fields are set by Cobra flag binding
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the profile rules command
+		Use:   "rules [<profile>] --language <language>",
+		Short: "Print the effective rules of a quality profile and where they come from",
+		Long: `Print the rules active on a quality profile, the inherited ones included, with
+the profile each activation comes from and the ancestor the rule was first
+activated on.
+
+The profile is given by name, or with --project, as the profile the project uses
+for the language. --rule restricts the output to one rule, to explain why it is
+active on a project.`,
+		Example: `  sonar-cli profile rules "Company Java" --language java
+  sonar-cli profile rules --project my-app --language java --rule java:S1135`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			return runProfileRules(cmd, name, flags, *format)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.language, "language", "", "Language of the quality profile")
+	cmd.Flags().StringVar(&flags.project, "project", "", "Project whose quality profile is explained, instead of a profile name")
+	cmd.Flags().StringVar(&flags.rule, "rule", "", "Rule to explain, such as java:S1135")
+
+	_ = cmd.MarkFlagRequired("language")
+
+	return cmd
+}
+
+// runProfileRules resolves the effective rules of a profile and prints them.
+func runProfileRules(cmd *cobra.Command, name string, flags *profileRulesFlags, format OutputFormat) error {
+	if (name == "") == (flags.project == "") {
+		err := fmt.Errorf("%w: give either a profile name or --project", errInvalidFlagValue)
+		Logger().Error("invalid profile selection", zap.Error(err))
+
+		return err
+	}
+
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return err
+	}
+
+	profile, err := resolveProfile(cmd, client, name, flags)
+	if err != nil {
+		return err
+	}
+
+	rules, err := inheritance.EffectiveRules(cmd.Context(), client, profile)
+	if err != nil {
+		Logger().Error("failed to resolve the effective rules", zap.String("profile", profile.Name), zap.Error(err))
+
+		return err
+	}
+
+	if flags.rule != "" {
+		rules = slices.DeleteFunc(rules, func(activation inheritance.Activation) bool { return activation.Rule != flags.rule })
+	}
+
+	report := &ProfileRulesReport{
+		Profile:   profile.Name,
+		Language:  profile.Language,
+		Project:   flags.project,
+		Ancestors: make([]string, 0, len(profile.Ancestors())),
+		Rules:     rules,
+	}
+
+	for _, ancestor := range profile.Ancestors() {
+		report.Ancestors = append(report.Ancestors, ancestor.Name)
+	}
+
+	if cmd.Flags().Changed("output") {
+		return writeResult(cmd, report, format)
+	}
+
+	recordResult(cmd.Context(), report)
+	writeProfileRules(cmd.OutOrStdout(), report, flags.rule)
+
+	return nil
+}
+
+// resolveProfile finds the profile named name, or used by the project, in the
+// inheritance forest of the language.
+func resolveProfile(cmd *cobra.Command, client *sonar.Client, name string, flags *profileRulesFlags) (*inheritance.Profile, error) {
+	forests, err := inheritance.Build(cmd.Context(), client, flags.language)
+	if err != nil {
+		Logger().Error("failed to build the inheritance tree", zap.Error(err))
+
+		return nil, err
+	}
+
+	var profile *inheritance.Profile
+
+	if flags.project != "" {
+		search, _, err := client.Qualityprofiles.Search(cmd.Context(), &sonar.QualityprofilesSearchOptions{ //nolint:exhaustruct // profile of the project
+			Language: flags.language,
+			Project:  flags.project,
+		})
+		if err != nil {
+			Logger().Error("failed to find the quality profile of the project", zap.String("project", flags.project), zap.Error(err))
+
+			return nil, fmt.Errorf("failed to find the quality profile of project %s: %w", flags.project, err)
+		}
+
+		if len(search.Profiles) > 0 {
+			profile = inheritance.Lookup(forests, search.Profiles[0].Key)
+		}
+
+		name = "used by project " + flags.project
+	} else if idx := slices.IndexFunc(forests, func(forest *inheritance.Forest) bool { return forest.Language == flags.language }); idx >= 0 {
+		profile = forests[idx].Named(name)
+	}
+
+	if profile == nil {
+		err := fmt.Errorf("%w: %s for language %s", errProfileNotFound, name, flags.language)
+		Logger().Error("quality profile not found", zap.Error(err))
+
+		return nil, withExitCode(exitNotFound, err)
+	}
+
+	return profile, nil
+}
+
+// writeProfileRules prints the effective rules of a profile as a table.
+func writeProfileRules(writer io.Writer, report *ProfileRulesReport, rule string) {
+	chain := append([]string{report.Profile}, report.Ancestors...)
+	_, _ = fmt.Fprintf(writer, "Quality profile %s (%s)\n", strings.Join(chain, " < "), report.Language)
+
+	if len(report.Rules) == 0 {
+		if rule != "" {
+			_, _ = fmt.Fprintf(writer, "Rule %s is not active.\n", rule)
+		} else {
+			_, _ = fmt.Fprintln(writer, "No active rules.")
+		}
+
+		return
+	}
+
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+	_, _ = fmt.Fprintln(table, "RULE\tSEVERITY\tINHERIT\tSOURCE\tACTIVATED ON\tPARAMS")
+
+	for _, activation := range report.Rules {
+		source := activation.Source
+		if activation.Overrides != "" {
+			source += " (overrides " + activation.Overrides + ")"
+		}
+
+		params := make([]string, 0, len(activation.Params))
+
+		for _, key := range slices.Sorted(maps.Keys(activation.Params)) {
+			params = append(params, key+"="+activation.Params[key])
+		}
+
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", activation.Rule, activation.Severity,
+			activation.Inherit, source, activation.ActivatedOn, strings.Join(params, " "))
+	}
+
+	_ = table.Flush()
+}
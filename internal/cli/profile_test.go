@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runProfileCLI runs the profile command against a server with a Company profile
+// inheriting from Sonar way, used by project app, and returns its output.
+func runProfileCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		switch {
+		case r.URL.Path == "/api/qualityprofiles/search" && query.Get("project") == "app":
+			_, _ = w.Write([]byte(`{"profiles": [{"key": "AX2", "name": "Company", "language": "java"}]}`))
+		case r.URL.Path == "/api/qualityprofiles/search" && query.Get("project") != "":
+			_, _ = w.Write([]byte(`{"profiles": []}`))
+		case r.URL.Path == "/api/qualityprofiles/search":
+			_, _ = w.Write([]byte(`{"profiles": [
+				{"key": "AX1", "name": "Sonar way", "language": "java", "isBuiltIn": true, "activeRuleCount": 1},
+				{"key": "AX2", "name": "Company", "language": "java", "parentKey": "AX1", "isDefault": true,
+					"activeRuleCount": 2, "projectCount": 1}]}`))
+		case r.URL.Path == "/api/rules/search" && query.Get("qprofile") == "AX1":
+			_, _ = w.Write([]byte(`{"paging": {"total": 1}, "rules": [{"key": "java:S100"}], "actives": {
+				"java:S100": [{"qProfile": "AX1", "inherit": "NONE", "severity": "MAJOR"}]}}`))
+		case r.URL.Path == "/api/rules/search" && query.Get("qprofile") == "AX2":
+			_, _ = w.Write([]byte(`{"paging": {"total": 2}, "rules": [{"key": "java:S100"}, {"key": "java:S101"}], "actives": {
+				"java:S100": [{"qProfile": "AX2", "inherit": "OVERRIDES", "severity": "BLOCKER"}],
+				"java:S101": [{"qProfile": "AX2", "inherit": "NONE", "severity": "MINOR",
+					"params": [{"key": "max", "value": "10"}]}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	var out bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().VarP(&outputFormatFlag{target: &format}, "output", "o", "")
+	rootCmd.AddCommand(newProfileCommand(&format))
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"profile"}, args...))
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+
+	return out.String(), err
+}
+
+// TestProfileTree_Formats tests that profile tree prints the forests in each format.
+func TestProfileTree_Formats(t *testing.T) {
+	out, err := runProfileCLI(t, "tree")
+	require.NoError(t, err)
+	assert.Equal(t, "java\n└── Sonar way (built-in, 1 rule)\n    └── Company (default, 2 rules, 1 project)\n", out)
+
+	out, err = runProfileCLI(t, "tree", "--format", "dot")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"AX1" -> "AX2";`)
+
+	out, err = runProfileCLI(t, "tree", "--format", "mermaid")
+	require.NoError(t, err)
+	assert.Contains(t, out, "p1 --> p2")
+
+	out, err = runProfileCLI(t, "tree", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"children"`)
+}
+
+// TestProfileTree_InvalidFormat tests that an unknown tree format is rejected.
+func TestProfileTree_InvalidFormat(t *testing.T) {
+	_, err := runProfileCLI(t, "tree", "--format", "svg")
+	require.ErrorIs(t, err, errInvalidFlagValue)
+	assert.Equal(t, exitValidation, ExitCode(err))
+}
+
+// TestProfileRules_ByName tests that profile rules prints the source of each activation.
+func TestProfileRules_ByName(t *testing.T) {
+	out, err := runProfileCLI(t, "rules", "Company", "--language", "java")
+	require.NoError(t, err)
+	assert.Equal(t, `Quality profile Company < Sonar way (java)
+RULE       SEVERITY  INHERIT    SOURCE                         ACTIVATED ON  PARAMS
+java:S100  BLOCKER   OVERRIDES  Company (overrides Sonar way)  Sonar way     `+`
+java:S101  MINOR     NONE       Company                        Company       max=10
+`, out)
+}
+
+// TestProfileRules_Project tests that profile rules resolves the profile of a project
+// and restricts the output to one rule.
+func TestProfileRules_Project(t *testing.T) {
+	out, err := runProfileCLI(t, "rules", "--project", "app", "--language", "java", "--rule", "java:S101", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"project": "app"`)
+	assert.Contains(t, out, `"rule": "java:S101"`)
+	assert.NotContains(t, out, "java:S100")
+
+	out, err = runProfileCLI(t, "rules", "--project", "app", "--language", "java", "--rule", "java:S999")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Rule java:S999 is not active.\n")
+}
+
+// TestProfileRules_NotFound tests that an unknown profile or project exits with the
+// not found status, and that a profile must be selected exactly once.
+func TestProfileRules_NotFound(t *testing.T) {
+	_, err := runProfileCLI(t, "rules", "Missing", "--language", "java")
+	require.ErrorIs(t, err, errProfileNotFound)
+	assert.Equal(t, exitNotFound, ExitCode(err))
+
+	_, err = runProfileCLI(t, "rules", "--project", "other", "--language", "java")
+	require.ErrorIs(t, err, errProfileNotFound)
+
+	_, err = runProfileCLI(t, "rules", "Company", "--project", "app", "--language", "java")
+	require.ErrorIs(t, err, errInvalidFlagValue)
+}
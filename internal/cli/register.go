@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -24,6 +26,10 @@ var streamingMethods = map[string]struct{}{
 //nolint:gochecknoglobals // constant configuration set
 var skipMethods = map[string]struct{}{
 	"Validate": {},
+	// Polling helpers; "gate check" exposes them with CI-oriented flags.
+	"WaitFor": {},
+	// Multi-request helpers; "plan" and "apply" reconcile profile rules from files.
+	"Reconcile": {},
 }
 
 // RegisterAllCommands discovers all services on the sonar.Client and registers
@@ -136,8 +142,8 @@ func buildMethodCommand(serviceName string, _ reflect.Type, method reflect.Metho
 		Use:   kebabName,
 		Short: description,
 		Long:  fmt.Sprintf("%s.%s - %s", serviceName, methodName, description),
-		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runMethodCommand(cmd, serviceName, methodName, optValue, isStreaming, canPaginate, pattern, responseType, format)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMethodCommand(cmd, args, serviceName, methodName, optValue, isStreaming, canPaginate, pattern, responseType, format)
 		},
 	}
 
@@ -145,6 +151,43 @@ func buildMethodCommand(serviceName string, _ reflect.Type, method reflect.Metho
 	if hasOpt {
 		optValue = reflect.New(optType)
 		BindFlags(cmd, optValue.Interface())
+		registerDynamicCompletions(cmd)
+		addFromFileFlag(cmd, optValue)
+	}
+
+	applyAPISpec(cmd, serviceName, methodName, optValue)
+
+	if flag := positionalFlag(cmd); flag != nil {
+		addPositionalArgs(cmd, flag)
+	}
+
+	// Positional arguments and explicit flags take precedence over the options file;
+	// required flags still missing afterwards are asked for, and all values are checked
+	// against the API specification.
+	loadOptions := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if loadOptions != nil {
+			err := applyPositionalArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = loadOptions(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = promptRequiredFlags(cmd)
+			if err != nil {
+				return err
+			}
+		}
+
+		return checkAPISpec(cmd)
+	}
+
+	if isDestructiveName(methodName) {
+		addYesFlag(cmd)
 	}
 
 	// Add --all flag for paginated methods.
@@ -156,8 +199,13 @@ func buildMethodCommand(serviceName string, _ reflect.Type, method reflect.Metho
 }
 
 // runMethodCommand executes a service method command, handling streaming, pagination, and normal invocation.
+// With several positional arguments, the method is called once for each and the results are printed as a list.
+// A failed target does not stop the others: the results of the rest are printed, and the errors returned.
+//
+//nolint:cyclop // unavoidable complexity for the streaming, single and fan-out paths
 func runMethodCommand(
 	cmd *cobra.Command,
+	args []string,
 	serviceName, methodName string,
 	optValue reflect.Value,
 	isStreaming, canPaginate bool,
@@ -182,39 +230,136 @@ func runMethodCommand(
 	}
 
 	if isStreaming {
-		return InvokeStreamingMethod(service, methodName, optValue, os.Stdout)
+		err = InvokeStreamingMethod(cmd.Context(), service, methodName, optValue, os.Stdout)
+		if isDryRun(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	call := func() (any, error) {
+		return callMethod(cmd, service, serviceName, methodName, optValue, canPaginate, pattern, responseType)
+	}
+
+	targets := fanOutTargets(cmd, args)
+	if targets == nil {
+		err = confirmMethodCall(cmd, client, service, methodName, optValue, pattern)
+		if err != nil {
+			return err
+		}
+
+		result, callErr := call()
+		if isDryRun(callErr) {
+			return nil
+		}
+
+		if callErr != nil && result != nil && isInterrupted(cmd) {
+			return writePartialResult(cmd, result, *format, callErr)
+		}
+
+		if callErr != nil {
+			return callErr
+		}
+
+		return writeResult(cmd, result, *format)
+	}
+
+	flag := positionalFlag(cmd)
+
+	err = confirmFanOut(cmd, client, service, methodName, optValue, pattern, flag.Name, targets)
+	if err != nil {
+		return err
+	}
+
+	results := make([]any, 0, len(targets))
+
+	var errs []error
+
+	for _, target := range targets {
+		_ = cmd.Flags().Set(flag.Name, target)
+
+		result, callErr := call()
+		if isDryRun(callErr) {
+			continue
+		}
+
+		if result != nil {
+			results = append(results, result)
+		}
+
+		if callErr != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", flag.Name, target, callErr))
+		}
+
+		if callErr != nil && isInterrupted(cmd) {
+			break
+		}
+	}
+
+	err = errors.Join(errs...)
+
+	if len(results) == 0 {
+		return err
+	}
+
+	if err != nil && isInterrupted(cmd) {
+		return writePartialResult(cmd, results, *format, err)
+	}
+
+	return errors.Join(err, writeResult(cmd, results, *format))
+}
+
+// writePartialResult prints what an interrupted command fetched before it stopped, with a
+// warning that it is incomplete, and returns the interruption error.
+func writePartialResult(cmd *cobra.Command, result any, format OutputFormat, callErr error) error {
+	Logger().Warn("command interrupted, output is partial", zap.Error(context.Cause(cmd.Context())))
+
+	err := writeResult(cmd, result, format)
+	if err != nil {
+		return errors.Join(callErr, err)
 	}
 
+	return callErr
+}
+
+// callMethod calls a service method once with the current option values, fetching all
+// pages when --all is set. Invocation errors are logged, except for dry runs.
+func callMethod(
+	cmd *cobra.Command,
+	service reflect.Value,
+	serviceName, methodName string,
+	optValue reflect.Value,
+	canPaginate bool,
+	pattern MethodReturnPattern,
+	responseType reflect.Type,
+) (any, error) {
 	// Check --all flag for pagination.
 	allPages, _ := cmd.Flags().GetBool("all")
 
 	if allPages && canPaginate {
-		result, paginateErr := PaginateAll(service, methodName, optValue, pattern, responseType)
-		if paginateErr != nil {
+		result, paginateErr := PaginateAll(cmd.Context(), service, methodName, optValue, pattern, responseType)
+		if paginateErr != nil && !isDryRun(paginateErr) {
 			Logger().Error("pagination failed",
 				zap.String("service", serviceName),
 				zap.String("method", methodName),
 				zap.Error(paginateErr))
-
-			return paginateErr
 		}
 
-		return FormatOutput(os.Stdout, result, *format)
+		return result, paginateErr
 	}
 
 	hasOpt := optValue.IsValid()
 
-	result, resp, invokeErr := InvokeMethod(service, methodName, optValue, pattern, hasOpt)
+	result, resp, invokeErr := InvokeMethod(cmd.Context(), service, methodName, optValue, pattern, hasOpt)
 	defer CloseBody(resp)
 
-	if invokeErr != nil {
+	if invokeErr != nil && !isDryRun(invokeErr) {
 		Logger().Error("method invocation failed",
 			zap.String("service", serviceName),
 			zap.String("method", methodName),
 			zap.Error(invokeErr))
-
-		return invokeErr
 	}
 
-	return FormatOutput(os.Stdout, result, *format)
+	return result, invokeErr
 }
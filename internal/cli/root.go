@@ -24,6 +24,11 @@ const (
 	completeNoDescDirective = "__completeNoDesc"
 )
 
+// clientFlagNames lists the global flags a sonar.Client is built from.
+//
+//nolint:gochecknoglobals // constant configuration set
+var clientFlagNames = []string{"url", "token", "username", "password", "timeout", "dry-run", "explain", "curl"}
+
 // contextKey is a typed key for storing values in cobra command context.
 type contextKey string
 
@@ -31,12 +36,18 @@ const clientContextKey contextKey = "sonar-client"
 
 // globalFlags holds the global CLI flag values.
 type globalFlags struct {
-	url      string
-	token    string
-	username string
-	password string
-	output   OutputFormat
-	timeout  time.Duration
+	url         string
+	token       string
+	username    string
+	password    string
+	output      OutputFormat
+	timeout     time.Duration
+	deadline    time.Duration
+	errorFormat string
+	table       TableOptions
+	dryRun      bool
+	explain     bool
+	curl        bool
 }
 
 // Execute creates the root command, registers all subcommands, and runs the CLI.
@@ -46,6 +57,26 @@ func Execute() error {
 
 	defer Sync() //nolint:errcheck
 
+	rootCmd := newCommandTree()
+	rootCmd.AddCommand(newShellCommand(newCommandTree))
+
+	ctx, stop := notifyContext(context.Background())
+	defer stop()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	err = interruptionError(cmd.Context(), err)
+
+	if err != nil && reportsJSONErrors(rootCmd) {
+		writeErrorReport(os.Stderr, err)
+	}
+
+	return err //nolint:wrapcheck // errors are logged at source (initClient, runMethodCommand)
+}
+
+// newCommandTree builds a root command with its global flags and every service, utility
+// and plugin subcommand. The interactive shell builds a fresh tree for each line it runs,
+// so no flag state leaks from one command to the next.
+func newCommandTree() *cobra.Command {
 	flags := &globalFlags{ //nolint:exhaustruct // fields are set by Cobra flag binding
 		output: defaultOutputFormat,
 	}
@@ -53,8 +84,29 @@ func Execute() error {
 	rootCmd := buildRootCommand(flags)
 
 	RegisterAllCommands(rootCmd, &flags.output)
+	addServiceSubcommand(rootCmd, "qualitygates", newSimulateCommand(&flags.output))
+	addServiceSubcommand(rootCmd, "permissions", newMatrixCommand(&flags.output))
+	addServiceSubcommand(rootCmd, "permissions", newTemplateCommand(&flags.output))
+	rootCmd.AddCommand(newAPICommand(&flags.output))
+	rootCmd.AddCommand(newBatchCommand(&flags.output))
+	rootCmd.AddCommand(newGateCommand(&flags.output))
+	rootCmd.AddCommand(newPlanCommand(&flags.output))
+	rootCmd.AddCommand(newApplyCommand(&flags.output))
+	rootCmd.AddCommand(newExportCommand(&flags.output))
+	rootCmd.AddCommand(newDiffCommand(flags))
+	rootCmd.AddCommand(newProfileCommand(&flags.output))
+	registerPlugins(rootCmd, flags)
+
+	return rootCmd
+}
 
-	return rootCmd.Execute() //nolint:wrapcheck // errors are logged at source (initClient, runMethodCommand)
+// addServiceSubcommand adds a hand-written command to a generated service command.
+func addServiceSubcommand(rootCmd *cobra.Command, service string, subCmd *cobra.Command) {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == service {
+			cmd.AddCommand(subCmd)
+		}
+	}
 }
 
 // buildRootCommand creates and configures the root Cobra command with global flags.
@@ -72,7 +124,16 @@ Usage:
 Examples:
   sonar-cli --token mytoken issues search --severities CRITICAL,MAJOR
   sonar-cli --url http://sonar:9000 --token mytoken projects search --all
-  sonar-cli --output table qualitygates list`,
+  sonar-cli --deadline 2m issues search --projects my-app --all
+  sonar-cli --output table qualitygates list
+  sonar-cli api GET issues/search -f projects=foo -f ps=500
+  sonar-cli --dry-run --curl projects bulk-delete --projects old-project
+  sonar-cli -o table issues search --columns key,severity,impacts[0].severity --sort-by -severity
+  sonar-cli gate check --project my-app --branch main
+  sonar-cli plan sonar.yaml
+  sonar-cli export backup/
+  sonar-cli diff --from context:prod --to context:staging
+  sonar-cli shell`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
@@ -99,28 +160,74 @@ func registerGlobalFlags(cmd *cobra.Command, flags *globalFlags) {
 	persistentFlags.StringVar(&flags.username, "username", os.Getenv("SONAR_CLI_USERNAME"), "Username for basic authentication (also read from SONAR_CLI_USERNAME env var)")
 	persistentFlags.StringVar(&flags.password, "password", os.Getenv("SONAR_CLI_PASSWORD"), "Password for basic authentication (also read from SONAR_CLI_PASSWORD env var)")
 	persistentFlags.DurationVar(&flags.timeout, "timeout", defaultTimeout, "HTTP request timeout")
+	persistentFlags.DurationVar(&flags.deadline, deadlineFlag, 0, "Maximum run time of the whole command, across all its requests and pages (0 for none)")
 
 	// Output format with custom validation.
 	flags.output = defaultOutputFormat
 
-	persistentFlags.Var(&outputFormatFlag{target: &flags.output}, "output", "Output format: json, table, yaml")
+	persistentFlags.VarP(&outputFormatFlag{target: &flags.output}, "output", "o", "Output format: json, table, wide, yaml")
+	persistentFlags.Var(&errorFormatValue{target: &flags.errorFormat}, errorFormatFlag,
+		"Error format on stderr: text (log lines), or json (a {code, status, endpoint, messages} document)")
+
+	// Table rendering options (table and wide output only).
+	persistentFlags.StringSliceVar(&flags.table.Columns, columnsFlag, nil, "Table columns to show, as paths into each row (e.g. key,impacts[0].severity)")
+	persistentFlags.StringVar(&flags.table.SortBy, sortByFlag, "", "Table column to sort rows by (prefix with - for descending order)")
+	persistentFlags.BoolVar(&flags.table.NoHeaders, noHeadersFlag, false, "Omit the header row in table output")
+
+	// Request inspection.
+	persistentFlags.BoolVar(&flags.dryRun, "dry-run", false, "Print the HTTP request that would be sent and exit without sending it")
+	persistentFlags.BoolVar(&flags.explain, "explain", false, "Log each HTTP request and its response status to stderr")
+	persistentFlags.BoolVar(&flags.curl, "curl", false, "With --dry-run or --explain, also print an equivalent curl command (credentials redacted)")
 }
 
 // initClient creates a sonar.Client from global flags and stores it in the command context.
 // This runs as PersistentPreRunE so the client is available to all subcommands.
 // It skips initialization for the completion command and shell completion directives.
 func initClient(cmd *cobra.Command, args []string, globalFlags *globalFlags) error {
+	applyErrorFormat(globalFlags.errorFormat)
+
 	if shouldSkipClientInit(cmd, args) {
 		return nil
 	}
 
+	applyDeadline(cmd, globalFlags.deadline)
+
+	// The interactive shell provides a persistent client; keep it unless this command
+	// overrides a flag the client is built from.
+	if _, ok := cmd.Context().Value(clientContextKey).(*sonar.Client); ok && !clientFlagsChanged(cmd) {
+		return nil
+	}
+
+	client, err := newClient(globalFlags)
+	if err != nil {
+		return err
+	}
+
+	cmd.SetContext(context.WithValue(cmd.Context(), clientContextKey, client))
+
+	return nil
+}
+
+// clientFlagsChanged reports whether any flag the client is built from was set on the command line.
+func clientFlagsChanged(cmd *cobra.Command) bool {
+	for _, name := range clientFlagNames {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newClient builds a sonar.Client from the global connection flags.
+func newClient(globalFlags *globalFlags) (*sonar.Client, error) {
 	opts := &sonar.ClientCreateOptions{} //nolint:exhaustruct // fields set conditionally below
 
 	if globalFlags.url == "" {
 		err := errors.New("server URL must be provided via --url flag or SONAR_CLI_URL env var")
 		Logger().Error("missing required configuration", zap.Error(err))
 
-		return err
+		return nil, err
 	}
 
 	opts.URL = &globalFlags.url
@@ -131,16 +238,34 @@ func initClient(cmd *cobra.Command, args []string, globalFlags *globalFlags) err
 	}
 	opts.HttpClient = httpClient
 
-	client, err := sonar.NewClient(opts)
+	client, err := sonar.NewClient(opts, requestInspectionOptions(globalFlags)...)
 	if err != nil {
 		Logger().Error("failed to initialize SonarQube client", zap.Error(err))
 
-		return fmt.Errorf("failed to create SonarQube client: %w", err)
+		return nil, fmt.Errorf("failed to create SonarQube client: %w", err)
 	}
 
-	cmd.SetContext(context.WithValue(cmd.Context(), clientContextKey, client))
+	return client, nil
+}
 
-	return nil
+// requestInspectionOptions returns the client options implementing --dry-run and --explain.
+// Dry-run wraps explain so that a dry run never reaches the logging (or the network).
+func requestInspectionOptions(globalFlags *globalFlags) []sonar.ClientOptionFunc {
+	var middlewares []sonar.Middleware
+
+	if globalFlags.dryRun {
+		middlewares = append(middlewares, dryRunMiddleware(os.Stdout, globalFlags.curl))
+	}
+
+	if globalFlags.explain {
+		middlewares = append(middlewares, explainMiddleware(globalFlags.curl))
+	}
+
+	if len(middlewares) == 0 {
+		return nil
+	}
+
+	return []sonar.ClientOptionFunc{sonar.WithMiddleware(middlewares...)}
 }
 
 // shouldSkipClientInit checks if client initialization should be skipped.
@@ -220,12 +345,12 @@ func (f *outputFormatFlag) String() string {
 // Set validates and sets the output format.
 func (f *outputFormatFlag) Set(val string) error {
 	switch OutputFormat(val) {
-	case OutputJSON, OutputTable, OutputYAML:
+	case OutputJSON, OutputTable, OutputWide, OutputYAML:
 		*f.target = OutputFormat(val)
 
 		return nil
 	default:
-		return fmt.Errorf("invalid output format %q: must be one of json, table, yaml", val)
+		return fmt.Errorf("invalid output format %q: must be one of json, table, wide, yaml", val)
 	}
 }
 
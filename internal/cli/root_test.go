@@ -18,7 +18,7 @@ func TestBuildRootCommand(t *testing.T) {
 	assert.True(t, cmd.SilenceErrors)
 
 	// Verify global flags are registered.
-	globalFlagNames := []string{"url", "token", "username", "password", "output", "timeout"}
+	globalFlagNames := []string{"url", "token", "username", "password", "output", "timeout", "columns", "sort-by", "no-headers"}
 	for _, name := range globalFlagNames {
 		f := cmd.PersistentFlags().Lookup(name)
 		assert.NotNil(t, f, "expected persistent flag %q", name)
@@ -51,6 +51,7 @@ func TestOutputFormatFlag(t *testing.T) {
 		{name: "json", input: "json", want: OutputJSON},
 		{name: "table", input: "table", want: OutputTable},
 		{name: "yaml", input: "yaml", want: OutputYAML},
+		{name: "wide", input: "wide", want: OutputWide},
 		{name: "invalid", input: "xml", wantErr: true},
 		{name: "empty", input: "", wantErr: true},
 	}
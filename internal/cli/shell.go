@@ -0,0 +1,659 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+const (
+	// shellPrompt is the interactive shell prompt.
+	shellPrompt = "sonar> "
+	// projectionSuffix marks a query path segment mapping the rest of the path over a slice.
+	projectionSuffix = "[]"
+	// pipePrefix starts a shell line applying a query to the previous result.
+	pipePrefix = "|"
+)
+
+// resultContextKey is the context key of the holder receiving a command's result in the shell.
+const resultContextKey contextKey = "shell-result"
+
+// errNoPreviousResult is returned when a pipe is used before any command produced a result.
+var errNoPreviousResult = errors.New("no previous result to pipe")
+
+// shellBuiltins lists the commands handled by the shell itself.
+//
+//nolint:gochecknoglobals // constant configuration set
+var shellBuiltins = []string{"exit", "help", "history", "quit", "set", "unset"}
+
+// shellHelp documents the shell built-in commands.
+const shellHelp = `Shell commands:
+  <service> <method> [flags]   run any sonar-cli command, e.g. "issues search --projects foo"
+  | --query <path> [-o format] query the previous result, e.g. "| --query issues[].key"
+  set [name=value ...]         set session variables used as default flag values, or list them
+  unset <name> ...             remove session variables
+  history                      show the command history
+  help [command]               show this help, or the help of a command
+  exit, quit                   leave the shell (Ctrl-D also works)
+`
+
+// shellResult receives the result of a command run from the shell.
+type shellResult struct {
+	value any
+	set   bool
+}
+
+// recordResult stores a command result for the interactive shell, if the command runs in one.
+func recordResult(ctx context.Context, result any) {
+	if holder, ok := ctx.Value(resultContextKey).(*shellResult); ok {
+		holder.value = result
+		holder.set = true
+	}
+}
+
+// writeResult records a command result for the shell and writes it in the requested format.
+func writeResult(cmd *cobra.Command, result any, format OutputFormat) error {
+	recordResult(cmd.Context(), result)
+
+	return FormatOutputWithOptions(cmd.OutOrStdout(), result, format, tableOptionsFromFlags(cmd, format))
+}
+
+// newShellCommand creates the shell command. newTree builds the command tree each
+// shell line is executed against.
+func newShellCommand(newTree func() *cobra.Command) *cobra.Command {
+	return &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the shell command
+		Use:   "shell",
+		Short: "Start an interactive sonar-cli shell",
+		Long: `Start an interactive shell running sonar-cli commands against a single,
+persistent client, so the connection is reused between commands.
+
+Global flags given to the shell command apply to every command in the session.
+On a terminal, the shell supports line editing, history (saved in the user cache
+directory) and tab completion of commands, flags and server values.
+
+` + shellHelp + `
+Examples:
+  sonar> set project=my-app
+  sonar> project-branches list
+  sonar> | --query branches[].name -o yaml
+  sonar> issues search --projects my-app --severities BLOCKER
+  sonar> | --query issues[0].key`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{sessionAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runShell(cmd, newTree)
+		},
+	}
+}
+
+// runShell starts a shell session on the standard streams.
+func runShell(cmd *cobra.Command, newTree func() *cobra.Command) error {
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	session := newShellSession(cmd, newTree, client)
+
+	// Signals interrupt the command running in the session, not the session itself:
+	// release them here and let execute catch them for each command.
+	signal.Reset(os.Interrupt, syscall.SIGTERM)
+
+	var reader lineReader
+
+	stdinFd := int(os.Stdin.Fd()) //nolint:gosec // file descriptors fit in an int
+	if term.IsTerminal(stdinFd) {
+		reader = newTerminalLineReader(stdinFd, os.Stdin, os.Stdout, session.history, session.complete)
+
+		_, _ = fmt.Fprintln(session.out, `sonar-cli shell - type "help" for shell commands, "exit" to quit`)
+	} else {
+		reader = &plainLineReader{scanner: bufio.NewScanner(cmd.InOrStdin())}
+	}
+
+	return session.run(cmd.Context(), reader)
+}
+
+// shellSession holds the state shared by the commands of a shell session.
+//
+//nolint:govet // fieldalignment: keeping logical field grouping for readability
+type shellSession struct {
+	newTree func() *cobra.Command
+	client  *sonar.Client
+	// vars holds the session variables, keyed by flag name.
+	vars map[string]string
+	// connection holds the connection flags given to the shell, keyed by flag name. They
+	// are re-applied when a line overrides another connection flag, and for completion.
+	connection map[string]string
+	history    *shellHistory
+	last       any
+	hasLast    bool
+	out        io.Writer
+	errOut     io.Writer
+}
+
+// newShellSession creates a session for the shell command. Global flags set on the
+// shell command become session variables, except the connection flags: those are
+// already applied to the session client.
+func newShellSession(cmd *cobra.Command, newTree func() *cobra.Command, client *sonar.Client) *shellSession {
+	session := &shellSession{ //nolint:exhaustruct // last result is set by commands
+		newTree:    newTree,
+		client:     client,
+		vars:       make(map[string]string),
+		connection: make(map[string]string),
+		history:    loadShellHistory(),
+		out:        cmd.OutOrStdout(),
+		errOut:     cmd.ErrOrStderr(),
+	}
+
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		value := flagValueString(flag)
+
+		if slices.Contains(clientFlagNames, flag.Name) {
+			session.connection[flag.Name] = value
+		} else {
+			session.vars[flag.Name] = value
+		}
+	})
+
+	return session
+}
+
+// flagValueString returns a flag value in command-line syntax.
+func flagValueString(flag *pflag.Flag) string {
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		return strings.Join(slice.GetSlice(), ",")
+	}
+
+	return flag.Value.String()
+}
+
+// run reads and executes lines until exit or end of input. Command errors are reported
+// and the session continues.
+func (s *shellSession) run(ctx context.Context, reader lineReader) error {
+	for {
+		line, err := reader.ReadLine(shellPrompt)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		s.history.add(line)
+
+		done, err := s.execLine(ctx, line)
+		if err != nil {
+			_, _ = fmt.Fprintf(s.errOut, "Error: %v\n", err)
+		}
+
+		if done {
+			return nil
+		}
+	}
+}
+
+// execLine executes one shell line and reports whether the session should end.
+func (s *shellSession) execLine(ctx context.Context, line string) (bool, error) {
+	if rest, ok := strings.CutPrefix(line, pipePrefix); ok {
+		return false, s.pipe(rest)
+	}
+
+	words, err := splitShellWords(line)
+	if err != nil {
+		return false, err
+	}
+
+	if len(words) == 0 {
+		return false, nil
+	}
+
+	switch words[0] {
+	case "exit", "quit":
+		return true, nil
+	case "set":
+		return false, s.set(words[1:])
+	case "unset":
+		for _, name := range words[1:] {
+			delete(s.vars, strings.TrimPrefix(name, "--"))
+		}
+
+		return false, nil
+	case "history":
+		for idx, entry := range s.history.entries {
+			_, _ = fmt.Fprintf(s.out, "%5d  %s\n", idx+1, entry)
+		}
+
+		return false, nil
+	case "help":
+		if len(words) == 1 {
+			_, _ = fmt.Fprint(s.out, shellHelp)
+
+			return false, nil
+		}
+	}
+
+	return false, s.execute(ctx, words)
+}
+
+// set assigns session variables, or lists them when called without arguments.
+func (s *shellSession) set(assignments []string) error {
+	if len(assignments) == 0 {
+		for _, name := range sortedMapKeys(s.vars) {
+			_, _ = fmt.Fprintf(s.out, "%s=%s\n", name, s.vars[name])
+		}
+
+		return nil
+	}
+
+	for _, assignment := range assignments {
+		name, value, ok := strings.Cut(assignment, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid assignment %q: expected name=value", assignment)
+		}
+
+		s.vars[strings.TrimPrefix(name, "--")] = value
+	}
+
+	return nil
+}
+
+// execute runs a sonar-cli command line against a fresh command tree and the session client.
+// A line overriding a connection flag gets its own client, built from the shell's other
+// connection flags and the override.
+func (s *shellSession) execute(ctx context.Context, words []string) error {
+	rootCmd := s.newTree()
+
+	if leaf, args, err := rootCmd.Find(words); err == nil && isPluginCommand(leaf) {
+		// Plugins run in their own process and cannot share the session client: pass them
+		// the connection and the global variables as leading global flags.
+		words = slices.Concat(globalFlagWords(rootCmd, s.connection, s.vars), words)
+	} else if err == nil {
+		words = injectFlags(leaf, words, sessionDefaults(leaf, args, s.vars))
+
+		if slices.ContainsFunc(clientFlagNames, func(name string) bool {
+			flag := leaf.Flag(name)
+
+			return flag != nil && wordsSetFlag(words, flag)
+		}) {
+			words = injectFlags(leaf, words, s.connection)
+		}
+	}
+
+	holder := &shellResult{value: nil, set: false}
+
+	ctx = context.WithValue(ctx, clientContextKey, s.client)
+	ctx = context.WithValue(ctx, resultContextKey, holder)
+
+	rootCmd.SetArgs(words)
+	rootCmd.SetOut(s.out)
+	rootCmd.SetErr(s.errOut)
+
+	ctx, stop := notifyContext(ctx)
+	defer stop()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+
+	if holder.set {
+		s.last = holder.value
+		s.hasLast = true
+	}
+
+	err = interruptionError(cmd.Context(), err)
+	if err != nil && reportsJSONErrors(rootCmd) {
+		writeErrorReport(s.errOut, err)
+
+		return nil
+	}
+
+	return err //nolint:wrapcheck // command errors are reported as-is
+}
+
+// injectFlags appends a --name=value flag for each value the command accepts and the
+// line does not set explicitly.
+func injectFlags(leaf *cobra.Command, words []string, values map[string]string) []string {
+	var injected []string
+
+	for _, name := range sortedMapKeys(values) {
+		flag := leaf.Flag(name)
+		if flag == nil || wordsSetFlag(words, flag) {
+			continue
+		}
+
+		injected = append(injected, "--"+name+"="+values[name])
+	}
+
+	if len(injected) == 0 {
+		return words
+	}
+
+	// Flags must precede a "--" terminator to be parsed as flags.
+	end := slices.Index(words, "--")
+	if end < 0 {
+		end = len(words)
+	}
+
+	return slices.Concat(words[:end], injected, words[end:])
+}
+
+// sessionDefaults returns the session variables to inject into a command line. The variable
+// of the command's positional flag is left out when the line passes positional arguments.
+func sessionDefaults(leaf *cobra.Command, args []string, values map[string]string) map[string]string {
+	flag := positionalFlag(leaf)
+	if flag == nil || positionalArgCount(leaf, args) == 0 {
+		return values
+	}
+
+	defaults := maps.Clone(values)
+	delete(defaults, flag.Name)
+
+	return defaults
+}
+
+// globalFlagWords returns a --name=value flag for each value naming a global flag. Earlier
+// value sets take precedence over later ones.
+func globalFlagWords(rootCmd *cobra.Command, valueSets ...map[string]string) []string {
+	var words []string
+
+	seen := make(map[string]bool)
+
+	for _, values := range valueSets {
+		for _, name := range sortedMapKeys(values) {
+			if seen[name] || rootCmd.PersistentFlags().Lookup(name) == nil {
+				continue
+			}
+
+			seen[name] = true
+
+			words = append(words, "--"+name+"="+values[name])
+		}
+	}
+
+	return words
+}
+
+// wordsSetFlag reports whether the command line sets the flag, by name or shorthand.
+func wordsSetFlag(words []string, flag *pflag.Flag) bool {
+	for _, word := range words {
+		if word == "--" {
+			return false
+		}
+
+		if word == "--"+flag.Name || strings.HasPrefix(word, "--"+flag.Name+"=") {
+			return true
+		}
+
+		if flag.Shorthand != "" && !strings.HasPrefix(word, "--") && strings.HasPrefix(word, "-"+flag.Shorthand) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pipe applies a query to the previous result and prints the selection, which becomes
+// the new previous result.
+func (s *shellSession) pipe(rest string) error {
+	words, err := splitShellWords(rest)
+	if err != nil {
+		return err
+	}
+
+	format := defaultOutputFormat
+	if value, ok := s.vars["output"]; ok {
+		format = OutputFormat(value)
+	}
+
+	pipeFlags := pflag.NewFlagSet(pipePrefix, pflag.ContinueOnError)
+	pipeFlags.SetOutput(io.Discard)
+	query := pipeFlags.String("query", "", "path to select from the previous result")
+	pipeFlags.VarP(&outputFormatFlag{target: &format}, "output", "o", "output format")
+
+	err = pipeFlags.Parse(words)
+	if err != nil {
+		return fmt.Errorf("invalid pipe: %w", err)
+	}
+
+	if *query == "" {
+		return errors.New("invalid pipe: expected | --query <path>")
+	}
+
+	if !s.hasLast {
+		return errNoPreviousResult
+	}
+
+	selected, ok := queryResult(reflect.ValueOf(s.last), *query)
+	if !ok {
+		return fmt.Errorf("query %q does not match the previous result", *query)
+	}
+
+	s.last = selected
+
+	return FormatOutputWithOptions(s.out, selected, format, TableOptions{}) //nolint:exhaustruct // default table rendering
+}
+
+// queryResult selects a value with a column-style path ("issues[0].key"). A segment
+// ending in "[]" maps the rest of the path over a slice ("issues[].key").
+func queryResult(val reflect.Value, path string) (any, bool) {
+	path = strings.TrimPrefix(path, ".")
+
+	head, rest, _ := strings.Cut(path, ".")
+
+	base, project := strings.CutSuffix(head, projectionSuffix)
+
+	val, ok := resolvePath(val, base)
+	if !ok {
+		return nil, false
+	}
+
+	if !project {
+		if rest != "" {
+			return queryResult(val, rest)
+		}
+
+		val = indirect(val)
+		if !val.IsValid() {
+			return nil, false
+		}
+
+		return val.Interface(), true
+	}
+
+	val = indirect(val)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	results := make([]any, 0, val.Len())
+
+	for idx := range val.Len() {
+		if rest == "" {
+			results = append(results, val.Index(idx).Interface())
+
+			continue
+		}
+
+		if selected, found := queryResult(val.Index(idx), rest); found {
+			results = append(results, selected)
+		}
+	}
+
+	return results, true
+}
+
+// complete returns the completions of the last word of a shell line, computed by the
+// command tree's own completion logic so dynamic completions work as on the command line.
+func (s *shellSession) complete(line string) ([]string, bool) {
+	words, err := splitShellWords(line)
+	if err != nil {
+		return nil, false
+	}
+
+	partial := ""
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	if len(words) == 0 {
+		builtins := filterPrefix(shellBuiltins, partial)
+		commands, noSpace := s.completeCommand(nil, partial)
+
+		return append(builtins, commands...), noSpace
+	}
+
+	return s.completeCommand(words, partial)
+}
+
+// completeCommand runs the hidden cobra completion command for words and partial.
+func (s *shellSession) completeCommand(words []string, partial string) ([]string, bool) {
+	rootCmd := s.newTree()
+
+	if leaf, args, err := rootCmd.Find(words); err == nil && len(words) > 0 {
+		words = injectFlags(leaf, words, sessionDefaults(leaf, args, s.vars))
+		words = injectFlags(leaf, words, s.connection)
+	}
+
+	var output bytes.Buffer
+
+	rootCmd.SetOut(&output)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs(slices.Concat([]string{completeNoDescDirective}, words, []string{partial}))
+
+	if err := rootCmd.Execute(); err != nil {
+		return nil, false
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) == 0 {
+		return nil, false
+	}
+
+	directive := cobra.ShellCompDirectiveDefault
+
+	if code, ok := strings.CutPrefix(lines[len(lines)-1], ":"); ok {
+		if parsed, err := strconv.Atoi(code); err == nil {
+			directive = cobra.ShellCompDirective(parsed)
+		}
+
+		lines = lines[:len(lines)-1]
+	}
+
+	var candidates []string
+
+	for _, candidate := range lines {
+		if candidate != "" {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates, directive&cobra.ShellCompDirectiveNoSpace != 0
+}
+
+// filterPrefix returns the values starting with prefix.
+func filterPrefix(values []string, prefix string) []string {
+	var matches []string
+
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			matches = append(matches, value)
+		}
+	}
+
+	return matches
+}
+
+// sortedMapKeys returns the keys of a string map in sorted order.
+func sortedMapKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// splitShellWords splits a shell line into words, honouring single quotes, double
+// quotes and backslash escapes like a POSIX shell (without expansions).
+//
+//nolint:cyclop // a single-pass tokenizer is clearest as one state machine
+func splitShellWords(line string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, char := range line {
+		switch {
+		case escaped:
+			current.WriteRune(char)
+
+			escaped = false
+		case quote == '\'':
+			if char == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(char)
+			}
+		case char == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if char == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(char)
+			}
+		case char == '\'' || char == '"':
+			quote = char
+			inWord = true
+		case char == ' ' || char == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+
+				inWord = false
+			}
+		default:
+			current.WriteRune(char)
+
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", line)
+	}
+
+	if inWord {
+		words = append(words, current.String())
+	}
+
+	return words, nil
+}
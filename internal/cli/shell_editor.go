@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	// shellHistoryFile is the file name of the persisted shell history.
+	shellHistoryFile = "shell_history"
+	// shellHistoryLimit is the maximum number of history entries kept.
+	shellHistoryLimit = 1000
+)
+
+// Control keys the shell handles before the terminal line editor.
+const (
+	keyCtrlC = 3
+	keyCtrlE = 5
+	keyEnter = 13
+	keyCtrlU = 21
+)
+
+// lineReader reads one line of shell input.
+type lineReader interface {
+	// ReadLine displays the prompt (when interactive) and returns the next line.
+	// It returns io.EOF when the input is exhausted.
+	ReadLine(prompt string) (string, error)
+}
+
+// completeFunc returns the completions of the last word of line and whether no space
+// should follow an accepted completion.
+type completeFunc func(line string) ([]string, bool)
+
+// plainLineReader reads lines from a non-interactive input such as a script or a pipe.
+type plainLineReader struct {
+	scanner *bufio.Scanner
+}
+
+// ReadLine returns the next input line; the prompt is not displayed.
+func (r *plainLineReader) ReadLine(_ string) (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+
+		return "", io.EOF
+	}
+
+	return r.scanner.Text(), nil
+}
+
+// terminalLineReader edits lines with golang.org/x/term on a terminal switched to raw
+// mode for each read.
+type terminalLineReader struct {
+	fd       int
+	terminal *term.Terminal
+}
+
+// newTerminalLineReader creates a line reader for the terminal fd, reading key presses
+// from in and echoing to out.
+func newTerminalLineReader(fd int, in io.Reader, out io.Writer, history *shellHistory, complete completeFunc) *terminalLineReader {
+	terminal := newShellTerminal(in, out, history, complete)
+
+	width, height, err := term.GetSize(fd)
+	if err == nil {
+		_ = terminal.SetSize(width, height)
+	}
+
+	return &terminalLineReader{fd: fd, terminal: terminal}
+}
+
+// ReadLine reads a line with editing, history and completion.
+func (r *terminalLineReader) ReadLine(prompt string) (string, error) {
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to enable raw terminal mode: %w", err)
+	}
+
+	defer func() { _ = term.Restore(r.fd, state) }()
+
+	r.terminal.SetPrompt(prompt)
+
+	line, err := r.terminal.ReadLine()
+	if errors.Is(err, term.ErrPasteIndicator) {
+		return line, nil
+	}
+
+	return line, err //nolint:wrapcheck // io.EOF must reach the caller unwrapped
+}
+
+// newShellTerminal creates the line editor of the shell: Emacs-style editing keys,
+// history browsing with the arrows and tab completion. Ctrl-C discards the line instead
+// of ending the input as it does in term.Terminal.
+func newShellTerminal(in io.Reader, out io.Writer, history *shellHistory, complete completeFunc) *term.Terminal {
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{&interruptReader{reader: in, pending: nil}, out}, "")
+
+	terminal.History = terminalHistory{history}
+
+	if complete != nil {
+		terminal.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+			if key != '\t' {
+				return "", 0, false
+			}
+
+			return completeLine(terminal, complete, line, pos)
+		}
+	}
+
+	return terminal
+}
+
+// completeLine completes the word before the cursor. A single candidate replaces the
+// word; several candidates are extended to their common prefix or listed.
+func completeLine(terminal *term.Terminal, complete completeFunc, line string, pos int) (string, int, bool) {
+	start := strings.LastIndexByte(line[:pos], ' ') + 1
+	word := line[start:pos]
+
+	candidates, noSpace := complete(line[:pos])
+
+	var replacement string
+
+	switch {
+	case len(candidates) == 0:
+		return "", 0, false
+	case len(candidates) == 1:
+		replacement = quoteCompletion(candidates[0])
+		if !noSpace {
+			replacement += " "
+		}
+	default:
+		prefix := commonPrefix(candidates)
+		if len(prefix) <= len(word) || strings.ContainsAny(prefix, " '\"") {
+			_, _ = terminal.Write([]byte(strings.Join(candidates, "  ") + "\n"))
+
+			return "", 0, false
+		}
+
+		replacement = prefix
+	}
+
+	return line[:start] + replacement + line[pos:], start + len(replacement), true
+}
+
+// interruptReader turns Ctrl-C into the keys discarding the line and entering it empty,
+// as term.Terminal ends the input on Ctrl-C.
+type interruptReader struct {
+	reader  io.Reader
+	pending []byte
+}
+
+// Read reads key presses, replacing each Ctrl-C.
+func (r *interruptReader) Read(buf []byte) (int, error) {
+	if len(r.pending) == 0 {
+		read, err := r.reader.Read(buf)
+		if read == 0 || !bytes.Contains(buf[:read], []byte{keyCtrlC}) {
+			return read, err //nolint:wrapcheck // io.EOF must reach the caller unwrapped
+		}
+
+		r.pending = bytes.ReplaceAll(buf[:read], []byte{keyCtrlC}, []byte{keyCtrlE, keyCtrlU, keyEnter})
+	}
+
+	copied := copy(buf, r.pending)
+	r.pending = r.pending[copied:]
+
+	return copied, nil
+}
+
+// quoteCompletion quotes a completion containing spaces or quotes for the shell parser.
+func quoteCompletion(candidate string) string {
+	if strings.ContainsAny(candidate, " '\"\\") {
+		return shellQuote(candidate)
+	}
+
+	return candidate
+}
+
+// commonPrefix returns the longest common prefix of the candidates.
+func commonPrefix(candidates []string) string {
+	prefix := candidates[0]
+
+	for _, candidate := range candidates[1:] {
+		for !strings.HasPrefix(candidate, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
+
+// terminalHistory lets term.Terminal browse the shell history. The session records the
+// lines it runs, so the lines the terminal reads are not added here.
+type terminalHistory struct {
+	*shellHistory
+}
+
+// Add ignores a line read by the terminal.
+func (terminalHistory) Add(string) {}
+
+// shellHistory holds the shell history and appends new entries to a file.
+type shellHistory struct {
+	entries []string
+	// path is the history file; empty disables persistence.
+	path string
+}
+
+// loadShellHistory reads the history file from the user cache directory.
+// A missing or unreadable file yields an empty history.
+func loadShellHistory() *shellHistory {
+	history := &shellHistory{entries: nil, path: ""}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return history
+	}
+
+	history.path = filepath.Join(cacheDir, "sonar-cli", shellHistoryFile)
+
+	data, err := os.ReadFile(history.path)
+	if err != nil {
+		return history
+	}
+
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if line != "" {
+			history.entries = append(history.entries, line)
+		}
+	}
+
+	if len(history.entries) > shellHistoryLimit {
+		history.entries = history.entries[len(history.entries)-shellHistoryLimit:]
+	}
+
+	return history
+}
+
+// Len returns the number of history entries.
+func (h *shellHistory) Len() int {
+	return len(h.entries)
+}
+
+// At returns a history entry, 0 being the most recent.
+func (h *shellHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+// add appends a line to the history, skipping repeats of the previous entry.
+// Lines carrying credentials are kept in memory but never written to disk.
+func (h *shellHistory) add(line string) {
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == line {
+		return
+	}
+
+	h.entries = append(h.entries, line)
+	if len(h.entries) > shellHistoryLimit {
+		h.entries = h.entries[1:]
+	}
+
+	if h.path == "" || containsCredentials(line) {
+		return
+	}
+
+	err := os.MkdirAll(filepath.Dir(h.path), 0o700) //nolint:mnd // private cache directory
+	if err != nil {
+		return
+	}
+
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:mnd // private history file
+	if err != nil {
+		return
+	}
+
+	_, _ = fmt.Fprintln(file, line)
+	_ = file.Close()
+}
+
+// containsCredentials reports whether a shell line passes a token or password,
+// either as a flag or as a session variable.
+func containsCredentials(line string) bool {
+	for _, marker := range []string{"--token", "--password", "token=", "password="} {
+		if strings.Contains(line, marker) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// editLine feeds key presses to the shell terminal and returns the edited line.
+func editLine(t *testing.T, keys string, history *shellHistory, complete completeFunc) (string, error) {
+	t.Helper()
+
+	terminal := newShellTerminal(strings.NewReader(keys), io.Discard, history, complete)
+
+	return terminal.ReadLine()
+}
+
+// TestShellTerminal_Editing tests insertion, cursor movement and deletion keys.
+func TestShellTerminal_Editing(t *testing.T) {
+	history := &shellHistory{}
+
+	tests := []struct {
+		name string
+		keys string
+		want string
+	}{
+		{name: "plain", keys: "issues search\r", want: "issues search"},
+		{name: "backspace", keys: "issuex\x7fs\r", want: "issues"},
+		{name: "left arrow insert", keys: "isues\x1b[D\x1b[D\x1b[D\x1b[Ds\r", want: "issues"},
+		{name: "home and end", keys: "ssue\x01i\x05s\r", want: "issues"},
+		{name: "kill to start", keys: "junk\x15ok\r", want: "ok"},
+		{name: "kill to end", keys: "okjunk\x1b[D\x1b[D\x1b[D\x1b[D\x0b\r", want: "ok"},
+		{name: "delete word", keys: "issues search\x17list\r", want: "issues list"},
+		{name: "unicode", keys: "héllé\x7fo\r", want: "héllo"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := editLine(t, tc.keys, history, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestShellTerminal_History tests browsing the history with arrows, keeping the draft,
+// and that read lines are left to the session to record.
+func TestShellTerminal_History(t *testing.T) {
+	history := &shellHistory{entries: []string{"first", "second"}}
+
+	got, err := editLine(t, "\x1b[A\x1b[A\r", history, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "first", got)
+
+	got, err = editLine(t, "draft\x1b[A\x1b[B\r", history, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "draft", got)
+
+	got, err = editLine(t, "\x10\x10\x10\x0e\r", history, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "second", got)
+
+	assert.Equal(t, []string{"first", "second"}, history.entries)
+}
+
+// TestShellTerminal_Completion tests tab completion with one and several candidates.
+func TestShellTerminal_Completion(t *testing.T) {
+	history := &shellHistory{}
+
+	complete := func(line string) ([]string, bool) {
+		switch {
+		case strings.HasSuffix(line, "qual"):
+			return []string{"qualitygates", "qualityprofiles"}, false
+		case strings.HasSuffix(line, "qualitygates l"):
+			return []string{"list"}, false
+		case strings.HasSuffix(line, "--gate-name "):
+			return []string{"Sonar way"}, false
+		case strings.HasSuffix(line, "--projects a,"):
+			return []string{"a,b"}, true
+		default:
+			return nil, false
+		}
+	}
+
+	got, err := editLine(t, "qual\t\tgates l\t\r", history, complete)
+	require.NoError(t, err)
+	assert.Equal(t, "qualitygates list ", got)
+
+	got, err = editLine(t, "x --gate-name \t\r", history, complete)
+	require.NoError(t, err)
+	assert.Equal(t, "x --gate-name 'Sonar way' ", got)
+
+	got, err = editLine(t, "x --projects a,\t\r", history, complete)
+	require.NoError(t, err)
+	assert.Equal(t, "x --projects a,b", got)
+}
+
+// TestShellTerminal_Candidates tests that ambiguous completions are listed.
+func TestShellTerminal_Candidates(t *testing.T) {
+	var out bytes.Buffer
+
+	complete := func(string) ([]string, bool) {
+		return []string{"issues", "projects"}, false
+	}
+
+	terminal := newShellTerminal(strings.NewReader("\t\r"), &out, &shellHistory{}, complete)
+
+	got, err := terminal.ReadLine()
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	assert.Contains(t, out.String(), "issues  projects")
+}
+
+// TestShellTerminal_ControlKeys tests that Ctrl-C discards the line and Ctrl-D on an
+// empty line ends the input.
+func TestShellTerminal_ControlKeys(t *testing.T) {
+	history := &shellHistory{}
+
+	got, err := editLine(t, "ab\x1b[Dc\x03", history, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = editLine(t, "\x04", history, nil)
+	require.ErrorIs(t, err, io.EOF)
+
+	terminal := newShellTerminal(strings.NewReader("abc\x03ok\r"), io.Discard, history, nil)
+
+	got, err = terminal.ReadLine()
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = terminal.ReadLine()
+	require.NoError(t, err)
+	assert.Equal(t, "ok", got)
+}
+
+// TestShellHistory tests persistence, de-duplication and credential filtering.
+func TestShellHistory(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	history := loadShellHistory()
+	history.add("issues search")
+	history.add("issues search")
+	history.add("projects search --token secret")
+	history.add("set project=foo")
+
+	assert.Equal(t, []string{"issues search", "projects search --token secret", "set project=foo"}, history.entries)
+
+	data, err := os.ReadFile(history.path)
+	require.NoError(t, err)
+	assert.Equal(t, "issues search\nset project=foo\n", string(data))
+	assert.Equal(t, "sonar-cli", filepath.Base(filepath.Dir(history.path)))
+
+	reloaded := loadShellHistory()
+	assert.Equal(t, []string{"issues search", "set project=foo"}, reloaded.entries)
+}
+
+// TestCommonPrefix tests the common prefix of completion candidates.
+func TestCommonPrefix(t *testing.T) {
+	assert.Equal(t, "quality", commonPrefix([]string{"qualitygates", "qualityprofiles"}))
+	assert.Equal(t, "list", commonPrefix([]string{"list"}))
+	assert.Empty(t, commonPrefix([]string{"a", "b"}))
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestShellSession creates a shell session with a test client and captured output.
+func newTestShellSession(t *testing.T, client *sonar.Client) (*shellSession, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	// No server URL in the environment: commands only work with the session client.
+	t.Setenv("SONAR_CLI_URL", "")
+
+	var out, errOut bytes.Buffer
+
+	cmd := &cobra.Command{Use: "shell"}
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+
+	return newShellSession(cmd, newCommandTree, client), &out, &errOut
+}
+
+// runScript runs shell lines through a session.
+func runScript(t *testing.T, session *shellSession, lines ...string) {
+	t.Helper()
+
+	reader := &plainLineReader{scanner: bufio.NewScanner(strings.NewReader(strings.Join(lines, "\n")))}
+	require.NoError(t, session.run(context.Background(), reader))
+}
+
+// TestShellSession_VariablesAndPipe tests session variables, client reuse and piping.
+func TestShellSession_VariablesAndPipe(t *testing.T) {
+	var projects []string
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		projects = append(projects, r.URL.Query().Get("project"))
+
+		_, _ = w.Write([]byte(`{"branches": [{"name": "main", "isMain": true}, {"name": "feature/x"}]}`))
+	})
+
+	session, out, errOut := newTestShellSession(t, client)
+
+	runScript(t, session,
+		"set project=my-app",
+		"project-branches list",
+		"project-branches list --project other",
+		"| --query branches[].name",
+		"exit",
+		"project-branches list",
+	)
+
+	assert.Empty(t, errOut.String())
+	assert.Equal(t, []string{"my-app", "other"}, projects)
+	assert.Contains(t, out.String(), "[\n  \"main\",\n  \"feature/x\"\n]")
+	assert.Equal(t, []any{"main", "feature/x"}, session.last)
+}
+
+// TestShellSession_Errors tests that failing lines are reported and the session continues.
+func TestShellSession_Errors(t *testing.T) {
+	session, out, errOut := newTestShellSession(t, newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	runScript(t, session, "| --query key", "no-such-command", "set broken", "help")
+
+	assert.Contains(t, errOut.String(), errNoPreviousResult.Error())
+	assert.Contains(t, errOut.String(), "unknown command")
+	assert.Contains(t, errOut.String(), `invalid assignment "broken"`)
+	assert.Contains(t, out.String(), "Shell commands:")
+}
+
+// TestShellSession_SetUnset tests listing and removing session variables.
+func TestShellSession_SetUnset(t *testing.T) {
+	session, out, _ := newTestShellSession(t, nil)
+
+	runScript(t, session, "set --project=a branch=main", "unset branch", "set")
+
+	assert.Equal(t, map[string]string{"project": "a"}, session.vars)
+	assert.Equal(t, "project=a\n", out.String())
+}
+
+// TestShellSession_SeedFromFlags tests that global flags given to the shell become
+// session variables, except connection flags.
+func TestShellSession_SeedFromFlags(t *testing.T) {
+	rootCmd := newCommandTree()
+	shellCmd := newShellCommand(newCommandTree)
+	rootCmd.AddCommand(shellCmd)
+
+	require.NoError(t, rootCmd.ParseFlags([]string{"-o", "table", "--columns", "key,name", "--url", "http://sonar"}))
+
+	session := newShellSession(rootCmd, newCommandTree, nil)
+
+	assert.Equal(t, map[string]string{"output": "table", "columns": "key,name"}, session.vars)
+	assert.Equal(t, map[string]string{"url": "http://sonar"}, session.connection)
+}
+
+// TestInjectFlags tests that variables only fill flags the command has and
+// the line does not set.
+func TestInjectFlags(t *testing.T) {
+	vars := map[string]string{"project": "a", "output": "yaml", "unknown": "x"}
+
+	rootCmd := newCommandTree()
+	leaf, _, err := rootCmd.Find([]string{"project-branches", "list"})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{"project-branches", "list", "--output=yaml", "--project=a"},
+		injectFlags(leaf, []string{"project-branches", "list"}, vars))
+	assert.Equal(t,
+		[]string{"project-branches", "list", "--project=b", "-o", "json"},
+		injectFlags(leaf, []string{"project-branches", "list", "--project=b", "-o", "json"}, vars))
+}
+
+// TestShellSession_Complete tests completion through the command tree.
+func TestShellSession_Complete(t *testing.T) {
+	session, _, _ := newTestShellSession(t, nil)
+
+	candidates, _ := session.complete("hi")
+	assert.Contains(t, candidates, "history")
+
+	candidates, _ = session.complete("quality")
+	assert.Contains(t, candidates, "qualitygates")
+
+	candidates, _ = session.complete("qualitygates li")
+	assert.Equal(t, []string{"list"}, candidates)
+
+	candidates, _ = session.complete("project-branches list --pro")
+	assert.Contains(t, candidates, "--project")
+}
+
+// TestQueryResult tests path and projection queries on results.
+func TestQueryResult(t *testing.T) {
+	result := &sonar.ProjectBranchesList{Branches: []sonar.ProjectBranch{{Name: "main"}, {Name: "dev"}}}
+
+	tests := []struct {
+		name  string
+		path  string
+		want  any
+		found bool
+	}{
+		{name: "index", path: "branches[1].name", want: "dev", found: true},
+		{name: "projection", path: "branches[].name", want: []any{"main", "dev"}, found: true},
+		{name: "leading dot", path: ".branches[0].Name", want: "main", found: true},
+		{name: "missing", path: "nope", want: nil, found: false},
+		{name: "projection on scalar", path: "branches[0].name[]", want: nil, found: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, found := queryResult(reflect.ValueOf(result), tc.path)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestSplitShellWords tests shell-style word splitting.
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{name: "plain", line: "issues search  --projects foo", want: []string{"issues", "search", "--projects", "foo"}},
+		{name: "single quotes", line: `set gate='Sonar way'`, want: []string{"set", "gate=Sonar way"}},
+		{name: "double quotes", line: `a "b \"c\"" d`, want: []string{"a", `b "c"`, "d"}},
+		{name: "escaped space", line: `a b\ c`, want: []string{"a", "b c"}},
+		{name: "empty quoted", line: `a ""`, want: []string{"a", ""}},
+		{name: "blank", line: "   ", want: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := splitShellWords(tc.line)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := splitShellWords(`a "b`)
+	require.Error(t, err)
+}
@@ -0,0 +1,499 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/parallel"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// defaultSimulateParallel is the default number of branches evaluated concurrently.
+const defaultSimulateParallel = 4
+
+var (
+	// errStatusChanges is returned by qualitygates simulate --detailed-exitcode when a
+	// status would change.
+	errStatusChanges = errors.New("the quality gate status of some branches would change")
+	// errSimulationIncomplete is returned when the measures of some branches could not be read.
+	errSimulationIncomplete = errors.New("quality gate simulation incomplete")
+)
+
+// QualityGateSimulation is the result of the qualitygates simulate command.
+type QualityGateSimulation struct {
+	// Gate is the name of the simulated quality gate.
+	Gate string `json:"gate"`
+	// Conditions are the simulated conditions.
+	Conditions []sonar.QualityGateCondition `json:"conditions"`
+	// Evaluated is the number of branches evaluated.
+	Evaluated int `json:"evaluated"`
+	// Failing is the number of branches that would start failing.
+	Failing int `json:"failing"`
+	// Passing is the number of branches that would start passing.
+	Passing int `json:"passing"`
+	// Errors is the number of branches whose measures could not be read.
+	Errors int `json:"errors"`
+	// Results are the branches whose status would change or that could not be evaluated,
+	// and with --show-unchanged every other branch.
+	Results []QualityGateSimulationResult `json:"results"`
+}
+
+// QualityGateSimulationResult is the simulated status of a project branch.
+type QualityGateSimulationResult struct {
+	// Project is the project key.
+	Project string `json:"project"`
+	// Branch is the branch, empty for the main branch when branches are not listed.
+	Branch string `json:"branch,omitempty"`
+	// Current is the status under the current conditions.
+	Current string `json:"current,omitempty"`
+	// Simulated is the status under the simulated conditions.
+	Simulated string `json:"simulated,omitempty"`
+	// Failed are the simulated conditions the branch fails.
+	Failed []sonar.QualityGateConditionStatus `json:"failed,omitempty"`
+	// Error is the reason the branch could not be evaluated.
+	Error string `json:"error,omitempty"`
+}
+
+// changed reports whether the status of the branch would change.
+func (r *QualityGateSimulationResult) changed() bool {
+	return r.Error == "" && r.Current != r.Simulated
+}
+
+// simulateFlags holds the flags of the qualitygates simulate command.
+type simulateFlags struct {
+	gate             string
+	conditions       []string
+	remove           []string
+	projects         []string
+	branches         bool
+	parallel         int
+	showUnchanged    bool
+	detailedExitCode bool
+}
+
+// newSimulateCommand creates the qualitygates simulate command.
+func newSimulateCommand(format *OutputFormat) *cobra.Command {
+	flags := &simulateFlags{} //nolint:exhaustruct // fields are set by Cobra flag binding
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the simulate command
+		Use:   "simulate --gate <name> [--condition <metric><op><threshold>]... [--remove-condition <metric>]...",
+		Short: "Report the branches whose quality gate status a change of conditions would flip",
+		Long: `Evaluate the conditions of a quality gate, changed by --condition and
+--remove-condition, against the last measures of the projects using it, and report
+the projects whose status would change. Nothing is changed on the server.
+
+A condition is a metric, < or > and the threshold the metric fails beyond, such as
+new_coverage<80 or new_violations>0; it replaces the condition on the same metric.
+The gate conditions are evaluated as the server does after an analysis, new code
+metrics and ratings (1 for A) included, and the current status is evaluated the
+same way from the same measures.
+
+The projects are the ones using the gate, or the --project ones. Only main branches
+are evaluated, unless --branches is given. With --detailed-exitcode, the command
+exits with status 2 when a status would change.`,
+		Example: `  sonar-cli qualitygates simulate --gate "Sonar way" --condition "new_coverage<85"
+  sonar-cli qualitygates simulate --gate Strict --remove-condition new_duplicated_lines_density --branches
+  sonar-cli qualitygates simulate --gate Strict --condition "new_violations>0" --project app -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			simulation, err := runSimulate(cmd, flags, *format)
+			if err != nil {
+				return err
+			}
+
+			if flags.detailedExitCode && simulation.Failing+simulation.Passing > 0 {
+				return withExitCode(exitPlanChanges, errStatusChanges)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.gate, "gate", "", "Name of the quality gate")
+	cmd.Flags().StringArrayVar(&flags.conditions, "condition", nil, "Condition to set, such as new_coverage<80 (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.remove, "remove-condition", nil, "Metric whose condition to remove (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.projects, "project", nil, "Project to evaluate instead of the projects using the gate (repeatable)")
+	cmd.Flags().BoolVar(&flags.branches, "branches", false, "Evaluate every branch, not only the main branch")
+	cmd.Flags().IntVar(&flags.parallel, "parallel", defaultSimulateParallel, "Number of branches evaluated concurrently")
+	cmd.Flags().BoolVar(&flags.showUnchanged, "show-unchanged", false, "Also report the branches whose status would not change")
+	cmd.Flags().BoolVar(&flags.detailedExitCode, "detailed-exitcode", false, "Exit with status 2 when a status would change")
+
+	_ = cmd.MarkFlagRequired("gate")
+
+	return cmd
+}
+
+// runSimulate evaluates the current and simulated conditions of the gate against the
+// branches and prints the ones whose status would change.
+func runSimulate(cmd *cobra.Command, flags *simulateFlags, format OutputFormat) (*QualityGateSimulation, error) {
+	if flags.parallel < 1 {
+		err := fmt.Errorf("%w %d for --parallel: must be at least 1", errInvalidFlagValue, flags.parallel)
+		Logger().Error("invalid parallelism", zap.Error(err))
+
+		return nil, err
+	}
+
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return nil, err
+	}
+
+	gate, _, err := client.Qualitygates.Show(cmd.Context(), &sonar.QualitygatesShowOptions{Name: flags.gate})
+	if err != nil {
+		Logger().Error("failed to read the quality gate", zap.String("gate", flags.gate), zap.Error(err))
+
+		return nil, fmt.Errorf("failed to read quality gate %s: %w", flags.gate, err)
+	}
+
+	simulated, err := simulatedConditions(gate.Conditions, flags.conditions, flags.remove)
+	if err != nil {
+		Logger().Error("invalid simulated conditions", zap.Error(err))
+
+		return nil, err
+	}
+
+	targets, err := simulationTargets(cmd.Context(), client, gate, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	results := evaluateTargets(cmd.Context(), client, targets, gate.Conditions, simulated, flags.parallel)
+	simulation := summarizeSimulation(gate.Name, simulated, results, flags.showUnchanged)
+
+	if cmd.Flags().Changed("output") {
+		err = writeResult(cmd, simulation, format)
+	} else {
+		recordResult(cmd.Context(), simulation)
+		writeSimulation(cmd.OutOrStdout(), simulation)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if simulation.Errors > 0 {
+		err := fmt.Errorf("%w: %d of %d branches could not be evaluated", errSimulationIncomplete, simulation.Errors, len(results))
+		Logger().Error("quality gate simulation incomplete", zap.Error(err))
+
+		return nil, err
+	}
+
+	return simulation, nil
+}
+
+// simulatedConditions returns the conditions of the gate with the changes applied:
+// each condition replaces the one on the same metric, or is added after the others.
+func simulatedConditions(current []sonar.QualityGateCondition, set, remove []string) ([]sonar.QualityGateCondition, error) {
+	conditions := slices.Clone(current)
+
+	for _, metric := range remove {
+		before := len(conditions)
+		conditions = slices.DeleteFunc(conditions, func(condition sonar.QualityGateCondition) bool { return condition.Metric == metric })
+
+		if len(conditions) == before {
+			return nil, fmt.Errorf("%w %q for --remove-condition: the gate has no condition on this metric", errInvalidFlagValue, metric)
+		}
+	}
+
+	for _, value := range set {
+		condition, err := parseSimulatedCondition(value)
+		if err != nil {
+			return nil, err
+		}
+
+		idx := slices.IndexFunc(conditions, func(existing sonar.QualityGateCondition) bool { return existing.Metric == condition.Metric })
+		if idx < 0 {
+			conditions = append(conditions, condition)
+
+			continue
+		}
+
+		conditions[idx].Op = condition.Op
+		conditions[idx].Error = condition.Error
+	}
+
+	return conditions, nil
+}
+
+// parseSimulatedCondition parses a --condition value, such as new_coverage<80.
+func parseSimulatedCondition(value string) (sonar.QualityGateCondition, error) {
+	condition := sonar.QualityGateCondition{} //nolint:exhaustruct // only the metric, operator and threshold are simulated
+
+	idx := strings.IndexAny(value, "<>")
+	if idx <= 0 || idx == len(value)-1 {
+		return condition, fmt.Errorf("%w %q for --condition: must be <metric><<|>><threshold>, such as new_coverage<80",
+			errInvalidFlagValue, value)
+	}
+
+	condition.Metric = strings.TrimSpace(value[:idx])
+	condition.Error = strings.TrimSpace(value[idx+1:])
+	condition.Op = sonar.QualityGateOperatorGreaterThan
+
+	if value[idx] == '<' {
+		condition.Op = sonar.QualityGateOperatorLessThan
+	}
+
+	return condition, nil
+}
+
+// simulationTarget is a project branch to evaluate.
+type simulationTarget struct {
+	project string
+	branch  string
+}
+
+// simulationTargets returns the branches to evaluate: the main branch, or every branch,
+// of the --project projects or of the projects using the gate.
+func simulationTargets(ctx context.Context, client *sonar.Client, gate *sonar.QualitygatesShow, flags *simulateFlags) ([]simulationTarget, error) {
+	projects := flags.projects
+
+	if len(projects) == 0 {
+		var err error
+
+		projects, err = gateProjects(ctx, client, gate, flags.parallel)
+		if err != nil {
+			Logger().Error("failed to list the projects of the quality gate", zap.String("gate", gate.Name), zap.Error(err))
+
+			return nil, fmt.Errorf("failed to list the projects of quality gate %s: %w", gate.Name, err)
+		}
+	}
+
+	targets := make([]simulationTarget, 0, len(projects))
+
+	for _, project := range projects {
+		if !flags.branches {
+			targets = append(targets, simulationTarget{project: project, branch: ""})
+
+			continue
+		}
+
+		branches, _, err := client.ProjectBranches.List(ctx, &sonar.ProjectBranchesListOptions{Project: project})
+		if err != nil {
+			Logger().Error("failed to list the branches of the project", zap.String("project", project), zap.Error(err))
+
+			return nil, fmt.Errorf("failed to list the branches of project %s: %w", project, err)
+		}
+
+		for _, branch := range branches.Branches {
+			targets = append(targets, simulationTarget{project: project, branch: branch.Name})
+		}
+	}
+
+	return targets, nil
+}
+
+// gateProjects returns the keys of the projects using the gate. The projects of the
+// default gate are not associated with it, so the gate of each other project is read.
+func gateProjects(ctx context.Context, client *sonar.Client, gate *sonar.QualitygatesShow, workers int) ([]string, error) {
+	selected, _, err := client.Qualitygates.SearchAll(ctx, &sonar.QualitygatesSearchOptions{ //nolint:exhaustruct // every project of the gate
+		GateName: gate.Name,
+		Selected: "selected",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]string, 0, len(selected))
+
+	for _, project := range selected {
+		projects = append(projects, project.Key)
+	}
+
+	if !gate.IsDefault {
+		return projects, nil
+	}
+
+	others, _, err := client.Qualitygates.SearchAll(ctx, &sonar.QualitygatesSearchOptions{ //nolint:exhaustruct // every other project
+		GateName: gate.Name,
+		Selected: "deselected",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usesGate := make([]bool, len(others))
+	errs := make([]error, len(others))
+
+	parallel.ForEach(len(others), workers, func(i int) {
+		projectGate, _, err := client.Qualitygates.GetByProject(ctx, &sonar.QualitygatesGetByProjectOptions{Project: others[i].Key})
+		usesGate[i], errs[i] = err == nil && projectGate.QualityGate.Name == gate.Name, err
+	})
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, project := range others {
+		if usesGate[i] {
+			projects = append(projects, project.Key)
+		}
+	}
+
+	return projects, nil
+}
+
+// evaluateTargets reads the measures of each branch and evaluates both sets of conditions.
+func evaluateTargets(
+	ctx context.Context,
+	client *sonar.Client,
+	targets []simulationTarget,
+	current, simulated []sonar.QualityGateCondition,
+	workers int,
+) []QualityGateSimulationResult {
+	metrics := map[string]struct{}{sonar.MetricNewLines: {}}
+
+	for _, condition := range slices.Concat(current, simulated) {
+		metrics[condition.Metric] = struct{}{}
+	}
+
+	metricKeys := slices.Sorted(maps.Keys(metrics))
+	results := make([]QualityGateSimulationResult, len(targets))
+
+	parallel.ForEach(len(targets), workers, func(i int) {
+		results[i] = evaluateTarget(ctx, client, targets[i], metricKeys, current, simulated)
+	})
+
+	return results
+}
+
+// evaluateTarget evaluates both sets of conditions against the measures of a branch.
+func evaluateTarget(
+	ctx context.Context,
+	client *sonar.Client,
+	target simulationTarget,
+	metricKeys []string,
+	current, simulated []sonar.QualityGateCondition,
+) QualityGateSimulationResult {
+	result := QualityGateSimulationResult{Project: target.project, Branch: target.branch} //nolint:exhaustruct // filled below
+
+	component, _, err := client.Measures.Component(ctx, &sonar.MeasuresComponentOptions{ //nolint:exhaustruct // project branches only
+		Component:        target.project,
+		Branch:           target.branch,
+		MetricKeys:       metricKeys,
+		AdditionalFields: []string{"metrics"},
+	})
+	if err != nil {
+		Logger().Warn("failed to read the measures", zap.String("project", target.project), zap.String("branch", target.branch), zap.Error(err))
+		result.Error = err.Error()
+
+		return result
+	}
+
+	measures := sonar.NewQualityGateMeasures(component)
+
+	before, err := sonar.EvaluateQualityGate(current, measures)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	after, err := sonar.EvaluateQualityGate(simulated, measures)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	result.Current = before.Status
+	result.Simulated = after.Status
+	result.Failed = after.Failed()
+
+	return result
+}
+
+// summarizeSimulation counts the status changes and keeps the results to report.
+func summarizeSimulation(gate string, conditions []sonar.QualityGateCondition, results []QualityGateSimulationResult, showUnchanged bool) *QualityGateSimulation {
+	simulation := &QualityGateSimulation{
+		Gate:       gate,
+		Conditions: conditions,
+		Evaluated:  0,
+		Failing:    0,
+		Passing:    0,
+		Errors:     0,
+		Results:    []QualityGateSimulationResult{},
+	}
+
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			simulation.Errors++
+		case result.Simulated == sonar.QualityGateStatusError && result.changed():
+			simulation.Failing++
+		case result.changed():
+			simulation.Passing++
+		}
+
+		if result.Error == "" {
+			simulation.Evaluated++
+		}
+
+		if showUnchanged || result.Error != "" || result.changed() {
+			simulation.Results = append(simulation.Results, result)
+		}
+	}
+
+	return simulation
+}
+
+// writeSimulation prints the simulated conditions, the branches whose status would
+// change and a summary.
+func writeSimulation(writer io.Writer, simulation *QualityGateSimulation) {
+	conditions := make([]string, 0, len(simulation.Conditions))
+
+	for _, condition := range simulation.Conditions {
+		conditions = append(conditions, condition.Metric+" "+conditionThreshold(sonar.QualityGateConditionStatus{ //nolint:exhaustruct // only the threshold is rendered
+			Comparator:     condition.Op,
+			ErrorThreshold: condition.Error,
+		}))
+	}
+
+	_, _ = fmt.Fprintf(writer, "Simulated quality gate %s: %s\n", simulation.Gate, strings.Join(conditions, ", "))
+
+	if len(simulation.Results) > 0 {
+		table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+		_, _ = fmt.Fprintln(table, "PROJECT\tBRANCH\tCURRENT\tSIMULATED\tFAILED CONDITIONS")
+
+		for _, result := range simulation.Results {
+			_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", result.Project, cmp.Or(result.Branch, "-"),
+				cmp.Or(result.Current, "-"), cmp.Or(result.Simulated, "-"), simulationDetails(result))
+		}
+
+		_ = table.Flush()
+	}
+
+	_, _ = fmt.Fprintf(writer, "%d of %d evaluated branches would change status: %d would fail, %d would pass.\n",
+		simulation.Failing+simulation.Passing, simulation.Evaluated, simulation.Failing, simulation.Passing)
+
+	if simulation.Errors > 0 {
+		_, _ = fmt.Fprintf(writer, "%d branches could not be evaluated.\n", simulation.Errors)
+	}
+}
+
+// simulationDetails describes the failed conditions of a result, or its error.
+func simulationDetails(result QualityGateSimulationResult) string {
+	if result.Error != "" {
+		return result.Error
+	}
+
+	failed := make([]string, 0, len(result.Failed))
+
+	for _, condition := range result.Failed {
+		failed = append(failed, fmt.Sprintf("%s=%s (%s)", condition.MetricKey, condition.ActualValue, conditionThreshold(condition)))
+	}
+
+	return strings.Join(failed, ", ")
+}
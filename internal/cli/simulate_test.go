@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/testutil"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// simulateResponses are the responses of the simulation test server, by path and
+// identifying parameter. Strict is the default gate: app is associated with it, lib
+// uses it by default and other uses Sonar way.
+//
+//nolint:gochecknoglobals // test fixture
+var simulateResponses = map[string]string{
+	"qualitygates/show": `{"name": "Strict", "isDefault": true, "conditions": [
+		{"id": "1", "metric": "new_coverage", "op": "LT", "error": "80"},
+		{"id": "2", "metric": "new_violations", "op": "GT", "error": "0"}]}`,
+	"qualitygates/search?selected=selected":     `{"paging": {"total": 1}, "results": [{"key": "app", "selected": true}]}`,
+	"qualitygates/search?selected=deselected":   `{"paging": {"total": 2}, "results": [{"key": "lib"}, {"key": "other"}]}`,
+	"qualitygates/get_by_project?project=lib":   `{"qualityGate": {"name": "Strict", "default": true}}`,
+	"qualitygates/get_by_project?project=other": `{"qualityGate": {"name": "Sonar way"}}`,
+	"project_branches/list?project=app":         `{"branches": [{"name": "main", "isMain": true}, {"name": "feature"}]}`,
+	"project_branches/list?project=lib":         `{"branches": [{"name": "main", "isMain": true}]}`,
+	"measures/component?component=app": `{"component": {"key": "app", "measures": [
+		{"metric": "new_coverage", "period": {"value": "82.5"}},
+		{"metric": "new_violations", "period": {"value": "0"}},
+		{"metric": "new_lines", "period": {"value": "400"}}]},
+		"metrics": [{"key": "new_coverage", "type": "PERCENT"}, {"key": "new_violations", "type": "INT"}]}`,
+	"measures/component?branch=main": `{"component": {"key": "app", "measures": [
+		{"metric": "new_coverage", "period": {"value": "82.5"}},
+		{"metric": "new_violations", "period": {"value": "0"}},
+		{"metric": "new_lines", "period": {"value": "400"}}]}}`,
+	"measures/component?branch=feature": `{"component": {"key": "app", "measures": [
+		{"metric": "new_coverage", "period": {"value": "95.0"}},
+		{"metric": "new_lines", "period": {"value": "400"}}]}}`,
+	"measures/component?component=lib": `{"component": {"key": "lib", "measures": [
+		{"metric": "new_coverage", "period": {"value": "70.0"}},
+		{"metric": "new_violations", "period": {"value": "0"}},
+		{"metric": "new_lines", "period": {"value": "12"}}]}}`,
+}
+
+// runSimulateCLI runs qualitygates simulate against the simulation test server, and
+// returns its output.
+func runSimulateCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	client := newTestClient(t, testutil.Handler(simulateResponses, "selected", "branch", "project", "component"))
+
+	var out bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().VarP(&outputFormatFlag{target: &format}, "output", "o", "")
+	rootCmd.AddCommand(&cobra.Command{Use: "qualitygates"}) //nolint:exhaustruct // stands for the generated command
+	addServiceSubcommand(rootCmd, "qualitygates", newSimulateCommand(&format))
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"qualitygates", "simulate"}, args...))
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+
+	return out.String(), err
+}
+
+// TestSimulate_Flips tests that simulate reports the projects of the default gate whose
+// status a stricter condition would flip, ignoring small changes as the server does.
+func TestSimulate_Flips(t *testing.T) {
+	out, err := runSimulateCLI(t, "--gate", "Strict", "--condition", "new_coverage<85")
+	require.NoError(t, err)
+	assert.Equal(t, `Simulated quality gate Strict: new_coverage < 85, new_violations > 0
+PROJECT  BRANCH  CURRENT  SIMULATED  FAILED CONDITIONS
+app      -       OK       ERROR      new_coverage=82.5 (< 85)
+1 of 2 evaluated branches would change status: 1 would fail, 0 would pass.
+`, out)
+
+	_, err = runSimulateCLI(t, "--gate", "Strict", "--condition", "new_coverage<85", "--detailed-exitcode")
+	require.ErrorIs(t, err, errStatusChanges)
+	assert.Equal(t, exitPlanChanges, ExitCode(err))
+}
+
+// TestSimulate_Branches tests that simulate evaluates every branch of the given projects
+// and reports unchanged branches on request.
+func TestSimulate_Branches(t *testing.T) {
+	out, err := runSimulateCLI(t, "--gate", "Strict", "--remove-condition", "new_violations",
+		"--condition", "new_duplicated_lines_density>3", "--project", "app", "--branches", "--show-unchanged", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"evaluated": 2`)
+	assert.Contains(t, out, `"branch": "feature"`)
+	assert.Contains(t, out, `"metric": "new_duplicated_lines_density"`)
+	assert.NotContains(t, out, `"metric": "new_violations"`)
+}
+
+// TestSimulate_Incomplete tests that a branch whose measures cannot be read is reported
+// and fails the command.
+func TestSimulate_Incomplete(t *testing.T) {
+	out, err := runSimulateCLI(t, "--gate", "Strict", "--project", "app", "--project", "missing")
+	require.ErrorIs(t, err, errSimulationIncomplete)
+	assert.Contains(t, out, "missing  -       -        -          ")
+	assert.Contains(t, out, "1 branches could not be evaluated.\n")
+}
+
+// TestSimulate_InvalidFlags tests that malformed or unknown conditions are rejected.
+func TestSimulate_InvalidFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"--condition", "new_coverage=80"},
+		{"--condition", "<80"},
+		{"--remove-condition", "coverage"},
+		{"--parallel", "0"},
+	} {
+		_, err := runSimulateCLI(t, append([]string{"--gate", "Strict"}, args...)...)
+		require.ErrorIs(t, err, errInvalidFlagValue, args)
+		assert.Equal(t, exitValidation, ExitCode(err))
+	}
+}
+
+// TestParseSimulatedCondition tests the parsing of --condition values.
+func TestParseSimulatedCondition(t *testing.T) {
+	condition, err := parseSimulatedCondition("new_reliability_rating > 1")
+	require.NoError(t, err)
+	assert.Equal(t, sonar.QualityGateCondition{Metric: "new_reliability_rating", Op: "GT", Error: "1"}, condition)
+
+	condition, err = parseSimulatedCondition("new_coverage<80")
+	require.NoError(t, err)
+	assert.Equal(t, sonar.QualityGateCondition{Metric: "new_coverage", Op: "LT", Error: "80"}, condition)
+}
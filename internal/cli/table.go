@@ -0,0 +1,403 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+const (
+	// columnsFlag is the global flag selecting table columns.
+	columnsFlag = "columns"
+	// sortByFlag is the global flag selecting the table sort column.
+	sortByFlag = "sort-by"
+	// noHeadersFlag is the global flag suppressing the table header row.
+	noHeadersFlag = "no-headers"
+	// descendingPrefix marks a --sort-by column as sorted in descending order.
+	descendingPrefix = "-"
+)
+
+// TableOptions controls how table and wide output is rendered.
+//
+//nolint:govet // fieldalignment: keeping logical field grouping for readability
+type TableOptions struct {
+	// Columns lists the column paths to render, e.g. "key" or "impacts[0].severity".
+	// When empty, the default column set for the response type is used.
+	Columns []string
+	// SortBy is the column path rows are sorted by. A leading "-" sorts descending.
+	SortBy string
+	// NoHeaders suppresses the header and separator rows.
+	NoHeaders bool
+	// Wide renders every field, including nested values, instead of the default column set.
+	Wide bool
+}
+
+// tableView describes the default table rendering for a response type.
+type tableView struct {
+	// slice is the field holding the rows to render (empty for plain slice responses).
+	slice string
+	// columns is the default column set, as paths into each row.
+	columns []string
+}
+
+// defaultTableViews maps response and element types to their default table rendering.
+// Keying by type rather than by name keeps same-named types of other packages apart.
+// Response types that carry several slices (such as IssuesSearch) pin the slice to render;
+// element types provide the columns used when no --columns flag is given.
+//
+//nolint:gochecknoglobals // static table view registry
+var defaultTableViews = map[reflect.Type]tableView{
+	reflect.TypeFor[sonar.IssuesSearch]():               {slice: "Issues", columns: nil},
+	reflect.TypeFor[sonar.IssuesList]():                 {slice: "Issues", columns: nil},
+	reflect.TypeFor[sonar.ProjectsSearch]():             {slice: "Components", columns: nil},
+	reflect.TypeFor[sonar.UsersSearch]():                {slice: "Users", columns: nil},
+	reflect.TypeFor[sonar.QualitygatesList]():           {slice: "Qualitygates", columns: nil},
+	reflect.TypeFor[BatchReport]():                      {slice: "Results", columns: nil},
+	reflect.TypeFor[GateCheckResult]():                  {slice: "Conditions", columns: nil},
+	reflect.TypeFor[sonar.Issue]():                      {slice: "", columns: []string{"key", "severity", "type", "component", "line", "message"}},
+	reflect.TypeFor[sonar.ProjectSearchComponent]():     {slice: "", columns: []string{"key", "name", "visibility", "lastAnalysisDate"}},
+	reflect.TypeFor[sonar.UsersSearchResult]():          {slice: "", columns: []string{"login", "name", "email", "active", "local"}},
+	reflect.TypeFor[sonar.QualityGate]():                {slice: "", columns: []string{"name", "isDefault", "isBuiltIn", "caycStatus"}},
+	reflect.TypeFor[BatchEntryResult]():                 {slice: "", columns: []string{"index", "row", "name", "status", "durationMs", "error"}},
+	reflect.TypeFor[sonar.QualityGateConditionStatus](): {slice: "", columns: []string{"metricKey", "comparator", "errorThreshold", "actualValue", "status"}},
+}
+
+// tableOptionsFromFlags reads the table rendering flags from a command.
+// Missing flags (e.g. on commands built outside the root command) yield zero values.
+func tableOptionsFromFlags(cmd *cobra.Command, format OutputFormat) TableOptions {
+	columns, _ := cmd.Flags().GetStringSlice(columnsFlag)
+	sortBy, _ := cmd.Flags().GetString(sortByFlag)
+	noHeaders, _ := cmd.Flags().GetBool(noHeadersFlag)
+
+	return TableOptions{
+		Columns:   columns,
+		SortBy:    sortBy,
+		NoHeaders: noHeaders,
+		Wide:      format == OutputWide,
+	}
+}
+
+// primarySliceFieldFor returns the slice field pinned by the default table view of
+// a response struct, if any.
+func primarySliceFieldFor(structVal reflect.Value) (reflect.Value, bool) {
+	view, ok := defaultTableViews[structVal.Type()]
+	if !ok || view.slice == "" {
+		return reflect.Value{}, false
+	}
+
+	field := structVal.FieldByName(view.slice)
+	if !field.IsValid() || field.Kind() != reflect.Slice {
+		return reflect.Value{}, false
+	}
+
+	return field, true
+}
+
+// tableColumns returns the column paths to render for rows of elemType.
+// Explicit columns always win; wide mode renders every exported field; otherwise the
+// default view for the element type is used, falling back to every scalar field.
+func tableColumns(elemType reflect.Type, opts TableOptions) []string {
+	if len(opts.Columns) > 0 {
+		return opts.Columns
+	}
+
+	if !opts.Wide {
+		if view, ok := defaultTableViews[elemType]; ok && len(view.columns) > 0 {
+			return view.columns
+		}
+	}
+
+	all := make([]string, 0, elemType.NumField())
+	scalars := make([]string, 0, elemType.NumField())
+
+	for field := range elemType.Fields() {
+		if !field.IsExported() {
+			continue
+		}
+
+		name := fieldColumnName(field)
+		all = append(all, name)
+
+		if !isNestedType(field.Type) {
+			scalars = append(scalars, name)
+		}
+	}
+
+	// Wide mode shows everything; the default mode hides nested values unless
+	// nothing else is left to show.
+	if opts.Wide || len(scalars) == 0 {
+		return all
+	}
+
+	return scalars
+}
+
+// fieldColumnName returns the column name of a struct field: its JSON name when
+// tagged, otherwise its Go name.
+func fieldColumnName(field reflect.StructField) string {
+	if jsonTag := field.Tag.Get("json"); jsonTag != "" {
+		name, _, _ := strings.Cut(jsonTag, ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// isNestedType reports whether values of the type render as nested JSON rather than
+// as a plain scalar (structs, maps and slices of non-scalar elements).
+func isNestedType(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	//nolint:exhaustive // only composite kinds are nested
+	switch typ.Kind() {
+	case reflect.Struct, reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		return isNestedType(typ.Elem()) || typ.Elem().Kind() == reflect.Slice
+	default:
+		return false
+	}
+}
+
+// resolvePath walks a dotted column path such as "textRange.startLine" or
+// "impacts[0].severity" into a value. Segments match struct fields by JSON name or
+// Go name (case-insensitively) and map keys verbatim. It returns false when any
+// segment cannot be resolved.
+func resolvePath(val reflect.Value, path string) (reflect.Value, bool) {
+	for segment := range strings.SplitSeq(path, ".") {
+		name, indexes, err := parsePathSegment(segment)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+
+		var ok bool
+
+		if name != "" {
+			val, ok = resolveName(val, name)
+			if !ok {
+				return reflect.Value{}, false
+			}
+		}
+
+		for _, index := range indexes {
+			val, ok = resolveIndex(val, index)
+			if !ok {
+				return reflect.Value{}, false
+			}
+		}
+	}
+
+	return val, true
+}
+
+// parsePathSegment splits a path segment such as "impacts[0]" into its name and indexes.
+func parsePathSegment(segment string) (string, []int, error) {
+	name, rest, hasIndex := strings.Cut(segment, "[")
+	if !hasIndex {
+		return name, nil, nil
+	}
+
+	var indexes []int
+
+	for rest != "" {
+		raw, after, closed := strings.Cut(rest, "]")
+		if !closed {
+			return "", nil, fmt.Errorf("unterminated index in %q", segment)
+		}
+
+		index, err := strconv.Atoi(raw)
+		if err != nil || index < 0 {
+			return "", nil, fmt.Errorf("invalid index %q in %q", raw, segment)
+		}
+
+		indexes = append(indexes, index)
+		rest = strings.TrimPrefix(after, "[")
+	}
+
+	return name, indexes, nil
+}
+
+// resolveName looks up a struct field or map key on val.
+func resolveName(val reflect.Value, name string) (reflect.Value, bool) {
+	val = indirect(val)
+
+	//nolint:exhaustive // only structs and maps have named members
+	switch val.Kind() {
+	case reflect.Struct:
+		for field := range val.Type().Fields() {
+			if !field.IsExported() {
+				continue
+			}
+
+			if strings.EqualFold(fieldColumnName(field), name) || strings.EqualFold(field.Name, name) {
+				return val.FieldByIndex(field.Index), true
+			}
+		}
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+
+		entry := val.MapIndex(reflect.ValueOf(name).Convert(val.Type().Key()))
+		if entry.IsValid() {
+			return entry, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// resolveIndex returns the element at index of a slice or array value.
+func resolveIndex(val reflect.Value, index int) (reflect.Value, bool) {
+	val = indirect(val)
+
+	if (val.Kind() != reflect.Slice && val.Kind() != reflect.Array) || index >= val.Len() {
+		return reflect.Value{}, false
+	}
+
+	return val.Index(index), true
+}
+
+// indirect dereferences pointers and interfaces until a concrete value is reached.
+func indirect(val reflect.Value) reflect.Value {
+	for val.IsValid() && (val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface) {
+		if val.IsNil() {
+			return reflect.Value{}
+		}
+
+		val = val.Elem()
+	}
+
+	return val
+}
+
+// formatCell renders a value as a table cell. Zero values render empty, slices of
+// scalars are comma-joined and other composite values are rendered as compact JSON.
+func formatCell(val reflect.Value) string {
+	val = indirect(val)
+	if !val.IsValid() || val.IsZero() {
+		return ""
+	}
+
+	//nolint:exhaustive // only composite kinds need special formatting
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		if !isNestedType(val.Type()) {
+			parts := make([]string, val.Len())
+			for idx := range val.Len() {
+				parts[idx] = formatCell(val.Index(idx))
+			}
+
+			return strings.Join(parts, ",")
+		}
+
+		return marshalCell(val)
+	case reflect.Struct, reflect.Map:
+		return marshalCell(val)
+	default:
+		return fmt.Sprintf("%v", val.Interface())
+	}
+}
+
+// marshalCell renders a composite value as compact JSON.
+func marshalCell(val reflect.Value) string {
+	data, err := json.Marshal(val.Interface())
+	if err != nil {
+		return fmt.Sprintf("%v", val.Interface())
+	}
+
+	return string(data)
+}
+
+// buildTableRows renders every element of a slice against the given column paths.
+func buildTableRows(sliceVal reflect.Value, columns []string) [][]string {
+	rows := make([][]string, sliceVal.Len())
+
+	for rowIdx := range sliceVal.Len() {
+		elem := sliceVal.Index(rowIdx)
+		row := make([]string, len(columns))
+
+		for colIdx, column := range columns {
+			if cell, ok := resolvePath(elem, column); ok {
+				row[colIdx] = formatCell(cell)
+			}
+		}
+
+		rows[rowIdx] = row
+	}
+
+	return rows
+}
+
+// sortTableRows sorts rows in place by the --sort-by column. The sort column does not
+// have to be displayed: its values are resolved from the original elements. Values
+// that parse as numbers are compared numerically.
+func sortTableRows(sliceVal reflect.Value, rows [][]string, sortBy string) {
+	if sortBy == "" {
+		return
+	}
+
+	path, descending := strings.CutPrefix(sortBy, descendingPrefix)
+
+	keys := make([]string, sliceVal.Len())
+	for idx := range sliceVal.Len() {
+		if cell, ok := resolvePath(sliceVal.Index(idx), path); ok {
+			keys[idx] = formatCell(cell)
+		}
+	}
+
+	order := make([]int, len(rows))
+	for idx := range order {
+		order[idx] = idx
+	}
+
+	slices.SortStableFunc(order, func(left, right int) int {
+		result := compareCells(keys[left], keys[right])
+		if descending {
+			return -result
+		}
+
+		return result
+	})
+
+	sorted := make([][]string, len(rows))
+	for idx, original := range order {
+		sorted[idx] = rows[original]
+	}
+
+	copy(rows, sorted)
+}
+
+// compareCells compares two cell values, numerically when both are numbers.
+func compareCells(left, right string) int {
+	leftNum, leftErr := strconv.ParseFloat(left, 64)
+	rightNum, rightErr := strconv.ParseFloat(right, 64)
+
+	if leftErr == nil && rightErr == nil {
+		return cmp.Compare(leftNum, rightNum)
+	}
+
+	return strings.Compare(left, right)
+}
+
+// columnHeaders returns the upper-cased header for each column path.
+func columnHeaders(columns []string) []string {
+	headers := make([]string, len(columns))
+	for idx, column := range columns {
+		headers[idx] = strings.ToUpper(column)
+	}
+
+	return headers
+}
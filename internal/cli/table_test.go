@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// tableRow is a test row type with nested values.
+type tableRow struct {
+	Key     string              `json:"key"`
+	Line    int64               `json:"line"`
+	Impacts []sonar.IssueImpact `json:"impacts"`
+	Labels  map[string]string   `json:"labels"`
+}
+
+// TestResolvePath tests dotted and indexed path resolution.
+func TestResolvePath(t *testing.T) {
+	row := tableRow{
+		Key:     "k1",
+		Line:    12,
+		Impacts: []sonar.IssueImpact{{SoftwareQuality: "SECURITY", Severity: "HIGH"}},
+		Labels:  map[string]string{"team": "core"},
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{name: "json name", path: "key", want: "k1", wantOK: true},
+		{name: "case insensitive", path: "KEY", want: "k1", wantOK: true},
+		{name: "go field name", path: "Line", want: "12", wantOK: true},
+		{name: "indexed nested", path: "impacts[0].severity", want: "HIGH", wantOK: true},
+		{name: "map key", path: "labels.team", want: "core", wantOK: true},
+		{name: "index out of range", path: "impacts[3].severity", wantOK: false},
+		{name: "unknown field", path: "missing", wantOK: false},
+		{name: "bad index", path: "impacts[x]", wantOK: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			val, ok := resolvePath(reflect.ValueOf(row), tc.path)
+			assert.Equal(t, tc.wantOK, ok)
+
+			if tc.wantOK {
+				assert.Equal(t, tc.want, formatCell(val))
+			}
+		})
+	}
+}
+
+// TestFormatOutput_Columns tests explicit column selection with nested paths.
+func TestFormatOutput_Columns(t *testing.T) {
+	rows := []tableRow{
+		{Key: "k1", Impacts: []sonar.IssueImpact{{Severity: "LOW"}}},
+		{Key: "k2", Impacts: []sonar.IssueImpact{{Severity: "HIGH"}}},
+	}
+
+	var buf bytes.Buffer
+
+	err := FormatOutputWithOptions(&buf, rows, OutputTable, TableOptions{Columns: []string{"key", "impacts[0].severity"}})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[0], "IMPACTS[0].SEVERITY")
+	assert.NotContains(t, lines[0], "LINE")
+	assert.Contains(t, lines[2], "LOW")
+	assert.Contains(t, lines[3], "HIGH")
+}
+
+// TestFormatOutput_SortBy tests ascending, descending and numeric sorting.
+func TestFormatOutput_SortBy(t *testing.T) {
+	rows := []tableRow{{Key: "b", Line: 10}, {Key: "a", Line: 9}, {Key: "c", Line: 100}}
+
+	tests := []struct {
+		name   string
+		sortBy string
+		want   []string
+	}{
+		{name: "ascending string", sortBy: "key", want: []string{"a", "b", "c"}},
+		{name: "descending string", sortBy: "-key", want: []string{"c", "b", "a"}},
+		{name: "numeric", sortBy: "line", want: []string{"a", "b", "c"}},
+		{name: "numeric descending", sortBy: "-line", want: []string{"c", "b", "a"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			opts := TableOptions{Columns: []string{"key"}, SortBy: tc.sortBy, NoHeaders: true}
+			require.NoError(t, FormatOutputWithOptions(&buf, rows, OutputTable, opts))
+
+			got := strings.Fields(buf.String())
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestFormatOutput_NoHeaders tests that the header and separator rows are omitted.
+func TestFormatOutput_NoHeaders(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := FormatOutputWithOptions(&buf, []tableRow{{Key: "k1"}}, OutputTable, TableOptions{NoHeaders: true})
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "KEY")
+	assert.NotContains(t, buf.String(), "---")
+	assert.Contains(t, buf.String(), "k1")
+}
+
+// TestFormatOutput_DefaultView tests that response types use their default table view.
+func TestFormatOutput_DefaultView(t *testing.T) {
+	result := &sonar.IssuesSearch{
+		Issues:     []sonar.Issue{{Key: "AX1", Severity: "MAJOR", Message: "fix me"}},
+		Components: []sonar.IssueComponent{{Key: "c1"}, {Key: "c2"}},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, FormatOutput(&buf, result, OutputTable))
+
+	output := buf.String()
+	assert.Contains(t, output, "AX1")
+	assert.Contains(t, output, "SEVERITY")
+	assert.Contains(t, output, "MESSAGE")
+	assert.NotContains(t, output, "HASH")
+	assert.NotContains(t, output, "c2")
+}
+
+// TestFormatOutput_DefaultViewByType tests that a type named like one with a default
+// view, but from another package, renders all of its fields.
+func TestFormatOutput_DefaultViewByType(t *testing.T) {
+	type Issue struct {
+		Key  string `json:"key"`
+		Hash string `json:"hash"`
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, FormatOutput(&buf, []Issue{{Key: "AX1", Hash: "h1"}}, OutputTable))
+	assert.Contains(t, buf.String(), "HASH")
+	assert.NotContains(t, buf.String(), "SEVERITY")
+}
+
+// TestFormatOutput_StructColumns tests column selection on key-value tables.
+func TestFormatOutput_StructColumns(t *testing.T) {
+	var buf bytes.Buffer
+
+	data := &sampleStruct{Name: "test", Value: 42}
+
+	err := FormatOutputWithOptions(&buf, data, OutputTable, TableOptions{Columns: []string{"value"}})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "42")
+	assert.NotContains(t, buf.String(), "test")
+}
+
+// TestTableOptionsFromFlags tests reading table options from global flags.
+func TestTableOptionsFromFlags(t *testing.T) {
+	rootCmd := buildRootCommand(&globalFlags{})
+	child := &cobra.Command{Use: "child", Run: func(*cobra.Command, []string) {}}
+	rootCmd.AddCommand(child)
+
+	rootCmd.SetArgs([]string{"child", "-o", "wide", "--columns", "a,b", "--sort-by", "-a", "--no-headers"})
+	rootCmd.PersistentPreRunE = nil
+
+	require.NoError(t, rootCmd.Execute())
+
+	opts := tableOptionsFromFlags(child, OutputWide)
+	assert.Equal(t, []string{"a", "b"}, opts.Columns)
+	assert.Equal(t, "-a", opts.SortBy)
+	assert.True(t, opts.NoHeaders)
+	assert.True(t, opts.Wide)
+}
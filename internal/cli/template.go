@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar/access"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultTemplateChunkSize is the default number of projects per template request.
+	defaultTemplateChunkSize = 100
+	// maxTemplateChunkSize is the number of projects the server accepts per request.
+	maxTemplateChunkSize = 1000
+)
+
+// errTemplateChanges is returned by permissions simulate-template --detailed-exitcode
+// when applying the templates would change the permissions of a project.
+var errTemplateChanges = errors.New("applying the permission templates would change project permissions")
+
+// TemplateSimulation is the result of the permissions simulate-template command.
+type TemplateSimulation struct {
+	// Projects are the permissions each template would give each project.
+	Projects []*access.TemplatePreview `json:"projects"`
+	// Applied is the number of projects the templates were applied to with --apply.
+	Applied int `json:"applied"`
+}
+
+// templateFlags holds the flags of the permissions simulate-template command.
+type templateFlags struct {
+	template         string
+	apply            bool
+	chunkSize        int
+	parallel         int
+	detailedExitCode bool
+}
+
+// newTemplateCommand creates the permissions simulate-template command.
+func newTemplateCommand(format *OutputFormat) *cobra.Command {
+	flags := &templateFlags{} //nolint:exhaustruct // fields are set by Cobra flag binding
+
+	cmd := &cobra.Command{ //nolint:exhaustruct // only setting fields relevant to the simulate-template command
+		Use:   "simulate-template <project>... [--template <name>] [--apply]",
+		Short: "Preview the permissions a permission template would give projects, and apply it",
+		Long: `Find the permission template the server applies to a new project with each key:
+the template whose project key pattern matches the whole key, or the default
+template. With --template, that template is used instead. Then print, for each
+project, the permissions the template grants against the current ones: + for a
+permission it would grant, - for one it would revoke, as applying a template
+replaces every permission of the project.
+
+Keys of projects that do not exist yet are previewed as new private projects, with
+the permissions their creator gets; nothing is applied to them.
+
+Nothing is changed on the server unless --apply is given: the templates are then
+applied to the projects whose permissions would change, in requests of up to
+--chunk-size projects, once confirmed on a terminal or with --yes. With
+--detailed-exitcode, a preview exits with status 2 when permissions would change.`,
+		Example: `  sonar-cli permissions simulate-template svc-billing new-service
+  sonar-cli permissions simulate-template app lib --template "Default template" -o json
+  sonar-cli permissions simulate-template $(cat projects.txt) --apply --chunk-size 200 --yes`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			simulation, err := runTemplateSimulation(cmd, args, flags, *format)
+			if err != nil {
+				return err
+			}
+
+			if flags.detailedExitCode && !flags.apply && slices.ContainsFunc(simulation.Projects, templateChangesProject) {
+				return withExitCode(exitPlanChanges, errTemplateChanges)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.template, "template", "", "Template to preview instead of the one matching each key")
+	cmd.Flags().BoolVar(&flags.apply, "apply", false, "Apply the templates to the projects whose permissions would change")
+	cmd.Flags().IntVar(&flags.chunkSize, "chunk-size", defaultTemplateChunkSize, "Number of projects per request with --apply (at most 1000)")
+	cmd.Flags().IntVar(&flags.parallel, "parallel", defaultMatrixParallel, "Number of projects read concurrently")
+	cmd.Flags().BoolVar(&flags.detailedExitCode, "detailed-exitcode", false, "Exit with status 2 when permissions would change")
+	addYesFlag(cmd)
+
+	return cmd
+}
+
+// runTemplateSimulation previews the templates of the projects, prints the preview and,
+// with --apply, applies the templates once confirmed.
+func runTemplateSimulation(cmd *cobra.Command, keys []string, flags *templateFlags, format OutputFormat) (*TemplateSimulation, error) {
+	err := validateTemplateFlags(flags)
+	if err != nil {
+		Logger().Error("invalid simulate-template flags", zap.Error(err))
+
+		return nil, err
+	}
+
+	client, err := clientFromContext(cmd)
+	if err != nil {
+		Logger().Error("failed to get SonarQube client", zap.Error(err))
+
+		return nil, err
+	}
+
+	templates, err := access.Templates(cmd.Context(), client)
+	if err != nil {
+		Logger().Error("failed to read the permission templates", zap.Error(err))
+
+		return nil, err
+	}
+
+	previews, err := access.PreviewTemplates(cmd.Context(), client, templates, keys, access.PreviewOptions{
+		Template: flags.template,
+		Parallel: flags.parallel,
+	})
+	if err != nil {
+		Logger().Error("failed to preview the permission templates", zap.Error(err))
+
+		return nil, err
+	}
+
+	simulation := &TemplateSimulation{Projects: previews, Applied: 0}
+	structured := cmd.Flags().Changed("output")
+
+	if !structured {
+		writeTemplatePreviews(cmd.OutOrStdout(), previews)
+	}
+
+	changing := 0
+
+	for _, preview := range previews {
+		if templateChangesProject(preview) {
+			changing++
+		}
+	}
+
+	if flags.apply && changing > 0 {
+		err = confirmChanges(cmd, "simulate-template --apply",
+			fmt.Sprintf("\nApply the templates to these %d project(s), replacing their permissions? [y/N] ", changing))
+		if err != nil {
+			return nil, err
+		}
+
+		var applyErr error
+
+		simulation.Applied, applyErr = access.ApplyTemplates(cmd.Context(), client, previews, access.ApplyOptions{ChunkSize: flags.chunkSize})
+		if applyErr != nil {
+			Logger().Error("failed to apply the permission templates", zap.Int("applied", simulation.Applied), zap.Error(applyErr))
+		}
+
+		if !structured {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Applied the templates to %d of %d project(s).\n", simulation.Applied, changing)
+		}
+
+		err = applyErr
+	}
+
+	if structured {
+		err = errors.Join(err, writeResult(cmd, simulation, format))
+	} else {
+		recordResult(cmd.Context(), simulation)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return simulation, nil
+}
+
+// validateTemplateFlags checks the chunk size and the parallelism.
+func validateTemplateFlags(flags *templateFlags) error {
+	if flags.chunkSize < 1 || flags.chunkSize > maxTemplateChunkSize {
+		return fmt.Errorf("%w %d for --chunk-size: must be between 1 and %d", errInvalidFlagValue, flags.chunkSize, maxTemplateChunkSize)
+	}
+
+	if flags.parallel < 1 {
+		return fmt.Errorf("%w %d for --parallel: must be at least 1", errInvalidFlagValue, flags.parallel)
+	}
+
+	return nil
+}
+
+// templateChangesProject reports whether applying the template would change the
+// permissions of an existing project.
+func templateChangesProject(preview *access.TemplatePreview) bool {
+	return preview.Exists && len(preview.Changes) > 0
+}
+
+// writeTemplatePreviews prints, for each project, its template, its permissions, marked
+// - when the template would revoke them, and the ones it would grant, marked +, then a
+// summary.
+func writeTemplatePreviews(writer io.Writer, previews []*access.TemplatePreview) {
+	changing, created := 0, 0
+
+	for _, preview := range previews {
+		_, _ = fmt.Fprintf(writer, "%s: %s\n", preview.Project, templateDescription(preview))
+
+		table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+		for _, granted := range preview.Current {
+			sign := " "
+			if !slices.Contains(preview.Result, granted) {
+				sign = "-"
+			}
+
+			_, _ = fmt.Fprintf(table, "%s  %s\t%s\n", sign, grantee(granted), granted.Permission)
+		}
+
+		for _, change := range preview.Changes {
+			if change.Type == access.ChangeGranted {
+				_, _ = fmt.Fprintf(table, "+  %s\t%s\n", grantee(change.Grant), change.Permission)
+			}
+		}
+
+		for _, permission := range preview.CreatorPermissions {
+			_, _ = fmt.Fprintf(table, "+  creator\t%s\n", permission)
+		}
+
+		_ = table.Flush()
+
+		switch {
+		case !preview.Exists:
+			created++
+		case len(preview.Changes) > 0:
+			changing++
+		}
+	}
+
+	_, _ = fmt.Fprintf(writer, "%d of %d project(s) would change", changing, len(previews)-created)
+
+	if created > 0 {
+		_, _ = fmt.Fprintf(writer, "; %d do not exist and get their template when created", created)
+	}
+
+	_, _ = fmt.Fprintln(writer, ".")
+}
+
+// templateDescription describes the template of a project and why it applies.
+func templateDescription(preview *access.TemplatePreview) string {
+	parts := []string{"template " + preview.Template}
+
+	if preview.Pattern != "" {
+		parts = append(parts, "key matches "+preview.Pattern)
+	}
+
+	if !preview.Exists {
+		parts = append(parts, "new project")
+	} else if preview.Public {
+		parts = append(parts, "public")
+	}
+
+	granted, revoked := 0, 0
+
+	for _, change := range preview.Changes {
+		if change.Type == access.ChangeGranted {
+			granted++
+		} else {
+			revoked++
+		}
+	}
+
+	if preview.Exists && granted+revoked == 0 {
+		parts = append(parts, "no changes")
+	} else if preview.Exists {
+		parts = append(parts, fmt.Sprintf("%d granted, %d revoked", granted, revoked))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// grantee describes the user or group of a grant.
+func grantee(granted access.Grant) string {
+	if granted.User != "" {
+		return "user " + granted.User
+	}
+
+	return "group " + granted.Group
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/testutil"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// templateResponses are the responses of the permission template test server, by path
+// and project or template: the default template grants admin to sonar-administrators,
+// and Services, for svc- keys, grants it to alice.
+//
+//nolint:gochecknoglobals // test fixture
+var templateResponses = map[string]string{
+	"permissions/search_templates": `{"defaultTemplates": [{"templateId": "default", "qualifier": "TRK"}],
+		"permissionTemplates": [
+			{"id": "default", "name": "Default", "permissions": [{"key": "admin", "withProjectCreator": true}]},
+			{"id": "services", "name": "Services", "projectKeyPattern": "svc-.*"}]}`,
+	"permissions/template_users?templateId=default":   `{"paging": {"total": 0}, "users": []}`,
+	"permissions/template_groups?templateId=default":  `{"paging": {"total": 1}, "groups": [{"name": "sonar-administrators", "permissions": ["admin"]}]}`,
+	"permissions/template_users?templateId=services":  `{"paging": {"total": 1}, "users": [{"login": "alice", "permissions": ["admin"]}]}`,
+	"permissions/template_groups?templateId=services": `{"paging": {"total": 0}, "groups": []}`,
+	"projects/search":                           `{"paging": {"total": 2}, "components": [{"key": "app"}, {"key": "svc-billing"}]}`,
+	"permissions/users?projectKey=app":          `{"paging": {"total": 0}, "users": []}`,
+	"permissions/groups?projectKey=app":         `{"paging": {"total": 1}, "groups": [{"name": "sonar-administrators", "permissions": ["admin"]}]}`,
+	"permissions/users?projectKey=svc-billing":  `{"paging": {"total": 1}, "users": [{"login": "bob", "permissions": ["admin"]}]}`,
+	"permissions/groups?projectKey=svc-billing": `{"paging": {"total": 0}, "groups": []}`,
+	"permissions/bulk_apply_template":           ``,
+}
+
+// runTemplateCLI runs permissions simulate-template against the permission template
+// test server, and returns its output and the bulk apply requests it received.
+func runTemplateCLI(t *testing.T, args ...string) (string, []string, error) {
+	t.Helper()
+
+	var applied []string
+
+	canned := testutil.Handler(templateResponses, "projectKey", "templateId")
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/permissions/bulk_apply_template" {
+			applied = append(applied, r.FormValue("templateName")+": "+r.FormValue("projects"))
+		}
+
+		canned(w, r)
+	})
+
+	var out bytes.Buffer
+
+	format := OutputJSON
+	rootCmd := &cobra.Command{Use: "test", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().VarP(&outputFormatFlag{target: &format}, "output", "o", "")
+	rootCmd.AddCommand(&cobra.Command{Use: "permissions"}) //nolint:exhaustruct // stands for the generated command
+	addServiceSubcommand(rootCmd, "permissions", newTemplateCommand(&format))
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(append([]string{"permissions", "simulate-template"}, args...))
+
+	err := rootCmd.ExecuteContext(context.WithValue(context.Background(), clientContextKey, client))
+
+	return out.String(), applied, err
+}
+
+// TestTemplate_Preview tests that each key gets the template the server would apply,
+// and that the permissions it would grant and revoke are shown.
+func TestTemplate_Preview(t *testing.T) {
+	out, applied, err := runTemplateCLI(t, "app", "svc-billing", "svc-new", "--detailed-exitcode")
+	require.ErrorIs(t, err, errTemplateChanges)
+	assert.Equal(t, exitPlanChanges, ExitCode(err))
+	assert.Empty(t, applied)
+	assert.Equal(t, `app: template Default, no changes
+   group sonar-administrators  admin
+svc-billing: template Services, key matches svc-.*, 1 granted, 1 revoked
+-  user bob    admin
++  user alice  admin
+svc-new: template Services, key matches svc-.*, new project
++  user alice  admin
+1 of 2 project(s) would change; 1 do not exist and get their template when created.
+`, out)
+}
+
+// TestTemplate_Apply tests that templates are only applied once confirmed, to the
+// projects whose permissions would change.
+func TestTemplate_Apply(t *testing.T) {
+	_, applied, err := runTemplateCLI(t, "app", "svc-billing", "--apply")
+	require.ErrorIs(t, err, errConfirmationRequired)
+	assert.Empty(t, applied)
+
+	out, applied, err := runTemplateCLI(t, "app", "svc-billing", "--template", "Default", "--apply", "--yes")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Default: svc-billing"}, applied)
+	assert.Contains(t, out, "-  user bob                    admin\n")
+	assert.Contains(t, out, "Applied the templates to 1 of 1 project(s).\n")
+}
+
+// TestTemplate_Structured tests the structured output and the creator permissions of
+// new projects.
+func TestTemplate_Structured(t *testing.T) {
+	out, _, err := runTemplateCLI(t, "new-app", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"template": "Default"`)
+	assert.Contains(t, out, `"creatorPermissions": [`)
+	assert.Contains(t, out, `"applied": 0`)
+}
+
+// TestTemplate_InvalidFlags tests that out-of-range chunk sizes and unknown templates
+// are rejected.
+func TestTemplate_InvalidFlags(t *testing.T) {
+	_, _, err := runTemplateCLI(t, "app", "--chunk-size", "5000")
+	require.ErrorIs(t, err, errInvalidFlagValue)
+	assert.Equal(t, exitValidation, ExitCode(err))
+
+	_, _, err = runTemplateCLI(t, "app", "--template", "Missing")
+	require.ErrorContains(t, err, "there is no permission template named Missing")
+}
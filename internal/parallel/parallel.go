@@ -0,0 +1,27 @@
+// Package parallel runs work concurrently with a bounded number of workers.
+package parallel
+
+import "sync"
+
+// ForEach calls fn for each index below n, with up to workers calls at a time. A worker
+// count below 1 runs the calls one at a time.
+func ForEach(n, workers int, fn func(i int)) {
+	var waiter sync.WaitGroup
+
+	queue := make(chan int)
+
+	for range min(max(workers, 1), max(n, 1)) {
+		waiter.Go(func() {
+			for i := range queue {
+				fn(i)
+			}
+		})
+	}
+
+	for i := range n {
+		queue <- i
+	}
+
+	close(queue)
+	waiter.Wait()
+}
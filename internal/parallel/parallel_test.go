@@ -0,0 +1,39 @@
+package parallel
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEach(t *testing.T) {
+	for name, workers := range map[string]int{"bounded": 3, "more workers than calls": 20, "zero": 0, "negative": -2} {
+		t.Run(name, func(t *testing.T) {
+			var running, peak atomic.Int32
+
+			called := make([]bool, 10)
+
+			ForEach(len(called), workers, func(i int) {
+				current := running.Add(1)
+				defer running.Add(-1)
+
+				for {
+					previous := peak.Load()
+					if current <= previous || peak.CompareAndSwap(previous, current) {
+						break
+					}
+				}
+
+				called[i] = true
+			})
+
+			assert.NotContains(t, called, false)
+			assert.LessOrEqual(t, int(peak.Load()), max(workers, 1))
+		})
+	}
+
+	t.Run("no calls", func(t *testing.T) {
+		ForEach(0, 4, func(int) { t.Fatal("unexpected call") })
+	})
+}
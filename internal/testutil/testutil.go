@@ -0,0 +1,75 @@
+// Package testutil serves canned SonarQube responses to the tests of the client's
+// packages.
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/stretchr/testify/require"
+)
+
+// ResponseKey returns the key of the canned response of a request: its path without
+// the /api/ prefix, followed by the first of params the request sets, such as
+// "qualitygates/show?name=Strict".
+func ResponseKey(r *http.Request, params ...string) string {
+	key := strings.TrimPrefix(r.URL.Path, "/api/")
+
+	for _, param := range params {
+		if value := r.URL.Query().Get(param); value != "" {
+			return key + "?" + param + "=" + value
+		}
+	}
+
+	return key
+}
+
+// NotFound answers a request with no canned response with the 404 error of the server.
+func NotFound(w http.ResponseWriter, key string) {
+	w.WriteHeader(http.StatusNotFound)
+	_, _ = w.Write([]byte(`{"errors": [{"msg": "unknown path ` + key + `"}]}`))
+}
+
+// Handler answers each request with the response of its ResponseKey, and 404 for any
+// other request.
+func Handler(responses map[string]string, params ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := ResponseKey(r, params...)
+
+		body, found := responses[key]
+		if !found {
+			NotFound(w, key)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// NewServerClient returns a client for a test server running handler, closed when the
+// test ends.
+func NewServerClient(t *testing.T, handler http.Handler) *sonar.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	serverURL := server.URL + "/api/"
+
+	client, err := sonar.NewClient(&sonar.ClientCreateOptions{URL: &serverURL})
+	require.NoError(t, err)
+
+	return client
+}
+
+// NewClient returns a client for a test server answering with responses, as Handler
+// does.
+func NewClient(t *testing.T, responses map[string]string, params ...string) *sonar.Client {
+	t.Helper()
+
+	return NewServerClient(t, Handler(responses, params...))
+}
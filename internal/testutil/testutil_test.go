@@ -0,0 +1,30 @@
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseKey(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/api/qualitygates/show?id=1&name=Strict", nil)
+
+	assert.Equal(t, "qualitygates/show", ResponseKey(request))
+	assert.Equal(t, "qualitygates/show?name=Strict", ResponseKey(request, "project", "name", "id"))
+}
+
+func TestHandler(t *testing.T) {
+	handler := Handler(map[string]string{"projects/search?projects=app": `{"components": []}`}, "projects")
+
+	found := httptest.NewRecorder()
+	handler(found, httptest.NewRequest(http.MethodGet, "/api/projects/search?projects=app", nil))
+	assert.Equal(t, http.StatusOK, found.Code)
+	assert.JSONEq(t, `{"components": []}`, found.Body.String())
+
+	missing := httptest.NewRecorder()
+	handler(missing, httptest.NewRequest(http.MethodGet, "/api/projects/search?projects=lib", nil))
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+	assert.Contains(t, missing.Body.String(), "unknown path projects/search?projects=lib")
+}
@@ -0,0 +1,232 @@
+package access
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/testutil"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testResponses are the responses of the test server, by path and, for the endpoints
+// called once per project or group, by the value of the identifying parameter.
+//
+//nolint:gochecknoglobals // test fixture
+var testResponses = map[string]string{
+	"projects/search": `{"paging": {"total": 2}, "components": [
+		{"key": "app", "visibility": "private"}, {"key": "lib", "visibility": "public"}]}`,
+	"permissions/users": `{"paging": {"total": 1}, "users": [{"login": "root", "permissions": ["admin"]}]}`,
+	"permissions/groups": `{"paging": {"total": 2}, "groups": [
+		{"name": "sonar-administrators", "permissions": ["admin", "gateadmin"]},
+		{"name": "sonar-users", "permissions": []}]}`,
+	"permissions/users?projectKey=app": `{"paging": {"total": 1}, "users": [{"login": "alice", "permissions": ["admin", "user"]}]}`,
+	"permissions/groups?projectKey=app": `{"paging": {"total": 2}, "groups": [
+		{"name": "developers", "permissions": ["user", "codeviewer"]},
+		{"name": "sonar-administrators", "permissions": ["admin"]}]}`,
+	"permissions/users?projectKey=lib":            `{"paging": {"total": 0}, "users": []}`,
+	"permissions/groups?projectKey=lib":           `{"paging": {"total": 1}, "groups": [{"name": "Anyone", "permissions": ["scan"]}]}`,
+	"user_groups/users?name=sonar-administrators": `{"paging": {"total": 2}, "users": [{"login": "root"}, {"login": "alice"}]}`,
+	"user_groups/users?name=developers":           `{"paging": {"total": 1}, "users": [{"login": "bob"}]}`,
+	"permissions/search_templates": `{"defaultTemplates": [{"templateId": "tpl-default", "qualifier": "TRK"}],
+		"permissionTemplates": [
+			{"id": "tpl-default", "name": "Default", "permissions": [{"key": "admin", "withProjectCreator": true}]},
+			{"id": "tpl-services", "name": "Services", "projectKeyPattern": "svc-.*"},
+			{"id": "tpl-legacy", "name": "Legacy", "projectKeyPattern": "svc-legacy|old-.*"}]}`,
+	"permissions/template_users?templateId=tpl-default": `{"paging": {"total": 0}, "users": []}`,
+	"permissions/template_groups?templateId=tpl-default": `{"paging": {"total": 3}, "groups": [
+		{"name": "sonar-administrators", "permissions": ["admin"]},
+		{"name": "developers", "permissions": ["codeviewer", "user"]},
+		{"name": "Anyone", "permissions": ["scan"]}]}`,
+	"permissions/template_users?templateId=tpl-services":  `{"paging": {"total": 1}, "users": [{"login": "alice", "permissions": ["admin"]}]}`,
+	"permissions/template_groups?templateId=tpl-services": `{"paging": {"total": 0}, "groups": []}`,
+	"permissions/template_users?templateId=tpl-legacy":    `{"paging": {"total": 0}, "users": []}`,
+	"permissions/template_groups?templateId=tpl-legacy":   `{"paging": {"total": 0}, "groups": []}`,
+}
+
+// identifyingParams are the parameters that select the response of the test server.
+//
+//nolint:gochecknoglobals // test fixture
+var identifyingParams = []string{"projectKey", "name", "templateId"}
+
+func TestBuild(t *testing.T) {
+	matrix, err := Build(t.Context(), testutil.NewClient(t, testResponses, identifyingParams...), Options{}) //nolint:exhaustruct // every project
+	require.NoError(t, err)
+
+	assert.Equal(t, []Entry{
+		{User: "*", Project: "lib", Permission: "codeviewer"},
+		{User: "*", Project: "lib", Permission: "scan", Group: "Anyone"},
+		{User: "*", Project: "lib", Permission: "user"},
+		{User: "alice", Permission: "admin", Group: "sonar-administrators"},
+		{User: "alice", Permission: "gateadmin", Group: "sonar-administrators"},
+		{User: "alice", Project: "app", Permission: "admin"},
+		{User: "alice", Project: "app", Permission: "admin", Group: "sonar-administrators"},
+		{User: "alice", Project: "app", Permission: "user"},
+		{User: "bob", Project: "app", Permission: "codeviewer", Group: "developers"},
+		{User: "bob", Project: "app", Permission: "user", Group: "developers"},
+		{User: "root", Permission: "admin"},
+		{User: "root", Permission: "admin", Group: "sonar-administrators"},
+		{User: "root", Permission: "gateadmin", Group: "sonar-administrators"},
+		{User: "root", Project: "app", Permission: "admin", Group: "sonar-administrators"},
+	}, matrix.Entries)
+}
+
+func TestBuild_Error(t *testing.T) {
+	client := testutil.NewClient(t, map[string]string{"projects/search": testResponses["projects/search"]}, identifyingParams...)
+
+	_, err := Build(t.Context(), client, Options{Projects: nil, Parallel: 1})
+	require.ErrorContains(t, err, "failed to read global permissions")
+}
+
+func TestBuild_InvalidParallel(t *testing.T) {
+	_, err := Build(t.Context(), testutil.NewClient(t, testResponses, identifyingParams...), Options{Projects: nil, Parallel: -1})
+
+	var validationErr *sonar.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "Parallel", validationErr.Field)
+	require.ErrorIs(t, err, sonar.ErrOutOfRange)
+}
+
+func TestMatrix_CSV(t *testing.T) {
+	matrix := &Matrix{Entries: []Entry{
+		{User: "alice", Permission: "admin", Group: "sonar-administrators"},
+		{User: "bob", Project: "app, legacy", Permission: "user"},
+	}}
+
+	var out strings.Builder
+
+	require.NoError(t, matrix.WriteCSV(&out))
+	assert.Equal(t, "user,project,permission,group\nalice,,admin,sonar-administrators\nbob,\"app, legacy\",user,\n", out.String())
+
+	read, err := ReadCSV(strings.NewReader(out.String()))
+	require.NoError(t, err)
+	assert.Equal(t, matrix, read)
+
+	_, err = ReadCSV(strings.NewReader("login,permission\nalice,admin\n"))
+	require.ErrorIs(t, err, errInvalidCSV)
+}
+
+func TestCompare(t *testing.T) {
+	from := &Matrix{Entries: []Entry{
+		{User: "alice", Project: "app", Permission: "admin", Group: "leads"},
+		{User: "bob", Project: "app", Permission: "user"},
+	}}
+	to := &Matrix{Entries: []Entry{
+		{User: "alice", Project: "app", Permission: "admin", Group: "sonar-administrators"},
+		{User: "bob", Project: "app", Permission: "user"},
+		{User: "carol", Permission: "scan"},
+	}}
+
+	assert.Empty(t, Compare(from, from))
+	assert.Equal(t, []Change{
+		{Entry: Entry{User: "alice", Project: "app", Permission: "admin", Group: "leads"}, Type: ChangeRevoked},
+		{Entry: Entry{User: "alice", Project: "app", Permission: "admin", Group: "sonar-administrators"}, Type: ChangeGranted},
+		{Entry: Entry{User: "carol", Permission: "scan"}, Type: ChangeGranted},
+	}, Compare(from, to))
+}
+
+func TestMatchTemplate(t *testing.T) {
+	templates := []*Template{
+		{Name: "Default", Default: true},
+		{Name: "Services", ProjectKeyPattern: "svc-.*"},
+		{Name: "Legacy", ProjectKeyPattern: "svc-legacy|old-.*"},
+	}
+
+	for key, want := range map[string]string{"app": "Default", "svc-billing": "Services", "old-app": "Legacy", "my-svc-app": "Default"} {
+		template, err := MatchTemplate(templates, key)
+		require.NoError(t, err, key)
+		assert.Equal(t, want, template.Name, key)
+	}
+
+	_, err := MatchTemplate(templates, "svc-legacy")
+	require.ErrorIs(t, err, ErrAmbiguousTemplate)
+	assert.ErrorContains(t, err, "Services, Legacy")
+
+	_, err = MatchTemplate(templates[1:], "app")
+	require.ErrorIs(t, err, ErrNoTemplate)
+
+	_, err = MatchTemplate([]*Template{{Name: "Broken", ProjectKeyPattern: "(?!tmp).*"}}, "app")
+	require.ErrorContains(t, err, "invalid project key pattern of permission template Broken")
+}
+
+func TestPreviewTemplates(t *testing.T) {
+	client := testutil.NewClient(t, testResponses, identifyingParams...)
+
+	templates, err := Templates(t.Context(), client)
+	require.NoError(t, err)
+	require.Len(t, templates, 3)
+	assert.True(t, templates[0].Default)
+	assert.Equal(t, []string{"admin"}, templates[0].CreatorPermissions)
+
+	previews, err := PreviewTemplates(t.Context(), client, templates, []string{"app", "svc-billing", "new-app"}, PreviewOptions{}) //nolint:exhaustruct // matching templates
+	require.NoError(t, err)
+	require.Len(t, previews, 3)
+
+	assert.Equal(t, "Default", previews[0].Template)
+	assert.Empty(t, previews[0].Pattern)
+	assert.True(t, previews[0].Exists)
+	assert.Equal(t, []GrantChange{
+		{Grant: Grant{User: "alice", Permission: "admin"}, Type: ChangeRevoked},
+		{Grant: Grant{User: "alice", Permission: "user"}, Type: ChangeRevoked},
+	}, previews[0].Changes)
+	assert.NotContains(t, previews[0].Result, Grant{Group: "Anyone", Permission: "scan"})
+
+	assert.Equal(t, "Services", previews[1].Template)
+	assert.Equal(t, "svc-.*", previews[1].Pattern)
+	assert.False(t, previews[1].Exists)
+	assert.Nil(t, previews[1].CreatorPermissions)
+	assert.Equal(t, []GrantChange{{Grant: Grant{User: "alice", Permission: "admin"}, Type: ChangeGranted}}, previews[1].Changes)
+
+	assert.Equal(t, "Default", previews[2].Template)
+	assert.Equal(t, []string{"admin"}, previews[2].CreatorPermissions)
+	assert.Len(t, previews[2].Changes, 3)
+
+	previews, err = PreviewTemplates(t.Context(), client, templates, []string{"lib"}, PreviewOptions{Template: "Default", Parallel: 1})
+	require.NoError(t, err)
+	assert.True(t, previews[0].Public)
+	assert.Equal(t, []Grant{{Group: "Anyone", Permission: "scan"}, {Group: "sonar-administrators", Permission: "admin"}}, previews[0].Result)
+}
+
+func TestPreviewTemplates_InvalidParallel(t *testing.T) {
+	_, err := PreviewTemplates(t.Context(), testutil.NewClient(t, testResponses, identifyingParams...), nil, []string{"app"}, PreviewOptions{Template: "", Parallel: -1})
+
+	var validationErr *sonar.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "Parallel", validationErr.Field)
+}
+
+func TestApplyTemplates(t *testing.T) {
+	var requests []string
+
+	client := testutil.NewServerClient(t, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.FormValue("templateName")+": "+r.FormValue("projects"))
+	}))
+
+	changed := []GrantChange{{Grant: Grant{User: "alice", Permission: "admin"}, Type: ChangeGranted}}
+	applied, err := ApplyTemplates(t.Context(), client, []*TemplatePreview{
+		{Project: "a", Template: "Default", Exists: true, Changes: changed},
+		{Project: "b", Template: "Services", Exists: true, Changes: changed},
+		{Project: "c", Template: "Default", Exists: true, Changes: changed},
+		{Project: "d", Template: "Default", Exists: true, Changes: []GrantChange{}},
+		{Project: "e", Template: "Default", Exists: false, Changes: changed},
+		{Project: "f", Template: "Default", Exists: true, Changes: changed},
+	}, ApplyOptions{ChunkSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 4, applied)
+	assert.Equal(t, []string{"Default: a,c", "Default: f", "Services: b"}, requests)
+}
+
+func TestApplyTemplates_InvalidChunkSize(t *testing.T) {
+	client := testutil.NewClient(t, testResponses, identifyingParams...)
+	previews := []*TemplatePreview{{Project: "a", Template: "Default", Exists: true, Changes: []GrantChange{
+		{Grant: Grant{User: "alice", Permission: "admin"}, Type: ChangeGranted},
+	}}}
+
+	for _, size := range []int{-1, 1001} {
+		applied, err := ApplyTemplates(t.Context(), client, previews, ApplyOptions{ChunkSize: size})
+		require.ErrorIs(t, err, sonar.ErrOutOfRange)
+		assert.Zero(t, applied)
+	}
+}
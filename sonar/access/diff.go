@@ -0,0 +1,59 @@
+package access
+
+import (
+	"slices"
+)
+
+// ChangeType is the kind of a change between two matrices.
+type ChangeType string
+
+const (
+	// ChangeGranted is a permission the second matrix has and the first has not.
+	ChangeGranted ChangeType = "granted"
+	// ChangeRevoked is a permission the first matrix has and the second has not.
+	ChangeRevoked ChangeType = "revoked"
+)
+
+// Change is a permission granted or revoked between two matrices.
+type Change struct {
+	Entry
+
+	// Type is the kind of change.
+	Type ChangeType `json:"type"`
+}
+
+// Compare returns the permissions granted and revoked from the first matrix to the
+// second, sorted as entries. A permission a user keeps through another group is reported
+// as granted through the new group and revoked through the old one.
+func Compare(from, to *Matrix) []Change {
+	var changes []Change
+
+	fromEntries, toEntries := from.set(), to.set()
+
+	for entry := range toEntries {
+		if _, found := fromEntries[entry]; !found {
+			changes = append(changes, Change{Entry: entry, Type: ChangeGranted})
+		}
+	}
+
+	for entry := range fromEntries {
+		if _, found := toEntries[entry]; !found {
+			changes = append(changes, Change{Entry: entry, Type: ChangeRevoked})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int { return compareEntries(a.Entry, b.Entry) })
+
+	return changes
+}
+
+// set returns the entries of the matrix as a set.
+func (m *Matrix) set() map[Entry]struct{} {
+	entries := make(map[Entry]struct{}, len(m.Entries))
+
+	for _, entry := range m.Entries {
+		entries[entry] = struct{}{}
+	}
+
+	return entries
+}
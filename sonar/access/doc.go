@@ -0,0 +1,22 @@
+// Package access builds the effective permission matrix of a SonarQube instance, to
+// answer questions such as "who can administer which projects".
+//
+// Build reads the global permissions and the permissions of every project, resolves the
+// members of the groups they are granted to, and returns a Matrix with one Entry per
+// user, project, permission and group the permission comes through:
+//
+//	matrix, err := access.Build(ctx, client, access.Options{Parallel: 8})
+//	err = matrix.WriteCSV(os.Stdout)
+//
+// A matrix is written and read back as CSV (WriteCSV, ReadCSV) or JSON, and Compare
+// lists the permissions granted and revoked since a previous matrix.
+//
+// Templates reads the permission templates, and MatchTemplate finds the one the server
+// applies to a new project with a given key, from the project key patterns and the
+// default template. PreviewTemplates compares the permissions a template would give
+// projects with their current ones, and ApplyTemplates applies the templates in chunks:
+//
+//	templates, err := access.Templates(ctx, client)
+//	previews, err := access.PreviewTemplates(ctx, client, templates, []string{"svc-billing"}, access.PreviewOptions{})
+//	applied, err := access.ApplyTemplates(ctx, client, previews, access.ApplyOptions{ChunkSize: 200})
+package access
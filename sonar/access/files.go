@@ -0,0 +1,68 @@
+package access
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// csvHeader is the header row of a matrix written as CSV.
+//
+//nolint:gochecknoglobals // constant header
+var csvHeader = []string{"user", "project", "permission", "group"}
+
+// errInvalidCSV is returned for a CSV document that is not a permission matrix.
+var errInvalidCSV = errors.New("invalid permission matrix CSV")
+
+// WriteCSV writes the matrix as CSV, with a user, project, permission and group column.
+func (m *Matrix) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(csvHeader)
+	if err != nil {
+		return fmt.Errorf("failed to write the permission matrix: %w", err)
+	}
+
+	for _, entry := range m.Entries {
+		err = writer.Write([]string{entry.User, entry.Project, entry.Permission, entry.Group})
+		if err != nil {
+			return fmt.Errorf("failed to write the permission matrix: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	err = writer.Error()
+	if err != nil {
+		return fmt.Errorf("failed to write the permission matrix: %w", err)
+	}
+
+	return nil
+}
+
+// ReadCSV reads a matrix written by WriteCSV.
+func ReadCSV(r io.Reader) (*Matrix, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvHeader)
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidCSV, err)
+	}
+
+	if len(records) == 0 || !slices.Equal(records[0], csvHeader) {
+		return nil, fmt.Errorf("%w: the header must be user,project,permission,group", errInvalidCSV)
+	}
+
+	matrix := &Matrix{Entries: make([]Entry, 0, len(records)-1)}
+
+	for _, record := range records[1:] {
+		matrix.Entries = append(matrix.Entries, Entry{User: record[0], Project: record[1], Permission: record[2], Group: record[3]})
+	}
+
+	slices.SortFunc(matrix.Entries, compareEntries)
+
+	return matrix, nil
+}
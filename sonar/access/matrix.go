@@ -0,0 +1,256 @@
+package access
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/parallel"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+const (
+	// Everyone is the user of the permissions granted to the Anyone group and, on public
+	// projects, of the browse and see source code permissions, which apply to every
+	// user and to anonymous access.
+	Everyone = "*"
+	// AnyoneGroup is the group standing for every user, whose members are not listed.
+	AnyoneGroup = "Anyone"
+
+	// defaultParallel is the number of projects read concurrently by default.
+	defaultParallel = 4
+)
+
+// publicPermissions are the permissions public projects grant to everyone.
+//
+//nolint:gochecknoglobals // constant list
+var publicPermissions = []string{"codeviewer", "user"}
+
+// Entry is a permission a user has, on a project or on the instance.
+type Entry struct {
+	// User is the login of the user, or Everyone.
+	User string `json:"user"`
+	// Project is the key of the project, empty for a global permission.
+	Project string `json:"project,omitempty"`
+	// Permission is the permission, such as admin or scan.
+	Permission string `json:"permission"`
+	// Group is the group the permission is granted to, empty when it is granted to the
+	// user directly or, for Everyone, by the visibility of a public project.
+	Group string `json:"group,omitempty"`
+}
+
+// compareEntries orders entries by user, project, permission and group.
+func compareEntries(a, b Entry) int {
+	return cmp.Or(
+		strings.Compare(a.User, b.User),
+		strings.Compare(a.Project, b.Project),
+		strings.Compare(a.Permission, b.Permission),
+		strings.Compare(a.Group, b.Group),
+	)
+}
+
+// Matrix is the effective permission matrix of an instance.
+type Matrix struct {
+	// Entries are the permissions, sorted by user, project, permission and group.
+	Entries []Entry `json:"entries"`
+}
+
+// Options selects what Build reads.
+type Options struct {
+	// Projects restricts the matrix to these projects. All projects are read when empty.
+	Projects []string
+	// Parallel is the number of projects read concurrently, 4 when zero. It must not be
+	// negative.
+	Parallel int
+}
+
+// Grant is a permission granted to a user or to a group.
+type Grant struct {
+	// User is the login of the user, empty for a group.
+	User string `json:"user,omitempty"`
+	// Group is the name of the group, empty for a user.
+	Group string `json:"group,omitempty"`
+	// Permission is the permission, such as admin or scan.
+	Permission string `json:"permission"`
+}
+
+// compareGrants orders grants by user, group and permission.
+func compareGrants(a, b Grant) int {
+	return cmp.Or(
+		strings.Compare(a.User, b.User),
+		strings.Compare(a.Group, b.Group),
+		strings.Compare(a.Permission, b.Permission),
+	)
+}
+
+// projectGrant is a permission granted on a project, or globally when project is empty.
+type projectGrant struct {
+	Grant
+
+	project string
+}
+
+// Build reads the global permissions and the permissions of the projects, and returns
+// them for each user, the members of the groups they are granted to included.
+func Build(ctx context.Context, client *sonar.Client, opt Options) (*Matrix, error) {
+	err := validateParallel(opt.Parallel)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, _, err := client.Projects.SearchAll(ctx, &sonar.ProjectsSearchOptions{ //nolint:exhaustruct // every project, or the selected ones
+		Projects: opt.Projects,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search projects: %w", err)
+	}
+
+	global, err := permissionGrants(ctx, client, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read global permissions: %w", err)
+	}
+
+	perProject := make([][]projectGrant, len(projects))
+	errs := make([]error, len(projects))
+
+	parallel.ForEach(len(projects), cmp.Or(opt.Parallel, defaultParallel), func(i int) {
+		perProject[i], errs[i] = permissionGrants(ctx, client, projects[i].Key)
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("failed to read the permissions of project %s: %w", projects[i].Key, errs[i])
+		}
+	})
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	grants := slices.Concat(append(perProject, global)...)
+
+	for _, project := range projects {
+		if project.Visibility == sonar.ProjectVisibilityPublic {
+			for _, permission := range publicPermissions {
+				grants = append(grants, projectGrant{Grant: Grant{User: Everyone, Group: "", Permission: permission}, project: project.Key})
+			}
+		}
+	}
+
+	members, err := groupMembers(ctx, client, grants, cmp.Or(opt.Parallel, defaultParallel))
+	if err != nil {
+		return nil, err
+	}
+
+	return expand(grants, members), nil
+}
+
+// permissionGrants reads the permissions granted on a project, or globally when
+// project is empty.
+func permissionGrants(ctx context.Context, client *sonar.Client, project string) ([]projectGrant, error) {
+	users, _, err := client.Permissions.UsersAll(ctx, &sonar.PermissionsUsersOptions{ //nolint:exhaustruct // every user with a permission
+		ProjectKey: project,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groups, _, err := client.Permissions.GroupsAll(ctx, &sonar.PermissionsGroupsOptions{ //nolint:exhaustruct // every group with a permission
+		ProjectKey: project,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var grants []projectGrant
+
+	for _, user := range users {
+		for _, permission := range user.Permissions {
+			grants = append(grants, projectGrant{Grant: Grant{User: user.Login, Group: "", Permission: permission}, project: project})
+		}
+	}
+
+	for _, group := range groups {
+		for _, permission := range group.Permissions {
+			grants = append(grants, projectGrant{Grant: Grant{User: "", Group: group.Name, Permission: permission}, project: project})
+		}
+	}
+
+	return grants, nil
+}
+
+// groupMembers returns the logins of the members of the groups permissions are granted
+// to, by group name.
+func groupMembers(ctx context.Context, client *sonar.Client, grants []projectGrant, workers int) (map[string][]string, error) {
+	var names []string
+
+	for _, granted := range grants {
+		if granted.Group != "" && granted.Group != AnyoneGroup && !slices.Contains(names, granted.Group) {
+			names = append(names, granted.Group)
+		}
+	}
+
+	logins := make([][]string, len(names))
+	errs := make([]error, len(names))
+
+	parallel.ForEach(len(names), workers, func(i int) {
+		users, _, err := client.UserGroups.UsersAll(ctx, &sonar.UserGroupsUsersOptions{ //nolint:exhaustruct // every member
+			Name:     names[i],
+			Selected: "selected",
+		})
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to read the members of group %s: %w", names[i], err)
+
+			return
+		}
+
+		for _, user := range users {
+			logins[i] = append(logins[i], user.Login)
+		}
+	})
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[string][]string, len(names))
+
+	for i, name := range names {
+		members[name] = logins[i]
+	}
+
+	return members, nil
+}
+
+// expand turns grants to groups into entries for their members, and sorts the entries.
+func expand(grants []projectGrant, members map[string][]string) *Matrix {
+	entries := make([]Entry, 0, len(grants))
+
+	for _, granted := range grants {
+		switch {
+		case granted.User != "":
+			entries = append(entries, Entry{User: granted.User, Project: granted.project, Permission: granted.Permission, Group: granted.Group})
+		case granted.Group == AnyoneGroup:
+			entries = append(entries, Entry{User: Everyone, Project: granted.project, Permission: granted.Permission, Group: granted.Group})
+		default:
+			for _, login := range members[granted.Group] {
+				entries = append(entries, Entry{User: login, Project: granted.project, Permission: granted.Permission, Group: granted.Group})
+			}
+		}
+	}
+
+	slices.SortFunc(entries, compareEntries)
+
+	return &Matrix{Entries: slices.Compact(entries)}
+}
+
+// validateParallel checks that a number of concurrent reads is not negative.
+func validateParallel(parallel int) error {
+	if parallel < 0 {
+		return sonar.NewValidationError("Parallel", "must not be negative", sonar.ErrOutOfRange)
+	}
+
+	return nil
+}
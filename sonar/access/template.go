@@ -0,0 +1,417 @@
+package access
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/parallel"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+const (
+	// defaultChunkSize is the number of projects a template is applied to per request
+	// by default.
+	defaultChunkSize = 100
+	// maxChunkSize is the number of projects the server accepts per request.
+	maxChunkSize = 1000
+)
+
+var (
+	// ErrAmbiguousTemplate is returned when the project key patterns of several templates
+	// match a project key: the server refuses to create such a project.
+	ErrAmbiguousTemplate = errors.New("several permission templates match the project key")
+	// ErrNoTemplate is returned when no template matches a project key and there is no
+	// default template.
+	ErrNoTemplate = errors.New("no permission template matches the project key")
+)
+
+// Template is a permission template with the permissions it grants.
+type Template struct {
+	// ID is the id of the template.
+	ID string `json:"id"`
+	// Name is the name of the template.
+	Name string `json:"name"`
+	// ProjectKeyPattern is the regular expression the whole key of a new project must
+	// match for the template to apply to it, empty for none.
+	ProjectKeyPattern string `json:"projectKeyPattern,omitempty"`
+	// Default reports whether the template applies to new projects no pattern matches.
+	Default bool `json:"default,omitempty"`
+	// Grants are the permissions the template grants to users and groups, sorted.
+	Grants []Grant `json:"grants,omitempty"`
+	// CreatorPermissions are the permissions the template grants to the creator of a
+	// new project.
+	CreatorPermissions []string `json:"creatorPermissions,omitempty"`
+}
+
+// Templates reads the permission templates, with the users and groups each one grants
+// permissions to.
+func Templates(ctx context.Context, client *sonar.Client) ([]*Template, error) {
+	search, _, err := client.Permissions.SearchTemplates(ctx, &sonar.PermissionsSearchTemplatesOptions{}) //nolint:exhaustruct // every template
+	if err != nil {
+		return nil, fmt.Errorf("failed to search permission templates: %w", err)
+	}
+
+	templates := make([]*Template, 0, len(search.PermissionTemplates))
+
+	for _, found := range search.PermissionTemplates {
+		template := &Template{
+			ID:                 found.ID,
+			Name:               found.Name,
+			ProjectKeyPattern:  found.ProjectKeyPattern,
+			Default:            false,
+			Grants:             nil,
+			CreatorPermissions: nil,
+		}
+
+		for _, defaultTemplate := range search.DefaultTemplates {
+			if defaultTemplate.TemplateID == found.ID && cmp.Or(defaultTemplate.Qualifier, sonar.ProjectQualifierTRK) == sonar.ProjectQualifierTRK {
+				template.Default = true
+			}
+		}
+
+		for _, permission := range found.Permissions {
+			if permission.WithProjectCreator {
+				template.CreatorPermissions = append(template.CreatorPermissions, permission.Key)
+			}
+		}
+
+		template.Grants, err = templateGrants(ctx, client, found.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read permission template %s: %w", found.Name, err)
+		}
+
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// templateGrants reads the permissions a template grants to users and groups.
+func templateGrants(ctx context.Context, client *sonar.Client, id string) ([]Grant, error) {
+	users, _, err := client.Permissions.TemplateUsersAll(ctx, &sonar.PermissionsTemplateUsersOptions{ //nolint:exhaustruct // every user of the template
+		TemplateID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groups, _, err := client.Permissions.TemplateGroupsAll(ctx, &sonar.PermissionsTemplateGroupsOptions{ //nolint:exhaustruct // every group of the template
+		TemplateID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var grants []Grant
+
+	for _, user := range users {
+		for _, permission := range user.Permissions {
+			grants = append(grants, Grant{User: user.Login, Group: "", Permission: permission})
+		}
+	}
+
+	for _, group := range groups {
+		for _, permission := range group.Permissions {
+			grants = append(grants, Grant{User: "", Group: group.Name, Permission: permission})
+		}
+	}
+
+	slices.SortFunc(grants, compareGrants)
+
+	return grants, nil
+}
+
+// Matches reports whether the project key pattern of the template matches the whole
+// key. Patterns are Java regular expressions; the ones Go cannot compile, such as
+// patterns with lookarounds, are reported as errors.
+func (t *Template) Matches(key string) (bool, error) {
+	if strings.TrimSpace(t.ProjectKeyPattern) == "" {
+		return false, nil
+	}
+
+	pattern, err := regexp.Compile(`^(?:` + t.ProjectKeyPattern + `)$`)
+	if err != nil {
+		return false, fmt.Errorf("invalid project key pattern of permission template %s: %w", t.Name, err)
+	}
+
+	return pattern.MatchString(key), nil
+}
+
+// MatchTemplate returns the template the server applies to a new project with the
+// given key: the one whose project key pattern matches the key, or the default
+// template when none does.
+func MatchTemplate(templates []*Template, key string) (*Template, error) {
+	var matching []string
+
+	found := -1
+
+	for i, template := range templates {
+		matches, err := template.Matches(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			matching = append(matching, template.Name)
+			found = i
+		}
+	}
+
+	if len(matching) > 1 {
+		return nil, fmt.Errorf("%w %s: %s", ErrAmbiguousTemplate, key, strings.Join(matching, ", "))
+	}
+
+	if found >= 0 {
+		return templates[found], nil
+	}
+
+	idx := slices.IndexFunc(templates, func(template *Template) bool { return template.Default })
+	if idx < 0 {
+		return nil, fmt.Errorf("%w %s", ErrNoTemplate, key)
+	}
+
+	return templates[idx], nil
+}
+
+// GrantChange is a permission a template would grant or revoke on a project.
+type GrantChange struct {
+	Grant
+
+	// Type is the kind of change.
+	Type ChangeType `json:"type"`
+}
+
+// TemplatePreview is the permission set a template would give a project.
+type TemplatePreview struct {
+	// Project is the project key.
+	Project string `json:"project"`
+	// Template is the name of the template.
+	Template string `json:"template"`
+	// Pattern is the project key pattern the key matches, empty when the template is
+	// the default one or was chosen.
+	Pattern string `json:"pattern,omitempty"`
+	// Exists reports whether the project exists. A template is applied to a new project
+	// when it is created, its creator getting the creator permissions too.
+	Exists bool `json:"exists"`
+	// Public reports whether the project is public. Browse and see source code are not
+	// granted on public projects, where everyone has them. New projects are previewed
+	// as private.
+	Public bool `json:"public,omitempty"`
+	// Current are the permissions granted on the project.
+	Current []Grant `json:"current"`
+	// Result are the permissions granted once the template is applied, which replace
+	// every current permission.
+	Result []Grant `json:"result"`
+	// CreatorPermissions are the permissions the creator of a new project gets.
+	CreatorPermissions []string `json:"creatorPermissions,omitempty"`
+	// Changes are the permissions granted and revoked by applying the template.
+	Changes []GrantChange `json:"changes"`
+}
+
+// PreviewOptions selects the template PreviewTemplates evaluates.
+type PreviewOptions struct {
+	// Template is the name of the template to apply. When empty, the template the
+	// server would apply to a new project with the key is used.
+	Template string
+	// Parallel is the number of projects read concurrently, 4 when zero. It must not be
+	// negative.
+	Parallel int
+}
+
+// PreviewTemplates returns the permissions applying a template would give each
+// project, compared with their current permissions. Nothing is changed on the server.
+func PreviewTemplates(ctx context.Context, client *sonar.Client, templates []*Template, keys []string, opt PreviewOptions) ([]*TemplatePreview, error) {
+	err := validateParallel(opt.Parallel)
+	if err != nil {
+		return nil, err
+	}
+
+	previews := make([]*TemplatePreview, len(keys))
+	chosen := make([]*Template, len(keys))
+
+	for i, key := range keys {
+		template, err := previewTemplate(templates, key, opt.Template)
+		if err != nil {
+			return nil, err
+		}
+
+		chosen[i] = template
+		previews[i] = &TemplatePreview{
+			Project:            key,
+			Template:           template.Name,
+			Pattern:            "",
+			Exists:             false,
+			Public:             false,
+			Current:            []Grant{},
+			Result:             nil,
+			CreatorPermissions: nil,
+			Changes:            nil,
+		}
+
+		if matches, _ := template.Matches(key); matches && opt.Template == "" {
+			previews[i].Pattern = template.ProjectKeyPattern
+		}
+	}
+
+	projects, _, err := client.Projects.SearchAll(ctx, &sonar.ProjectsSearchOptions{Projects: keys}) //nolint:exhaustruct // the given projects
+	if err != nil {
+		return nil, fmt.Errorf("failed to search projects: %w", err)
+	}
+
+	for _, project := range projects {
+		for _, preview := range previews {
+			if preview.Project == project.Key {
+				preview.Exists = true
+				preview.Public = project.Visibility == sonar.ProjectVisibilityPublic
+			}
+		}
+	}
+
+	errs := make([]error, len(previews))
+
+	parallel.ForEach(len(previews), cmp.Or(opt.Parallel, defaultParallel), func(i int) {
+		if !previews[i].Exists {
+			return
+		}
+
+		grants, err := permissionGrants(ctx, client, previews[i].Project)
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to read the permissions of project %s: %w", previews[i].Project, err)
+
+			return
+		}
+
+		for _, granted := range grants {
+			previews[i].Current = append(previews[i].Current, granted.Grant)
+		}
+
+		slices.SortFunc(previews[i].Current, compareGrants)
+	})
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, preview := range previews {
+		preview.Result = templateResult(chosen[i], preview.Public)
+		preview.Changes = compareGrantSets(preview.Current, preview.Result)
+
+		if !preview.Exists {
+			preview.CreatorPermissions = chosen[i].CreatorPermissions
+		}
+	}
+
+	return previews, nil
+}
+
+// previewTemplate returns the template named name, or the one matching the key when
+// name is empty.
+func previewTemplate(templates []*Template, key, name string) (*Template, error) {
+	if name == "" {
+		return MatchTemplate(templates, key)
+	}
+
+	idx := slices.IndexFunc(templates, func(template *Template) bool { return template.Name == name })
+	if idx < 0 {
+		return nil, fmt.Errorf("%w: there is no permission template named %s", ErrNoTemplate, name)
+	}
+
+	return templates[idx], nil
+}
+
+// templateResult returns the permissions the template grants on a project, as the
+// server applies them: browse and see source code are skipped on public projects, and
+// permissions of the Anyone group on private ones.
+func templateResult(template *Template, public bool) []Grant {
+	result := make([]Grant, 0, len(template.Grants))
+
+	for _, granted := range template.Grants {
+		if public && slices.Contains(publicPermissions, granted.Permission) || !public && granted.Group == AnyoneGroup {
+			continue
+		}
+
+		result = append(result, granted)
+	}
+
+	return result
+}
+
+// compareGrantSets returns the grants of to missing from from, as granted, and the
+// grants of from missing from to, as revoked, sorted.
+func compareGrantSets(from, to []Grant) []GrantChange {
+	changes := []GrantChange{}
+
+	for _, granted := range to {
+		if !slices.Contains(from, granted) {
+			changes = append(changes, GrantChange{Grant: granted, Type: ChangeGranted})
+		}
+	}
+
+	for _, granted := range from {
+		if !slices.Contains(to, granted) {
+			changes = append(changes, GrantChange{Grant: granted, Type: ChangeRevoked})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b GrantChange) int { return compareGrants(a.Grant, b.Grant) })
+
+	return changes
+}
+
+// ApplyOptions configures ApplyTemplates.
+type ApplyOptions struct {
+	// ChunkSize is the number of projects per request, 100 when zero. The server
+	// accepts up to 1000, and it must not be negative.
+	ChunkSize int
+}
+
+// ApplyTemplates applies to each existing project with changes the template of its
+// preview, replacing its permissions. Projects sharing a template are sent in chunks,
+// one request per chunk, and it stops at the first failed request. It returns the
+// number of projects the templates were applied to.
+func ApplyTemplates(ctx context.Context, client *sonar.Client, previews []*TemplatePreview, opt ApplyOptions) (int, error) {
+	err := sonar.ValidateRange(int64(opt.ChunkSize), 0, maxChunkSize, "ChunkSize")
+	if err != nil {
+		return 0, err
+	}
+
+	var names []string
+
+	projects := map[string][]string{}
+
+	for _, preview := range previews {
+		if !preview.Exists || len(preview.Changes) == 0 {
+			continue
+		}
+
+		if _, found := projects[preview.Template]; !found {
+			names = append(names, preview.Template)
+		}
+
+		projects[preview.Template] = append(projects[preview.Template], preview.Project)
+	}
+
+	applied := 0
+
+	for _, name := range names {
+		for chunk := range slices.Chunk(projects[name], cmp.Or(opt.ChunkSize, defaultChunkSize)) {
+			_, err = client.Permissions.BulkApplyTemplate(ctx, &sonar.PermissionsBulkApplyTemplateOptions{ //nolint:exhaustruct // the given projects only
+				Projects:     chunk,
+				TemplateName: name,
+			})
+			if err != nil {
+				return applied, fmt.Errorf("failed to apply permission template %s to %s: %w", name, strings.Join(chunk, ", "), err)
+			}
+
+			applied += len(chunk)
+		}
+	}
+
+	return applied, nil
+}
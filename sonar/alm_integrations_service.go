@@ -235,14 +235,14 @@ type GitlabRepository struct {
 type AlmIntegrationsCheckPatOptions struct {
 	// AlmSetting is the DevOps Platform setting key (required).
 	// Maximum length: 200 characters
-	AlmSetting string `url:"almSetting,omitempty"`
+	AlmSetting string `url:"almSetting,omitempty" cli:"positional"`
 }
 
 // AlmIntegrationsGetGithubClientIDOptions contains options for getting a GitHub client ID.
 type AlmIntegrationsGetGithubClientIDOptions struct {
 	// AlmSetting is the DevOps Platform setting key (required).
 	// Maximum length: 200 characters
-	AlmSetting string `url:"almSetting,omitempty"`
+	AlmSetting string `url:"almSetting,omitempty" cli:"positional"`
 }
 
 // AlmIntegrationsImportAzureProjectOptions contains options for importing an Azure DevOps project.
@@ -353,14 +353,14 @@ type AlmIntegrationsImportGitlabProjectOptions struct {
 type AlmIntegrationsListAzureProjectsOptions struct {
 	// AlmSetting is the DevOps Platform setting key (required).
 	// Maximum length: 200 characters
-	AlmSetting string `url:"almSetting,omitempty"`
+	AlmSetting string `url:"almSetting,omitempty" cli:"positional"`
 }
 
 // AlmIntegrationsListBitbucketServerProjectsOptions contains options for listing Bitbucket Server projects.
 type AlmIntegrationsListBitbucketServerProjectsOptions struct {
 	// AlmSetting is the DevOps Platform setting key (required).
 	// Maximum length: 200 characters
-	AlmSetting string `url:"almSetting,omitempty"`
+	AlmSetting string `url:"almSetting,omitempty" cli:"positional"`
 	// PageSize is the number of items to return (optional, default: 25, max: 100).
 	PageSize int64 `url:"pageSize,omitempty"`
 	// Start is the start number for the page (inclusive, optional).
@@ -377,7 +377,7 @@ type AlmIntegrationsListGithubOrganizationsOptions struct {
 
 	// AlmSetting is the DevOps Platform setting key (required).
 	// Maximum length: 200 characters
-	AlmSetting string `url:"almSetting,omitempty"`
+	AlmSetting string `url:"almSetting,omitempty" cli:"positional"`
 	// Token is the GitHub authorization code (optional).
 	// Maximum length: 200 characters
 	Token string `url:"token,omitempty"`
@@ -404,7 +404,7 @@ type AlmIntegrationsListGithubRepositoriesOptions struct {
 type AlmIntegrationsSearchAzureReposOptions struct {
 	// AlmSetting is the DevOps Platform setting key (required).
 	// Maximum length: 200 characters
-	AlmSetting string `url:"almSetting,omitempty"`
+	AlmSetting string `url:"almSetting,omitempty" cli:"positional"`
 	// ProjectName is the project name filter (optional).
 	// Maximum length: 200 characters
 	ProjectName string `url:"projectName,omitempty"`
@@ -422,7 +422,7 @@ type AlmIntegrationsSearchBitbucketCloudReposOptions struct {
 
 	// AlmSetting is the DevOps Platform setting key (required).
 	// Maximum length: 200 characters
-	AlmSetting string `url:"almSetting,omitempty"`
+	AlmSetting string `url:"almSetting,omitempty" cli:"positional"`
 	// RepositoryName is the repository name filter (optional).
 	// Maximum length: 200 characters
 	RepositoryName string `url:"repositoryName,omitempty"`
@@ -434,7 +434,7 @@ type AlmIntegrationsSearchBitbucketCloudReposOptions struct {
 type AlmIntegrationsSearchBitbucketServerReposOptions struct {
 	// AlmSetting is the DevOps Platform setting key (required).
 	// Maximum length: 200 characters
-	AlmSetting string `url:"almSetting,omitempty"`
+	AlmSetting string `url:"almSetting,omitempty" cli:"positional"`
 	// PageSize is the number of items to return (optional, default: 25, max: 100).
 	PageSize int64 `url:"pageSize,omitempty"`
 	// ProjectName is the project name filter (optional).
@@ -457,7 +457,7 @@ type AlmIntegrationsSearchGitlabReposOptions struct {
 
 	// AlmSetting is the DevOps Platform setting key (required).
 	// Maximum length: 200 characters
-	AlmSetting string `url:"almSetting,omitempty"`
+	AlmSetting string `url:"almSetting,omitempty" cli:"positional"`
 	// ProjectName is the project name filter (optional).
 	// Maximum length: 200 characters
 	ProjectName string `url:"projectName,omitempty"`
@@ -185,7 +185,7 @@ type AlmValidationError struct {
 type AlmSettingsCountBindingOptions struct {
 	// AlmSetting is the DevOps Platform setting key.
 	// This field is required.
-	AlmSetting string `url:"almSetting"`
+	AlmSetting string `url:"almSetting" cli:"positional"`
 }
 
 // AlmSettingsCreateAzureOptions contains parameters for the CreateAzure method.
@@ -295,21 +295,21 @@ type AlmSettingsCreateGithubFromManifestOptions struct {
 type AlmSettingsDeleteOptions struct {
 	// Key is the DevOps Platform Setting key.
 	// This field is required.
-	Key string `url:"key"`
+	Key string `url:"key" cli:"positional"`
 }
 
 // AlmSettingsDeleteBindingOptions contains parameters for the DeleteBinding method.
 type AlmSettingsDeleteBindingOptions struct {
 	// Project is the project key.
 	// This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 }
 
 // AlmSettingsGetBindingOptions contains parameters for the GetBinding method.
 type AlmSettingsGetBindingOptions struct {
 	// Project is the project key.
 	// This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 }
 
 // AlmSettingsSetAzureBindingOptions contains parameters for the SetAzureBinding method.
@@ -510,7 +510,7 @@ type AlmSettingsUpdateGitlabOptions struct {
 type AlmSettingsValidateOptions struct {
 	// Key is the unique key of the DevOps Platform settings.
 	// This field is required. Maximum length: 200 characters.
-	Key string `url:"key"`
+	Key string `url:"key" cli:"positional"`
 }
 
 // -----------------------------------------------------------------------------
@@ -33,7 +33,7 @@ type AnalysisCacheGetOptions struct {
 	Branch string `url:"branch,omitempty"`
 	// Project key.
 	// This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 }
 
 // -----------------------------------------------------------------------------
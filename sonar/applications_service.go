@@ -133,13 +133,13 @@ type ApplicationsCreateOptions struct {
 // ApplicationsDeleteOptions contains parameters for the Delete method.
 type ApplicationsDeleteOptions struct {
 	// Application is the application key. This field is required.
-	Application string `url:"application"`
+	Application string `url:"application" cli:"positional"`
 }
 
 // ApplicationsShowOptions contains parameters for the Show method.
 type ApplicationsShowOptions struct {
 	// Application is the application key. This field is required.
-	Application string `url:"application"`
+	Application string `url:"application" cli:"positional"`
 	// Branch is the branch name. Optional.
 	Branch string `url:"branch,omitempty"`
 }
@@ -147,7 +147,7 @@ type ApplicationsShowOptions struct {
 // ApplicationsShowLeakOptions contains parameters for the ShowLeak method.
 type ApplicationsShowLeakOptions struct {
 	// Application is the application key. This field is required.
-	Application string `url:"application"`
+	Application string `url:"application" cli:"positional"`
 	// Branch is the branch name. Optional.
 	Branch string `url:"branch,omitempty"`
 }
@@ -227,7 +227,7 @@ type ApplicationsSearchProjectsOptions struct {
 	PaginationArgs
 
 	// Application is the application key. This field is required.
-	Application string `url:"application"`
+	Application string `url:"application" cli:"positional"`
 	// Query limits search to project names containing this string. Optional.
 	Query string `url:"q,omitempty"`
 	// Selected filters by selection state (selected, deselected, all). Optional.
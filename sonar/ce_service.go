@@ -345,7 +345,7 @@ type CeAnalysisStatusOptions struct {
 	Branch string `url:"branch,omitempty"`
 	// Component is the component key.
 	// This field is required.
-	Component string `url:"component"`
+	Component string `url:"component" cli:"positional"`
 	// PullRequest is the pull request ID.
 	PullRequest string `url:"pullRequest,omitempty"`
 }
@@ -354,14 +354,14 @@ type CeAnalysisStatusOptions struct {
 type CeCancelOptions struct {
 	// ID is the ID of the task to cancel.
 	// This field is required.
-	ID string `url:"id"`
+	ID string `url:"id" cli:"positional"`
 }
 
 // CeComponentOptions contains parameters for the Component method.
 type CeComponentOptions struct {
 	// Component is the component key.
 	// This field is required.
-	Component string `url:"component"`
+	Component string `url:"component" cli:"positional"`
 }
 
 // CeDismissAnalysisWarningOptions contains parameters for the DismissAnalysisWarning method.
@@ -401,7 +401,7 @@ type CeTaskOptions struct {
 	AdditionalFields []string `url:"additionalFields,omitempty,comma"`
 	// ID is the ID of the task.
 	// This field is required.
-	ID string `url:"id"`
+	ID string `url:"id" cli:"positional"`
 }
 
 // -----------------------------------------------------------------------------
@@ -430,7 +430,7 @@ type ComponentsAppOptions struct {
 	Branch string `url:"branch,omitempty"`
 	// Component is the component key.
 	// This field is required.
-	Component string `url:"component"`
+	Component string `url:"component" cli:"positional"`
 	// PullRequest is the pull request id. Not available in the community edition.
 	// Either branch or pullRequest can be provided, not both.
 	PullRequest string `url:"pullRequest,omitempty"`
@@ -485,7 +485,7 @@ type ComponentsShowOptions struct {
 	Branch string `url:"branch,omitempty"`
 	// Component is the component key.
 	// This field is required.
-	Component string `url:"component"`
+	Component string `url:"component" cli:"positional"`
 	// PullRequest is the pull request id. Not available in the community edition.
 	PullRequest string `url:"pullRequest,omitempty"`
 }
@@ -519,7 +519,7 @@ type ComponentsTreeOptions struct {
 	Branch string `url:"branch,omitempty"`
 	// Component is the base component key. The search is based on this component.
 	// This field is required.
-	Component string `url:"component"`
+	Component string `url:"component" cli:"positional"`
 	// PullRequest is the pull request id. Not available in the community edition.
 	PullRequest string `url:"pullRequest,omitempty"`
 	// Query limits search to component names that contain the supplied string
@@ -0,0 +1,35 @@
+// Package config manages SonarQube instance configuration as code.
+//
+// A Configuration declares the quality gates, quality profiles, permission templates,
+// settings, webhooks and new code periods an instance should have. It is usually
+// written in YAML and read with Load or LoadFiles:
+//
+//	qualityGates:
+//	  - name: Strict
+//	    default: true
+//	    conditions:
+//	      - {metric: new_coverage, op: LT, error: "80"}
+//	qualityProfiles:
+//	  - name: Strict Java
+//	    language: java
+//	    parent: Sonar way
+//	    rules:
+//	      - {key: "java:S138", severity: MAJOR, params: {max: "60"}}
+//
+// NewPlan compares a Configuration with a live server and returns the changes that
+// would make the server match it, and Plan.Apply makes those changes through the
+// sonar services, undoing the applied ones if a change fails:
+//
+//	desired, err := config.LoadFiles("sonar.yaml")
+//	plan, err := config.NewPlan(ctx, client, desired)
+//	result, err := plan.Apply(ctx)
+//
+// # What is managed
+//
+// Objects the configuration does not declare are left alone: planning never deletes
+// a quality gate, profile, template, setting or webhook it does not know about.
+// Within a declared object, a declared list is authoritative: the conditions of a
+// gate with a conditions list, the rules of a profile with a rules list and the
+// permissions of a template with groups or users are made to match the list exactly,
+// while an omitted list leaves that part of the object unmanaged.
+package config
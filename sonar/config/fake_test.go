@@ -0,0 +1,695 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGate is a quality gate of the fake server.
+type fakeGate struct {
+	builtIn    bool
+	conditions []sonar.QualityGateCondition
+}
+
+// fakeProfile is a quality profile of the fake server.
+type fakeProfile struct {
+	profile sonar.QualityProfile
+	rules   map[string]sonar.RulesActivation
+}
+
+// fakeTemplate is a permission template of the fake server.
+type fakeTemplate struct {
+	template sonar.PermissionTemplate
+	groups   map[string][]string
+	users    map[string][]string
+}
+
+// fakeWebhook is a webhook of the fake server.
+type fakeWebhook struct {
+	webhook sonar.WebhooksDefinition
+	project string
+}
+
+// fakeServer is a stateful SonarQube serving the endpoints the planner uses.
+type fakeServer struct {
+	mu sync.Mutex
+
+	nextID      int
+	gates       map[string]*fakeGate
+	defaultGate string
+	profiles    map[string]*fakeProfile
+	templates   map[string]*fakeTemplate
+	defaultTmpl string
+	settings    map[string]map[string]sonar.SettingValue
+	secured     map[string]bool
+	webhooks    []*fakeWebhook
+	periods     map[string]sonar.NewCodePeriodsShow
+
+	// failOn makes the endpoint with this path fail with 400.
+	failOn string
+	// writes records the write requests, as "path?query".
+	writes []string
+}
+
+// newFakeServer returns a fake server holding a built-in gate, a built-in Java profile
+// with one rule and the default permission template, and a client for it.
+func newFakeServer(t *testing.T) (*fakeServer, *sonar.Client) {
+	t.Helper()
+
+	fake := &fakeServer{
+		gates: map[string]*fakeGate{"Sonar way": {builtIn: true, conditions: []sonar.QualityGateCondition{
+			{ID: "c0", Metric: "new_coverage", Op: OpLowerThan, Error: "80"},
+		}}},
+		defaultGate: "Sonar way",
+		profiles: map[string]*fakeProfile{"java-sonar-way": {
+			profile: sonar.QualityProfile{Key: "java-sonar-way", Name: "Sonar way", Language: "java", IsBuiltIn: true, IsDefault: true},
+			rules:   map[string]sonar.RulesActivation{"java:S100": {QProfile: "java-sonar-way", Severity: "MINOR", Inherit: "NONE"}},
+		}},
+		templates: map[string]*fakeTemplate{"default_template": {
+			template: sonar.PermissionTemplate{ID: "default_template", Name: "Default template"},
+			groups:   map[string][]string{"sonar-administrators": {"admin"}},
+			users:    map[string][]string{},
+		}},
+		defaultTmpl: "default_template",
+		settings:    map[string]map[string]sonar.SettingValue{"": {}},
+		secured:     map[string]bool{},
+		periods:     map[string]sonar.NewCodePeriodsShow{"": {Type: sonar.NewCodePeriodTypePreviousVersion}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(server.Close)
+
+	serverURL := server.URL + "/api/"
+
+	client, err := sonar.NewClient(&sonar.ClientCreateOptions{URL: &serverURL})
+	require.NoError(t, err)
+
+	return fake, client
+}
+
+// serveHTTP dispatches a request to the handler of its path.
+func (f *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/")
+	query := r.URL.Query()
+
+	if r.Method == http.MethodPost {
+		f.writes = append(f.writes, path+"?"+r.URL.RawQuery)
+	}
+
+	if path == f.failOn {
+		writeFake(w, http.StatusBadRequest, map[string]any{"errors": []map[string]string{{"msg": "injected failure"}}})
+
+		return
+	}
+
+	handlers := map[string]func(url.Values) (any, int){
+		"qualitygates/list":                      f.listGates,
+		"qualitygates/show":                      f.showGate,
+		"qualitygates/create":                    f.createGate,
+		"qualitygates/destroy":                   f.destroyGate,
+		"qualitygates/set_as_default":            f.setDefaultGate,
+		"qualitygates/create_condition":          f.createCondition,
+		"qualitygates/update_condition":          f.updateCondition,
+		"qualitygates/delete_condition":          f.deleteCondition,
+		"qualityprofiles/search":                 f.searchProfiles,
+		"qualityprofiles/create":                 f.createProfile,
+		"qualityprofiles/delete":                 f.deleteProfile,
+		"qualityprofiles/change_parent":          f.changeParent,
+		"qualityprofiles/set_default":            f.setDefaultProfile,
+		"qualityprofiles/activate_rule":          f.activateRule,
+		"qualityprofiles/deactivate_rule":        f.deactivateRule,
+		"rules/search":                           f.searchRules,
+		"permissions/search_templates":           f.searchTemplates,
+		"permissions/create_template":            f.createTemplate,
+		"permissions/update_template":            f.updateTemplate,
+		"permissions/delete_template":            f.deleteTemplate,
+		"permissions/set_default_template":       f.setDefaultTemplate,
+		"permissions/template_groups":            f.templateGrants(true),
+		"permissions/template_users":             f.templateGrants(false),
+		"permissions/add_group_to_template":      f.grant(true, true),
+		"permissions/remove_group_from_template": f.grant(true, false),
+		"permissions/add_user_to_template":       f.grant(false, true),
+		"permissions/remove_user_from_template":  f.grant(false, false),
+		"settings/values":                        f.settingValues,
+		"settings/set":                           f.setSetting,
+		"settings/reset":                         f.resetSetting,
+		"webhooks/list":                          f.listWebhooks,
+		"webhooks/create":                        f.createWebhook,
+		"webhooks/update":                        f.updateWebhook,
+		"webhooks/delete":                        f.deleteWebhook,
+		"new_code_periods/show":                  f.showPeriod,
+		"new_code_periods/set":                   f.setPeriod,
+		"new_code_periods/unset":                 f.unsetPeriod,
+	}
+
+	handler, found := handlers[path]
+	if !found {
+		writeFake(w, http.StatusNotFound, nil)
+
+		return
+	}
+
+	body, status := handler(query)
+	writeFake(w, status, body)
+}
+
+// writeFake writes a JSON response.
+func writeFake(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// id returns a new identifier.
+func (f *fakeServer) id(prefix string) string {
+	f.nextID++
+
+	return fmt.Sprintf("%s%d", prefix, f.nextID)
+}
+
+// fakeError is the body of an error response.
+func fakeError(message string) any {
+	return map[string]any{"errors": []map[string]string{{"msg": message}}}
+}
+
+func (f *fakeServer) listGates(url.Values) (any, int) {
+	var gates []sonar.QualityGate
+
+	for _, name := range sortedKeys(f.gates) {
+		gates = append(gates, sonar.QualityGate{Name: name, IsBuiltIn: f.gates[name].builtIn, IsDefault: name == f.defaultGate})
+	}
+
+	return sonar.QualitygatesList{Qualitygates: gates}, http.StatusOK
+}
+
+func (f *fakeServer) showGate(query url.Values) (any, int) {
+	gate, found := f.gates[query.Get("name")]
+	if !found {
+		return fakeError("no gate"), http.StatusNotFound
+	}
+
+	return sonar.QualitygatesShow{Name: query.Get("name"), Conditions: gate.conditions}, http.StatusOK
+}
+
+func (f *fakeServer) createGate(query url.Values) (any, int) {
+	name := query.Get("name")
+	if _, found := f.gates[name]; found {
+		return fakeError("name taken"), http.StatusBadRequest
+	}
+
+	f.gates[name] = &fakeGate{}
+
+	return sonar.QualitygatesCreate{ID: f.id("g"), Name: name}, http.StatusOK
+}
+
+func (f *fakeServer) destroyGate(query url.Values) (any, int) {
+	delete(f.gates, query.Get("name"))
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) setDefaultGate(query url.Values) (any, int) {
+	f.defaultGate = query.Get("name")
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) createCondition(query url.Values) (any, int) {
+	gate, found := f.gates[query.Get("gateName")]
+	if !found {
+		return fakeError("no gate"), http.StatusNotFound
+	}
+
+	condition := sonar.QualityGateCondition{ID: f.id("c"), Metric: query.Get("metric"), Op: query.Get("op"), Error: query.Get("error")}
+	gate.conditions = append(gate.conditions, condition)
+
+	return sonar.QualitygatesCreateCondition{ID: condition.ID, Metric: condition.Metric, Op: condition.Op, Error: condition.Error}, http.StatusOK
+}
+
+func (f *fakeServer) updateCondition(query url.Values) (any, int) {
+	for _, gate := range f.gates {
+		for idx, condition := range gate.conditions {
+			if condition.ID == query.Get("id") {
+				gate.conditions[idx] = sonar.QualityGateCondition{ID: condition.ID, Metric: query.Get("metric"), Op: query.Get("op"), Error: query.Get("error")}
+
+				return nil, http.StatusNoContent
+			}
+		}
+	}
+
+	return fakeError("no condition"), http.StatusNotFound
+}
+
+func (f *fakeServer) deleteCondition(query url.Values) (any, int) {
+	for _, gate := range f.gates {
+		gate.conditions = slices.DeleteFunc(gate.conditions, func(condition sonar.QualityGateCondition) bool {
+			return condition.ID == query.Get("id")
+		})
+	}
+
+	return nil, http.StatusNoContent
+}
+
+// profileByName returns the profile of a language with a name.
+func (f *fakeServer) profileByName(language, name string) *fakeProfile {
+	for _, profile := range f.profiles {
+		if profile.profile.Language == language && profile.profile.Name == name {
+			return profile
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeServer) searchProfiles(url.Values) (any, int) {
+	var profiles []sonar.QualityProfile
+
+	for _, key := range sortedKeys(f.profiles) {
+		profiles = append(profiles, f.profiles[key].profile)
+	}
+
+	return sonar.QualityprofilesSearch{Profiles: profiles}, http.StatusOK
+}
+
+func (f *fakeServer) createProfile(query url.Values) (any, int) {
+	if f.profileByName(query.Get("language"), query.Get("name")) != nil {
+		return fakeError("name taken"), http.StatusBadRequest
+	}
+
+	profile := sonar.QualityProfile{Key: f.id("qp"), Name: query.Get("name"), Language: query.Get("language")}
+	f.profiles[profile.Key] = &fakeProfile{profile: profile, rules: map[string]sonar.RulesActivation{}}
+
+	return sonar.QualityprofilesCreate{Profile: sonar.QualityprofilesCreatedProfile{Key: profile.Key, Name: profile.Name, Language: profile.Language}}, http.StatusOK
+}
+
+func (f *fakeServer) deleteProfile(query url.Values) (any, int) {
+	profile := f.profileByName(query.Get("language"), query.Get("qualityProfile"))
+	if profile == nil {
+		return fakeError("no profile"), http.StatusNotFound
+	}
+
+	delete(f.profiles, profile.profile.Key)
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) changeParent(query url.Values) (any, int) {
+	profile := f.profileByName(query.Get("language"), query.Get("qualityProfile"))
+	if profile == nil {
+		return fakeError("no profile"), http.StatusNotFound
+	}
+
+	profile.profile.ParentName = query.Get("parentQualityProfile")
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) setDefaultProfile(query url.Values) (any, int) {
+	for _, profile := range f.profiles {
+		if profile.profile.Language == query.Get("language") {
+			profile.profile.IsDefault = profile.profile.Name == query.Get("qualityProfile")
+		}
+	}
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) activateRule(query url.Values) (any, int) {
+	profile, found := f.profiles[query.Get("key")]
+	if !found {
+		return fakeError("no profile"), http.StatusNotFound
+	}
+
+	rule := query.Get("rule")
+
+	if query.Get("reset") == "true" {
+		active := profile.rules[rule]
+		active.Inherit = sonar.InheritanceTypeInherited
+		profile.rules[rule] = active
+
+		return nil, http.StatusNoContent
+	}
+
+	activation := sonar.RulesActivation{QProfile: profile.profile.Key, Severity: query.Get("severity"), Inherit: sonar.InheritanceTypeNone}
+	activation.PrioritizedRule = query.Get("prioritizedRule") == "true"
+
+	if params := query.Get("params"); params != "" {
+		for _, pair := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(pair, "=")
+			activation.Params = append(activation.Params, sonar.RulesParamKV{Key: name, Value: value})
+		}
+	}
+
+	profile.rules[rule] = activation
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) deactivateRule(query url.Values) (any, int) {
+	profile, found := f.profiles[query.Get("key")]
+	if !found {
+		return fakeError("no profile"), http.StatusNotFound
+	}
+
+	delete(profile.rules, query.Get("rule"))
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) searchRules(query url.Values) (any, int) {
+	profile, found := f.profiles[query.Get("qprofile")]
+	if !found {
+		return fakeError("no profile"), http.StatusNotFound
+	}
+
+	result := sonar.RulesSearch{Actives: map[string][]sonar.RulesActivation{}}
+
+	for _, key := range sortedKeys(profile.rules) {
+		result.Rules = append(result.Rules, sonar.RulesDetails{Key: key})
+		result.Actives[key] = []sonar.RulesActivation{profile.rules[key]}
+	}
+
+	result.Paging = sonar.Paging{PageIndex: 1, PageSize: sonar.MaxPageSize, Total: int64(len(result.Rules))}
+
+	return result, http.StatusOK
+}
+
+// templateByName returns the template with a name.
+func (f *fakeServer) templateByName(name string) *fakeTemplate {
+	for _, template := range f.templates {
+		if template.template.Name == name {
+			return template
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeServer) searchTemplates(url.Values) (any, int) {
+	var templates []sonar.PermissionTemplate
+
+	for _, id := range sortedKeys(f.templates) {
+		templates = append(templates, f.templates[id].template)
+	}
+
+	return sonar.PermissionsSearchTemplates{
+		PermissionTemplates: templates,
+		DefaultTemplates:    []sonar.PermissionsDefaultTemplate{{Qualifier: sonar.ProjectQualifierTRK, TemplateID: f.defaultTmpl}},
+	}, http.StatusOK
+}
+
+func (f *fakeServer) createTemplate(query url.Values) (any, int) {
+	if f.templateByName(query.Get("name")) != nil {
+		return fakeError("name taken"), http.StatusBadRequest
+	}
+
+	template := sonar.PermissionTemplate{
+		ID:                f.id("tpl"),
+		Name:              query.Get("name"),
+		Description:       query.Get("description"),
+		ProjectKeyPattern: query.Get("projectKeyPattern"),
+	}
+	f.templates[template.ID] = &fakeTemplate{template: template, groups: map[string][]string{}, users: map[string][]string{}}
+
+	return sonar.PermissionsCreateTemplate{PermissionTemplate: sonar.PermissionsTemplateBasic{ID: template.ID, Name: template.Name}}, http.StatusOK
+}
+
+func (f *fakeServer) updateTemplate(query url.Values) (any, int) {
+	template, found := f.templates[query.Get("id")]
+	if !found {
+		return fakeError("no template"), http.StatusNotFound
+	}
+
+	if query.Has("description") {
+		template.template.Description = query.Get("description")
+	}
+
+	if query.Has("projectKeyPattern") {
+		template.template.ProjectKeyPattern = query.Get("projectKeyPattern")
+	}
+
+	return sonar.PermissionsUpdateTemplate{}, http.StatusOK
+}
+
+func (f *fakeServer) deleteTemplate(query url.Values) (any, int) {
+	template := f.templateByName(query.Get("templateName"))
+	if template == nil {
+		return fakeError("no template"), http.StatusNotFound
+	}
+
+	delete(f.templates, template.template.ID)
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) setDefaultTemplate(query url.Values) (any, int) {
+	if id := query.Get("templateId"); id != "" {
+		f.defaultTmpl = id
+
+		return nil, http.StatusNoContent
+	}
+
+	template := f.templateByName(query.Get("templateName"))
+	if template == nil {
+		return fakeError("no template"), http.StatusNotFound
+	}
+
+	f.defaultTmpl = template.template.ID
+
+	return nil, http.StatusNoContent
+}
+
+// templateGrants serves the groups or users of a template.
+func (f *fakeServer) templateGrants(groups bool) func(url.Values) (any, int) {
+	return func(query url.Values) (any, int) {
+		template := f.templateByName(query.Get("templateName"))
+		if template == nil {
+			return fakeError("no template"), http.StatusNotFound
+		}
+
+		if groups {
+			var result sonar.PermissionsTemplateGroups
+
+			for _, name := range sortedKeys(template.groups) {
+				result.Groups = append(result.Groups, sonar.PermissionsTemplateGroup{Name: name, Permissions: template.groups[name]})
+			}
+
+			result.Paging = sonar.Paging{PageIndex: 1, PageSize: sonar.MaxPageSize, Total: int64(len(result.Groups))}
+
+			return result, http.StatusOK
+		}
+
+		var result sonar.PermissionsTemplateUsers
+
+		for _, login := range sortedKeys(template.users) {
+			result.Users = append(result.Users, sonar.PermissionsTemplateUser{Login: login, Permissions: template.users[login]})
+		}
+
+		result.Paging = sonar.Paging{PageIndex: 1, PageSize: sonar.MaxPageSize, Total: int64(len(result.Users))}
+
+		return result, http.StatusOK
+	}
+}
+
+// grant serves the addition or removal of a permission of a template group or user.
+func (f *fakeServer) grant(groups, add bool) func(url.Values) (any, int) {
+	return func(query url.Values) (any, int) {
+		template := f.templateByName(query.Get("templateName"))
+		if template == nil {
+			return fakeError("no template"), http.StatusNotFound
+		}
+
+		grants, name := template.users, query.Get("login")
+		if groups {
+			grants, name = template.groups, query.Get("groupName")
+		}
+
+		permission := query.Get("permission")
+		grants[name] = slices.DeleteFunc(grants[name], func(granted string) bool { return granted == permission })
+
+		if add {
+			grants[name] = append(grants[name], permission)
+		}
+
+		if len(grants[name]) == 0 {
+			delete(grants, name)
+		}
+
+		return nil, http.StatusNoContent
+	}
+}
+
+func (f *fakeServer) settingValues(query url.Values) (any, int) {
+	component := query.Get("component")
+
+	var result sonar.SettingsValues
+
+	for _, key := range strings.Split(query.Get("keys"), ",") {
+		if f.secured[component+"/"+key] {
+			result.SetSecuredSettings = append(result.SetSecuredSettings, key)
+
+			continue
+		}
+
+		if value, found := f.settings[component][key]; found {
+			result.Settings = append(result.Settings, value)
+		} else if value, found := f.settings[""][key]; found && component != "" {
+			value.Inherited = true
+			result.Settings = append(result.Settings, value)
+		}
+	}
+
+	return result, http.StatusOK
+}
+
+func (f *fakeServer) setSetting(query url.Values) (any, int) {
+	component, key := query.Get("component"), query.Get("key")
+
+	if strings.HasSuffix(key, securedSuffix) {
+		f.secured[component+"/"+key] = true
+
+		return nil, http.StatusNoContent
+	}
+
+	if f.settings[component] == nil {
+		f.settings[component] = map[string]sonar.SettingValue{}
+	}
+
+	f.settings[component][key] = sonar.SettingValue{Key: key, Value: query.Get("value"), Values: query["values"]}
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) resetSetting(query url.Values) (any, int) {
+	component := query.Get("component")
+
+	for _, key := range strings.Split(query.Get("keys"), ",") {
+		delete(f.settings[component], key)
+		delete(f.secured, component+"/"+key)
+	}
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) listWebhooks(query url.Values) (any, int) {
+	var result sonar.WebhooksList
+
+	for _, webhook := range f.webhooks {
+		if webhook.project == query.Get("project") {
+			result.Webhooks = append(result.Webhooks, webhook.webhook)
+		}
+	}
+
+	return result, http.StatusOK
+}
+
+func (f *fakeServer) createWebhook(query url.Values) (any, int) {
+	webhook := sonar.WebhooksDefinition{Key: f.id("wh"), Name: query.Get("name"), URL: query.Get("url"), HasSecret: query.Get("secret") != ""}
+	f.webhooks = append(f.webhooks, &fakeWebhook{webhook: webhook, project: query.Get("project")})
+
+	return sonar.WebhooksCreate{Webhook: webhook}, http.StatusOK
+}
+
+func (f *fakeServer) updateWebhook(query url.Values) (any, int) {
+	for _, webhook := range f.webhooks {
+		if webhook.webhook.Key == query.Get("webhook") {
+			webhook.webhook.Name = query.Get("name")
+			webhook.webhook.URL = query.Get("url")
+			webhook.webhook.HasSecret = webhook.webhook.HasSecret || query.Get("secret") != ""
+
+			return nil, http.StatusNoContent
+		}
+	}
+
+	return fakeError("no webhook"), http.StatusNotFound
+}
+
+func (f *fakeServer) deleteWebhook(query url.Values) (any, int) {
+	f.webhooks = slices.DeleteFunc(f.webhooks, func(webhook *fakeWebhook) bool {
+		return webhook.webhook.Key == query.Get("webhook")
+	})
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) showPeriod(query url.Values) (any, int) {
+	project := query.Get("project")
+
+	if period, found := f.periods[project]; found {
+		return period, http.StatusOK
+	}
+
+	period := f.periods[""]
+	period.Inherited = true
+	period.ProjectKey = project
+
+	return period, http.StatusOK
+}
+
+func (f *fakeServer) setPeriod(query url.Values) (any, int) {
+	f.periods[query.Get("project")] = sonar.NewCodePeriodsShow{ProjectKey: query.Get("project"), Type: query.Get("type"), Value: query.Get("value")}
+
+	return nil, http.StatusNoContent
+}
+
+func (f *fakeServer) unsetPeriod(query url.Values) (any, int) {
+	delete(f.periods, query.Get("project"))
+
+	return nil, http.StatusNoContent
+}
+
+// snapshot returns a copy of the state the tests compare before and after a rollback.
+func (f *fakeServer) snapshot() map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	gates := map[string][]string{}
+
+	for name, gate := range f.gates {
+		for _, condition := range gate.conditions {
+			gates[name] = append(gates[name], condition.Metric+condition.Op+condition.Error)
+		}
+
+		slices.Sort(gates[name])
+	}
+
+	profiles := map[string]any{}
+
+	for _, profile := range f.profiles {
+		profiles[profileID(profile.profile.Language, profile.profile.Name)] = []any{profile.profile.ParentName, profile.profile.IsDefault, maps.Clone(profile.rules)}
+	}
+
+	templates := map[string]any{}
+
+	for _, template := range f.templates {
+		templates[template.template.Name] = []any{template.template.Description, maps.Clone(template.groups), maps.Clone(template.users)}
+	}
+
+	return map[string]any{
+		"gates":       gates,
+		"defaultGate": f.defaultGate,
+		"profiles":    profiles,
+		"templates":   templates,
+		"defaultTmpl": f.defaultTmpl,
+		"settings":    maps.Clone(f.settings[""]),
+		"periods":     maps.Clone(f.periods),
+		"webhooks":    len(f.webhooks),
+	}
+}
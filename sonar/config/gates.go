@@ -0,0 +1,249 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// planQualityGates plans the creation of missing quality gates, the changes of their
+// conditions and the default gate.
+func (p *planner) planQualityGates(ctx context.Context, gates []QualityGate) error {
+	if len(gates) == 0 {
+		return nil
+	}
+
+	list, _, err := p.client.Qualitygates.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list quality gates: %w", err)
+	}
+
+	live := make(map[string]sonar.QualityGate, len(list.Qualitygates))
+	previousDefault := ""
+
+	for _, gate := range list.Qualitygates {
+		live[gate.Name] = gate
+
+		if gate.IsDefault {
+			previousDefault = gate.Name
+		}
+	}
+
+	for _, gate := range gates {
+		existing, found := live[gate.Name]
+
+		var conditions []sonar.QualityGateCondition
+
+		if found {
+			conditions, err = p.liveConditions(ctx, existing, gate.Conditions)
+			if err != nil {
+				return err
+			}
+		} else {
+			p.add(p.createQualityGate(gate.Name))
+		}
+
+		if gate.Conditions != nil {
+			p.planConditions(gate.Name, conditions, gate.Conditions)
+		}
+
+		if gate.Default && gate.Name != previousDefault {
+			p.add(p.setDefaultQualityGate(gate.Name, previousDefault))
+		}
+	}
+
+	return nil
+}
+
+// liveConditions returns the conditions of an existing gate, failing if the
+// configuration would change those of a built-in gate.
+func (p *planner) liveConditions(ctx context.Context, gate sonar.QualityGate, desired []Condition) ([]sonar.QualityGateCondition, error) {
+	show, _, err := p.client.Qualitygates.Show(ctx, &sonar.QualitygatesShowOptions{Name: gate.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quality gate %q: %w", gate.Name, err)
+	}
+
+	if gate.IsBuiltIn && desired != nil && !sameConditions(show.Conditions, desired) {
+		return nil, fmt.Errorf("quality gate %q: %w", gate.Name, ErrBuiltIn)
+	}
+
+	return show.Conditions, nil
+}
+
+// sameConditions reports whether the live conditions are exactly the desired ones.
+func sameConditions(live []sonar.QualityGateCondition, desired []Condition) bool {
+	if len(live) != len(desired) {
+		return false
+	}
+
+	byMetric := make(map[string]sonar.QualityGateCondition, len(live))
+
+	for _, condition := range live {
+		byMetric[condition.Metric] = condition
+	}
+
+	for _, condition := range desired {
+		current, found := byMetric[condition.Metric]
+		if !found || current.Op != condition.Op || current.Error != condition.Error {
+			return false
+		}
+	}
+
+	return true
+}
+
+// planConditions plans the deletion of undeclared conditions, then the update and
+// creation of declared ones, so that a gate never holds two conditions on one metric.
+func (p *planner) planConditions(gateName string, live []sonar.QualityGateCondition, desired []Condition) {
+	declared := make(map[string]Condition, len(desired))
+
+	for _, condition := range desired {
+		declared[condition.Metric] = condition
+	}
+
+	byMetric := make(map[string]sonar.QualityGateCondition, len(live))
+
+	for _, condition := range live {
+		byMetric[condition.Metric] = condition
+
+		if _, found := declared[condition.Metric]; !found {
+			p.add(p.deleteCondition(gateName, condition))
+		}
+	}
+
+	for _, condition := range desired {
+		current, found := byMetric[condition.Metric]
+
+		switch {
+		case !found:
+			p.add(p.createCondition(gateName, condition))
+		case current.Op != condition.Op || current.Error != condition.Error:
+			p.add(p.updateCondition(gateName, current, condition))
+		}
+	}
+}
+
+// createQualityGate returns the change creating an empty quality gate.
+func (p *planner) createQualityGate(name string) *Change {
+	gates := p.client.Qualitygates
+
+	return &Change{
+		Action:  ActionCreate,
+		Kind:    KindQualityGate,
+		Address: name,
+		Diffs:   nil,
+		apply: func(ctx context.Context) error {
+			_, _, err := gates.Create(ctx, &sonar.QualitygatesCreateOptions{Name: name})
+
+			return err
+		},
+		revert: call(gates.Delete, &sonar.QualitygatesDeleteOptions{Name: name}),
+	}
+}
+
+// setDefaultQualityGate returns the change making a gate the default one. Without a
+// previous default gate to restore, the change cannot be rolled back.
+func (p *planner) setDefaultQualityGate(name, previous string) *Change {
+	gates := p.client.Qualitygates
+
+	change := &Change{
+		Action:  ActionUpdate,
+		Kind:    KindQualityGate,
+		Address: name,
+		Diffs:   []Diff{{Name: "default", Before: formatBool(false), After: formatBool(true)}},
+		apply:   call(gates.SetDefault, &sonar.QualitygatesSetDefaultOptions{Name: name}),
+		revert:  nil,
+	}
+
+	if previous != "" {
+		change.revert = call(gates.SetDefault, &sonar.QualitygatesSetDefaultOptions{Name: previous})
+	}
+
+	return change
+}
+
+// createCondition returns the change adding a condition to a gate.
+func (p *planner) createCondition(gateName string, condition Condition) *Change {
+	gates := p.client.Qualitygates
+	id := new(string)
+
+	return &Change{
+		Action:  ActionCreate,
+		Kind:    KindQualityGateCondition,
+		Address: address(gateName, condition.Metric),
+		Diffs:   []Diff{{Name: "op", Before: "", After: condition.Op}, {Name: "error", Before: "", After: condition.Error}},
+		apply: func(ctx context.Context) error {
+			created, _, err := gates.CreateCondition(ctx, &sonar.QualitygatesCreateConditionOptions{
+				GateName: gateName,
+				Metric:   condition.Metric,
+				Op:       condition.Op,
+				Error:    condition.Error,
+			})
+			if err != nil {
+				return err
+			}
+
+			*id = created.ID
+
+			return nil
+		},
+		revert: func(ctx context.Context) error {
+			_, err := gates.DeleteCondition(ctx, &sonar.QualitygatesDeleteConditionOptions{ID: *id})
+
+			return err
+		},
+	}
+}
+
+// updateCondition returns the change setting the operator and threshold of a condition.
+func (p *planner) updateCondition(gateName string, current sonar.QualityGateCondition, condition Condition) *Change {
+	gates := p.client.Qualitygates
+
+	var diffs []Diff
+
+	diffs = append(diffs, diffIf("op", current.Op, condition.Op)...)
+	diffs = append(diffs, diffIf("error", current.Error, condition.Error)...)
+
+	return &Change{
+		Action:  ActionUpdate,
+		Kind:    KindQualityGateCondition,
+		Address: address(gateName, condition.Metric),
+		Diffs:   diffs,
+		apply: call(gates.UpdateCondition, &sonar.QualitygatesUpdateConditionOptions{
+			ID:     current.ID,
+			Metric: condition.Metric,
+			Op:     condition.Op,
+			Error:  condition.Error,
+		}),
+		revert: call(gates.UpdateCondition, &sonar.QualitygatesUpdateConditionOptions{
+			ID:     current.ID,
+			Metric: current.Metric,
+			Op:     current.Op,
+			Error:  current.Error,
+		}),
+	}
+}
+
+// deleteCondition returns the change removing a condition from a gate.
+func (p *planner) deleteCondition(gateName string, current sonar.QualityGateCondition) *Change {
+	gates := p.client.Qualitygates
+
+	return &Change{
+		Action:  ActionDelete,
+		Kind:    KindQualityGateCondition,
+		Address: address(gateName, current.Metric),
+		Diffs:   []Diff{{Name: "op", Before: current.Op, After: ""}, {Name: "error", Before: current.Error, After: ""}},
+		apply:   call(gates.DeleteCondition, &sonar.QualitygatesDeleteConditionOptions{ID: current.ID}),
+		revert: func(ctx context.Context) error {
+			_, _, err := gates.CreateCondition(ctx, &sonar.QualitygatesCreateConditionOptions{
+				GateName: gateName,
+				Metric:   current.Metric,
+				Op:       current.Op,
+				Error:    current.Error,
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,526 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// OpLowerThan fails a quality gate condition when the measure is lower than the threshold.
+	OpLowerThan = "LT"
+	// OpGreaterThan fails a quality gate condition when the measure is greater than the threshold.
+	OpGreaterThan = "GT"
+)
+
+//nolint:gochecknoglobals // constant sets of allowed values
+var (
+	// conditionOps is the set of quality gate condition operators.
+	conditionOps = map[string]struct{}{OpLowerThan: {}, OpGreaterThan: {}}
+
+	// templatePermissions is the set of permissions a permission template can grant.
+	templatePermissions = map[string]struct{}{
+		"admin":                {},
+		"codeviewer":           {},
+		"issueadmin":           {},
+		"securityhotspotadmin": {},
+		"scan":                 {},
+		"user":                 {},
+	}
+
+	// ruleSeverities is the set of rule activation severities.
+	ruleSeverities = map[string]struct{}{
+		sonar.RuleSeverityBlocker:  {},
+		sonar.RuleSeverityCritical: {},
+		sonar.RuleSeverityMajor:    {},
+		sonar.RuleSeverityMinor:    {},
+		sonar.RuleSeverityInfo:     {},
+	}
+
+	// newCodePeriodTypes is the set of new code period types.
+	newCodePeriodTypes = map[string]struct{}{
+		sonar.NewCodePeriodTypePreviousVersion:  {},
+		sonar.NewCodePeriodTypeNumberOfDays:     {},
+		sonar.NewCodePeriodTypeReferenceBranch:  {},
+		sonar.NewCodePeriodTypeSpecificAnalysis: {},
+	}
+)
+
+// Configuration is the desired configuration of a SonarQube instance.
+type Configuration struct {
+	// QualityGates are the quality gates, identified by name.
+	QualityGates []QualityGate `json:"qualityGates,omitempty" yaml:"qualityGates,omitempty"`
+	// QualityProfiles are the quality profiles, identified by language and name.
+	QualityProfiles []QualityProfile `json:"qualityProfiles,omitempty" yaml:"qualityProfiles,omitempty"`
+	// PermissionTemplates are the permission templates, identified by name.
+	PermissionTemplates []PermissionTemplate `json:"permissionTemplates,omitempty" yaml:"permissionTemplates,omitempty"`
+	// Settings are global settings, identified by key.
+	Settings []Setting `json:"settings,omitempty" yaml:"settings,omitempty"`
+	// Webhooks are global webhooks, identified by name.
+	Webhooks []Webhook `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	// NewCodePeriod is the global new code period.
+	NewCodePeriod *NewCodePeriod `json:"newCodePeriod,omitempty" yaml:"newCodePeriod,omitempty"`
+	// Projects hold the configuration of existing projects, identified by key.
+	Projects []Project `json:"projects,omitempty" yaml:"projects,omitempty"`
+}
+
+// QualityGate is a quality gate and its conditions.
+type QualityGate struct {
+	// Name is the name of the quality gate.
+	Name string `json:"name" yaml:"name"`
+	// Conditions are the conditions of the gate, at most one per metric. A nil list
+	// leaves the conditions unmanaged.
+	Conditions []Condition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	// Default makes the gate the default quality gate.
+	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// Condition is a quality gate condition.
+type Condition struct {
+	// Metric is the key of the metric the condition is on.
+	Metric string `json:"metric" yaml:"metric"`
+	// Op is the operator, OpLowerThan or OpGreaterThan.
+	Op string `json:"op" yaml:"op"`
+	// Error is the error threshold.
+	Error string `json:"error" yaml:"error"`
+}
+
+// QualityProfile is a quality profile and its rule activations.
+type QualityProfile struct {
+	// Name is the name of the profile.
+	Name string `json:"name" yaml:"name"`
+	// Language is the language of the profile.
+	Language string `json:"language" yaml:"language"`
+	// Parent is the name of the profile it inherits from, of the same language. An empty
+	// parent leaves the inheritance unmanaged unless NoParent is set.
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	// NoParent breaks the inheritance of the profile.
+	NoParent bool `json:"noParent,omitempty" yaml:"noParent,omitempty"`
+	// Rules are the rules activated on the profile itself. Rules inherited from the parent
+	// need not be listed; a rule listed with a different severity or parameters overrides
+	// the inherited activation. A nil list leaves the rules unmanaged.
+	Rules []RuleActivation `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// Default makes the profile the default profile of its language.
+	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// RuleActivation is a rule activated on a quality profile.
+type RuleActivation struct {
+	// Key is the rule key, such as java:S138.
+	Key string `json:"key" yaml:"key"`
+	// Severity is the severity of the activation. An empty severity keeps the one of the
+	// server, or the rule default.
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	// Impacts are the severities of the activation per software quality, such as
+	// MAINTAINABILITY: HIGH. Empty impacts keep the ones of the server.
+	Impacts map[string]string `json:"impacts,omitempty" yaml:"impacts,omitempty"`
+	// Params are rule parameters. Parameters not listed keep the values of the server.
+	Params map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
+	// Prioritized marks the rule as prioritized in the profile.
+	Prioritized bool `json:"prioritized,omitempty" yaml:"prioritized,omitempty"`
+}
+
+// PermissionTemplate is a permission template and the permissions it grants.
+type PermissionTemplate struct {
+	// Name is the name of the template.
+	Name string `json:"name" yaml:"name"`
+	// Description is the description of the template.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// ProjectKeyPattern is the Java regular expression of the project keys the template
+	// applies to when they are created.
+	ProjectKeyPattern string `json:"projectKeyPattern,omitempty" yaml:"projectKeyPattern,omitempty"`
+	// Groups maps group names to the permissions the template grants them. A nil map
+	// leaves the group permissions unmanaged.
+	Groups map[string][]string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	// Users maps user logins to the permissions the template grants them. A nil map
+	// leaves the user permissions unmanaged.
+	Users map[string][]string `json:"users,omitempty" yaml:"users,omitempty"`
+	// Default makes the template the default template for projects.
+	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// Setting is the value of a setting. Exactly one of Value and Values is set. Property
+// set settings are not supported.
+type Setting struct {
+	// Key is the setting key.
+	Key string `json:"key" yaml:"key"`
+	// Value is the value of a single-value setting.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Values are the values of a multi-value setting.
+	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+// Webhook is a webhook called when analyses complete.
+type Webhook struct {
+	// Name is the name of the webhook.
+	Name string `json:"name" yaml:"name"`
+	// URL is the URL the webhook posts to.
+	URL string `json:"url" yaml:"url"`
+	// Secret is the HMAC secret signing the payloads. The server does not disclose
+	// secrets, so a secret is only set on webhooks that have none.
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+// NewCodePeriod is the definition of new code.
+type NewCodePeriod struct {
+	// Type is the type of the period, such as NUMBER_OF_DAYS or PREVIOUS_VERSION.
+	Type string `json:"type" yaml:"type"`
+	// Value is the value of the period, such as a number of days or a branch name.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Project is the configuration of an existing project.
+type Project struct {
+	// Key is the project key.
+	Key string `json:"key" yaml:"key"`
+	// Settings are project settings, identified by key.
+	Settings []Setting `json:"settings,omitempty" yaml:"settings,omitempty"`
+	// Webhooks are project webhooks, identified by name.
+	Webhooks []Webhook `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	// NewCodePeriod is the new code period of the project.
+	NewCodePeriod *NewCodePeriod `json:"newCodePeriod,omitempty" yaml:"newCodePeriod,omitempty"`
+}
+
+// Load reads a configuration in YAML (or JSON) and validates it. Unknown fields are
+// rejected, so that a misspelled key is not silently ignored.
+func Load(reader io.Reader) (*Configuration, error) {
+	configuration := &Configuration{} //nolint:exhaustruct // filled by the decoder
+
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(configuration)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	err = configuration.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+// LoadFiles reads and merges configuration files, in order. A directory stands for the
+// .yaml, .yml and .json files it contains, in lexical order.
+func LoadFiles(paths ...string) (*Configuration, error) {
+	files, err := expandPaths(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &Configuration{} //nolint:exhaustruct // filled by the files
+
+	for _, path := range files {
+		data, err := os.ReadFile(path) //nolint:gosec // reading user-provided configuration files is the purpose
+		if err != nil {
+			return nil, fmt.Errorf("failed to read configuration: %w", err)
+		}
+
+		configuration, err := Load(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		merged.Merge(configuration)
+	}
+
+	err = merged.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// expandPaths replaces directories with the configuration files they contain.
+func expandPaths(paths []string) ([]string, error) {
+	var files []string
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read configuration: %w", err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read configuration directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// Merge appends the objects of other to the configuration. The new code period of other,
+// if set, replaces the configuration's.
+func (c *Configuration) Merge(other *Configuration) {
+	c.QualityGates = append(c.QualityGates, other.QualityGates...)
+	c.QualityProfiles = append(c.QualityProfiles, other.QualityProfiles...)
+	c.PermissionTemplates = append(c.PermissionTemplates, other.PermissionTemplates...)
+	c.Settings = append(c.Settings, other.Settings...)
+	c.Webhooks = append(c.Webhooks, other.Webhooks...)
+	c.Projects = append(c.Projects, other.Projects...)
+
+	if other.NewCodePeriod != nil {
+		c.NewCodePeriod = other.NewCodePeriod
+	}
+}
+
+// Validate checks the configuration for missing and duplicate identifiers and invalid
+// values, and returns every problem found, as *sonar.ValidationError.
+func (c *Configuration) Validate() error {
+	var errs []error
+
+	errs = append(errs, validateQualityGates(c.QualityGates)...)
+	errs = append(errs, validateQualityProfiles(c.QualityProfiles)...)
+	errs = append(errs, validatePermissionTemplates(c.PermissionTemplates)...)
+	errs = append(errs, validateSettings("settings", c.Settings)...)
+	errs = append(errs, validateWebhooks("webhooks", c.Webhooks)...)
+	errs = append(errs, validateNewCodePeriod("newCodePeriod", c.NewCodePeriod)...)
+
+	projects := make(map[string]bool, len(c.Projects))
+
+	for idx, project := range c.Projects {
+		field := fmt.Sprintf("projects[%d]", idx)
+
+		errs = append(errs, validateIdentifier(field+".key", project.Key, projects)...)
+		errs = append(errs, validateSettings(field+".settings", project.Settings)...)
+		errs = append(errs, validateWebhooks(field+".webhooks", project.Webhooks)...)
+		errs = append(errs, validateNewCodePeriod(field+".newCodePeriod", project.NewCodePeriod)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateIdentifier checks that an identifier is set and was not seen before.
+func validateIdentifier(field, value string, seen map[string]bool) []error {
+	if value == "" {
+		return []error{sonar.NewValidationError(field, "is required", sonar.ErrMissingRequired)}
+	}
+
+	if seen[value] {
+		return []error{sonar.NewValidationError(field, fmt.Sprintf("%q is declared more than once", value), sonar.ErrInvalidValue)}
+	}
+
+	seen[value] = true
+
+	return nil
+}
+
+// validateQualityGates checks the quality gates and their conditions.
+func validateQualityGates(gates []QualityGate) []error {
+	var errs []error
+
+	names := make(map[string]bool, len(gates))
+	defaults := 0
+
+	for idx, gate := range gates {
+		field := fmt.Sprintf("qualityGates[%d]", idx)
+		errs = append(errs, validateIdentifier(field+".name", gate.Name, names)...)
+
+		if gate.Default {
+			defaults++
+		}
+
+		metrics := make(map[string]bool, len(gate.Conditions))
+
+		for condIdx, condition := range gate.Conditions {
+			condField := fmt.Sprintf("%s.conditions[%d]", field, condIdx)
+
+			errs = append(errs, validateIdentifier(condField+".metric", condition.Metric, metrics)...)
+			errs = appendIfErr(errs, sonar.ValidateRequired(condition.Error, condField+".error"))
+			errs = appendIfErr(errs, sonar.ValidateRequired(condition.Op, condField+".op"))
+			errs = appendIfErr(errs, sonar.IsValueAuthorized(condition.Op, conditionOps, condField+".op"))
+		}
+	}
+
+	if defaults > 1 {
+		errs = append(errs, sonar.NewValidationError("qualityGates", "only one quality gate can be the default", sonar.ErrInvalidValue))
+	}
+
+	return errs
+}
+
+// validateQualityProfiles checks the quality profiles and their rules.
+func validateQualityProfiles(profiles []QualityProfile) []error {
+	var errs []error
+
+	names := make(map[string]bool, len(profiles))
+	defaults := make(map[string]int)
+
+	for idx, profile := range profiles {
+		field := fmt.Sprintf("qualityProfiles[%d]", idx)
+
+		errs = appendIfErr(errs, sonar.ValidateRequired(profile.Language, field+".language"))
+		errs = append(errs, validateIdentifier(field+".name", profileID(profile.Language, profile.Name), names)...)
+
+		if profile.Parent != "" && profile.NoParent {
+			errs = append(errs, sonar.NewValidationError(field+".noParent", "cannot be set with parent", sonar.ErrInvalidValue))
+		}
+
+		if profile.Parent == profile.Name && profile.Name != "" {
+			errs = append(errs, sonar.NewValidationError(field+".parent", "cannot be the profile itself", sonar.ErrInvalidValue))
+		}
+
+		if profile.Default {
+			defaults[profile.Language]++
+		}
+
+		keys := make(map[string]bool, len(profile.Rules))
+
+		for ruleIdx, rule := range profile.Rules {
+			ruleField := fmt.Sprintf("%s.rules[%d]", field, ruleIdx)
+			errs = append(errs, validateIdentifier(ruleField+".key", rule.Key, keys)...)
+			errs = appendIfErr(errs, sonar.IsValueAuthorized(rule.Severity, ruleSeverities, ruleField+".severity"))
+
+			if rule.Severity != "" && len(rule.Impacts) > 0 {
+				errs = append(errs, sonar.NewValidationError(ruleField+".impacts", "cannot be set with severity", sonar.ErrInvalidValue))
+			}
+		}
+	}
+
+	for _, language := range sortedKeys(defaults) {
+		if defaults[language] > 1 {
+			errs = append(errs, sonar.NewValidationError("qualityProfiles",
+				fmt.Sprintf("only one %s quality profile can be the default", language), sonar.ErrInvalidValue))
+		}
+	}
+
+	return errs
+}
+
+// validatePermissionTemplates checks the permission templates and their permissions.
+func validatePermissionTemplates(templates []PermissionTemplate) []error {
+	var errs []error
+
+	names := make(map[string]bool, len(templates))
+	defaults := 0
+
+	for idx, template := range templates {
+		field := fmt.Sprintf("permissionTemplates[%d]", idx)
+		errs = append(errs, validateIdentifier(field+".name", template.Name, names)...)
+
+		if template.Default {
+			defaults++
+		}
+
+		for _, grants := range []struct {
+			field       string
+			permissions map[string][]string
+		}{{field: field + ".groups", permissions: template.Groups}, {field: field + ".users", permissions: template.Users}} {
+			for _, name := range sortedKeys(grants.permissions) {
+				for _, permission := range grants.permissions[name] {
+					errs = appendIfErr(errs, sonar.IsValueAuthorized(permission, templatePermissions, grants.field+"."+name))
+				}
+			}
+		}
+	}
+
+	if defaults > 1 {
+		errs = append(errs, sonar.NewValidationError("permissionTemplates", "only one permission template can be the default", sonar.ErrInvalidValue))
+	}
+
+	return errs
+}
+
+// validateSettings checks settings for keys and for exactly one kind of value.
+func validateSettings(field string, settings []Setting) []error {
+	var errs []error
+
+	keys := make(map[string]bool, len(settings))
+
+	for idx, setting := range settings {
+		settingField := fmt.Sprintf("%s[%d]", field, idx)
+		errs = append(errs, validateIdentifier(settingField+".key", setting.Key, keys)...)
+
+		if (setting.Value == "") == (setting.Values == nil) {
+			errs = append(errs, sonar.NewValidationError(settingField, "exactly one of value and values must be set", sonar.ErrInvalidValue))
+		}
+	}
+
+	return errs
+}
+
+// validateWebhooks checks webhooks for names, URLs and secret lengths.
+func validateWebhooks(field string, webhooks []Webhook) []error {
+	var errs []error
+
+	names := make(map[string]bool, len(webhooks))
+
+	for idx, webhook := range webhooks {
+		webhookField := fmt.Sprintf("%s[%d]", field, idx)
+
+		errs = append(errs, validateIdentifier(webhookField+".name", webhook.Name, names)...)
+		errs = appendIfErr(errs, sonar.ValidateRequired(webhook.URL, webhookField+".url"))
+		errs = appendIfErr(errs, sonar.ValidateMinLength(webhook.Secret, sonar.MinWebhookSecretLength, webhookField+".secret"))
+		errs = appendIfErr(errs, sonar.ValidateMaxLength(webhook.Secret, sonar.MaxWebhookSecretLength, webhookField+".secret"))
+	}
+
+	return errs
+}
+
+// validateNewCodePeriod checks the type of a new code period.
+func validateNewCodePeriod(field string, period *NewCodePeriod) []error {
+	if period == nil {
+		return nil
+	}
+
+	var errs []error
+
+	errs = appendIfErr(errs, sonar.ValidateRequired(period.Type, field+".type"))
+	errs = appendIfErr(errs, sonar.IsValueAuthorized(period.Type, newCodePeriodTypes, field+".type"))
+
+	return errs
+}
+
+// appendIfErr appends err to errs unless it is nil.
+func appendIfErr(errs []error, err error) []error {
+	if err == nil {
+		return errs
+	}
+
+	return append(errs, err)
+}
+
+// profileID identifies a quality profile, whose name is only unique per language.
+func profileID(language, name string) string {
+	return language + "/" + name
+}
+
+// sortedKeys returns the keys of a map in lexical order.
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
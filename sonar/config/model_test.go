@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	desired, err := Load(strings.NewReader(testConfiguration))
+	require.NoError(t, err)
+	assert.Len(t, desired.QualityGates, 1)
+	assert.Equal(t, Condition{Metric: "new_coverage", Op: OpLowerThan, Error: "90"}, desired.QualityGates[0].Conditions[0])
+	assert.Equal(t, map[string]string{"max": "60"}, desired.QualityProfiles[0].Rules[0].Params)
+	assert.Equal(t, []string{"codeviewer", "user"}, desired.PermissionTemplates[0].Groups["developers"])
+	assert.Equal(t, "my-app", desired.Projects[0].Key)
+
+	desired, err = Load(strings.NewReader(`{"qualityGates": [{"name": "Strict", "conditions": []}]}`))
+	require.NoError(t, err)
+	assert.NotNil(t, desired.QualityGates[0].Conditions, "an empty list is managed")
+
+	desired, err = Load(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Equal(t, &Configuration{}, desired)
+
+	_, err = Load(strings.NewReader("qualityGate:\n  - name: Strict\n"))
+	require.ErrorContains(t, err, "field qualityGate not found")
+}
+
+func TestConfiguration_Validate(t *testing.T) {
+	_, err := Load(strings.NewReader(`
+qualityGates:
+  - name: Strict
+    conditions:
+      - {metric: coverage, op: EQ, error: "80"}
+      - {metric: coverage, op: LT}
+  - name: Strict
+    default: true
+  - name: Lax
+    default: true
+qualityProfiles:
+  - {name: A, language: java, rules: [{key: "java:S1", severity: HUGE}]}
+permissionTemplates:
+  - {name: T, groups: {devs: [browse]}}
+settings:
+  - {key: a, value: x, values: [y]}
+webhooks:
+  - {name: CI, url: "https://ci", secret: short}
+newCodePeriod: {type: FOREVER}
+`))
+	require.ErrorIs(t, err, sonar.ErrInvalidValue)
+	require.ErrorIs(t, err, sonar.ErrMissingRequired)
+
+	for _, field := range []string{
+		"qualityGates[0].conditions[0].op",
+		"qualityGates[0].conditions[1].metric",
+		"qualityGates[0].conditions[1].error",
+		"qualityGates[1].name",
+		"qualityProfiles[0].rules[0].severity",
+		"permissionTemplates[0].groups.devs",
+		"settings[0]",
+		"webhooks[0].secret",
+		"newCodePeriod.type",
+	} {
+		assert.ErrorContains(t, err, `"`+field+`"`)
+	}
+
+	assert.ErrorContains(t, err, "only one quality gate can be the default")
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a-gates.yaml"), []byte("qualityGates:\n  - name: A\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b-gates.json"), []byte(`{"qualityGates": [{"name": "B"}]}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not configuration"), 0o600))
+
+	extra := filepath.Join(t.TempDir(), "extra.yml")
+	require.NoError(t, os.WriteFile(extra, []byte("newCodePeriod: {type: PREVIOUS_VERSION}\n"), 0o600))
+
+	desired, err := LoadFiles(dir, extra)
+	require.NoError(t, err)
+	assert.Equal(t, []QualityGate{{Name: "A"}, {Name: "B"}}, desired.QualityGates)
+	assert.Equal(t, &NewCodePeriod{Type: sonar.NewCodePeriodTypePreviousVersion}, desired.NewCodePeriod)
+
+	_, err = LoadFiles(dir, filepath.Join(dir, "a-gates.yaml"))
+	require.ErrorContains(t, err, `"A" is declared more than once`)
+
+	_, err = LoadFiles(filepath.Join(dir, "missing.yaml"))
+	require.ErrorContains(t, err, "failed to read configuration")
+}
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// Action is what a change does to an object.
+type Action string
+
+const (
+	// ActionCreate creates an object, or sets a value the server does not have yet.
+	ActionCreate Action = "create"
+	// ActionUpdate changes an existing object.
+	ActionUpdate Action = "update"
+	// ActionDelete deletes an object.
+	ActionDelete Action = "delete"
+)
+
+// Kind is the kind of object a change applies to.
+type Kind string
+
+const (
+	// KindQualityGate is a quality gate.
+	KindQualityGate Kind = "quality_gate"
+	// KindQualityGateCondition is a condition of a quality gate.
+	KindQualityGateCondition Kind = "quality_gate_condition"
+	// KindQualityProfile is a quality profile.
+	KindQualityProfile Kind = "quality_profile"
+	// KindQualityProfileRule is a rule activation of a quality profile.
+	KindQualityProfileRule Kind = "quality_profile_rule"
+	// KindPermissionTemplate is a permission template.
+	KindPermissionTemplate Kind = "permission_template"
+	// KindPermissionTemplatePermission is a permission a template grants a group or user.
+	KindPermissionTemplatePermission Kind = "permission_template_permission"
+	// KindSetting is a global or project setting.
+	KindSetting Kind = "setting"
+	// KindWebhook is a global or project webhook.
+	KindWebhook Kind = "webhook"
+	// KindNewCodePeriod is the global or a project new code period.
+	KindNewCodePeriod Kind = "new_code_period"
+)
+
+// sensitiveValue replaces values the plan must not show, such as secrets.
+const sensitiveValue = "(sensitive)"
+
+var (
+	// ErrApplyFailed is returned by Plan.Apply when a change failed.
+	ErrApplyFailed = errors.New("apply failed")
+	// ErrBuiltIn is returned by NewPlan when the configuration changes a built-in object.
+	ErrBuiltIn = errors.New("built-in objects cannot be changed")
+)
+
+// Diff is the change of one attribute of an object.
+type Diff struct {
+	// Name is the name of the attribute.
+	Name string `json:"name"`
+	// Before is the value on the server, empty for a new object.
+	Before string `json:"before"`
+	// After is the value in the configuration, empty for a deleted object.
+	After string `json:"after"`
+}
+
+// Change is one change a plan makes to the server.
+type Change struct {
+	// Action is what the change does.
+	Action Action `json:"action"`
+	// Kind is the kind of the changed object.
+	Kind Kind `json:"kind"`
+	// Address identifies the changed object, such as "Strict/new_coverage" for the
+	// new_coverage condition of the Strict quality gate.
+	Address string `json:"address"`
+	// Diffs are the changed attributes.
+	Diffs []Diff `json:"diffs,omitempty"`
+
+	apply func(ctx context.Context) error
+	// revert undoes apply, or is nil when the change cannot be rolled back.
+	revert func(ctx context.Context) error
+}
+
+// String describes the change, such as `create quality_gate "Strict"`.
+func (c *Change) String() string {
+	return fmt.Sprintf("%s %s %q", c.Action, c.Kind, c.Address)
+}
+
+// Plan is the ordered list of changes that make a server match a configuration.
+type Plan struct {
+	// Changes are the changes, in the order they are applied.
+	Changes []*Change `json:"changes"`
+}
+
+// Summary counts the changes of a plan by action.
+type Summary struct {
+	// Create is the number of created objects.
+	Create int `json:"create"`
+	// Update is the number of updated objects.
+	Update int `json:"update"`
+	// Delete is the number of deleted objects.
+	Delete int `json:"delete"`
+}
+
+// ApplyResult is the outcome of Plan.Apply.
+type ApplyResult struct {
+	// Applied are the changes that were applied and are still in effect.
+	Applied []*Change `json:"applied"`
+	// Failed is the change that failed, if any.
+	Failed *Change `json:"failed,omitempty"`
+	// RolledBack are the applied changes that were undone after the failure, in the
+	// order they were undone.
+	RolledBack []*Change `json:"rolledBack,omitempty"`
+}
+
+// Empty reports whether the server already matches the configuration.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Summary counts the changes of the plan.
+func (p *Plan) Summary() Summary {
+	var summary Summary
+
+	for _, change := range p.Changes {
+		switch change.Action {
+		case ActionCreate:
+			summary.Create++
+		case ActionUpdate:
+			summary.Update++
+		case ActionDelete:
+			summary.Delete++
+		}
+	}
+
+	return summary
+}
+
+// NewPlan compares the configuration with the server and returns the changes that make
+// the server match it. Nothing is changed on the server.
+func NewPlan(ctx context.Context, client *sonar.Client, desired *Configuration) (*Plan, error) {
+	if client == nil {
+		return nil, sonar.NewValidationError("client", "is required", sonar.ErrMissingRequired)
+	}
+
+	if desired == nil {
+		return nil, sonar.NewValidationError("desired", "is required", sonar.ErrMissingRequired)
+	}
+
+	err := desired.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &planner{client: client, changes: nil}
+
+	steps := []func(context.Context) error{
+		func(ctx context.Context) error { return p.planQualityProfiles(ctx, desired.QualityProfiles) },
+		func(ctx context.Context) error { return p.planQualityGates(ctx, desired.QualityGates) },
+		func(ctx context.Context) error {
+			return p.planPermissionTemplates(ctx, desired.PermissionTemplates)
+		},
+		func(ctx context.Context) error { return p.planSettings(ctx, "", desired.Settings) },
+		func(ctx context.Context) error { return p.planWebhooks(ctx, "", desired.Webhooks) },
+		func(ctx context.Context) error { return p.planNewCodePeriod(ctx, "", desired.NewCodePeriod) },
+	}
+
+	for _, project := range desired.Projects {
+		steps = append(steps,
+			func(ctx context.Context) error { return p.planSettings(ctx, project.Key, project.Settings) },
+			func(ctx context.Context) error { return p.planWebhooks(ctx, project.Key, project.Webhooks) },
+			func(ctx context.Context) error {
+				return p.planNewCodePeriod(ctx, project.Key, project.NewCodePeriod)
+			},
+		)
+	}
+
+	for _, step := range steps {
+		err := step(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Plan{Changes: p.changes}, nil
+}
+
+// Apply makes the changes of the plan, in order. If a change fails, the changes already
+// applied are undone in reverse order, and the error wraps ErrApplyFailed along with any
+// error undoing them. Undoing is best effort: it restores values, not identities, so a
+// deleted object that is recreated gets a new identifier, and a change with nothing to
+// restore, such as a default gate set where there was none, stays applied.
+func (p *Plan) Apply(ctx context.Context) (*ApplyResult, error) {
+	result := &ApplyResult{Applied: nil, Failed: nil, RolledBack: nil}
+
+	for _, change := range p.Changes {
+		err := change.apply(ctx)
+		if err == nil {
+			result.Applied = append(result.Applied, change)
+
+			continue
+		}
+
+		result.Failed = change
+		errs := []error{fmt.Errorf("%w: %s: %w", ErrApplyFailed, change, err)}
+
+		// Roll back even when ctx was canceled, so that an interrupted apply does not
+		// leave the server half-configured.
+		rollbackCtx := context.WithoutCancel(ctx)
+
+		for idx := len(result.Applied) - 1; idx >= 0; idx-- {
+			applied := result.Applied[idx]
+			if applied.revert == nil {
+				continue
+			}
+
+			revertErr := applied.revert(rollbackCtx)
+			if revertErr != nil {
+				errs = append(errs, fmt.Errorf("failed to roll back %s: %w", applied, revertErr))
+
+				continue
+			}
+
+			result.RolledBack = append(result.RolledBack, applied)
+		}
+
+		result.Applied = stillApplied(result.Applied, result.RolledBack)
+
+		return result, errors.Join(errs...)
+	}
+
+	return result, nil
+}
+
+// stillApplied returns the applied changes that were not rolled back.
+func stillApplied(applied, rolledBack []*Change) []*Change {
+	undone := make(map[*Change]bool, len(rolledBack))
+
+	for _, change := range rolledBack {
+		undone[change] = true
+	}
+
+	var remaining []*Change
+
+	for _, change := range applied {
+		if !undone[change] {
+			remaining = append(remaining, change)
+		}
+	}
+
+	return remaining
+}
+
+// planner collects the changes of a plan.
+type planner struct {
+	client  *sonar.Client
+	changes []*Change
+}
+
+// add appends a change to the plan.
+func (p *planner) add(change *Change) {
+	p.changes = append(p.changes, change)
+}
+
+// call adapts a service method returning only a response and an error to an apply or
+// revert function.
+func call[O any](method func(context.Context, *O) (*http.Response, error), opt *O) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := method(ctx, opt)
+
+		return err
+	}
+}
+
+// address joins the parts identifying an object.
+func address(parts ...string) string {
+	return strings.Join(parts, "/")
+}
+
+// scoped prefixes the address of a project-level object with the project key.
+func scoped(project, name string) string {
+	if project == "" {
+		return name
+	}
+
+	return address(project, name)
+}
+
+// diffIf returns a one-element diff list if before and after differ.
+func diffIf(name, before, after string) []Diff {
+	if before == after {
+		return nil
+	}
+
+	return []Diff{{Name: name, Before: before, After: after}}
+}
+
+// formatBool formats a boolean attribute.
+func formatBool(value bool) string {
+	return fmt.Sprintf("%t", value)
+}
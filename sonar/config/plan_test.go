@@ -0,0 +1,278 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfiguration = `
+qualityGates:
+  - name: Strict
+    default: true
+    conditions:
+      - {metric: new_coverage, op: LT, error: "90"}
+      - {metric: new_duplicated_lines_density, op: GT, error: "3"}
+qualityProfiles:
+  - name: Strict Java
+    language: java
+    parent: Company Java
+    default: true
+    rules:
+      - {key: "java:S138", severity: MAJOR, params: {max: "60"}}
+  - name: Company Java
+    language: java
+    rules:
+      - {key: "java:S100", severity: MINOR}
+permissionTemplates:
+  - name: Default template
+    groups:
+      sonar-administrators: [admin]
+      developers: [codeviewer, user]
+  - name: Team
+    projectKeyPattern: "team-.*"
+    default: true
+    users:
+      alice: [admin]
+settings:
+  - {key: sonar.exclusions, values: ["**/gen/**"]}
+  - {key: sonar.auth.secured, value: hunter2}
+webhooks:
+  - {name: CI, url: "https://ci.example.com/hook", secret: 0123456789abcdef}
+newCodePeriod: {type: NUMBER_OF_DAYS, value: "30"}
+projects:
+  - key: my-app
+    settings:
+      - {key: sonar.coverage.exclusions, value: "**/test/**"}
+    newCodePeriod: {type: REFERENCE_BRANCH, value: main}
+`
+
+func loadTestConfiguration(t *testing.T, data string) *Configuration {
+	t.Helper()
+
+	desired, err := Load(strings.NewReader(data))
+	require.NoError(t, err)
+
+	return desired
+}
+
+func changeStrings(plan *Plan) []string {
+	changes := make([]string, 0, len(plan.Changes))
+
+	for _, change := range plan.Changes {
+		changes = append(changes, change.String())
+	}
+
+	return changes
+}
+
+func TestNewPlan(t *testing.T) {
+	fake, client := newFakeServer(t)
+
+	plan, err := NewPlan(t.Context(), client, loadTestConfiguration(t, testConfiguration))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`create quality_profile "java/Company Java"`,
+		`create quality_profile_rule "java/Company Java/java:S100"`,
+		`create quality_profile "java/Strict Java"`,
+		`update quality_profile "java/Strict Java"`,
+		`create quality_profile_rule "java/Strict Java/java:S138"`,
+		`update quality_profile "java/Strict Java"`,
+		`create quality_gate "Strict"`,
+		`create quality_gate_condition "Strict/new_coverage"`,
+		`create quality_gate_condition "Strict/new_duplicated_lines_density"`,
+		`update quality_gate "Strict"`,
+		`create permission_template_permission "Default template/group/developers/codeviewer"`,
+		`create permission_template_permission "Default template/group/developers/user"`,
+		`create permission_template "Team"`,
+		`create permission_template_permission "Team/user/alice/admin"`,
+		`update permission_template "Team"`,
+		`create setting "sonar.exclusions"`,
+		`create setting "sonar.auth.secured"`,
+		`create webhook "CI"`,
+		`update new_code_period "new_code_period"`,
+		`create setting "my-app/sonar.coverage.exclusions"`,
+		`create new_code_period "my-app/new_code_period"`,
+	}, changeStrings(plan))
+	assert.Equal(t, Summary{Create: 16, Update: 5, Delete: 0}, plan.Summary())
+	assert.Equal(t, []Diff{{Name: "value", Before: "", After: sensitiveValue}}, plan.Changes[16].Diffs)
+	assert.Empty(t, fake.writes, "planning must not change the server")
+}
+
+func TestPlan_Apply(t *testing.T) {
+	fake, client := newFakeServer(t)
+	desired := loadTestConfiguration(t, testConfiguration)
+
+	plan, err := NewPlan(t.Context(), client, desired)
+	require.NoError(t, err)
+
+	result, err := plan.Apply(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, result.Applied, len(plan.Changes))
+	assert.Nil(t, result.Failed)
+
+	assert.Equal(t, "Strict", fake.defaultGate)
+	assert.Equal(t, "Company Java", fake.profileByName("java", "Strict Java").profile.ParentName)
+	assert.Equal(t, []string{"codeviewer", "user"}, fake.templateByName("Default template").groups["developers"])
+	assert.Equal(t, "tpl", fake.defaultTmpl[:3])
+
+	replan, err := NewPlan(t.Context(), client, desired)
+	require.NoError(t, err)
+	assert.True(t, replan.Empty(), "unexpected changes after apply: %v", changeStrings(replan))
+}
+
+func TestNewPlan_UpdatesAndDeletes(t *testing.T) {
+	fake, client := newFakeServer(t)
+
+	plan, err := NewPlan(t.Context(), client, loadTestConfiguration(t, testConfiguration))
+	require.NoError(t, err)
+	_, err = plan.Apply(t.Context())
+	require.NoError(t, err)
+
+	strict := fake.profileByName("java", "Strict Java")
+	strict.rules["java:S101"] = sonar.RulesActivation{QProfile: strict.profile.Key, Severity: "MAJOR", Inherit: sonar.InheritanceTypeNone}
+	strict.rules["java:S100"] = sonar.RulesActivation{QProfile: strict.profile.Key, Severity: "BLOCKER", Inherit: sonar.InheritanceTypeOverrides}
+	strict.rules["java:S102"] = sonar.RulesActivation{QProfile: strict.profile.Key, Severity: "MINOR", Inherit: sonar.InheritanceTypeInherited}
+
+	plan, err = NewPlan(t.Context(), client, loadTestConfiguration(t, `
+qualityGates:
+  - name: Strict
+    conditions:
+      - {metric: new_coverage, op: LT, error: "85"}
+qualityProfiles:
+  - name: Strict Java
+    language: java
+    noParent: true
+    rules:
+      - {key: "java:S138", params: {max: "80"}}
+permissionTemplates:
+  - name: Default template
+    description: Projects
+    groups:
+      developers: [user]
+settings:
+  - {key: sonar.exclusions, values: ["**/gen/**", "**/vendor/**"]}
+  - {key: sonar.auth.secured, value: changed}
+webhooks:
+  - {name: CI, url: "https://ci.example.com/v2"}
+newCodePeriod: {type: NUMBER_OF_DAYS, value: "30"}
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`update quality_profile "java/Strict Java"`,
+		`update quality_profile_rule "java/Strict Java/java:S138"`,
+		`update quality_profile_rule "java/Strict Java/java:S100"`,
+		`delete quality_profile_rule "java/Strict Java/java:S101"`,
+		`delete quality_gate_condition "Strict/new_duplicated_lines_density"`,
+		`update quality_gate_condition "Strict/new_coverage"`,
+		`update permission_template "Default template"`,
+		`delete permission_template_permission "Default template/group/developers/codeviewer"`,
+		`delete permission_template_permission "Default template/group/sonar-administrators/admin"`,
+		`update setting "sonar.exclusions"`,
+		`update webhook "CI"`,
+	}, changeStrings(plan))
+	assert.Equal(t, []Diff{{Name: "params.max", Before: "60", After: "80"}}, plan.Changes[1].Diffs)
+	assert.Equal(t, []Diff{{Name: "value", Before: "[**/gen/**]", After: "[**/gen/**, **/vendor/**]"}}, plan.Changes[9].Diffs)
+
+	_, err = plan.Apply(t.Context())
+	require.NoError(t, err)
+
+	rule := strict.rules["java:S138"]
+	assert.Equal(t, "MAJOR", rule.Severity, "undeclared attributes keep their value")
+	assert.Equal(t, []sonar.RulesParamKV{{Key: "max", Value: "80"}}, rule.Params)
+	assert.Equal(t, sonar.InheritanceTypeInherited, strict.rules["java:S100"].Inherit)
+	assert.Contains(t, strict.rules, "java:S102", "inherited rules are left alone")
+	assert.NotContains(t, strict.rules, "java:S101")
+}
+
+func TestPlan_Apply_Rollback(t *testing.T) {
+	fake, client := newFakeServer(t)
+
+	fake.settings[""]["sonar.exclusions"] = sonar.SettingValue{Key: "sonar.exclusions", Values: []string{"old"}}
+	before := fake.snapshot()
+
+	plan, err := NewPlan(t.Context(), client, loadTestConfiguration(t, testConfiguration))
+	require.NoError(t, err)
+
+	fake.failOn = "new_code_periods/set"
+
+	result, err := plan.Apply(t.Context())
+	require.ErrorIs(t, err, ErrApplyFailed)
+	assert.ErrorContains(t, err, `update new_code_period "new_code_period"`)
+	assert.Equal(t, `update new_code_period "new_code_period"`, result.Failed.String())
+	assert.Len(t, result.RolledBack, 18)
+	assert.Empty(t, result.Applied)
+	assert.Equal(t, before, fake.snapshot())
+}
+
+func TestPlan_Apply_RollbackWithoutDefaultGate(t *testing.T) {
+	fake, client := newFakeServer(t)
+
+	fake.defaultGate = ""
+
+	plan, err := NewPlan(t.Context(), client, loadTestConfiguration(t, `
+qualityGates:
+  - name: Strict
+    default: true
+newCodePeriod: {type: NUMBER_OF_DAYS, value: "30"}
+`))
+	require.NoError(t, err)
+
+	fake.failOn = "new_code_periods/set"
+
+	result, err := plan.Apply(t.Context())
+	require.ErrorIs(t, err, ErrApplyFailed)
+	assert.Equal(t, []string{`create quality_gate "Strict"`}, changeStrings(&Plan{Changes: result.RolledBack}))
+	assert.Equal(t, []string{`update quality_gate "Strict"`}, changeStrings(&Plan{Changes: result.Applied}))
+
+	var setDefaults []string
+
+	for _, write := range fake.writes {
+		if strings.HasPrefix(write, "qualitygates/set_as_default") {
+			setDefaults = append(setDefaults, write)
+		}
+	}
+
+	assert.Equal(t, []string{"qualitygates/set_as_default?name=Strict"}, setDefaults)
+}
+
+func TestNewPlan_BuiltIn(t *testing.T) {
+	_, client := newFakeServer(t)
+
+	_, err := NewPlan(t.Context(), client, loadTestConfiguration(t, `
+qualityGates:
+  - name: Sonar way
+    conditions:
+      - {metric: new_coverage, op: LT, error: "90"}
+`))
+	require.ErrorIs(t, err, ErrBuiltIn)
+
+	plan, err := NewPlan(t.Context(), client, loadTestConfiguration(t, `
+qualityGates:
+  - name: Sonar way
+    default: true
+    conditions:
+      - {metric: new_coverage, op: LT, error: "80"}
+`))
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+}
+
+func TestNewPlan_Validation(t *testing.T) {
+	_, client := newFakeServer(t)
+
+	_, err := NewPlan(t.Context(), client, nil)
+	require.ErrorIs(t, err, sonar.ErrMissingRequired)
+
+	_, err = NewPlan(t.Context(), client, &Configuration{QualityProfiles: []QualityProfile{
+		{Name: "A", Language: "java", Parent: "B"},
+		{Name: "B", Language: "java", Parent: "A"},
+	}})
+	require.ErrorIs(t, err, sonar.ErrInvalidValue)
+	assert.ErrorContains(t, err, "inherits from itself")
+}
@@ -0,0 +1,442 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// planQualityProfiles plans the creation of missing quality profiles, their inheritance,
+// their rule activations and the default profiles. Profiles are planned parents first,
+// so that a new profile exists before its children inherit from it.
+func (p *planner) planQualityProfiles(ctx context.Context, profiles []QualityProfile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	ordered, err := parentsFirst(profiles)
+	if err != nil {
+		return err
+	}
+
+	search, _, err := p.client.Qualityprofiles.Search(ctx, &sonar.QualityprofilesSearchOptions{}) //nolint:exhaustruct // every profile
+	if err != nil {
+		return fmt.Errorf("failed to search quality profiles: %w", err)
+	}
+
+	live := make(map[string]sonar.QualityProfile, len(search.Profiles))
+	previousDefaults := make(map[string]string)
+
+	for _, profile := range search.Profiles {
+		live[profileID(profile.Language, profile.Name)] = profile
+
+		if profile.IsDefault {
+			previousDefaults[profile.Language] = profile.Name
+		}
+	}
+
+	for _, profile := range ordered {
+		err := p.planQualityProfile(ctx, profile, live, previousDefaults[profile.Language])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planQualityProfile plans the changes of one quality profile.
+func (p *planner) planQualityProfile(ctx context.Context, profile QualityProfile, live map[string]sonar.QualityProfile, previousDefault string) error {
+	id := profileID(profile.Language, profile.Name)
+	existing, found := live[id]
+	key := new(string)
+	activations := map[string]sonar.RulesActivation{}
+
+	if found {
+		*key = existing.Key
+
+		if existing.IsBuiltIn && (profile.Rules != nil || profile.Parent != "") {
+			return fmt.Errorf("quality profile %q: %w", id, ErrBuiltIn)
+		}
+
+		if profile.Rules != nil {
+			var err error
+
+			activations, _, err = p.client.Rules.SearchActivations(ctx, &sonar.RulesSearchActivationsOptions{Qprofile: existing.Key})
+			if err != nil {
+				return fmt.Errorf("failed to read the rules of quality profile %q: %w", id, err)
+			}
+		}
+	} else {
+		p.add(p.createQualityProfile(profile, key))
+	}
+
+	if (profile.Parent != "" || profile.NoParent) && profile.Parent != existing.ParentName {
+		p.add(p.changeParent(profile, existing.ParentName))
+	}
+
+	if profile.Rules != nil {
+		p.planRules(id, key, activations, profile.Rules)
+	}
+
+	if profile.Default && profile.Name != previousDefault {
+		p.add(p.setDefaultQualityProfile(profile, previousDefault))
+	}
+
+	return nil
+}
+
+// parentsFirst orders profiles so that a profile comes after the declared profile it
+// inherits from, and fails on inheritance cycles.
+func parentsFirst(profiles []QualityProfile) ([]QualityProfile, error) {
+	byID := make(map[string]QualityProfile, len(profiles))
+
+	for _, profile := range profiles {
+		byID[profileID(profile.Language, profile.Name)] = profile
+	}
+
+	ordered := make([]QualityProfile, 0, len(profiles))
+	done := make(map[string]bool, len(profiles))
+	visiting := make(map[string]bool)
+
+	var visit func(profile QualityProfile) error
+
+	visit = func(profile QualityProfile) error {
+		id := profileID(profile.Language, profile.Name)
+
+		switch {
+		case done[id]:
+			return nil
+		case visiting[id]:
+			return sonar.NewValidationError("qualityProfiles", fmt.Sprintf("%q inherits from itself", id), sonar.ErrInvalidValue)
+		}
+
+		visiting[id] = true
+
+		if parent, declared := byID[profileID(profile.Language, profile.Parent)]; declared && profile.Parent != "" {
+			err := visit(parent)
+			if err != nil {
+				return err
+			}
+		}
+
+		done[id] = true
+		ordered = append(ordered, profile)
+
+		return nil
+	}
+
+	for _, profile := range profiles {
+		err := visit(profile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// planRules plans the activation of declared rules, the update of those whose declared
+// severity, impacts, parameters or priority differ, the deactivation of undeclared rules
+// activated on the profile itself and the reset of undeclared overrides of inherited rules.
+func (p *planner) planRules(id string, key *string, live map[string]sonar.RulesActivation, rules []RuleActivation) {
+	declared := make(map[string]bool, len(rules))
+
+	for _, rule := range rules {
+		declared[rule.Key] = true
+
+		current, found := live[rule.Key]
+		if !found {
+			p.add(p.activateRule(id, key, rule))
+
+			continue
+		}
+
+		diffs := ruleDiffs(current, rule)
+		if len(diffs) > 0 {
+			p.add(p.updateRule(id, key, current, rule, diffs))
+		}
+	}
+
+	for _, ruleKey := range sortedKeys(live) {
+		if declared[ruleKey] {
+			continue
+		}
+
+		switch current := live[ruleKey]; current.Inherit {
+		case "", sonar.InheritanceTypeNone:
+			p.add(p.deactivateRule(id, key, ruleKey, current))
+		case sonar.InheritanceTypeOverrides:
+			p.add(p.resetRule(id, key, ruleKey, current))
+		}
+	}
+}
+
+// ruleDiffs compares the declared attributes of a rule with its activation.
+func ruleDiffs(current sonar.RulesActivation, rule RuleActivation) []Diff {
+	var diffs []Diff
+
+	if rule.Severity != "" {
+		diffs = append(diffs, diffIf("severity", current.Severity, rule.Severity)...)
+	}
+
+	impacts := activationImpacts(current)
+
+	for _, quality := range sortedKeys(rule.Impacts) {
+		diffs = append(diffs, diffIf("impacts."+quality, impacts[quality], rule.Impacts[quality])...)
+	}
+
+	params := activationParams(current)
+
+	for _, name := range sortedKeys(rule.Params) {
+		diffs = append(diffs, diffIf("params."+name, params[name], rule.Params[name])...)
+	}
+
+	diffs = append(diffs, diffIf("prioritized", formatBool(current.PrioritizedRule), formatBool(rule.Prioritized))...)
+
+	return diffs
+}
+
+// activationImpacts returns the impact severities of an activation by software quality.
+func activationImpacts(activation sonar.RulesActivation) map[string]string {
+	impacts := make(map[string]string, len(activation.Impacts))
+
+	for _, impact := range activation.Impacts {
+		impacts[impact.SoftwareQuality] = impact.Severity
+	}
+
+	return impacts
+}
+
+// activationParams returns the parameters of an activation by name.
+func activationParams(activation sonar.RulesActivation) map[string]string {
+	params := make(map[string]string, len(activation.Params))
+
+	for _, param := range activation.Params {
+		params[param.Key] = param.Value
+	}
+
+	return params
+}
+
+// restoreActivation returns the options activating a rule as it was.
+func restoreActivation(profileKey, ruleKey string, activation sonar.RulesActivation) *sonar.QualityprofilesActivateRuleOptions {
+	if activation.Inherit == sonar.InheritanceTypeInherited {
+		return &sonar.QualityprofilesActivateRuleOptions{ //nolint:exhaustruct // reset to the parent activation
+			Key:   profileKey,
+			Rule:  ruleKey,
+			Reset: true,
+		}
+	}
+
+	return &sonar.QualityprofilesActivateRuleOptions{ //nolint:exhaustruct // impacts follow the severity
+		Key:             profileKey,
+		Rule:            ruleKey,
+		Severity:        activation.Severity,
+		Params:          activationParams(activation),
+		PrioritizedRule: activation.PrioritizedRule,
+	}
+}
+
+// createQualityProfile returns the change creating an empty quality profile, recording
+// its key for the changes that follow.
+func (p *planner) createQualityProfile(profile QualityProfile, key *string) *Change {
+	profiles := p.client.Qualityprofiles
+
+	return &Change{
+		Action:  ActionCreate,
+		Kind:    KindQualityProfile,
+		Address: profileID(profile.Language, profile.Name),
+		Diffs:   nil,
+		apply: func(ctx context.Context) error {
+			created, _, err := profiles.Create(ctx, &sonar.QualityprofilesCreateOptions{
+				Language: profile.Language,
+				Name:     profile.Name,
+			})
+			if err != nil {
+				return err
+			}
+
+			*key = created.Profile.Key
+
+			return nil
+		},
+		revert: call(profiles.Delete, &sonar.QualityprofilesDeleteOptions{
+			Language:       profile.Language,
+			QualityProfile: profile.Name,
+		}),
+	}
+}
+
+// changeParent returns the change setting, or breaking, the inheritance of a profile.
+func (p *planner) changeParent(profile QualityProfile, previous string) *Change {
+	profiles := p.client.Qualityprofiles
+
+	return &Change{
+		Action:  ActionUpdate,
+		Kind:    KindQualityProfile,
+		Address: profileID(profile.Language, profile.Name),
+		Diffs:   []Diff{{Name: "parent", Before: previous, After: profile.Parent}},
+		apply: call(profiles.ChangeParent, &sonar.QualityprofilesChangeParentOptions{
+			Language:             profile.Language,
+			QualityProfile:       profile.Name,
+			ParentQualityProfile: profile.Parent,
+		}),
+		revert: call(profiles.ChangeParent, &sonar.QualityprofilesChangeParentOptions{
+			Language:             profile.Language,
+			QualityProfile:       profile.Name,
+			ParentQualityProfile: previous,
+		}),
+	}
+}
+
+// setDefaultQualityProfile returns the change making a profile the default of its language.
+func (p *planner) setDefaultQualityProfile(profile QualityProfile, previous string) *Change {
+	profiles := p.client.Qualityprofiles
+
+	return &Change{
+		Action:  ActionUpdate,
+		Kind:    KindQualityProfile,
+		Address: profileID(profile.Language, profile.Name),
+		Diffs:   []Diff{{Name: "default", Before: formatBool(false), After: formatBool(true)}},
+		apply: call(profiles.SetDefault, &sonar.QualityprofilesSetDefaultOptions{
+			Language:       profile.Language,
+			QualityProfile: profile.Name,
+		}),
+		revert: call(profiles.SetDefault, &sonar.QualityprofilesSetDefaultOptions{
+			Language:       profile.Language,
+			QualityProfile: previous,
+		}),
+	}
+}
+
+// activateRule returns the change activating a rule that is not active on the profile.
+func (p *planner) activateRule(id string, key *string, rule RuleActivation) *Change {
+	profiles := p.client.Qualityprofiles
+
+	diffs := diffIf("severity", "", rule.Severity)
+
+	for _, quality := range sortedKeys(rule.Impacts) {
+		diffs = append(diffs, Diff{Name: "impacts." + quality, Before: "", After: rule.Impacts[quality]})
+	}
+
+	for _, name := range sortedKeys(rule.Params) {
+		diffs = append(diffs, Diff{Name: "params." + name, Before: "", After: rule.Params[name]})
+	}
+
+	if rule.Prioritized {
+		diffs = append(diffs, Diff{Name: "prioritized", Before: "", After: formatBool(true)})
+	}
+
+	return &Change{
+		Action:  ActionCreate,
+		Kind:    KindQualityProfileRule,
+		Address: address(id, rule.Key),
+		Diffs:   diffs,
+		apply: func(ctx context.Context) error {
+			_, err := profiles.ActivateRule(ctx, &sonar.QualityprofilesActivateRuleOptions{ //nolint:exhaustruct // no reset
+				Key:             *key,
+				Rule:            rule.Key,
+				Severity:        rule.Severity,
+				Impacts:         rule.Impacts,
+				Params:          rule.Params,
+				PrioritizedRule: rule.Prioritized,
+			})
+
+			return err
+		},
+		revert: func(ctx context.Context) error {
+			_, err := profiles.DeactivateRule(ctx, &sonar.QualityprofilesDeactivateRuleOptions{Key: *key, Rule: rule.Key})
+
+			return err
+		},
+	}
+}
+
+// updateRule returns the change updating an active rule. Attributes the configuration
+// does not declare keep their current values.
+func (p *planner) updateRule(id string, key *string, current sonar.RulesActivation, rule RuleActivation, diffs []Diff) *Change {
+	profiles := p.client.Qualityprofiles
+
+	params := activationParams(current)
+	maps.Copy(params, rule.Params)
+
+	severity := rule.Severity
+	if severity == "" && len(rule.Impacts) == 0 {
+		severity = current.Severity
+	}
+
+	return &Change{
+		Action:  ActionUpdate,
+		Kind:    KindQualityProfileRule,
+		Address: address(id, rule.Key),
+		Diffs:   diffs,
+		apply: func(ctx context.Context) error {
+			_, err := profiles.ActivateRule(ctx, &sonar.QualityprofilesActivateRuleOptions{ //nolint:exhaustruct // no reset
+				Key:             *key,
+				Rule:            rule.Key,
+				Severity:        severity,
+				Impacts:         rule.Impacts,
+				Params:          params,
+				PrioritizedRule: rule.Prioritized,
+			})
+
+			return err
+		},
+		revert: func(ctx context.Context) error {
+			_, err := profiles.ActivateRule(ctx, restoreActivation(*key, rule.Key, current))
+
+			return err
+		},
+	}
+}
+
+// deactivateRule returns the change deactivating an undeclared rule.
+func (p *planner) deactivateRule(id string, key *string, ruleKey string, current sonar.RulesActivation) *Change {
+	profiles := p.client.Qualityprofiles
+
+	return &Change{
+		Action:  ActionDelete,
+		Kind:    KindQualityProfileRule,
+		Address: address(id, ruleKey),
+		Diffs:   diffIf("severity", current.Severity, ""),
+		apply: func(ctx context.Context) error {
+			_, err := profiles.DeactivateRule(ctx, &sonar.QualityprofilesDeactivateRuleOptions{Key: *key, Rule: ruleKey})
+
+			return err
+		},
+		revert: func(ctx context.Context) error {
+			_, err := profiles.ActivateRule(ctx, restoreActivation(*key, ruleKey, current))
+
+			return err
+		},
+	}
+}
+
+// resetRule returns the change resetting an undeclared override to the inherited activation.
+func (p *planner) resetRule(id string, key *string, ruleKey string, current sonar.RulesActivation) *Change {
+	profiles := p.client.Qualityprofiles
+
+	return &Change{
+		Action:  ActionUpdate,
+		Kind:    KindQualityProfileRule,
+		Address: address(id, ruleKey),
+		Diffs:   []Diff{{Name: "inherit", Before: sonar.InheritanceTypeOverrides, After: sonar.InheritanceTypeInherited}},
+		apply: func(ctx context.Context) error {
+			_, err := profiles.ActivateRule(ctx, &sonar.QualityprofilesActivateRuleOptions{ //nolint:exhaustruct // reset to the parent activation
+				Key:   *key,
+				Rule:  ruleKey,
+				Reset: true,
+			})
+
+			return err
+		},
+		revert: func(ctx context.Context) error {
+			_, err := profiles.ActivateRule(ctx, restoreActivation(*key, ruleKey, current))
+
+			return err
+		},
+	}
+}
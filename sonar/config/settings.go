@@ -0,0 +1,276 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// securedSuffix ends the keys of settings whose values the server does not disclose.
+const securedSuffix = ".secured"
+
+// planSettings plans the global settings, or the settings of a project.
+func (p *planner) planSettings(ctx context.Context, project string, settings []Setting) error {
+	if len(settings) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(settings))
+
+	for _, setting := range settings {
+		keys = append(keys, setting.Key)
+	}
+
+	values, _, err := p.client.Settings.Values(ctx, &sonar.SettingsValuesOptions{Component: project, Keys: keys})
+	if err != nil {
+		return fmt.Errorf("failed to read settings%s: %w", ofProject(project), err)
+	}
+
+	live := make(map[string]sonar.SettingValue, len(values.Settings))
+
+	for _, value := range values.Settings {
+		live[value.Key] = value
+	}
+
+	for _, setting := range settings {
+		// Secured values cannot be compared, so a secured setting is only set when the
+		// server has none.
+		if slices.Contains(values.SetSecuredSettings, setting.Key) {
+			continue
+		}
+
+		current, found := live[setting.Key]
+		own := found && !current.Inherited
+
+		before, after := settingValue(current), settingValue(setting.toValue())
+		if own && before == after {
+			continue
+		}
+
+		action := ActionUpdate
+		if !own {
+			action, before = ActionCreate, ""
+		}
+
+		if strings.HasSuffix(setting.Key, securedSuffix) {
+			after = sensitiveValue
+		}
+
+		p.add(p.setSetting(action, project, setting, current, own, Diff{Name: "value", Before: before, After: after}))
+	}
+
+	return nil
+}
+
+// toValue returns the setting as the server would report it.
+func (s Setting) toValue() sonar.SettingValue {
+	return sonar.SettingValue{Key: s.Key, Value: s.Value, Values: s.Values, FieldValues: nil, Inherited: false, ParentValue: ""}
+}
+
+// settingValue formats the value of a setting for diffs.
+func settingValue(value sonar.SettingValue) string {
+	if value.Values != nil {
+		return "[" + strings.Join(value.Values, ", ") + "]"
+	}
+
+	return value.Value
+}
+
+// ofProject qualifies error messages about project-level objects.
+func ofProject(project string) string {
+	if project == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" of project %q", project)
+}
+
+// setSetting returns the change setting a value. Undoing it restores the previous value,
+// or resets the setting if it had none of its own.
+func (p *planner) setSetting(action Action, project string, setting Setting, current sonar.SettingValue, own bool, diff Diff) *Change {
+	settings := p.client.Settings
+
+	revert := call(settings.Reset, &sonar.SettingsResetOptions{Component: project, Keys: []string{setting.Key}})
+	if own {
+		revert = call(settings.Set, &sonar.SettingsSetOptions{ //nolint:exhaustruct // property sets are not managed
+			Component: project,
+			Key:       setting.Key,
+			Value:     current.Value,
+			Values:    current.Values,
+		})
+	}
+
+	return &Change{
+		Action:  action,
+		Kind:    KindSetting,
+		Address: scoped(project, setting.Key),
+		Diffs:   []Diff{diff},
+		apply: call(settings.Set, &sonar.SettingsSetOptions{ //nolint:exhaustruct // property sets are not managed
+			Component: project,
+			Key:       setting.Key,
+			Value:     setting.Value,
+			Values:    setting.Values,
+		}),
+		revert: revert,
+	}
+}
+
+// planWebhooks plans the creation and update of global webhooks, or of the webhooks of a
+// project.
+func (p *planner) planWebhooks(ctx context.Context, project string, webhooks []Webhook) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	list, _, err := p.client.Webhooks.List(ctx, &sonar.WebhooksListOptions{Project: project})
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks%s: %w", ofProject(project), err)
+	}
+
+	live := make(map[string]sonar.WebhooksDefinition, len(list.Webhooks))
+
+	for _, webhook := range list.Webhooks {
+		live[webhook.Name] = webhook
+	}
+
+	for _, webhook := range webhooks {
+		current, found := live[webhook.Name]
+		if !found {
+			p.add(p.createWebhook(project, webhook))
+
+			continue
+		}
+
+		diffs := diffIf("url", current.URL, webhook.URL)
+
+		// Secrets cannot be compared, so a secret is only set on webhooks that have none.
+		setSecret := webhook.Secret != "" && !current.HasSecret
+		if setSecret {
+			diffs = append(diffs, Diff{Name: "secret", Before: "", After: sensitiveValue})
+		}
+
+		if len(diffs) > 0 {
+			p.add(p.updateWebhook(project, webhook, current, setSecret, diffs))
+		}
+	}
+
+	return nil
+}
+
+// createWebhook returns the change creating a webhook, recording its key for the rollback.
+func (p *planner) createWebhook(project string, webhook Webhook) *Change {
+	webhooks := p.client.Webhooks
+	key := new(string)
+
+	diffs := []Diff{{Name: "url", Before: "", After: webhook.URL}}
+	if webhook.Secret != "" {
+		diffs = append(diffs, Diff{Name: "secret", Before: "", After: sensitiveValue})
+	}
+
+	return &Change{
+		Action:  ActionCreate,
+		Kind:    KindWebhook,
+		Address: scoped(project, webhook.Name),
+		Diffs:   diffs,
+		apply: func(ctx context.Context) error {
+			created, _, err := webhooks.Create(ctx, &sonar.WebhooksCreateOptions{
+				Name:    webhook.Name,
+				Project: project,
+				Secret:  webhook.Secret,
+				URL:     webhook.URL,
+			})
+			if err != nil {
+				return err
+			}
+
+			*key = created.Webhook.Key
+
+			return nil
+		},
+		revert: func(ctx context.Context) error {
+			_, err := webhooks.Delete(ctx, &sonar.WebhooksDeleteOptions{Webhook: *key})
+
+			return err
+		},
+	}
+}
+
+// updateWebhook returns the change updating the URL, and possibly the secret, of a
+// webhook. Undoing it restores the URL; the server cannot remove a secret it was given.
+func (p *planner) updateWebhook(project string, webhook Webhook, current sonar.WebhooksDefinition, setSecret bool, diffs []Diff) *Change {
+	webhooks := p.client.Webhooks
+
+	secret := ""
+	if setSecret {
+		secret = webhook.Secret
+	}
+
+	return &Change{
+		Action:  ActionUpdate,
+		Kind:    KindWebhook,
+		Address: scoped(project, webhook.Name),
+		Diffs:   diffs,
+		apply: call(webhooks.Update, &sonar.WebhooksUpdateOptions{
+			Webhook: current.Key,
+			Name:    webhook.Name,
+			URL:     webhook.URL,
+			Secret:  secret,
+		}),
+		revert: call(webhooks.Update, &sonar.WebhooksUpdateOptions{ //nolint:exhaustruct // the secret is kept
+			Webhook: current.Key,
+			Name:    current.Name,
+			URL:     current.URL,
+		}),
+	}
+}
+
+// planNewCodePeriod plans the global new code period, or that of a project.
+func (p *planner) planNewCodePeriod(ctx context.Context, project string, period *NewCodePeriod) error {
+	if period == nil {
+		return nil
+	}
+
+	current, _, err := p.client.NewCodePeriods.Show(ctx, &sonar.NewCodePeriodsShowOptions{Branch: "", Project: project})
+	if err != nil {
+		return fmt.Errorf("failed to read the new code period%s: %w", ofProject(project), err)
+	}
+
+	own := project == "" || !current.Inherited
+	if own && current.Type == period.Type && current.Value == period.Value {
+		return nil
+	}
+
+	periods := p.client.NewCodePeriods
+	change := &Change{
+		Action:  ActionUpdate,
+		Kind:    KindNewCodePeriod,
+		Address: scoped(project, "new_code_period"),
+		Diffs: append(diffIf("type", current.Type, period.Type),
+			diffIf("value", current.Value, period.Value)...),
+		apply: call(periods.Set, &sonar.NewCodePeriodsSetOptions{
+			Branch:  "",
+			Project: project,
+			Type:    period.Type,
+			Value:   period.Value,
+		}),
+		revert: call(periods.Set, &sonar.NewCodePeriodsSetOptions{
+			Branch:  "",
+			Project: project,
+			Type:    current.Type,
+			Value:   current.Value,
+		}),
+	}
+
+	if !own {
+		change.Action = ActionCreate
+		change.Diffs = append(diffIf("type", "", period.Type), diffIf("value", "", period.Value)...)
+		change.revert = call(periods.Unset, &sonar.NewCodePeriodsUnsetOptions{Branch: "", Project: project})
+	}
+
+	p.add(change)
+
+	return nil
+}
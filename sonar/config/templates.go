@@ -0,0 +1,296 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+const (
+	// principalGroup addresses the permissions a template grants a group.
+	principalGroup = "group"
+	// principalUser addresses the permissions a template grants a user.
+	principalUser = "user"
+)
+
+// planPermissionTemplates plans the creation and update of permission templates, the
+// permissions they grant and the default template.
+func (p *planner) planPermissionTemplates(ctx context.Context, templates []PermissionTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+
+	search, _, err := p.client.Permissions.SearchTemplates(ctx, &sonar.PermissionsSearchTemplatesOptions{}) //nolint:exhaustruct // every template
+	if err != nil {
+		return fmt.Errorf("failed to search permission templates: %w", err)
+	}
+
+	live := make(map[string]sonar.PermissionTemplate, len(search.PermissionTemplates))
+
+	for _, template := range search.PermissionTemplates {
+		live[template.Name] = template
+	}
+
+	previousDefault := ""
+
+	for _, defaultTemplate := range search.DefaultTemplates {
+		if defaultTemplate.Qualifier == sonar.ProjectQualifierTRK {
+			previousDefault = defaultTemplate.TemplateID
+		}
+	}
+
+	for _, template := range templates {
+		err := p.planPermissionTemplate(ctx, template, live, previousDefault)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planPermissionTemplate plans the changes of one permission template.
+func (p *planner) planPermissionTemplate(ctx context.Context, template PermissionTemplate, live map[string]sonar.PermissionTemplate, previousDefault string) error {
+	existing, found := live[template.Name]
+
+	groups := map[string][]string{}
+	users := map[string][]string{}
+
+	if found {
+		var err error
+
+		groups, users, err = p.templateGrants(ctx, template)
+		if err != nil {
+			return err
+		}
+
+		if change := p.updatePermissionTemplate(template, existing); change != nil {
+			p.add(change)
+		}
+	} else {
+		p.add(p.createPermissionTemplate(template))
+	}
+
+	if template.Groups != nil {
+		p.planGrants(template.Name, principalGroup, groups, template.Groups)
+	}
+
+	if template.Users != nil {
+		p.planGrants(template.Name, principalUser, users, template.Users)
+	}
+
+	if template.Default && (!found || existing.ID != previousDefault) {
+		p.add(p.setDefaultPermissionTemplate(template.Name, previousDefault))
+	}
+
+	return nil
+}
+
+// templateGrants returns the permissions an existing template grants, by group name and
+// by user login, for the principals the configuration manages.
+func (p *planner) templateGrants(ctx context.Context, template PermissionTemplate) (map[string][]string, map[string][]string, error) {
+	groups := map[string][]string{}
+	users := map[string][]string{}
+
+	if template.Groups != nil {
+		live, _, err := p.client.Permissions.TemplateGroupsAll(ctx, &sonar.PermissionsTemplateGroupsOptions{ //nolint:exhaustruct // every group
+			TemplateName: template.Name,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read the groups of permission template %q: %w", template.Name, err)
+		}
+
+		for _, group := range live {
+			groups[group.Name] = group.Permissions
+		}
+	}
+
+	if template.Users != nil {
+		live, _, err := p.client.Permissions.TemplateUsersAll(ctx, &sonar.PermissionsTemplateUsersOptions{ //nolint:exhaustruct // every user
+			TemplateName: template.Name,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read the users of permission template %q: %w", template.Name, err)
+		}
+
+		for _, user := range live {
+			users[user.Login] = user.Permissions
+		}
+	}
+
+	return groups, users, nil
+}
+
+// planGrants plans the permissions to add to and remove from the groups or users of a
+// template so that they match the configuration exactly.
+func (p *planner) planGrants(templateName, principal string, live, desired map[string][]string) {
+	for _, name := range sortedKeys(live) {
+		wanted := toSet(desired[name])
+
+		for _, permission := range live[name] {
+			if _, keep := wanted[permission]; !keep {
+				p.add(p.grantChange(ActionDelete, templateName, principal, name, permission))
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(desired) {
+		granted := toSet(live[name])
+
+		for _, permission := range desired[name] {
+			if _, found := granted[permission]; !found {
+				p.add(p.grantChange(ActionCreate, templateName, principal, name, permission))
+			}
+		}
+	}
+}
+
+// toSet returns the values of a list as a set.
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+
+	return set
+}
+
+// grantChange returns the change granting (ActionCreate) or revoking (ActionDelete) a
+// permission of a template to a group or user.
+func (p *planner) grantChange(action Action, templateName, principal, name, permission string) *Change {
+	add, remove := p.grantFuncs(templateName, principal, name, permission)
+
+	before, after := "", permission
+	if action == ActionDelete {
+		add, remove = remove, add
+		before, after = permission, ""
+	}
+
+	return &Change{
+		Action:  action,
+		Kind:    KindPermissionTemplatePermission,
+		Address: address(templateName, principal, name, permission),
+		Diffs:   []Diff{{Name: "permission", Before: before, After: after}},
+		apply:   add,
+		revert:  remove,
+	}
+}
+
+// grantFuncs returns the functions granting and revoking a permission of a template.
+func (p *planner) grantFuncs(templateName, principal, name, permission string) (func(context.Context) error, func(context.Context) error) {
+	permissions := p.client.Permissions
+
+	if principal == principalGroup {
+		return call(permissions.AddGroupToTemplate, &sonar.PermissionsAddGroupToTemplateOptions{ //nolint:exhaustruct // by template name
+				GroupName:    name,
+				Permission:   permission,
+				TemplateName: templateName,
+			}), call(permissions.RemoveGroupFromTemplate, &sonar.PermissionsRemoveGroupFromTemplateOptions{ //nolint:exhaustruct // by template name
+				GroupName:    name,
+				Permission:   permission,
+				TemplateName: templateName,
+			})
+	}
+
+	return call(permissions.AddUserToTemplate, &sonar.PermissionsAddUserToTemplateOptions{ //nolint:exhaustruct // by template name
+			Login:        name,
+			Permission:   permission,
+			TemplateName: templateName,
+		}), call(permissions.RemoveUserFromTemplate, &sonar.PermissionsRemoveUserFromTemplateOptions{ //nolint:exhaustruct // by template name
+			Login:        name,
+			Permission:   permission,
+			TemplateName: templateName,
+		})
+}
+
+// createPermissionTemplate returns the change creating a permission template.
+func (p *planner) createPermissionTemplate(template PermissionTemplate) *Change {
+	permissions := p.client.Permissions
+
+	return &Change{
+		Action:  ActionCreate,
+		Kind:    KindPermissionTemplate,
+		Address: template.Name,
+		Diffs: append(diffIf("description", "", template.Description),
+			diffIf("projectKeyPattern", "", template.ProjectKeyPattern)...),
+		apply: func(ctx context.Context) error {
+			_, _, err := permissions.CreateTemplate(ctx, &sonar.PermissionsCreateTemplateOptions{
+				Name:              template.Name,
+				Description:       template.Description,
+				ProjectKeyPattern: template.ProjectKeyPattern,
+			})
+
+			return err
+		},
+		revert: call(permissions.DeleteTemplate, &sonar.PermissionsDeleteTemplateOptions{ //nolint:exhaustruct // by template name
+			TemplateName: template.Name,
+		}),
+	}
+}
+
+// updatePermissionTemplate returns the change updating the description and project key
+// pattern of a template, or nil if they match. The server cannot clear them, so empty
+// values in the configuration are left alone.
+func (p *planner) updatePermissionTemplate(template PermissionTemplate, existing sonar.PermissionTemplate) *Change {
+	var diffs []Diff
+
+	if template.Description != "" {
+		diffs = append(diffs, diffIf("description", existing.Description, template.Description)...)
+	}
+
+	if template.ProjectKeyPattern != "" {
+		diffs = append(diffs, diffIf("projectKeyPattern", existing.ProjectKeyPattern, template.ProjectKeyPattern)...)
+	}
+
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	permissions := p.client.Permissions
+
+	return &Change{
+		Action:  ActionUpdate,
+		Kind:    KindPermissionTemplate,
+		Address: template.Name,
+		Diffs:   diffs,
+		apply: func(ctx context.Context) error {
+			_, _, err := permissions.UpdateTemplate(ctx, &sonar.PermissionsUpdateTemplateOptions{ //nolint:exhaustruct // the name is kept
+				ID:                existing.ID,
+				Description:       template.Description,
+				ProjectKeyPattern: template.ProjectKeyPattern,
+			})
+
+			return err
+		},
+		revert: func(ctx context.Context) error {
+			_, _, err := permissions.UpdateTemplate(ctx, &sonar.PermissionsUpdateTemplateOptions{ //nolint:exhaustruct // the name is kept
+				ID:                existing.ID,
+				Description:       existing.Description,
+				ProjectKeyPattern: existing.ProjectKeyPattern,
+			})
+
+			return err
+		},
+	}
+}
+
+// setDefaultPermissionTemplate returns the change making a template the default one for
+// projects.
+func (p *planner) setDefaultPermissionTemplate(name, previousID string) *Change {
+	permissions := p.client.Permissions
+
+	return &Change{
+		Action:  ActionUpdate,
+		Kind:    KindPermissionTemplate,
+		Address: name,
+		Diffs:   []Diff{{Name: "default", Before: formatBool(false), After: formatBool(true)}},
+		apply: call(permissions.SetDefaultTemplate, &sonar.PermissionsSetDefaultTemplateOptions{ //nolint:exhaustruct // projects by default
+			TemplateName: name,
+		}),
+		revert: call(permissions.SetDefaultTemplate, &sonar.PermissionsSetDefaultTemplateOptions{ //nolint:exhaustruct // projects by default
+			TemplateID: previousID,
+		}),
+	}
+}
@@ -66,7 +66,7 @@ type DuplicationsShowOptions struct {
 	Branch string `url:"branch,omitempty"`
 	// Key is the file key.
 	// This field is required.
-	Key string `url:"key"`
+	Key string `url:"key" cli:"positional"`
 	// PullRequest is the pull request id.
 	// WARNING: This parameter is internal and may change without notice.
 	PullRequest string `url:"pullRequest,omitempty"`
@@ -44,14 +44,14 @@ type FavoritesAddOptions struct {
 	// Component is the component key.
 	// Only components with qualifiers TRK, VW, SVW, APP are supported.
 	// This field is required.
-	Component string `url:"component"`
+	Component string `url:"component" cli:"positional"`
 }
 
 // FavoritesRemoveOptions contains parameters for the Remove method.
 type FavoritesRemoveOptions struct {
 	// Component is the component key.
 	// This field is required.
-	Component string `url:"component"`
+	Component string `url:"component" cli:"positional"`
 }
 
 // FavoritesSearchOptions contains parameters for the Search method.
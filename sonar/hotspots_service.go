@@ -308,7 +308,7 @@ type HotspotsAssignOptions struct {
 	Comment string `url:"comment,omitempty"`
 	// Hotspot is the key of the Security Hotspot.
 	// This field is required.
-	Hotspot string `url:"hotspot"`
+	Hotspot string `url:"hotspot" cli:"positional"`
 }
 
 // HotspotsChangeStatusOptions contains parameters for the ChangeStatus method.
@@ -333,7 +333,7 @@ type HotspotsChangeStatusOptions struct {
 type HotspotsDeleteCommentOptions struct {
 	// Comment is the key of the comment to delete.
 	// This field is required.
-	Comment string `url:"comment"`
+	Comment string `url:"comment" cli:"positional"`
 }
 
 // HotspotsEditCommentOptions contains parameters for the EditComment method.
@@ -358,7 +358,7 @@ type HotspotsListOptions struct {
 	Branch string `url:"branch,omitempty"`
 	// Project is the key of the project.
 	// This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 	// PullRequest is the pull request id. Not available in the community edition.
 	// This field is optional.
 	PullRequest string `url:"pullRequest,omitempty"`
@@ -478,7 +478,7 @@ type HotspotsSearchOptions struct {
 type HotspotsShowOptions struct {
 	// Hotspot is the key of the Security Hotspot.
 	// This field is required.
-	Hotspot string `url:"hotspot"`
+	Hotspot string `url:"hotspot" cli:"positional"`
 }
 
 // =============================================================================
@@ -0,0 +1,19 @@
+// Package inheritance resolves the inheritance of SonarQube quality profiles.
+//
+// Qualityprofiles.Inheritance only returns one level of ancestors and children. Build
+// reads every profile and links them into one Forest per language, whose roots are the
+// profiles without a parent:
+//
+//	forests, err := inheritance.Build(ctx, client, "java")
+//	err = inheritance.WriteTree(os.Stdout, forests)
+//
+// WriteTree, WriteDOT and WriteMermaid render forests as an indented tree, a Graphviz
+// graph or a Mermaid flowchart.
+//
+// # Effective rules
+//
+// EffectiveRules returns the rules active on a profile, the inherited ones included,
+// with the profile each activation comes from: the profile itself, or the ancestor
+// whose severity and parameters it inherits, and the ancestor the rule was first
+// activated on. This explains why a rule is active on the projects using the profile.
+package inheritance
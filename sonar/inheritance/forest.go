@@ -0,0 +1,166 @@
+package inheritance
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// Profile is a quality profile in an inheritance forest.
+type Profile struct {
+	// Key is the key of the profile.
+	Key string `json:"key"`
+	// Name is the name of the profile.
+	Name string `json:"name"`
+	// Language is the language of the profile.
+	Language string `json:"language"`
+	// BuiltIn reports whether the profile is provided by the server.
+	BuiltIn bool `json:"builtIn,omitempty"`
+	// Default reports whether the profile is the default profile of its language.
+	Default bool `json:"default,omitempty"`
+	// ActiveRules is the number of rules active on the profile, inherited ones included.
+	ActiveRules int64 `json:"activeRules"`
+	// Projects is the number of projects explicitly using the profile.
+	Projects int64 `json:"projects,omitempty"`
+	// Parent is the profile it inherits from, nil for a root.
+	Parent *Profile `json:"-"`
+	// Children are the profiles inheriting from it, sorted by name.
+	Children []*Profile `json:"children,omitempty"`
+}
+
+// Ancestors returns the profiles the profile inherits from, its parent first.
+func (p *Profile) Ancestors() []*Profile {
+	var ancestors []*Profile
+
+	for parent := p.Parent; parent != nil; parent = parent.Parent {
+		ancestors = append(ancestors, parent)
+	}
+
+	return ancestors
+}
+
+// Forest is the inheritance forest of the quality profiles of a language.
+type Forest struct {
+	// Language is the language of the profiles.
+	Language string `json:"language"`
+	// Roots are the profiles without a parent, sorted by name.
+	Roots []*Profile `json:"roots"`
+}
+
+// Profiles returns the profiles of the forest, each before its children.
+func (f *Forest) Profiles() []*Profile {
+	var profiles []*Profile
+
+	var walk func(level []*Profile)
+
+	walk = func(level []*Profile) {
+		for _, profile := range level {
+			profiles = append(profiles, profile)
+			walk(profile.Children)
+		}
+	}
+
+	walk(f.Roots)
+
+	return profiles
+}
+
+// Named returns the profile of the forest with the given name, or nil.
+func (f *Forest) Named(name string) *Profile {
+	profiles := f.Profiles()
+
+	idx := slices.IndexFunc(profiles, func(profile *Profile) bool { return profile.Name == name })
+	if idx < 0 {
+		return nil
+	}
+
+	return profiles[idx]
+}
+
+// Lookup returns the profile with the given key in the forests, or nil.
+func Lookup(forests []*Forest, key string) *Profile {
+	for _, forest := range forests {
+		for _, profile := range forest.Profiles() {
+			if profile.Key == key {
+				return profile
+			}
+		}
+	}
+
+	return nil
+}
+
+// Build reads the quality profiles of a language, or of every language when language is
+// empty, and returns their inheritance forests sorted by language.
+func Build(ctx context.Context, client *sonar.Client, language string) ([]*Forest, error) {
+	search, _, err := client.Qualityprofiles.Search(ctx, &sonar.QualityprofilesSearchOptions{ //nolint:exhaustruct // every profile of the language
+		Language: language,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search quality profiles: %w", err)
+	}
+
+	return buildForests(search.Profiles), nil
+}
+
+// buildForests links profiles to their parents. A profile whose parent is not listed is
+// a root.
+func buildForests(profiles []sonar.QualityProfile) []*Forest {
+	byKey := make(map[string]*Profile, len(profiles))
+
+	for _, profile := range profiles {
+		byKey[profile.Key] = &Profile{
+			Key:         profile.Key,
+			Name:        profile.Name,
+			Language:    profile.Language,
+			BuiltIn:     profile.IsBuiltIn,
+			Default:     profile.IsDefault,
+			ActiveRules: profile.ActiveRuleCount,
+			Projects:    profile.ProjectCount,
+			Parent:      nil,
+			Children:    nil,
+		}
+	}
+
+	byLanguage := make(map[string]*Forest)
+
+	for _, profile := range profiles {
+		node := byKey[profile.Key]
+
+		if parent, found := byKey[profile.ParentKey]; found && profile.ParentKey != "" {
+			node.Parent = parent
+			parent.Children = append(parent.Children, node)
+
+			continue
+		}
+
+		forest, found := byLanguage[profile.Language]
+		if !found {
+			forest = &Forest{Language: profile.Language, Roots: nil}
+			byLanguage[profile.Language] = forest
+		}
+
+		forest.Roots = append(forest.Roots, node)
+	}
+
+	byName := func(a, b *Profile) int { return strings.Compare(a.Name, b.Name) }
+
+	for _, node := range byKey {
+		slices.SortFunc(node.Children, byName)
+	}
+
+	forests := make([]*Forest, 0, len(byLanguage))
+
+	for _, forest := range byLanguage {
+		slices.SortFunc(forest.Roots, byName)
+		forests = append(forests, forest)
+	}
+
+	slices.SortFunc(forests, func(a, b *Forest) int { return cmp.Compare(a.Language, b.Language) })
+
+	return forests
+}
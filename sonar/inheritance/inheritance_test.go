@@ -0,0 +1,157 @@
+package inheritance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testResponses are the responses of the test server, by path and, for rule searches,
+// by quality profile.
+//
+//nolint:gochecknoglobals // test fixture
+var testResponses = map[string]string{
+	"qualityprofiles/search": `{"profiles": [
+		{"key": "AX3", "name": "Team", "language": "java", "parentKey": "AX2", "activeRuleCount": 4, "projectCount": 2},
+		{"key": "JS1", "name": "Sonar way", "language": "js", "isBuiltIn": true, "isDefault": true, "activeRuleCount": 120},
+		{"key": "AX1", "name": "Sonar way", "language": "java", "isBuiltIn": true, "activeRuleCount": 2},
+		{"key": "AX4", "name": "Legacy \"old\"", "language": "java", "parentKey": "AX1", "activeRuleCount": 2},
+		{"key": "AX2", "name": "Company", "language": "java", "parentKey": "AX1", "isDefault": true, "activeRuleCount": 3}]}`,
+	"rules/search?qprofile=AX1": `{"paging": {"total": 2}, "rules": [{"key": "java:S100"}, {"key": "java:S101"}], "actives": {
+		"java:S100": [{"qProfile": "AX1", "inherit": "NONE", "severity": "MAJOR"}],
+		"java:S101": [{"qProfile": "AX1", "inherit": "NONE", "severity": "MINOR"}]}}`,
+	"rules/search?qprofile=AX2": `{"paging": {"total": 3}, "rules": [{"key": "java:S100"}, {"key": "java:S101"}, {"key": "java:S200"}], "actives": {
+		"java:S100": [{"qProfile": "AX2", "inherit": "INHERITED", "severity": "MAJOR"}],
+		"java:S101": [{"qProfile": "AX2", "inherit": "OVERRIDES", "severity": "CRITICAL"}],
+		"java:S200": [{"qProfile": "AX2", "inherit": "NONE", "severity": "INFO"}]}}`,
+	"rules/search?qprofile=AX3": `{"paging": {"total": 4}, "rules": [{"key": "java:S100"}, {"key": "java:S101"}, {"key": "java:S200"}, {"key": "java:S300"}], "actives": {
+		"java:S100": [{"qProfile": "AX3", "inherit": "INHERITED", "severity": "MAJOR"}, {"qProfile": "AX1", "inherit": "NONE", "severity": "MAJOR"}],
+		"java:S101": [{"qProfile": "AX3", "inherit": "INHERITED", "severity": "CRITICAL"}],
+		"java:S200": [{"qProfile": "AX3", "inherit": "OVERRIDES", "severity": "MAJOR",
+			"impacts": [{"softwareQuality": "RELIABILITY", "severity": "MEDIUM"}]}],
+		"java:S300": [{"qProfile": "AX3", "severity": "MINOR", "params": [{"key": "max", "value": "10"}]}]}}`,
+}
+
+// names returns the names of profiles.
+func names(profiles []*Profile) []string {
+	result := make([]string, 0, len(profiles))
+
+	for _, profile := range profiles {
+		result = append(result, profile.Name)
+	}
+
+	return result
+}
+
+func TestBuild(t *testing.T) {
+	forests, err := Build(t.Context(), testutil.NewClient(t, testResponses, "qprofile"), "")
+	require.NoError(t, err)
+	require.Len(t, forests, 2)
+
+	java := forests[0]
+	assert.Equal(t, "java", java.Language)
+	assert.Equal(t, []string{"Sonar way", "Company", "Team", `Legacy "old"`}, names(java.Profiles()))
+	assert.Equal(t, "js", forests[1].Language)
+
+	team := Lookup(forests, "AX3")
+	require.NotNil(t, team)
+	assert.Equal(t, []string{"Company", "Sonar way"}, names(team.Ancestors()))
+	assert.Same(t, team, java.Named("Team"))
+	assert.Nil(t, java.Named("Missing"))
+	assert.Nil(t, Lookup(forests, "missing"))
+}
+
+func TestEffectiveRules(t *testing.T) {
+	client := testutil.NewClient(t, testResponses, "qprofile")
+
+	forests, err := Build(t.Context(), client, "java")
+	require.NoError(t, err)
+
+	rules, err := EffectiveRules(t.Context(), client, Lookup(forests, "AX3"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Activation{
+		{Rule: "java:S100", Severity: "MAJOR", Inherit: "INHERITED", Source: "Sonar way", ActivatedOn: "Sonar way"},
+		{Rule: "java:S101", Severity: "CRITICAL", Inherit: "INHERITED", Source: "Company", ActivatedOn: "Sonar way"},
+		{
+			Rule: "java:S200", Severity: "MAJOR", Impacts: map[string]string{"RELIABILITY": "MEDIUM"},
+			Inherit: "OVERRIDES", Source: "Team", Overrides: "Company", ActivatedOn: "Company",
+		},
+		{Rule: "java:S300", Severity: "MINOR", Params: map[string]string{"max": "10"}, Inherit: "NONE", Source: "Team", ActivatedOn: "Team"},
+	}, rules)
+}
+
+func TestEffectiveRules_Error(t *testing.T) {
+	client := testutil.NewClient(t, testResponses, "qprofile")
+
+	_, err := EffectiveRules(t.Context(), client, &Profile{Key: "missing", Name: "Missing"}) //nolint:exhaustruct // profile without ancestors
+	require.ErrorContains(t, err, "failed to read the rules of quality profile Missing")
+}
+
+func TestWriteTree(t *testing.T) {
+	forests, err := Build(t.Context(), testutil.NewClient(t, testResponses, "qprofile"), "")
+	require.NoError(t, err)
+
+	var out strings.Builder
+
+	require.NoError(t, WriteTree(&out, forests))
+	assert.Equal(t, `java
+└── Sonar way (built-in, 2 rules)
+    ├── Company (default, 3 rules)
+    │   └── Team (4 rules, 2 projects)
+    └── Legacy "old" (2 rules)
+
+js
+└── Sonar way (built-in, default, 120 rules)
+`, out.String())
+}
+
+func TestWriteDOT(t *testing.T) {
+	forests, err := Build(t.Context(), testutil.NewClient(t, testResponses, "qprofile"), "")
+	require.NoError(t, err)
+
+	var out strings.Builder
+
+	require.NoError(t, WriteDOT(&out, forests[:1]))
+	assert.Equal(t, `digraph inheritance {
+  rankdir=LR;
+  node [shape=box];
+  subgraph cluster_0 {
+    label="java";
+    "AX1" [label="Sonar way (built-in, 2 rules)"];
+    "AX2" [label="Company (default, 3 rules)"];
+    "AX3" [label="Team (4 rules, 2 projects)"];
+    "AX4" [label="Legacy \"old\" (2 rules)"];
+    "AX1" -> "AX2";
+    "AX1" -> "AX4";
+    "AX2" -> "AX3";
+  }
+}
+`, out.String())
+}
+
+func TestWriteMermaid(t *testing.T) {
+	forests, err := Build(t.Context(), testutil.NewClient(t, testResponses, "qprofile"), "")
+	require.NoError(t, err)
+
+	var out strings.Builder
+
+	require.NoError(t, WriteMermaid(&out, forests))
+	assert.Equal(t, `flowchart LR
+  subgraph l0 ["java"]
+    p1["Sonar way (built-in, 2 rules)"]
+    p2["Company (default, 3 rules)"]
+    p3["Team (4 rules, 2 projects)"]
+    p4["Legacy #quot;old#quot; (2 rules)"]
+    p1 --> p2
+    p1 --> p4
+    p2 --> p3
+  end
+  subgraph l1 ["js"]
+    p5["Sonar way (built-in, default, 120 rules)"]
+  end
+`, out.String())
+}
@@ -0,0 +1,150 @@
+package inheritance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Label returns the description of a profile used by the renderings, such as
+// "Sonar way (built-in, default, 412 rules)".
+func (p *Profile) Label() string {
+	details := make([]string, 0, 4) //nolint:mnd // built-in, default, rules and projects
+
+	if p.BuiltIn {
+		details = append(details, "built-in")
+	}
+
+	if p.Default {
+		details = append(details, "default")
+	}
+
+	details = append(details, count(p.ActiveRules, "rule"))
+
+	if p.Projects > 0 {
+		details = append(details, count(p.Projects, "project"))
+	}
+
+	return fmt.Sprintf("%s (%s)", p.Name, strings.Join(details, ", "))
+}
+
+// count returns n followed by noun, in the plural unless n is 1.
+func count(n int64, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// WriteTree writes the forests as indented trees, one per language.
+func WriteTree(w io.Writer, forests []*Forest) error {
+	// The buffered writer keeps the first write error, which Flush returns.
+	out := bufio.NewWriter(w)
+
+	var walk func(level []*Profile, prefix string)
+
+	walk = func(level []*Profile, prefix string) {
+		for i, profile := range level {
+			branch, indent := "├── ", "│   "
+			if i == len(level)-1 {
+				branch, indent = "└── ", "    "
+			}
+
+			_, _ = fmt.Fprintf(out, "%s%s%s\n", prefix, branch, profile.Label())
+			walk(profile.Children, prefix+indent)
+		}
+	}
+
+	for i, forest := range forests {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+
+		_, _ = fmt.Fprintln(out, forest.Language)
+		walk(forest.Roots, "")
+	}
+
+	err := out.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write the inheritance tree: %w", err)
+	}
+
+	return nil
+}
+
+// WriteDOT writes the forests as a Graphviz graph, with one cluster per language and an
+// edge from each parent to its children.
+func WriteDOT(w io.Writer, forests []*Forest) error {
+	out := bufio.NewWriter(w)
+
+	_, _ = fmt.Fprintln(out, "digraph inheritance {")
+	_, _ = fmt.Fprintln(out, "  rankdir=LR;")
+	_, _ = fmt.Fprintln(out, "  node [shape=box];")
+
+	for i, forest := range forests {
+		_, _ = fmt.Fprintf(out, "  subgraph cluster_%d {\n", i)
+		_, _ = fmt.Fprintf(out, "    label=%s;\n", strconv.Quote(forest.Language))
+
+		for _, profile := range forest.Profiles() {
+			_, _ = fmt.Fprintf(out, "    %s [label=%s];\n", strconv.Quote(profile.Key), strconv.Quote(profile.Label()))
+		}
+
+		for _, profile := range forest.Profiles() {
+			for _, child := range profile.Children {
+				_, _ = fmt.Fprintf(out, "    %s -> %s;\n", strconv.Quote(profile.Key), strconv.Quote(child.Key))
+			}
+		}
+
+		_, _ = fmt.Fprintln(out, "  }")
+	}
+
+	_, _ = fmt.Fprintln(out, "}")
+
+	err := out.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write the inheritance graph: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMermaid writes the forests as a Mermaid flowchart, with one subgraph per language
+// and an edge from each parent to its children.
+func WriteMermaid(w io.Writer, forests []*Forest) error {
+	out := bufio.NewWriter(w)
+	ids := make(map[*Profile]string)
+
+	_, _ = fmt.Fprintln(out, "flowchart LR")
+
+	for i, forest := range forests {
+		_, _ = fmt.Fprintf(out, "  subgraph l%d [%s]\n", i, mermaidText(forest.Language))
+
+		for _, profile := range forest.Profiles() {
+			ids[profile] = fmt.Sprintf("p%d", len(ids)+1)
+			_, _ = fmt.Fprintf(out, "    %s[%s]\n", ids[profile], mermaidText(profile.Label()))
+		}
+
+		for _, profile := range forest.Profiles() {
+			for _, child := range profile.Children {
+				_, _ = fmt.Fprintf(out, "    %s --> %s\n", ids[profile], ids[child])
+			}
+		}
+
+		_, _ = fmt.Fprintln(out, "  end")
+	}
+
+	err := out.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write the inheritance flowchart: %w", err)
+	}
+
+	return nil
+}
+
+// mermaidText quotes a label for Mermaid, which takes entity codes for quotes.
+func mermaidText(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,135 @@
+package inheritance
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// Activation is a rule active on a profile and where its activation comes from.
+type Activation struct {
+	// Rule is the rule key, such as java:S138.
+	Rule string `json:"rule"`
+	// Severity is the severity of the activation.
+	Severity string `json:"severity,omitempty"`
+	// Impacts are the severities of the activation per software quality.
+	Impacts map[string]string `json:"impacts,omitempty"`
+	// Params are the values of the rule parameters.
+	Params map[string]string `json:"params,omitempty"`
+	// Inherit is the inheritance of the activation on the profile: NONE for a rule
+	// activated on the profile itself, INHERITED or OVERRIDES.
+	Inherit string `json:"inherit"`
+	// Source is the name of the profile whose activation the profile has: the profile
+	// itself for an activation of its own or an override, an ancestor otherwise.
+	Source string `json:"source"`
+	// Overrides is the name of the profile whose activation an override replaces.
+	Overrides string `json:"overrides,omitempty"`
+	// ActivatedOn is the name of the profile the rule was first activated on, the
+	// top-most ancestor it is active on.
+	ActivatedOn string `json:"activatedOn"`
+}
+
+// EffectiveRules returns the rules active on a profile of a forest, the inherited ones
+// included, sorted by rule key. The activations of the profile and of its ancestors are
+// read with Rules.Search, so that the source of each one is known.
+func EffectiveRules(ctx context.Context, client *sonar.Client, profile *Profile) ([]Activation, error) {
+	chain := append([]*Profile{profile}, profile.Ancestors()...)
+	activations := make([]map[string]sonar.RulesActivation, len(chain))
+
+	for i, current := range chain {
+		active, _, err := client.Rules.SearchActivations(ctx, &sonar.RulesSearchActivationsOptions{Qprofile: current.Key})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the rules of quality profile %s: %w", current.Name, err)
+		}
+
+		activations[i] = active
+	}
+
+	return resolve(chain, activations), nil
+}
+
+// resolve returns the activations of the first profile of the chain, with their source
+// found in the activations of its ancestors.
+func resolve(chain []*Profile, activations []map[string]sonar.RulesActivation) []Activation {
+	// source returns the index in the chain of the profile, from start up, whose
+	// activation of the rule is not inherited.
+	source := func(rule string, start int) int {
+		for i := start; i < len(chain); i++ {
+			active, found := activations[i][rule]
+			if !found || active.Inherit != sonar.InheritanceTypeInherited {
+				return i
+			}
+		}
+
+		return len(chain) - 1
+	}
+
+	effective := make([]Activation, 0, len(activations[0]))
+
+	for _, rule := range slices.Sorted(maps.Keys(activations[0])) {
+		active := activations[0][rule]
+		activation := Activation{
+			Rule:        rule,
+			Severity:    active.Severity,
+			Impacts:     impacts(active),
+			Params:      params(active),
+			Inherit:     active.Inherit,
+			Source:      chain[source(rule, 0)].Name,
+			Overrides:   "",
+			ActivatedOn: chain[0].Name,
+		}
+
+		if activation.Inherit == "" {
+			activation.Inherit = sonar.InheritanceTypeNone
+		}
+
+		if active.Inherit == sonar.InheritanceTypeOverrides && len(chain) > 1 {
+			activation.Overrides = chain[source(rule, 1)].Name
+		}
+
+		for i := 1; i < len(chain); i++ {
+			if _, found := activations[i][rule]; !found {
+				break
+			}
+
+			activation.ActivatedOn = chain[i].Name
+		}
+
+		effective = append(effective, activation)
+	}
+
+	return effective
+}
+
+// impacts returns the impact severities of an activation by software quality.
+func impacts(active sonar.RulesActivation) map[string]string {
+	if len(active.Impacts) == 0 {
+		return nil
+	}
+
+	byQuality := make(map[string]string, len(active.Impacts))
+
+	for _, impact := range active.Impacts {
+		byQuality[impact.SoftwareQuality] = impact.Severity
+	}
+
+	return byQuality
+}
+
+// params returns the parameters of an activation by name.
+func params(active sonar.RulesActivation) map[string]string {
+	if len(active.Params) == 0 {
+		return nil
+	}
+
+	byKey := make(map[string]string, len(active.Params))
+
+	for _, param := range active.Params {
+		byKey[param.Key] = param.Value
+	}
+
+	return byKey
+}
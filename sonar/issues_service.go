@@ -470,13 +470,13 @@ type IssuesAddCommentOptions struct {
 // IssuesAnticipatedTransitionsOptions contains options for anticipated transitions.
 type IssuesAnticipatedTransitionsOptions struct {
 	// ProjectKey is the key of the project (required).
-	ProjectKey string `url:"projectKey,omitempty"`
+	ProjectKey string `url:"projectKey,omitempty" cli:"positional"`
 }
 
 // IssuesAssignOptions contains options for assigning an issue.
 type IssuesAssignOptions struct {
 	// Issue is the key of the issue to assign (required).
-	Issue string `url:"issue,omitempty"`
+	Issue string `url:"issue,omitempty" cli:"positional"`
 	// Assignee is the login of the assignee. When not set, it will unassign the issue.
 	// Use '_me' to assign to the current user.
 	Assignee string `url:"assignee,omitempty"`
@@ -522,7 +522,7 @@ type IssuesBulkChangeOptions struct {
 // IssuesChangelogOptions contains options for retrieving issue changelog.
 type IssuesChangelogOptions struct {
 	// Issue is the key of the issue (required).
-	Issue string `url:"issue,omitempty"`
+	Issue string `url:"issue,omitempty" cli:"positional"`
 }
 
 // IssuesComponentTagsOptions contains options for listing component tags.
@@ -538,7 +538,7 @@ type IssuesComponentTagsOptions struct {
 // IssuesDeleteCommentOptions contains options for deleting a comment.
 type IssuesDeleteCommentOptions struct {
 	// Comment is the key of the comment to delete (required).
-	Comment string `url:"comment,omitempty"`
+	Comment string `url:"comment,omitempty" cli:"positional"`
 }
 
 // IssuesDoTransitionOptions contains options for performing a transition.
@@ -587,7 +587,7 @@ type IssuesListOptions struct {
 //nolint:govet // Field alignment less important than maintaining consistent field order for readability
 type IssuesPullOptions struct {
 	// ProjectKey is the project key (required).
-	ProjectKey string `url:"projectKey,omitempty"`
+	ProjectKey string `url:"projectKey,omitempty" cli:"positional"`
 	// BranchName is the branch name to fetch issues for.
 	BranchName string `url:"branchName,omitempty"`
 	// Languages is the list of languages to filter by.
@@ -605,7 +605,7 @@ type IssuesPullOptions struct {
 //nolint:govet // Field alignment less important than maintaining consistent field order for readability
 type IssuesPullTaintOptions struct {
 	// ProjectKey is the project key (required).
-	ProjectKey string `url:"projectKey,omitempty"`
+	ProjectKey string `url:"projectKey,omitempty" cli:"positional"`
 	// BranchName is the branch name to fetch taint vulnerabilities for.
 	BranchName string `url:"branchName,omitempty"`
 	// Languages is the list of languages to filter by.
@@ -617,7 +617,7 @@ type IssuesPullTaintOptions struct {
 // IssuesReindexOptions contains options for reindexing issues.
 type IssuesReindexOptions struct {
 	// Project is the project key (required).
-	Project string `url:"project,omitempty"`
+	Project string `url:"project,omitempty" cli:"positional"`
 }
 
 // IssuesSearchOptions contains options for searching issues.
@@ -757,7 +757,7 @@ type IssuesSearchOptions struct {
 // IssuesSetSeverityOptions contains options for setting severity.
 type IssuesSetSeverityOptions struct {
 	// Issue is the key of the issue (required).
-	Issue string `url:"issue,omitempty"`
+	Issue string `url:"issue,omitempty" cli:"positional"`
 	// Severity is the new severity level.
 	// Allowed values: BLOCKER, CRITICAL, MAJOR, MINOR, INFO
 	Severity string `url:"severity,omitempty"`
@@ -768,7 +768,7 @@ type IssuesSetSeverityOptions struct {
 // IssuesSetTagsOptions contains options for setting tags.
 type IssuesSetTagsOptions struct {
 	// Issue is the key of the issue (required).
-	Issue string `url:"issue,omitempty"`
+	Issue string `url:"issue,omitempty" cli:"positional"`
 	// Tags is the list of tags to set. Empty list removes all tags.
 	Tags []string `url:"tags,omitempty,comma"`
 }
@@ -0,0 +1,34 @@
+// Package migrate copies configuration from one SonarQube instance to another, such as
+// from a 9.9 LTS server to a 2025 one.
+//
+// A Migrator copies, in order, user groups and their members, custom quality profiles
+// (restored from their backups, parents first), custom quality gates and their
+// conditions, permission templates and their grants, then the settings, quality gate
+// and quality profiles of each project found on both instances. Users and projects are
+// not created.
+//
+// Objects are matched by what identifies them on both instances rather than by key:
+// gates, groups and templates by name, profiles by language and name. The keys and IDs
+// the target gives migrated profiles and templates are returned as Mappings.
+//
+// What the target cannot hold is skipped rather than failed: profiles of languages it
+// does not support, gate conditions on metrics it does not have, default templates for
+// qualifiers its edition lacks, and grants to users or groups it does not know.
+//
+// Every object is recorded in a Log with its status. A log kept in a file with OpenLog
+// makes a migration resumable: running it again skips the objects already done or
+// skipped and retries the failed ones.
+//
+//	log, err := migrate.OpenLog("migration.log")
+//	if err != nil {
+//		return err
+//	}
+//	defer log.Close()
+//
+//	migrator, err := migrate.New(source, target, migrate.Selection{}, log)
+//	if err != nil {
+//		return err
+//	}
+//
+//	result, err := migrator.Run(ctx)
+package migrate
@@ -0,0 +1,162 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// migrateQualityGates copies the custom quality gates of the source and their
+// conditions. Gates are matched by name; built-in gates are left to the target.
+func (m *Migrator) migrateQualityGates(ctx context.Context) error {
+	if !m.kindSelected(KindQualityGate) {
+		return nil
+	}
+
+	list, _, err := m.source.Qualitygates.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the quality gates of the source: %w", err)
+	}
+
+	existing, _, err := m.target.Qualitygates.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the quality gates of the target: %w", err)
+	}
+
+	onTarget := make(map[string]bool, len(existing.Qualitygates))
+
+	for _, gate := range existing.Qualitygates {
+		onTarget[gate.Name] = true
+	}
+
+	for _, gate := range list.Qualitygates {
+		if gate.IsBuiltIn {
+			continue
+		}
+
+		err = m.migrate(ctx, KindQualityGate, gate.Name, "", func(ctx context.Context) (*outcome, error) {
+			return m.copyQualityGate(ctx, gate, onTarget[gate.Name])
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyQualityGate creates a gate on the target, unless it exists, and makes its
+// conditions those of the source. Conditions on metrics the target does not have are
+// left out.
+func (m *Migrator) copyQualityGate(ctx context.Context, gate sonar.QualityGate, exists bool) (*outcome, error) {
+	source, _, err := m.source.Qualitygates.Show(ctx, &sonar.QualitygatesShowOptions{Name: gate.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the gate: %w", err)
+	}
+
+	var current []sonar.QualityGateCondition
+
+	if exists {
+		target, _, err := m.target.Qualitygates.Show(ctx, &sonar.QualitygatesShowOptions{Name: gate.Name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the gate on the target: %w", err)
+		}
+
+		current = target.Conditions
+	} else {
+		_, _, err = m.target.Qualitygates.Create(ctx, &sonar.QualitygatesCreateOptions{Name: gate.Name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the gate: %w", err)
+		}
+	}
+
+	var desired []sonar.QualityGateCondition
+
+	var unknown []string
+
+	for _, condition := range source.Conditions {
+		if m.metrics[condition.Metric] {
+			desired = append(desired, condition)
+		} else {
+			unknown = append(unknown, condition.Metric)
+		}
+	}
+
+	err = m.syncConditions(ctx, gate.Name, current, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if gate.IsDefault {
+		_, err = m.target.Qualitygates.SetDefault(ctx, &sonar.QualitygatesSetDefaultOptions{Name: gate.Name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to make the gate the default: %w", err)
+		}
+	}
+
+	result := done("")
+	if len(unknown) > 0 {
+		result.detail = "conditions on metrics missing on the target left out: " + strings.Join(unknown, ", ")
+	}
+
+	return result, nil
+}
+
+// syncConditions deletes the conditions of a target gate that the source gate does not
+// have, then updates and creates the others, so that the gate never holds two
+// conditions on one metric.
+func (m *Migrator) syncConditions(ctx context.Context, gateName string, current []sonar.QualityGateCondition, desired []sonar.QualityGateCondition) error {
+	gates := m.target.Qualitygates
+	wanted := make(map[string]bool, len(desired))
+
+	for _, condition := range desired {
+		wanted[condition.Metric] = true
+	}
+
+	byMetric := make(map[string]sonar.QualityGateCondition, len(current))
+
+	for _, condition := range current {
+		byMetric[condition.Metric] = condition
+
+		if wanted[condition.Metric] {
+			continue
+		}
+
+		_, err := gates.DeleteCondition(ctx, &sonar.QualitygatesDeleteConditionOptions{ID: condition.ID})
+		if err != nil {
+			return fmt.Errorf("failed to delete the condition on %s: %w", condition.Metric, err)
+		}
+	}
+
+	for _, condition := range desired {
+		metric := condition.Metric
+		existing, found := byMetric[metric]
+
+		switch {
+		case !found:
+			_, _, err := gates.CreateCondition(ctx, &sonar.QualitygatesCreateConditionOptions{
+				GateName: gateName,
+				Metric:   metric,
+				Op:       condition.Op,
+				Error:    condition.Error,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create the condition on %s: %w", metric, err)
+			}
+		case existing.Op != condition.Op || existing.Error != condition.Error:
+			_, err := gates.UpdateCondition(ctx, &sonar.QualitygatesUpdateConditionOptions{
+				ID:     existing.ID,
+				Metric: metric,
+				Op:     condition.Op,
+				Error:  condition.Error,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update the condition on %s: %w", metric, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// migrateGroups copies the groups of the source and their members. Users are not
+// migrated: members without an account on the target are left out.
+func (m *Migrator) migrateGroups(ctx context.Context) error {
+	if !m.kindSelected(KindGroup) {
+		return nil
+	}
+
+	groups, _, err := m.source.UserGroups.SearchAll(ctx, &sonar.UserGroupsSearchOptions{}) //nolint:exhaustruct // every group
+	if err != nil {
+		return fmt.Errorf("failed to search the groups of the source: %w", err)
+	}
+
+	existing, _, err := m.target.UserGroups.SearchAll(ctx, &sonar.UserGroupsSearchOptions{}) //nolint:exhaustruct // every group
+	if err != nil {
+		return fmt.Errorf("failed to search the groups of the target: %w", err)
+	}
+
+	onTarget := make(map[string]sonar.UserGroupsDetail, len(existing))
+
+	for _, group := range existing {
+		onTarget[group.Name] = group
+	}
+
+	for _, group := range groups {
+		err = m.migrate(ctx, KindGroup, group.Name, "", func(ctx context.Context) (*outcome, error) {
+			return m.copyGroup(ctx, group, onTarget)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyGroup creates a group on the target, unless it exists, and adds its members.
+func (m *Migrator) copyGroup(ctx context.Context, group sonar.UserGroupsDetail, onTarget map[string]sonar.UserGroupsDetail) (*outcome, error) {
+	switch {
+	case group.Default:
+		return skipped("the default group holds every user"), nil
+	case group.Managed:
+		return skipped("managed by an identity provider"), nil
+	case onTarget[group.Name].Managed:
+		return skipped("managed by an identity provider on the target"), nil
+	}
+
+	if _, found := onTarget[group.Name]; !found {
+		_, _, err := m.target.UserGroups.Create(ctx, &sonar.UserGroupsCreateOptions{Name: group.Name, Description: group.Description})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the group: %w", err)
+		}
+	}
+
+	members, _, err := m.source.UserGroups.UsersAll(ctx, &sonar.UserGroupsUsersOptions{ //nolint:exhaustruct // every member
+		Name:     group.Name,
+		Selected: "selected",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the members of the group: %w", err)
+	}
+
+	missing := 0
+
+	for _, member := range members {
+		_, err = m.target.UserGroups.AddUser(ctx, &sonar.UserGroupsAddUserOptions{Name: group.Name, Login: member.Login})
+
+		switch {
+		case sonar.IsNotFound(err):
+			missing++
+		case err != nil:
+			return nil, fmt.Errorf("failed to add %q to the group: %w", member.Login, err)
+		}
+	}
+
+	result := done("")
+	if missing > 0 {
+		result.detail = fmt.Sprintf("%d member(s) without an account on the target", missing)
+	}
+
+	return result, nil
+}
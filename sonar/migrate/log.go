@@ -0,0 +1,193 @@
+package migrate
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of the migration of an object.
+type Status string
+
+const (
+	// StatusDone is the status of an object copied to the target.
+	StatusDone Status = "done"
+	// StatusSkipped is the status of an object the target cannot hold, such as a profile
+	// of a language the target does not support.
+	StatusSkipped Status = "skipped"
+	// StatusFailed is the status of an object whose migration failed. It is retried when
+	// the migration is resumed.
+	StatusFailed Status = "failed"
+)
+
+// logFilePermissions are the permissions of the log files OpenLog creates.
+const logFilePermissions = 0o600
+
+// Entry records the migration of an object.
+type Entry struct {
+	// Kind is the kind of the object.
+	Kind Kind `json:"kind"`
+	// Name identifies the object, such as "java/Company Java" for a quality profile.
+	Name string `json:"name"`
+	// Status is the outcome of the migration.
+	Status Status `json:"status"`
+	// SourceKey is the key or ID of the object on the source, if it has one.
+	SourceKey string `json:"sourceKey,omitempty"`
+	// TargetKey is the key or ID of the object on the target, if it has one.
+	TargetKey string `json:"targetKey,omitempty"`
+	// Detail explains a skipped or failed migration, or what a migration left out.
+	Detail string `json:"detail,omitempty"`
+	// Time is the time the entry was recorded.
+	Time time.Time `json:"time"`
+}
+
+// Log records the status of each migrated object, so that an interrupted or partly
+// failed migration can be resumed. Every entry is appended to the writer of the log as
+// a JSON line as soon as it is recorded.
+type Log struct {
+	mu      sync.Mutex
+	writer  io.Writer
+	closer  io.Closer
+	entries map[logKey]Entry
+	order   []logKey
+}
+
+// logKey identifies an object in a log.
+type logKey struct {
+	kind Kind
+	name string
+}
+
+// NewLog returns an empty log appending its entries to writer, which may be nil.
+func NewLog(writer io.Writer) *Log {
+	return &Log{writer: writer, closer: nil, entries: map[logKey]Entry{}, order: nil} //nolint:exhaustruct // zero mutex
+}
+
+// ReadLog returns a log holding the entries of a previous migration, read from reader,
+// and appending new entries to writer, which may be nil.
+func ReadLog(reader io.Reader, writer io.Writer) (*Log, error) {
+	log := NewLog(nil)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, bufio.MaxScanTokenSize*16) //nolint:mnd // room for long details
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration log line %d: %w", line, err)
+		}
+
+		err = log.record(entry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration log: %w", err)
+	}
+
+	log.writer = writer
+
+	return log, nil
+}
+
+// OpenLog returns the log stored in the file at path, creating the file if needed. New
+// entries are appended to the file; Close closes it.
+func OpenLog(path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, logFilePermissions) //nolint:gosec // the caller chooses the log file
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migration log: %w", err)
+	}
+
+	log, err := ReadLog(file, file)
+	if err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+
+	log.closer = file
+
+	return log, nil
+}
+
+// Close closes the file of a log opened with OpenLog.
+func (l *Log) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+
+	err := l.closer.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close migration log: %w", err)
+	}
+
+	return nil
+}
+
+// Entries returns the latest entry of each object, in the order the objects were first
+// recorded.
+func (l *Log) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]Entry, 0, len(l.order))
+
+	for _, key := range l.order {
+		entries = append(entries, l.entries[key])
+	}
+
+	return entries
+}
+
+// Lookup returns the latest entry of an object.
+func (l *Log) Lookup(kind Kind, name string) (Entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, found := l.entries[logKey{kind: kind, name: name}]
+
+	return entry, found
+}
+
+// record stores an entry and appends it to the writer of the log.
+func (l *Log) record(entry Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := logKey{kind: entry.Kind, name: entry.Name}
+	if _, found := l.entries[key]; !found {
+		l.order = append(l.order, key)
+	}
+
+	l.entries[key] = entry
+
+	if l.writer == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode migration log entry: %w", err)
+	}
+
+	_, err = l.writer.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write migration log: %w", err)
+	}
+
+	return nil
+}
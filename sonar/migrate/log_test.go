@@ -0,0 +1,44 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "migration.log")
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	log, err := OpenLog(path)
+	require.NoError(t, err)
+	require.NoError(t, log.record(Entry{Kind: KindQualityGate, Name: "Strict", Status: StatusFailed, Detail: "boom", Time: at}))
+	require.NoError(t, log.record(Entry{Kind: KindGroup, Name: "developers", Status: StatusDone, Time: at}))
+	require.NoError(t, log.Close())
+
+	log, err = OpenLog(path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = log.Close() })
+
+	require.NoError(t, log.record(Entry{Kind: KindQualityGate, Name: "Strict", Status: StatusDone, Time: at}))
+
+	assert.Equal(t, []Entry{
+		{Kind: KindQualityGate, Name: "Strict", Status: StatusDone, Time: at},
+		{Kind: KindGroup, Name: "developers", Status: StatusDone, Time: at},
+	}, log.Entries(), "the latest entry of each object, in first-seen order")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 3, "entries are appended")
+}
+
+func TestReadLog_Invalid(t *testing.T) {
+	_, err := ReadLog(strings.NewReader(`{"kind": "group", "name": "a", "status": "done"}`+"\n\nnot json\n"), nil)
+	require.ErrorContains(t, err, "invalid migration log line 3")
+}
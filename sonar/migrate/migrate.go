@@ -0,0 +1,287 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// Kind is a kind of object a migration copies.
+type Kind string
+
+const (
+	// KindGroup is a user group and its members, identified by name.
+	KindGroup Kind = "group"
+	// KindQualityProfile is a custom quality profile, identified by "language/name".
+	KindQualityProfile Kind = "quality_profile"
+	// KindQualityGate is a custom quality gate and its conditions, identified by name.
+	KindQualityGate Kind = "quality_gate"
+	// KindPermissionTemplate is a permission template and its grants, identified by name.
+	KindPermissionTemplate Kind = "permission_template"
+	// KindProjectSettings are the settings set on a project, identified by project key.
+	KindProjectSettings Kind = "project_settings"
+	// KindProjectQualityGate is the quality gate assigned to a project, identified by
+	// project key.
+	KindProjectQualityGate Kind = "project_quality_gate"
+	// KindProjectQualityProfile is the quality profile a project uses for a language,
+	// identified by "project/language".
+	KindProjectQualityProfile Kind = "project_quality_profile"
+)
+
+// ErrIncomplete is returned by Migrator.Run when some objects failed to migrate. Running
+// the migration again with the same log retries them.
+var ErrIncomplete = errors.New("migration incomplete")
+
+// errNotOnTarget is returned when an object the migration wrote cannot be found on the
+// target.
+var errNotOnTarget = errors.New("not found on the target")
+
+// Selection chooses the objects a migration copies.
+type Selection struct {
+	// Kinds are the kinds of objects to copy. Empty means every kind.
+	Kinds []Kind
+	// Projects are the keys of the projects whose settings and associations are copied.
+	// Empty means every project of the source.
+	Projects []string
+	// Filter, if set, is called with the kind and name of each object and copies only
+	// those it returns true for.
+	Filter func(kind Kind, name string) bool
+}
+
+// Result summarizes a migration run.
+type Result struct {
+	// Done is the number of objects copied by this run.
+	Done int `json:"done"`
+	// Skipped is the number of objects the target cannot hold.
+	Skipped int `json:"skipped"`
+	// Failed is the number of objects that failed to migrate.
+	Failed int `json:"failed"`
+	// Resumed is the number of objects a previous run already migrated or skipped.
+	Resumed int `json:"resumed"`
+	// Mappings map source keys and IDs to target ones.
+	Mappings Mappings `json:"mappings"`
+}
+
+// Mappings map the keys and IDs of objects on the source to those on the target.
+// Quality gates and groups are identified by name on both instances and need none.
+type Mappings struct {
+	// QualityProfiles maps source profile keys to target profile keys.
+	QualityProfiles map[string]string `json:"qualityProfiles"`
+	// PermissionTemplates maps source template IDs to target template IDs.
+	PermissionTemplates map[string]string `json:"permissionTemplates"`
+}
+
+// Migrator copies configuration from a source instance to a target instance.
+type Migrator struct {
+	source    *sonar.Client
+	target    *sonar.Client
+	selection Selection
+	log       *Log
+	result    *Result
+
+	// languages, metrics and qualifiers are those the target supports.
+	languages  map[string]bool
+	metrics    map[string]bool
+	qualifiers []string
+}
+
+// outcome is the outcome of copying one object.
+type outcome struct {
+	status    Status
+	targetKey string
+	detail    string
+}
+
+// done is the outcome of an object copied without remarks.
+func done(targetKey string) *outcome {
+	return &outcome{status: StatusDone, targetKey: targetKey, detail: ""}
+}
+
+// skipped is the outcome of an object the target cannot hold.
+func skipped(format string, args ...any) *outcome {
+	return &outcome{status: StatusSkipped, targetKey: "", detail: fmt.Sprintf(format, args...)}
+}
+
+// New returns a migrator copying the selected objects from source to target and
+// recording them in log. A nil log keeps the entries in memory only.
+func New(source, target *sonar.Client, selection Selection, log *Log) (*Migrator, error) {
+	if source == nil {
+		return nil, sonar.NewValidationError("source", "is required", sonar.ErrMissingRequired)
+	}
+
+	if target == nil {
+		return nil, sonar.NewValidationError("target", "is required", sonar.ErrMissingRequired)
+	}
+
+	if log == nil {
+		log = NewLog(nil)
+	}
+
+	return &Migrator{ //nolint:exhaustruct // capabilities are read by Run
+		source:    source,
+		target:    target,
+		selection: selection,
+		log:       log,
+	}, nil
+}
+
+// Log returns the log of the migrator.
+func (m *Migrator) Log() *Log {
+	return m.log
+}
+
+// Run copies the selected objects: groups, quality profiles (parents first), quality
+// gates and permission templates, then the settings, quality gate and quality profiles
+// of each project found on both instances. Objects the log records as done or skipped
+// are not copied again. A failed object is recorded and the migration goes on; Run then
+// returns ErrIncomplete along with the result. Other errors, such as a source that
+// cannot be listed, stop the migration.
+func (m *Migrator) Run(ctx context.Context) (*Result, error) {
+	m.result = &Result{ //nolint:exhaustruct // counters start at zero
+		Mappings: Mappings{QualityProfiles: map[string]string{}, PermissionTemplates: map[string]string{}},
+	}
+
+	err := m.readCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []func(context.Context) error{
+		m.migrateGroups,
+		m.migrateQualityProfiles,
+		m.migrateQualityGates,
+		m.migratePermissionTemplates,
+		m.migrateProjects,
+	}
+
+	for _, step := range steps {
+		err = step(ctx)
+		if err != nil {
+			return m.result, err
+		}
+	}
+
+	if m.result.Failed > 0 {
+		return m.result, fmt.Errorf("%w: %d object(s) failed", ErrIncomplete, m.result.Failed)
+	}
+
+	return m.result, nil
+}
+
+// readCapabilities reads the languages, metrics and qualifiers the target supports.
+func (m *Migrator) readCapabilities(ctx context.Context) error {
+	languages, _, err := m.target.Languages.List(ctx, &sonar.LanguagesListOptions{Query: "", PageSize: 0})
+	if err != nil {
+		return fmt.Errorf("failed to list the languages of the target: %w", err)
+	}
+
+	m.languages = make(map[string]bool, len(languages.Languages))
+
+	for _, language := range languages.Languages {
+		m.languages[language.Key] = true
+	}
+
+	metrics, _, err := m.target.Metrics.SearchAll(ctx, &sonar.MetricsSearchOptions{}) //nolint:exhaustruct // every metric
+	if err != nil {
+		return fmt.Errorf("failed to list the metrics of the target: %w", err)
+	}
+
+	m.metrics = make(map[string]bool, len(metrics))
+
+	for _, metric := range metrics {
+		m.metrics[metric.Key] = true
+	}
+
+	global, _, err := m.target.Navigation.Global(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the target: %w", err)
+	}
+
+	m.qualifiers = global.Qualifiers
+
+	return nil
+}
+
+// kindSelected reports whether the selection includes a kind of objects.
+func (m *Migrator) kindSelected(kind Kind) bool {
+	return len(m.selection.Kinds) == 0 || slices.Contains(m.selection.Kinds, kind)
+}
+
+// selected reports whether the selection includes an object.
+func (m *Migrator) selected(kind Kind, name string) bool {
+	return m.kindSelected(kind) && (m.selection.Filter == nil || m.selection.Filter(kind, name))
+}
+
+// migrate copies one selected object, unless the log records it as done or skipped,
+// and records the outcome. Only a cancelled context or a failure to write the log stops
+// the migration.
+func (m *Migrator) migrate(ctx context.Context, kind Kind, name, sourceKey string, copyObject func(context.Context) (*outcome, error)) error {
+	if !m.selected(kind, name) {
+		return nil
+	}
+
+	previous, found := m.log.Lookup(kind, name)
+	if found && previous.Status != StatusFailed {
+		m.result.Resumed++
+		m.remember(previous)
+
+		return nil
+	}
+
+	result, err := copyObject(ctx)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("migration interrupted: %w", ctxErr)
+	}
+
+	if err != nil {
+		result = &outcome{status: StatusFailed, targetKey: "", detail: err.Error()}
+	}
+
+	entry := Entry{
+		Kind:      kind,
+		Name:      name,
+		Status:    result.status,
+		SourceKey: sourceKey,
+		TargetKey: result.targetKey,
+		Detail:    result.detail,
+		Time:      time.Now().UTC(),
+	}
+
+	switch entry.Status {
+	case StatusDone:
+		m.result.Done++
+	case StatusSkipped:
+		m.result.Skipped++
+	case StatusFailed:
+		m.result.Failed++
+	}
+
+	m.remember(entry)
+
+	return m.log.record(entry)
+}
+
+// remember adds the key mapping of a migrated object to the result.
+func (m *Migrator) remember(entry Entry) {
+	if entry.Status != StatusDone || entry.SourceKey == "" || entry.TargetKey == "" {
+		return
+	}
+
+	switch entry.Kind {
+	case KindQualityProfile:
+		m.result.Mappings.QualityProfiles[entry.SourceKey] = entry.TargetKey
+	case KindPermissionTemplate:
+		m.result.Mappings.PermissionTemplates[entry.SourceKey] = entry.TargetKey
+	default:
+	}
+}
+
+// joinDetails joins the remarks on a migrated object.
+func joinDetails(details []string) string {
+	return strings.Join(details, "; ")
+}
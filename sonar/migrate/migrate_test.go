@@ -0,0 +1,313 @@
+package migrate
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/testutil"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sourceResponses are the responses of the source server, by path and, for endpoints
+// called once per object, by the value of the identifying parameter.
+//
+//nolint:gochecknoglobals // test fixture
+var sourceResponses = map[string]string{
+	"user_groups/search": `{"paging": {"total": 2}, "groups": [
+		{"name": "developers", "description": "Devs"}, {"name": "sonar-users", "default": true}]}`,
+	"user_groups/users?name=developers": `{"paging": {"total": 2}, "users": [{"login": "alice"}, {"login": "ghost"}]}`,
+	"qualityprofiles/search": `{"profiles": [
+		{"key": "S0", "name": "Sonar way", "language": "java", "isBuiltIn": true},
+		{"key": "S2", "name": "Strict Company", "language": "java", "parentName": "Company"},
+		{"key": "S1", "name": "Company", "language": "java", "parentName": "Sonar way", "isDefault": true},
+		{"key": "S3", "name": "Cobol Rules", "language": "cobol"}]}`,
+	"qualityprofiles/backup?qualityProfile=Company":        `<profile><name>Company</name></profile>`,
+	"qualityprofiles/backup?qualityProfile=Strict Company": `<profile><name>Strict Company</name></profile>`,
+	"qualitygates/list": `{"qualitygates": [
+		{"name": "Sonar way", "isBuiltIn": true}, {"name": "Strict", "isDefault": true}]}`,
+	"qualitygates/show?name=Strict": `{"name": "Strict", "conditions": [
+		{"id": "1", "metric": "new_coverage", "op": "LT", "error": "80"},
+		{"id": "2", "metric": "new_violations", "op": "GT", "error": "0"},
+		{"id": "3", "metric": "new_security_hotspots_reviewed", "op": "LT", "error": "100"}]}`,
+	"permissions/search_templates": `{
+		"defaultTemplates": [{"templateId": "T1", "qualifier": "TRK"}, {"templateId": "T1", "qualifier": "VW"}],
+		"permissionTemplates": [{"id": "T1", "name": "Default template",
+			"permissions": [{"key": "admin", "withProjectCreator": true}]}]}`,
+	"permissions/template_groups?templateName=Default template": `{"paging": {"total": 1}, "groups": [
+		{"name": "developers", "permissions": ["user", "codeviewer"]}]}`,
+	"permissions/template_users?templateName=Default template": `{"paging": {"total": 1}, "users": [
+		{"login": "ghost", "permissions": ["user"]}]}`,
+	"projects/search?qualifiers=TRK": `{"paging": {"total": 2}, "components": [{"key": "app"}, {"key": "gone"}]}`,
+	"settings/values?component=app": `{"setSecuredSettings": ["sonar.token.secured"], "settings": [
+		{"key": "sonar.exclusions", "values": ["**/gen/**"]},
+		{"key": "sonar.scm.provider", "value": "git", "inherited": true},
+		{"key": "sonar.issue.ignore.multicriteria", "fieldValues": [{"ruleKey": "*"}]}]}`,
+	"qualitygates/get_by_project?project=app":  `{"qualityGate": {"name": "Legacy"}}`,
+	"qualitygates/get_by_project?project=gone": `{"qualityGate": {"name": "Strict", "default": true}}`,
+	"qualityprofiles/search?project=app": `{"profiles": [
+		{"key": "S2", "name": "Strict Company", "language": "java"},
+		{"key": "P1", "name": "Sonar way", "language": "py", "isDefault": true}]}`,
+	"qualityprofiles/search?project=gone": `{"profiles": []}`,
+}
+
+// targetResponses are the responses of the target server.
+//
+//nolint:gochecknoglobals // test fixture
+var targetResponses = map[string]string{
+	"languages/list":     `{"languages": [{"key": "java"}, {"key": "py"}]}`,
+	"metrics/search":     `{"paging": {"total": 2}, "metrics": [{"key": "new_coverage"}, {"key": "new_violations"}]}`,
+	"navigation/global":  `{"version": "2025.1", "edition": "developer", "qualifiers": ["TRK"]}`,
+	"user_groups/search": `{"paging": {"total": 1}, "groups": [{"name": "sonar-users", "default": true}]}`,
+	"qualityprofiles/search?qualityProfile=Company":        `{"profiles": [{"key": "TP1", "name": "Company", "language": "java"}]}`,
+	"qualityprofiles/search?qualityProfile=Strict Company": `{"profiles": [{"key": "TP2", "name": "Strict Company", "language": "java"}]}`,
+	"qualitygates/list": `{"qualitygates": [{"name": "Sonar way", "isBuiltIn": true, "isDefault": true}, {"name": "Strict"}]}`,
+	"qualitygates/show?name=Strict": `{"name": "Strict", "conditions": [
+		{"id": "C1", "metric": "new_coverage", "op": "LT", "error": "90"},
+		{"id": "C2", "metric": "new_duplicated_lines_density", "op": "GT", "error": "3"}]}`,
+	"permissions/search_templates":                      `{}`,
+	"permissions/create_template?name=Default template": `{"permissionTemplate": {"id": "TT1", "name": "Default template"}}`,
+	"projects/search?qualifiers=TRK":                    `{"paging": {"total": 1}, "components": [{"key": "app"}]}`,
+}
+
+// identifyingParams are the parameters that select the response of the test servers.
+//
+//nolint:gochecknoglobals // test fixture
+var identifyingParams = []string{"login", "name", "qualityProfile", "templateName", "project", "component", "qualifiers"}
+
+// fakeServer is a SonarQube server answering from a map of responses and recording the
+// POST requests it receives.
+type fakeServer struct {
+	mu        sync.Mutex
+	responses map[string]string
+	statuses  map[string]int
+	calls     []string
+}
+
+// newFakeServer returns a server answering with responses and, for the paths in
+// statuses, with the given status code.
+func newFakeServer(t *testing.T, responses map[string]string, statuses map[string]int) (*fakeServer, *sonar.Client) {
+	t.Helper()
+
+	fake := &fakeServer{responses: responses, statuses: statuses}
+	client := testutil.NewServerClient(t, http.HandlerFunc(fake.serve))
+
+	return fake, client
+}
+
+// serve answers a request.
+func (f *fakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/")
+	query := r.URL.Query()
+	key := testutil.ResponseKey(r, identifyingParams...)
+
+	f.mu.Lock()
+	status := f.statuses[key]
+
+	if r.Method == http.MethodPost {
+		params := make([]string, 0, len(query))
+
+		for name := range query {
+			params = append(params, name+"="+strings.Join(query[name], ","))
+		}
+
+		slices.Sort(params)
+		f.calls = append(f.calls, path+" "+strings.Join(params, "&"))
+	}
+	f.mu.Unlock()
+
+	body, found := f.responses[key]
+
+	switch {
+	case status != 0:
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"errors": [{"msg": "failed"}]}`))
+	case found:
+		_, _ = w.Write([]byte(body))
+	case r.Method == http.MethodPost:
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		testutil.NotFound(w, key)
+	}
+}
+
+// posted returns the POST requests received so far.
+func (f *fakeServer) posted() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.calls)
+}
+
+// missingOnTarget are the statuses of the target for the user the target does not know.
+//
+//nolint:gochecknoglobals // test fixture
+var missingOnTarget = map[string]int{
+	"user_groups/add_user?login=ghost":             http.StatusNotFound,
+	"permissions/add_user_to_template?login=ghost": http.StatusNotFound,
+}
+
+// statusesWith returns missingOnTarget and the given statuses.
+func statusesWith(statuses map[string]int) map[string]int {
+	merged := map[string]int{}
+
+	for key, status := range missingOnTarget {
+		merged[key] = status
+	}
+
+	for key, status := range statuses {
+		merged[key] = status
+	}
+
+	return merged
+}
+
+// details returns the status and detail of each log entry, by kind and name.
+func details(entries []Entry) map[string]string {
+	described := make(map[string]string, len(entries))
+
+	for _, entry := range entries {
+		described[string(entry.Kind)+" "+entry.Name] = strings.TrimSpace(string(entry.Status) + " " + entry.Detail)
+	}
+
+	return described
+}
+
+func TestMigrator_Run(t *testing.T) {
+	_, source := newFakeServer(t, sourceResponses, nil)
+	target, targetClient := newFakeServer(t, targetResponses, statusesWith(nil))
+
+	migrator, err := New(source, targetClient, Selection{}, nil)
+	require.NoError(t, err)
+
+	result, err := migrator.Run(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, &Result{
+		Done:    8,
+		Skipped: 3,
+		Mappings: Mappings{
+			QualityProfiles:     map[string]string{"S1": "TP1", "S2": "TP2"},
+			PermissionTemplates: map[string]string{"T1": "TT1"},
+		},
+	}, result)
+
+	assert.Equal(t, map[string]string{
+		"group developers":                     "done 1 member(s) without an account on the target",
+		"group sonar-users":                    "skipped the default group holds every user",
+		"quality_profile cobol/Cobol Rules":    `skipped language "cobol" is not available on the target`,
+		"quality_profile java/Company":         "done",
+		"quality_profile java/Strict Company":  "done",
+		"quality_gate Strict":                  "done conditions on metrics missing on the target left out: new_security_hotspots_reviewed",
+		"permission_template Default template": "done 1 grant(s) to groups or users missing on the target left out; not made the default for qualifiers missing on the target: VW",
+		"project_settings app":                 "done 1 property set setting(s) left out; 1 secured setting(s) left out",
+		"project_settings gone":                "skipped project missing on the target",
+		"project_quality_gate app":             "done",
+		"project_quality_profile app/java":     "done",
+	}, details(migrator.Log().Entries()))
+
+	posted := target.posted()
+	assert.Equal(t, []string{
+		"user_groups/create description=Devs&name=developers",
+		"user_groups/add_user login=alice&name=developers",
+		"user_groups/add_user login=ghost&name=developers",
+	}, posted[:3])
+	assert.Subset(t, posted, []string{
+		"qualityprofiles/change_parent language=java&parentQualityProfile=Sonar way&qualityProfile=Company",
+		"qualityprofiles/set_default language=java&qualityProfile=Company",
+		"qualityprofiles/change_parent language=java&parentQualityProfile=Company&qualityProfile=Strict Company",
+		"qualitygates/delete_condition id=C2",
+		"qualitygates/update_condition error=80&id=C1&metric=new_coverage&op=LT",
+		"qualitygates/create_condition error=0&gateName=Strict&metric=new_violations&op=GT",
+		"permissions/add_group_to_template groupName=developers&permission=codeviewer&templateId=TT1",
+		"permissions/add_project_creator_to_template permission=admin&templateId=TT1",
+		"permissions/set_default_template qualifier=TRK&templateId=TT1",
+		"settings/set component=app&key=sonar.exclusions&values=**/gen/**",
+		"qualitygates/select gateName=Legacy&projectKey=app",
+		"qualityprofiles/add_project language=java&project=app&qualityProfile=Strict Company",
+	})
+	assert.NotContains(t, strings.Join(posted, "\n"), "Cobol", "profiles of unsupported languages are not restored")
+	assert.NotContains(t, strings.Join(posted, "\n"), "qualitygates/create ", "existing gates are updated")
+}
+
+func TestMigrator_Resume(t *testing.T) {
+	_, source := newFakeServer(t, sourceResponses, nil)
+	_, failing := newFakeServer(t, targetResponses, statusesWith(map[string]int{
+		"qualitygates/create_condition": http.StatusInternalServerError,
+	}))
+
+	var written bytes.Buffer
+
+	migrator, err := New(source, failing, Selection{}, NewLog(&written))
+	require.NoError(t, err)
+
+	result, err := migrator.Run(t.Context())
+	require.ErrorIs(t, err, ErrIncomplete)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 7, result.Done)
+
+	entry, found := migrator.Log().Lookup(KindQualityGate, "Strict")
+	require.True(t, found)
+	assert.Equal(t, StatusFailed, entry.Status)
+	assert.Contains(t, entry.Detail, "failed to create the condition on new_violations")
+
+	log, err := ReadLog(bytes.NewReader(written.Bytes()), nil)
+	require.NoError(t, err)
+
+	target, targetClient := newFakeServer(t, targetResponses, statusesWith(nil))
+
+	migrator, err = New(source, targetClient, Selection{}, log)
+	require.NoError(t, err)
+
+	result, err = migrator.Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Done)
+	assert.Equal(t, 10, result.Resumed)
+	assert.Equal(t, map[string]string{"S1": "TP1", "S2": "TP2"}, result.Mappings.QualityProfiles, "mappings are restored from the log")
+
+	for _, call := range target.posted() {
+		assert.True(t, strings.HasPrefix(call, "qualitygates/"), "only the failed gate is migrated again: %s", call)
+	}
+
+	entry, _ = log.Lookup(KindQualityGate, "Strict")
+	assert.Equal(t, StatusDone, entry.Status)
+}
+
+func TestMigrator_Selection(t *testing.T) {
+	_, source := newFakeServer(t, sourceResponses, nil)
+	target, targetClient := newFakeServer(t, targetResponses, statusesWith(nil))
+
+	migrator, err := New(source, targetClient, Selection{
+		Kinds:  []Kind{KindQualityProfile, KindProjectQualityProfile},
+		Filter: func(_ Kind, name string) bool { return !strings.HasSuffix(name, "Strict Company") },
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = migrator.Run(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"quality_profile cobol/Cobol Rules": `skipped language "cobol" is not available on the target`,
+		"quality_profile java/Company":      "done",
+		"project_quality_profile app/java":  "done",
+	}, details(migrator.Log().Entries()))
+
+	for _, call := range target.posted() {
+		assert.True(t, strings.HasPrefix(call, "qualityprofiles/"), "only profiles are migrated: %s", call)
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, client := newFakeServer(t, targetResponses, nil)
+
+	_, err := New(nil, client, Selection{}, nil)
+	require.ErrorIs(t, err, sonar.ErrMissingRequired)
+
+	_, err = New(client, nil, Selection{}, nil)
+	require.ErrorIs(t, err, sonar.ErrMissingRequired)
+}
@@ -0,0 +1,145 @@
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// migrateQualityProfiles copies the custom quality profiles of the source by restoring
+// their backups on the target, parents before their children. Profiles are matched by
+// language and name; built-in profiles are left to the target.
+func (m *Migrator) migrateQualityProfiles(ctx context.Context) error {
+	if !m.kindSelected(KindQualityProfile) {
+		return nil
+	}
+
+	search, _, err := m.source.Qualityprofiles.Search(ctx, &sonar.QualityprofilesSearchOptions{}) //nolint:exhaustruct // every profile
+	if err != nil {
+		return fmt.Errorf("failed to search the quality profiles of the source: %w", err)
+	}
+
+	for _, profile := range parentsFirst(search.Profiles) {
+		err = m.migrate(ctx, KindQualityProfile, profileName(profile.Language, profile.Name), profile.Key, func(ctx context.Context) (*outcome, error) {
+			return m.copyQualityProfile(ctx, profile)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// profileName returns the name identifying a quality profile in the log.
+func profileName(language, name string) string {
+	return language + "/" + name
+}
+
+// parentsFirst returns the custom profiles, each after its ancestors.
+func parentsFirst(profiles []sonar.QualityProfile) []sonar.QualityProfile {
+	byName := make(map[string]sonar.QualityProfile, len(profiles))
+
+	for _, profile := range profiles {
+		byName[profileName(profile.Language, profile.Name)] = profile
+	}
+
+	depths := make(map[string]int, len(profiles))
+
+	var depth func(profile sonar.QualityProfile) int
+
+	depth = func(profile sonar.QualityProfile) int {
+		name := profileName(profile.Language, profile.Name)
+		if d, found := depths[name]; found {
+			return d
+		}
+
+		depths[name] = 0 // guards against inheritance cycles
+
+		if parent, found := byName[profileName(profile.Language, profile.ParentName)]; found && profile.ParentName != "" {
+			depths[name] = depth(parent) + 1
+		}
+
+		return depths[name]
+	}
+
+	custom := make([]sonar.QualityProfile, 0, len(profiles))
+
+	for _, profile := range profiles {
+		if !profile.IsBuiltIn {
+			custom = append(custom, profile)
+		}
+	}
+
+	slices.SortStableFunc(custom, func(a, b sonar.QualityProfile) int {
+		return cmp.Or(
+			cmp.Compare(depth(a), depth(b)),
+			strings.Compare(a.Language, b.Language),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+
+	return custom
+}
+
+// copyQualityProfile restores the backup of a profile on the target, then sets its
+// parent and, if it is the default profile of its language, makes it the default.
+func (m *Migrator) copyQualityProfile(ctx context.Context, profile sonar.QualityProfile) (*outcome, error) {
+	if !m.languages[profile.Language] {
+		return skipped("language %q is not available on the target", profile.Language), nil
+	}
+
+	backup, _, err := m.source.Qualityprofiles.Backup(ctx, &sonar.QualityprofilesBackupOptions{
+		Language:       profile.Language,
+		QualityProfile: profile.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up the profile: %w", err)
+	}
+
+	_, err = m.target.Qualityprofiles.Restore(ctx, &sonar.QualityprofilesRestoreOptions{Backup: *backup})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore the profile: %w", err)
+	}
+
+	if profile.ParentName != "" {
+		_, err = m.target.Qualityprofiles.ChangeParent(ctx, &sonar.QualityprofilesChangeParentOptions{
+			Language:             profile.Language,
+			QualityProfile:       profile.Name,
+			ParentQualityProfile: profile.ParentName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set the parent profile %q: %w", profile.ParentName, err)
+		}
+	}
+
+	if profile.IsDefault {
+		_, err = m.target.Qualityprofiles.SetDefault(ctx, &sonar.QualityprofilesSetDefaultOptions{
+			Language:       profile.Language,
+			QualityProfile: profile.Name,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to make the profile the default: %w", err)
+		}
+	}
+
+	restored, _, err := m.target.Qualityprofiles.Search(ctx, &sonar.QualityprofilesSearchOptions{ //nolint:exhaustruct // one profile
+		Language:       profile.Language,
+		QualityProfile: profile.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the restored profile: %w", err)
+	}
+
+	for _, candidate := range restored.Profiles {
+		if candidate.Language == profile.Language && candidate.Name == profile.Name {
+			return done(candidate.Key), nil
+		}
+	}
+
+	return nil, fmt.Errorf("restored profile %w", errNotOnTarget)
+}
@@ -0,0 +1,194 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// migrateProjects copies the settings, quality gate and quality profiles of the
+// selected projects of the source. Projects are not created: those missing on the
+// target are skipped.
+func (m *Migrator) migrateProjects(ctx context.Context) error {
+	if !m.kindSelected(KindProjectSettings) && !m.kindSelected(KindProjectQualityGate) && !m.kindSelected(KindProjectQualityProfile) {
+		return nil
+	}
+
+	opt := &sonar.ProjectsSearchOptions{ //nolint:exhaustruct // every selected project
+		Qualifiers: []string{sonar.ProjectQualifierTRK},
+		Projects:   m.selection.Projects,
+	}
+
+	projects, _, err := m.source.Projects.SearchAll(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("failed to search the projects of the source: %w", err)
+	}
+
+	existing, _, err := m.target.Projects.SearchAll(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("failed to search the projects of the target: %w", err)
+	}
+
+	onTarget := make(map[string]bool, len(existing))
+
+	for _, project := range existing {
+		onTarget[project.Key] = true
+	}
+
+	for _, project := range projects {
+		err = m.migrateProject(ctx, project.Key, onTarget[project.Key])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateProject copies the settings, quality gate and quality profiles of a project.
+func (m *Migrator) migrateProject(ctx context.Context, key string, exists bool) error {
+	err := m.migrate(ctx, KindProjectSettings, key, key, func(ctx context.Context) (*outcome, error) {
+		if !exists {
+			return skipped("project missing on the target"), nil
+		}
+
+		return m.copyProjectSettings(ctx, key)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = m.migrateProjectQualityGate(ctx, key, exists)
+	if err != nil {
+		return err
+	}
+
+	return m.migrateProjectQualityProfiles(ctx, key, exists)
+}
+
+// copyProjectSettings sets the settings set on a source project on the target project.
+// Secured settings cannot be read and property sets cannot be written whole: both are
+// left out.
+func (m *Migrator) copyProjectSettings(ctx context.Context, key string) (*outcome, error) {
+	values, _, err := m.source.Settings.Values(ctx, &sonar.SettingsValuesOptions{Component: key, Keys: nil})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the settings of the project: %w", err)
+	}
+
+	var details []string
+
+	propertySets := 0
+
+	for _, value := range values.Settings {
+		if value.Inherited {
+			continue
+		}
+
+		if value.FieldValues != nil {
+			propertySets++
+
+			continue
+		}
+
+		_, err = m.target.Settings.Set(ctx, &sonar.SettingsSetOptions{
+			Component:   key,
+			Key:         value.Key,
+			Value:       value.Value,
+			Values:      value.Values,
+			FieldValues: nil,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", value.Key, err)
+		}
+	}
+
+	if propertySets > 0 {
+		details = append(details, fmt.Sprintf("%d property set setting(s) left out", propertySets))
+	}
+
+	if len(values.SetSecuredSettings) > 0 {
+		details = append(details, fmt.Sprintf("%d secured setting(s) left out", len(values.SetSecuredSettings)))
+	}
+
+	result := done(key)
+	result.detail = joinDetails(details)
+
+	return result, nil
+}
+
+// migrateProjectQualityGate assigns the gate of a source project to the target project,
+// unless the project uses the default gate.
+func (m *Migrator) migrateProjectQualityGate(ctx context.Context, key string, exists bool) error {
+	if !m.kindSelected(KindProjectQualityGate) {
+		return nil
+	}
+
+	gate, _, err := m.source.Qualitygates.GetByProject(ctx, &sonar.QualitygatesGetByProjectOptions{Project: key})
+	if err != nil {
+		return fmt.Errorf("failed to read the quality gate of project %q: %w", key, err)
+	}
+
+	if gate.QualityGate.Default {
+		return nil
+	}
+
+	return m.migrate(ctx, KindProjectQualityGate, key, key, func(ctx context.Context) (*outcome, error) {
+		if !exists {
+			return skipped("project missing on the target"), nil
+		}
+
+		_, err := m.target.Qualitygates.Assign(ctx, &sonar.QualitygatesAssignOptions{GateName: gate.QualityGate.Name, ProjectKey: key})
+		if err != nil {
+			return nil, fmt.Errorf("failed to assign quality gate %q: %w", gate.QualityGate.Name, err)
+		}
+
+		return done(key), nil
+	})
+}
+
+// migrateProjectQualityProfiles associates the target project with the profiles the
+// source project uses, except the default profiles of their languages.
+func (m *Migrator) migrateProjectQualityProfiles(ctx context.Context, key string, exists bool) error {
+	if !m.kindSelected(KindProjectQualityProfile) {
+		return nil
+	}
+
+	search, _, err := m.source.Qualityprofiles.Search(ctx, &sonar.QualityprofilesSearchOptions{ //nolint:exhaustruct // profiles of the project
+		Project: key,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read the quality profiles of project %q: %w", key, err)
+	}
+
+	for _, profile := range search.Profiles {
+		if profile.IsDefault {
+			continue
+		}
+
+		err = m.migrate(ctx, KindProjectQualityProfile, key+"/"+profile.Language, key, func(ctx context.Context) (*outcome, error) {
+			switch {
+			case !exists:
+				return skipped("project missing on the target"), nil
+			case !m.languages[profile.Language]:
+				return skipped("language %q is not available on the target", profile.Language), nil
+			}
+
+			_, err := m.target.Qualityprofiles.AddProject(ctx, &sonar.QualityprofilesAddProjectOptions{
+				Language:       profile.Language,
+				Project:        key,
+				QualityProfile: profile.Name,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to associate quality profile %q: %w", profile.Name, err)
+			}
+
+			return done(key), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
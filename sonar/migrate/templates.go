@@ -0,0 +1,196 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// migratePermissionTemplates copies the permission templates of the source and the
+// permissions they grant. Templates are matched by name; grants are only added, never
+// removed from an existing template.
+func (m *Migrator) migratePermissionTemplates(ctx context.Context) error {
+	if !m.kindSelected(KindPermissionTemplate) {
+		return nil
+	}
+
+	search, _, err := m.source.Permissions.SearchTemplates(ctx, &sonar.PermissionsSearchTemplatesOptions{}) //nolint:exhaustruct // every template
+	if err != nil {
+		return fmt.Errorf("failed to search the permission templates of the source: %w", err)
+	}
+
+	existing, _, err := m.target.Permissions.SearchTemplates(ctx, &sonar.PermissionsSearchTemplatesOptions{}) //nolint:exhaustruct // every template
+	if err != nil {
+		return fmt.Errorf("failed to search the permission templates of the target: %w", err)
+	}
+
+	onTarget := make(map[string]string, len(existing.PermissionTemplates))
+
+	for _, template := range existing.PermissionTemplates {
+		onTarget[template.Name] = template.ID
+	}
+
+	defaults := make(map[string][]string, len(search.DefaultTemplates))
+
+	for _, template := range search.DefaultTemplates {
+		defaults[template.TemplateID] = append(defaults[template.TemplateID], template.Qualifier)
+	}
+
+	for _, template := range search.PermissionTemplates {
+		err = m.migrate(ctx, KindPermissionTemplate, template.Name, template.ID, func(ctx context.Context) (*outcome, error) {
+			return m.copyPermissionTemplate(ctx, template, onTarget[template.Name], defaults[template.ID])
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyPermissionTemplate creates or updates a template on the target, adds its grants
+// and makes it the default for the qualifiers the target supports.
+func (m *Migrator) copyPermissionTemplate(ctx context.Context, template sonar.PermissionTemplate, targetID string, defaultFor []string) (*outcome, error) {
+	permissions := m.target.Permissions
+
+	if targetID == "" {
+		created, _, err := permissions.CreateTemplate(ctx, &sonar.PermissionsCreateTemplateOptions{
+			Name:              template.Name,
+			Description:       template.Description,
+			ProjectKeyPattern: template.ProjectKeyPattern,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the template: %w", err)
+		}
+
+		targetID = created.PermissionTemplate.ID
+	} else {
+		_, _, err := permissions.UpdateTemplate(ctx, &sonar.PermissionsUpdateTemplateOptions{
+			ID:                targetID,
+			Name:              template.Name,
+			Description:       template.Description,
+			ProjectKeyPattern: template.ProjectKeyPattern,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update the template: %w", err)
+		}
+	}
+
+	missing, err := m.copyTemplateGrants(ctx, template, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var details []string
+
+	if missing > 0 {
+		details = append(details, fmt.Sprintf("%d grant(s) to groups or users missing on the target left out", missing))
+	}
+
+	var unsupported []string
+
+	for _, qualifier := range defaultFor {
+		if !slices.Contains(m.qualifiers, qualifier) {
+			unsupported = append(unsupported, qualifier)
+
+			continue
+		}
+
+		_, err = permissions.SetDefaultTemplate(ctx, &sonar.PermissionsSetDefaultTemplateOptions{
+			Qualifier:    qualifier,
+			TemplateID:   targetID,
+			TemplateName: "",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to make the template the default for %s: %w", qualifier, err)
+		}
+	}
+
+	if len(unsupported) > 0 {
+		details = append(details, "not made the default for qualifiers missing on the target: "+strings.Join(unsupported, ", "))
+	}
+
+	result := done(targetID)
+	result.detail = joinDetails(details)
+
+	return result, nil
+}
+
+// copyTemplateGrants adds the group, user and project creator grants of a source
+// template to a target template, and returns the number of grants left out because the
+// group or user does not exist on the target.
+func (m *Migrator) copyTemplateGrants(ctx context.Context, template sonar.PermissionTemplate, targetID string) (int, error) {
+	permissions := m.target.Permissions
+
+	groups, _, err := m.source.Permissions.TemplateGroupsAll(ctx, &sonar.PermissionsTemplateGroupsOptions{ //nolint:exhaustruct // every group
+		TemplateName: template.Name,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the groups of the template: %w", err)
+	}
+
+	users, _, err := m.source.Permissions.TemplateUsersAll(ctx, &sonar.PermissionsTemplateUsersOptions{ //nolint:exhaustruct // every user
+		TemplateName: template.Name,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the users of the template: %w", err)
+	}
+
+	missing := 0
+
+	for _, group := range groups {
+		for _, permission := range group.Permissions {
+			_, err = permissions.AddGroupToTemplate(ctx, &sonar.PermissionsAddGroupToTemplateOptions{
+				GroupName:    group.Name,
+				Permission:   permission,
+				TemplateID:   targetID,
+				TemplateName: "",
+			})
+
+			switch {
+			case sonar.IsNotFound(err):
+				missing++
+			case err != nil:
+				return 0, fmt.Errorf("failed to grant %s to group %q: %w", permission, group.Name, err)
+			}
+		}
+	}
+
+	for _, user := range users {
+		for _, permission := range user.Permissions {
+			_, err = permissions.AddUserToTemplate(ctx, &sonar.PermissionsAddUserToTemplateOptions{
+				Login:        user.Login,
+				Permission:   permission,
+				TemplateID:   targetID,
+				TemplateName: "",
+			})
+
+			switch {
+			case sonar.IsNotFound(err):
+				missing++
+			case err != nil:
+				return 0, fmt.Errorf("failed to grant %s to user %q: %w", permission, user.Login, err)
+			}
+		}
+	}
+
+	for _, permission := range template.Permissions {
+		if !permission.WithProjectCreator {
+			continue
+		}
+
+		_, err = permissions.AddProjectCreatorToTemplate(ctx, &sonar.PermissionsAddProjectCreatorToTemplateOptions{
+			Permission:   permission.Key,
+			TemplateID:   targetID,
+			TemplateName: "",
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to grant %s to the project creator: %w", permission.Key, err)
+		}
+	}
+
+	return missing, nil
+}
@@ -101,7 +101,7 @@ type NewCodePeriodsShow struct {
 type NewCodePeriodsListOptions struct {
 	// Project is the project key.
 	// This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 }
 
 // NewCodePeriodsSetOptions contains parameters for the Set method.
@@ -583,7 +583,7 @@ type PermissionsUpdateTemplateOptions struct {
 	Description string `url:"description,omitempty"`
 	// ID is the template id.
 	// This field is required.
-	ID string `url:"id"`
+	ID string `url:"id" cli:"positional"`
 	// Name is the template name.
 	Name string `url:"name,omitempty"`
 	// ProjectKeyPattern is a project key pattern. Must be a valid Java regular expression.
@@ -264,13 +264,13 @@ type PluginUpdateDetail struct {
 // PluginsDownloadOptions represents options for downloading a plugin.
 type PluginsDownloadOptions struct {
 	// Plugin is the key identifying the plugin to download (required).
-	Plugin string `url:"plugin,omitempty"`
+	Plugin string `url:"plugin,omitempty" cli:"positional"`
 }
 
 // PluginsInstallOptions represents options for installing a plugin.
 type PluginsInstallOptions struct {
 	// Key is the key identifying the plugin to install (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 }
 
 // PluginsInstalledOptions represents options for listing installed plugins.
@@ -288,13 +288,13 @@ type PluginsInstalledOptions struct {
 // PluginsUninstallOptions represents options for uninstalling a plugin.
 type PluginsUninstallOptions struct {
 	// Key is the key identifying the plugin to uninstall (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 }
 
 // PluginsUpdateOptions represents options for updating a plugin.
 type PluginsUpdateOptions struct {
 	// Key is the key identifying the plugin to update (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 }
 
 // -----------------------------------------------------------------------------
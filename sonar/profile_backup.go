@@ -0,0 +1,523 @@
+package sonar
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// profileBackupDeclaration is the XML declaration of the server's backups.
+	profileBackupDeclaration = "<?xml version='1.0' encoding='UTF-8'?>"
+
+	// BackupDifferenceAdded is a rule or attribute only the second backup has.
+	BackupDifferenceAdded = "added"
+	// BackupDifferenceRemoved is a rule or attribute only the first backup has.
+	BackupDifferenceRemoved = "removed"
+	// BackupDifferenceChanged is an attribute whose value differs between the backups.
+	BackupDifferenceChanged = "changed"
+)
+
+// -----------------------------------------------------------------------------
+// Shared Types
+// -----------------------------------------------------------------------------
+
+// ProfileBackup is a quality profile backup, as returned by Qualityprofiles.Backup and
+// accepted by Qualityprofiles.Restore.
+type ProfileBackup struct {
+	// Name is the name of the profile.
+	Name string `json:"name"`
+	// Language is the language of the profile.
+	Language string `json:"language"`
+	// Rules are the rules the profile activates, in the order of the backup.
+	Rules []ProfileBackupRule `json:"rules"`
+	// Indent is the indentation of the XML: empty for the single-line layout of the
+	// server's backups, such as two spaces for a file reviewed in version control.
+	// ParseProfileBackup sets it from the document.
+	Indent string `json:"-"`
+}
+
+// ProfileBackupRule is the activation of a rule in a quality profile backup.
+//
+// A nil Impacts or Parameters list is absent from the XML, while an empty one is
+// written as an empty element, as the server writes the parameters of every rule.
+type ProfileBackupRule struct {
+	// RepositoryKey is the repository of the rule, such as java.
+	RepositoryKey string `json:"repositoryKey"`
+	// Key is the key of the rule in its repository, such as S138.
+	Key string `json:"key"`
+	// Type is the type of the rule, such as CODE_SMELL.
+	Type string `json:"type,omitempty"`
+	// Priority is the severity of the activation.
+	Priority string `json:"priority,omitempty"`
+	// Impacts are the severities of the activation per software quality.
+	Impacts []ProfileBackupImpact `json:"impacts,omitempty"`
+	// PrioritizedRule marks the rule as prioritized in the profile. Nil when absent.
+	PrioritizedRule *bool `json:"prioritizedRule,omitempty"`
+	// Name is the name of a custom rule.
+	Name string `json:"name,omitempty"`
+	// TemplateKey is the key of the template of a custom rule.
+	TemplateKey string `json:"templateKey,omitempty"`
+	// Description is the description of a custom rule.
+	Description string `json:"description,omitempty"`
+	// Extra are the elements of the rule the format does not define, kept as they are
+	// and written before the parameters.
+	Extra []ProfileBackupElement `json:"extra,omitempty"`
+	// Parameters are the values of the rule parameters.
+	Parameters []ProfileBackupParameter `json:"parameters,omitempty"`
+}
+
+// ProfileBackupImpact is the severity of a rule activation for a software quality.
+type ProfileBackupImpact struct {
+	// SoftwareQuality is the software quality, such as MAINTAINABILITY.
+	SoftwareQuality string `json:"softwareQuality" xml:"softwareQuality"`
+	// Severity is the severity, such as HIGH.
+	Severity string `json:"severity" xml:"severity"`
+}
+
+// ProfileBackupParameter is the value of a rule parameter in a quality profile backup.
+type ProfileBackupParameter struct {
+	// Key is the parameter name.
+	Key string `json:"key" xml:"key"`
+	// Value is the parameter value.
+	Value string `json:"value" xml:"value"`
+}
+
+// ProfileBackupElement is an element of a backup rule the format does not define.
+type ProfileBackupElement struct {
+	// XMLName is the name of the element.
+	XMLName xml.Name `json:"-"`
+	// Content is the raw XML content of the element.
+	Content string `json:"content" xml:",innerxml"`
+}
+
+// ProfileBackupDifference is a difference between two quality profile backups.
+type ProfileBackupDifference struct {
+	// Rule is the rule key, such as java:S138, or empty for an attribute of the profile.
+	Rule string `json:"rule,omitempty"`
+	// Field is the attribute that differs, such as "severity", "impacts.SECURITY" or
+	// "params.max". It is empty when the rule was added or removed.
+	Field string `json:"field,omitempty"`
+	// Type is BackupDifferenceAdded, BackupDifferenceRemoved or BackupDifferenceChanged.
+	Type string `json:"type"`
+	// From is the value in the first backup, or the summary of a removed rule.
+	From string `json:"from,omitempty"`
+	// To is the value in the second backup, or the summary of an added rule.
+	To string `json:"to,omitempty"`
+}
+
+// -----------------------------------------------------------------------------
+// Parsing and Serialization
+// -----------------------------------------------------------------------------
+
+// xmlProfileBackup is the XML document of a quality profile backup.
+type xmlProfileBackup struct {
+	XMLName  xml.Name        `xml:"profile"`
+	Name     string          `xml:"name"`
+	Language string          `xml:"language"`
+	Rules    []xmlBackupRule `xml:"rules>rule"`
+}
+
+// xmlBackupRule is the XML element of a rule. The lists are pointers so that an empty
+// element is told apart from an absent one.
+type xmlBackupRule struct {
+	RepositoryKey   string  `xml:"repositoryKey"`
+	Key             string  `xml:"key"`
+	Type            string  `xml:"type"`
+	Priority        string  `xml:"priority"`
+	PrioritizedRule *string `xml:"prioritizedRule"`
+	Impacts         *struct {
+		Impacts []ProfileBackupImpact `xml:"impact"`
+	} `xml:"impacts"`
+	Name        string `xml:"name"`
+	TemplateKey string `xml:"templateKey"`
+	Description string `xml:"description"`
+	Parameters  *struct {
+		Parameters []ProfileBackupParameter `xml:"parameter"`
+	} `xml:"parameters"`
+	Extra []ProfileBackupElement `xml:",any"`
+}
+
+// ParseProfileBackup parses the XML of a quality profile backup.
+//
+// Only the backups of the server and the documents String writes round-trip exactly:
+// String returns them unchanged. Other documents, such as hand-edited ones, come back
+// in the layout of the server: the declaration is rewritten with single quotes, empty
+// elements such as <parameters/> are written with a closing tag, only &, < and > are
+// escaped, elements the format does not define are moved before the parameters, and
+// comments are dropped.
+func ParseProfileBackup(backup string) (*ProfileBackup, error) {
+	var parsed xmlProfileBackup
+
+	err := xml.Unmarshal([]byte(backup), &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quality profile backup: %w", err)
+	}
+
+	profile := &ProfileBackup{
+		Name:     parsed.Name,
+		Language: parsed.Language,
+		Rules:    make([]ProfileBackupRule, 0, len(parsed.Rules)),
+		Indent:   backupIndent(backup),
+	}
+
+	for _, rule := range parsed.Rules {
+		converted, err := rule.convert()
+		if err != nil {
+			return nil, fmt.Errorf("invalid quality profile backup: rule %s:%s: %w", rule.RepositoryKey, rule.Key, err)
+		}
+
+		profile.Rules = append(profile.Rules, converted)
+	}
+
+	return profile, nil
+}
+
+// convert returns the rule of the XML element.
+func (r xmlBackupRule) convert() (ProfileBackupRule, error) {
+	rule := ProfileBackupRule{
+		RepositoryKey:   r.RepositoryKey,
+		Key:             r.Key,
+		Type:            r.Type,
+		Priority:        r.Priority,
+		Impacts:         nil,
+		PrioritizedRule: nil,
+		Name:            r.Name,
+		TemplateKey:     r.TemplateKey,
+		Description:     r.Description,
+		Extra:           r.Extra,
+		Parameters:      nil,
+	}
+
+	if r.PrioritizedRule != nil {
+		prioritized, err := strconv.ParseBool(*r.PrioritizedRule)
+		if err != nil {
+			return rule, fmt.Errorf("prioritizedRule: %w", err)
+		}
+
+		rule.PrioritizedRule = &prioritized
+	}
+
+	if r.Impacts != nil {
+		rule.Impacts = append([]ProfileBackupImpact{}, r.Impacts.Impacts...)
+	}
+
+	if r.Parameters != nil {
+		rule.Parameters = append([]ProfileBackupParameter{}, r.Parameters.Parameters...)
+	}
+
+	return rule, nil
+}
+
+// backupIndent returns the indentation of the first child of the profile element, or
+// "" when the document is on a single line.
+func backupIndent(backup string) string {
+	_, rest, found := strings.Cut(backup, "<profile>")
+	if !found {
+		return ""
+	}
+
+	rest, found = strings.CutPrefix(rest, "\n")
+	if !found {
+		return ""
+	}
+
+	return rest[:len(rest)-len(strings.TrimLeft(rest, " \t"))]
+}
+
+// String returns the XML of the backup, in the layout of the server's backups: elements
+// in the server's order and, unless Indent is set, on a single line. An indented
+// document ends with a newline.
+func (b *ProfileBackup) String() string {
+	w := &backupWriter{indent: b.Indent, depth: 0, builder: strings.Builder{}}
+
+	w.builder.WriteString(profileBackupDeclaration)
+	w.begin("profile")
+	w.prop("name", b.Name)
+	w.prop("language", b.Language)
+	w.list("rules", len(b.Rules), func() {
+		for _, rule := range b.Rules {
+			w.begin("rule")
+			rule.write(w)
+			w.end("rule")
+		}
+	})
+	w.end("profile")
+
+	if b.Indent != "" {
+		w.builder.WriteString("\n")
+	}
+
+	return w.builder.String()
+}
+
+// write writes the elements of a rule.
+func (r ProfileBackupRule) write(w *backupWriter) {
+	w.prop("repositoryKey", r.RepositoryKey)
+	w.prop("key", r.Key)
+	w.optionalProp("type", r.Type)
+	w.optionalProp("priority", r.Priority)
+
+	if r.Impacts != nil {
+		w.list("impacts", len(r.Impacts), func() {
+			for _, impact := range r.Impacts {
+				w.begin("impact")
+				w.prop("softwareQuality", impact.SoftwareQuality)
+				w.prop("severity", impact.Severity)
+				w.end("impact")
+			}
+		})
+	}
+
+	if r.PrioritizedRule != nil {
+		w.prop("prioritizedRule", strconv.FormatBool(*r.PrioritizedRule))
+	}
+
+	w.optionalProp("name", r.Name)
+	w.optionalProp("templateKey", r.TemplateKey)
+	w.optionalProp("description", r.Description)
+
+	for _, element := range r.Extra {
+		w.newline()
+		w.builder.WriteString("<" + element.XMLName.Local + ">" + element.Content + "</" + element.XMLName.Local + ">")
+	}
+
+	if r.Parameters != nil {
+		w.list("parameters", len(r.Parameters), func() {
+			for _, param := range r.Parameters {
+				w.begin("parameter")
+				w.prop("key", param.Key)
+				w.prop("value", param.Value)
+				w.end("parameter")
+			}
+		})
+	}
+}
+
+// backupWriter writes the elements of a backup, indented or on a single line.
+type backupWriter struct {
+	builder strings.Builder
+	indent  string
+	depth   int
+}
+
+// newline starts a line at the current depth, in an indented document.
+func (w *backupWriter) newline() {
+	if w.indent != "" {
+		w.builder.WriteString("\n" + strings.Repeat(w.indent, w.depth))
+	}
+}
+
+// begin opens an element with children.
+func (w *backupWriter) begin(name string) {
+	w.newline()
+	w.builder.WriteString("<" + name + ">")
+	w.depth++
+}
+
+// end closes an element with children.
+func (w *backupWriter) end(name string) {
+	w.depth--
+	w.newline()
+	w.builder.WriteString("</" + name + ">")
+}
+
+// prop writes an element holding text.
+func (w *backupWriter) prop(name, value string) {
+	w.newline()
+	w.builder.WriteString("<" + name + ">" + escapeBackupText(value) + "</" + name + ">")
+}
+
+// optionalProp writes an element holding text, unless the text is empty.
+func (w *backupWriter) optionalProp(name, value string) {
+	if value != "" {
+		w.prop(name, value)
+	}
+}
+
+// list writes an element holding count children, written by children, or an empty
+// element on one line.
+func (w *backupWriter) list(name string, count int, children func()) {
+	if count == 0 {
+		w.prop(name, "")
+
+		return
+	}
+
+	w.begin(name)
+	children()
+	w.end(name)
+}
+
+// escapeBackupText escapes text the way the server does: only the characters that
+// cannot appear as they are.
+func escapeBackupText(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// -----------------------------------------------------------------------------
+// Rules and Differences
+// -----------------------------------------------------------------------------
+
+// RuleKey returns the key of the rule, such as java:S138.
+func (r ProfileBackupRule) RuleKey() string {
+	return r.RepositoryKey + ":" + r.Key
+}
+
+// String summarizes the activation: its severity, impacts, parameters and priority,
+// such as "MAJOR MAINTAINABILITY=HIGH max=10".
+func (r ProfileBackupRule) String() string {
+	var parts []string
+
+	if r.Priority != "" {
+		parts = append(parts, r.Priority)
+	}
+
+	for _, impact := range r.Impacts {
+		parts = append(parts, impact.SoftwareQuality+"="+impact.Severity)
+	}
+
+	params := r.params()
+
+	for _, key := range slices.Sorted(maps.Keys(params)) {
+		parts = append(parts, key+"="+params[key])
+	}
+
+	if r.PrioritizedRule != nil && *r.PrioritizedRule {
+		parts = append(parts, "prioritized")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Rule returns the rule of the backup with the given key, such as java:S138.
+func (b *ProfileBackup) Rule(ruleKey string) (ProfileBackupRule, bool) {
+	idx := slices.IndexFunc(b.Rules, func(rule ProfileBackupRule) bool { return rule.RuleKey() == ruleKey })
+	if idx < 0 {
+		return ProfileBackupRule{}, false //nolint:exhaustruct // not found
+	}
+
+	return b.Rules[idx], true
+}
+
+// rulesByKey returns the rules of the backup by rule key.
+func (b *ProfileBackup) rulesByKey() map[string]ProfileBackupRule {
+	rules := make(map[string]ProfileBackupRule, len(b.Rules))
+
+	for _, rule := range b.Rules {
+		rules[rule.RuleKey()] = rule
+	}
+
+	return rules
+}
+
+// fields returns the attributes of the activation by name, the names DiffProfileBackups
+// reports.
+func (r ProfileBackupRule) fields() map[string]string {
+	fields := map[string]string{
+		"type":        r.Type,
+		"severity":    r.Priority,
+		"name":        r.Name,
+		"templateKey": r.TemplateKey,
+		"description": r.Description,
+	}
+
+	if r.PrioritizedRule != nil {
+		fields["prioritized"] = strconv.FormatBool(*r.PrioritizedRule)
+	}
+
+	for _, impact := range r.Impacts {
+		fields["impacts."+impact.SoftwareQuality] = impact.Severity
+	}
+
+	for key, value := range r.params() {
+		fields["params."+key] = value
+	}
+
+	for _, element := range r.Extra {
+		fields[element.XMLName.Local] = element.Content
+	}
+
+	return fields
+}
+
+// params returns the parameter values by key.
+func (r ProfileBackupRule) params() map[string]string {
+	params := make(map[string]string, len(r.Parameters))
+
+	for _, param := range r.Parameters {
+		params[param.Key] = param.Value
+	}
+
+	return params
+}
+
+// DiffProfileBackups returns the differences between two quality profile backups, from
+// the first to the second: the name and language of the profile, the rules only one
+// activates, and the attributes of the rules both activate (severity, impacts,
+// parameters, priority, and the name, template and description of custom rules).
+// The order of the rules and the layout of the documents are ignored. Differences are
+// sorted by rule key and field, the profile attributes first.
+func DiffProfileBackups(from, to *ProfileBackup) []ProfileBackupDifference {
+	var differences []ProfileBackupDifference
+
+	add := func(rule, field, from, to string) {
+		switch {
+		case from == to:
+			return
+		case from == "":
+			differences = append(differences, ProfileBackupDifference{Rule: rule, Field: field, Type: BackupDifferenceAdded, From: "", To: to})
+		case to == "":
+			differences = append(differences, ProfileBackupDifference{Rule: rule, Field: field, Type: BackupDifferenceRemoved, From: from, To: ""})
+		default:
+			differences = append(differences, ProfileBackupDifference{Rule: rule, Field: field, Type: BackupDifferenceChanged, From: from, To: to})
+		}
+	}
+
+	add("", "name", from.Name, to.Name)
+	add("", "language", from.Language, to.Language)
+
+	fromRules, toRules := from.rulesByKey(), to.rulesByKey()
+
+	for _, rule := range to.Rules {
+		previous, found := fromRules[rule.RuleKey()]
+		if !found {
+			differences = append(differences, ProfileBackupDifference{
+				Rule: rule.RuleKey(), Field: "", Type: BackupDifferenceAdded, From: "", To: rule.String(),
+			})
+
+			continue
+		}
+
+		before, after := previous.fields(), rule.fields()
+		names := slices.Collect(maps.Keys(before))
+
+		for name := range after {
+			if _, found := before[name]; !found {
+				names = append(names, name)
+			}
+		}
+
+		for _, name := range names {
+			add(rule.RuleKey(), name, before[name], after[name])
+		}
+	}
+
+	for _, rule := range from.Rules {
+		if _, found := toRules[rule.RuleKey()]; !found {
+			differences = append(differences, ProfileBackupDifference{
+				Rule: rule.RuleKey(), Field: "", Type: BackupDifferenceRemoved, From: rule.String(), To: "",
+			})
+		}
+	}
+
+	slices.SortFunc(differences, func(a, b ProfileBackupDifference) int {
+		return cmp.Or(strings.Compare(a.Rule, b.Rule), strings.Compare(a.Field, b.Field))
+	})
+
+	return differences
+}
@@ -0,0 +1,157 @@
+package sonar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serverBackup is a backup in the single-line layout of the server, with a custom rule,
+// impacts, escaped text, an empty parameter list and an element the format does not define.
+const serverBackup = `<?xml version='1.0' encoding='UTF-8'?>` +
+	`<profile><name>Company &amp; Co</name><language>java</language><rules>` +
+	`<rule><repositoryKey>java</repositoryKey><key>S100</key><type>CODE_SMELL</type><priority>MAJOR</priority>` +
+	`<impacts><impact><softwareQuality>MAINTAINABILITY</softwareQuality><severity>MEDIUM</severity></impact></impacts>` +
+	`<prioritizedRule>true</prioritizedRule>` +
+	`<parameters><parameter><key>format</key><value>^[a-z]+$</value></parameter></parameters></rule>` +
+	`<rule><repositoryKey>java</repositoryKey><key>NoFoo</key><type>BUG</type><priority>MINOR</priority>` +
+	`<name>No foo</name><templateKey>S124</templateKey><description>Avoid "foo" in a &lt; b</description>` +
+	`<cleanCodeAttribute>CLEAR</cleanCodeAttribute><parameters></parameters></rule>` +
+	`</rules></profile>`
+
+// indentedBackup is a backup indented for review in version control.
+const indentedBackup = `<?xml version='1.0' encoding='UTF-8'?>
+<profile>
+  <name>Company</name>
+  <language>java</language>
+  <rules>
+    <rule>
+      <repositoryKey>java</repositoryKey>
+      <key>S101</key>
+      <priority>INFO</priority>
+      <parameters>
+        <parameter>
+          <key>max</key>
+          <value>10</value>
+        </parameter>
+      </parameters>
+    </rule>
+  </rules>
+</profile>
+`
+
+func TestParseProfileBackup(t *testing.T) {
+	backup, err := ParseProfileBackup(serverBackup)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Company & Co", backup.Name)
+	assert.Empty(t, backup.Indent)
+	require.Len(t, backup.Rules, 2)
+
+	rule := backup.Rules[0]
+	assert.Equal(t, "java:S100", rule.RuleKey())
+	assert.Equal(t, []ProfileBackupImpact{{SoftwareQuality: "MAINTAINABILITY", Severity: "MEDIUM"}}, rule.Impacts)
+	require.NotNil(t, rule.PrioritizedRule)
+	assert.True(t, *rule.PrioritizedRule)
+	assert.Equal(t, "MAJOR MAINTAINABILITY=MEDIUM format=^[a-z]+$ prioritized", rule.String())
+
+	custom, found := backup.Rule("java:NoFoo")
+	require.True(t, found)
+	assert.Equal(t, `Avoid "foo" in a < b`, custom.Description)
+	assert.Equal(t, "S124", custom.TemplateKey)
+	assert.NotNil(t, custom.Parameters)
+	assert.Empty(t, custom.Parameters)
+	require.Len(t, custom.Extra, 1)
+	assert.Equal(t, "cleanCodeAttribute", custom.Extra[0].XMLName.Local)
+	assert.Equal(t, "CLEAR", custom.Extra[0].Content)
+}
+
+func TestProfileBackup_String(t *testing.T) {
+	for name, document := range map[string]string{"server": serverBackup, "indented": indentedBackup} {
+		t.Run(name, func(t *testing.T) {
+			backup, err := ParseProfileBackup(document)
+			require.NoError(t, err)
+			assert.Equal(t, document, backup.String())
+		})
+	}
+
+	backup, err := ParseProfileBackup(indentedBackup)
+	require.NoError(t, err)
+	assert.Equal(t, "  ", backup.Indent)
+
+	// An edited backup is written in the same layout.
+	backup.Indent = ""
+	backup.Rules[0].Parameters[0].Value = "20"
+	assert.Equal(t, `<?xml version='1.0' encoding='UTF-8'?><profile><name>Company</name><language>java</language><rules>`+
+		`<rule><repositoryKey>java</repositoryKey><key>S101</key><priority>INFO</priority>`+
+		`<parameters><parameter><key>max</key><value>20</value></parameter></parameters></rule></rules></profile>`, backup.String())
+}
+
+func TestProfileBackup_String_Edited(t *testing.T) {
+	edited := `<?xml version="1.0" encoding="UTF-8"?>
+<!-- reviewed -->
+<profile><name>Company</name><language>java</language><rules>` +
+		`<rule><repositoryKey>java</repositoryKey><key>S100</key><priority>MAJOR</priority>` +
+		`<parameters/><cleanCodeAttribute>CLEAR</cleanCodeAttribute></rule>` +
+		`<rule><repositoryKey>java</repositoryKey><key>NoFoo</key><description>Avoid &quot;foo&quot;</description></rule>` +
+		`</rules></profile>`
+
+	backup, err := ParseProfileBackup(edited)
+	require.NoError(t, err)
+
+	written := `<?xml version='1.0' encoding='UTF-8'?><profile><name>Company</name><language>java</language><rules>` +
+		`<rule><repositoryKey>java</repositoryKey><key>S100</key><priority>MAJOR</priority>` +
+		`<cleanCodeAttribute>CLEAR</cleanCodeAttribute><parameters></parameters></rule>` +
+		`<rule><repositoryKey>java</repositoryKey><key>NoFoo</key><description>Avoid "foo"</description></rule>` +
+		`</rules></profile>`
+	assert.Equal(t, written, backup.String())
+
+	// The document String wrote round-trips exactly.
+	rewritten, err := ParseProfileBackup(written)
+	require.NoError(t, err)
+	assert.Equal(t, written, rewritten.String())
+	assert.Empty(t, DiffProfileBackups(backup, rewritten))
+}
+
+func TestParseProfileBackup_Invalid(t *testing.T) {
+	_, err := ParseProfileBackup("<profile>")
+	require.ErrorContains(t, err, "invalid quality profile backup")
+
+	_, err = ParseProfileBackup("<rules></rules>")
+	require.ErrorContains(t, err, "invalid quality profile backup")
+
+	_, err = ParseProfileBackup(`<profile><rules><rule><repositoryKey>java</repositoryKey><key>S1</key>` +
+		`<prioritizedRule>maybe</prioritizedRule></rule></rules></profile>`)
+	require.ErrorContains(t, err, "rule java:S1: prioritizedRule")
+}
+
+func TestDiffProfileBackups(t *testing.T) {
+	from, err := ParseProfileBackup(serverBackup)
+	require.NoError(t, err)
+
+	to, err := ParseProfileBackup(serverBackup)
+	require.NoError(t, err)
+
+	assert.Empty(t, DiffProfileBackups(from, to))
+
+	to.Name = "Company"
+	to.Rules[0].Priority = "CRITICAL"
+	to.Rules[0].Impacts = nil
+	to.Rules[0].Parameters[0].Value = "^[a-zA-Z]+$"
+	to.Rules[1] = ProfileBackupRule{ //nolint:exhaustruct // standard rule
+		RepositoryKey: "java",
+		Key:           "S102",
+		Priority:      "INFO",
+		Parameters:    []ProfileBackupParameter{{Key: "max", Value: "10"}},
+	}
+
+	assert.Equal(t, []ProfileBackupDifference{
+		{Field: "name", Type: BackupDifferenceChanged, From: "Company & Co", To: "Company"},
+		{Rule: "java:NoFoo", Type: BackupDifferenceRemoved, From: "MINOR"},
+		{Rule: "java:S100", Field: "impacts.MAINTAINABILITY", Type: BackupDifferenceRemoved, From: "MEDIUM"},
+		{Rule: "java:S100", Field: "params.format", Type: BackupDifferenceChanged, From: "^[a-z]+$", To: "^[a-zA-Z]+$"},
+		{Rule: "java:S100", Field: "severity", Type: BackupDifferenceChanged, From: "MAJOR", To: "CRITICAL"},
+		{Rule: "java:S102", Type: BackupDifferenceAdded, To: "INFO max=10"},
+	}, DiffProfileBackups(from, to))
+}
@@ -119,13 +119,13 @@ type ProjectAnalysesCreateEventOptions struct {
 // ProjectAnalysesDeleteOptions represents options for deleting an analysis.
 type ProjectAnalysesDeleteOptions struct {
 	// Analysis is the analysis key (required).
-	Analysis string `url:"analysis,omitempty"`
+	Analysis string `url:"analysis,omitempty" cli:"positional"`
 }
 
 // ProjectAnalysesDeleteEventOptions represents options for deleting an event.
 type ProjectAnalysesDeleteEventOptions struct {
 	// Event is the event key (required).
-	Event string `url:"event,omitempty"`
+	Event string `url:"event,omitempty" cli:"positional"`
 }
 
 // ProjectAnalysesSearchOptions represents options for searching analyses.
@@ -144,7 +144,7 @@ type ProjectAnalysesSearchOptions struct {
 	// Format: date or datetime (YYYY-MM-DD or YYYY-MM-DDTHH:mm:ssZ).
 	From string `url:"from,omitempty"`
 	// Project is the project key (required).
-	Project string `url:"project,omitempty"`
+	Project string `url:"project,omitempty" cli:"positional"`
 	// PullRequest is the pull request key.
 	PullRequest string `url:"pullRequest,omitempty"`
 	// To is the filter by date (inclusive).
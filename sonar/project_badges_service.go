@@ -120,7 +120,7 @@ type ProjectBadgesQualityGateOptions struct {
 	Branch string `url:"branch,omitempty"`
 	// Project is the project or application key.
 	// This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 	// Token is the project badge token.
 	Token string `url:"token,omitempty"`
 }
@@ -129,14 +129,14 @@ type ProjectBadgesQualityGateOptions struct {
 type ProjectBadgesRenewTokenOptions struct {
 	// Project is the project or application key.
 	// This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 }
 
 // ProjectBadgesTokenOptions contains parameters for the Token method.
 type ProjectBadgesTokenOptions struct {
 	// Project is the project or application key.
 	// This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 }
 
 // -----------------------------------------------------------------------------
@@ -71,7 +71,7 @@ type ProjectBranchesDeleteOptions struct {
 type ProjectBranchesListOptions struct {
 	// Project is the project key.
 	// This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 }
 
 // ProjectBranchesRenameOptions contains parameters for the Rename method.
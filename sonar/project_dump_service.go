@@ -51,7 +51,7 @@ type ProjectDumpStatus struct {
 type ProjectDumpExportOptions struct {
 	// Key is the project key.
 	// This field is required.
-	Key string `url:"key"`
+	Key string `url:"key" cli:"positional"`
 }
 
 // ProjectDumpStatusOptions contains parameters for the Status method.
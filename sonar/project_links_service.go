@@ -69,7 +69,7 @@ type ProjectLinksCreateOptions struct {
 type ProjectLinksDeleteOptions struct {
 	// ID is the unique identifier of the link to delete.
 	// This field is required.
-	ID string `url:"id"`
+	ID string `url:"id" cli:"positional"`
 }
 
 // ProjectLinksSearchOptions contains parameters for the Search method.
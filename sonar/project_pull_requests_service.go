@@ -71,7 +71,7 @@ type ProjectPullRequestsDeleteOptions struct {
 // ProjectPullRequestsListOptions contains parameters for the List method.
 type ProjectPullRequestsListOptions struct {
 	// Project is the project key. This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 }
 
 // -----------------------------------------------------------------------------
@@ -192,7 +192,7 @@ type ProjectsCreateOptions struct {
 // ProjectsDeleteOptions represents options for deleting a project.
 type ProjectsDeleteOptions struct {
 	// Project is the project key (required).
-	Project string `url:"project,omitempty"`
+	Project string `url:"project,omitempty" cli:"positional"`
 }
 
 // ProjectsSearchOptions represents options for searching projects.
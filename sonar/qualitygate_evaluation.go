@@ -0,0 +1,248 @@
+package sonar
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// QualityGateOperatorGreaterThan fails a condition whose value is above the threshold.
+	QualityGateOperatorGreaterThan = "GT"
+	// QualityGateOperatorLessThan fails a condition whose value is below the threshold.
+	QualityGateOperatorLessThan = "LT"
+
+	// MetricNewLines is the number of new lines, which decides whether a change is small.
+	MetricNewLines = "new_lines"
+
+	// smallChangesetLines is the number of new lines under which a change is small.
+	smallChangesetLines = 20
+	// newCodeMetricPrefix starts the key of the metrics computed on new code.
+	newCodeMetricPrefix = "new_"
+)
+
+// ErrUnsupportedCondition is returned for a quality gate condition the server would not
+// evaluate: an unknown operator, a metric type without order or an invalid threshold.
+var ErrUnsupportedCondition = errors.New("unsupported quality gate condition")
+
+//nolint:gochecknoglobals // constant lookup tables
+var (
+	// integerMetricTypes are the metric types whose thresholds are whole numbers.
+	integerMetricTypes = map[string]struct{}{
+		"BOOL":     {},
+		"INT":      {},
+		"RATING":   {},
+		"MILLISEC": {},
+		"WORK_DUR": {},
+	}
+
+	// comparableMetricTypes are the metric types a condition can be set on.
+	comparableMetricTypes = map[string]struct{}{
+		"BOOL":     {},
+		"INT":      {},
+		"RATING":   {},
+		"MILLISEC": {},
+		"WORK_DUR": {},
+		"FLOAT":    {},
+		"PERCENT":  {},
+	}
+
+	// smallChangesetMetrics are the metrics whose conditions the server ignores on
+	// changes of fewer than 20 new lines.
+	smallChangesetMetrics = map[string]struct{}{
+		"new_coverage":                 {},
+		"new_line_coverage":            {},
+		"new_branch_coverage":          {},
+		"new_duplicated_lines_density": {},
+		"new_duplicated_lines":         {},
+		"new_duplicated_blocks":        {},
+	}
+)
+
+// -----------------------------------------------------------------------------
+// Shared Types
+// -----------------------------------------------------------------------------
+
+// QualityGateMeasures are the measures of a project, branch or pull request a quality
+// gate is evaluated against.
+type QualityGateMeasures struct {
+	// Values are the measure values by metric key. A metric without a value leaves its
+	// conditions passed, as the server does.
+	Values map[string]string `json:"values"`
+	// Types are the metric types by key, such as PERCENT or RATING. A metric without a
+	// type is compared as a decimal number.
+	Types map[string]string `json:"types,omitempty"`
+	// IgnoreSmallChanges skips the coverage and duplication conditions on new code when
+	// there are fewer than 20 new lines, as the server does unless the
+	// sonar.qualitygate.ignoreSmallChanges setting is turned off.
+	IgnoreSmallChanges bool `json:"ignoreSmallChanges"`
+}
+
+// QualityGateEvaluation is the outcome of evaluating a quality gate offline.
+type QualityGateEvaluation struct {
+	// Status is ERROR when a condition failed, OK otherwise.
+	Status string `json:"status"`
+	// Conditions are the evaluated conditions, in the order of the gate.
+	Conditions []QualityGateConditionStatus `json:"conditions"`
+}
+
+// Failed returns the conditions in error.
+func (e *QualityGateEvaluation) Failed() []QualityGateConditionStatus {
+	var failed []QualityGateConditionStatus
+
+	for _, condition := range e.Conditions {
+		if condition.Status == QualityGateStatusError {
+			failed = append(failed, condition)
+		}
+	}
+
+	return failed
+}
+
+// NewQualityGateMeasures returns the measures of a Measures.Component response, with
+// the period value of new code metrics and the metric types when the response has them
+// (AdditionalFields "metrics"). Small changes are ignored, as by default on the server.
+func NewQualityGateMeasures(component *MeasuresComponent) *QualityGateMeasures {
+	measures := &QualityGateMeasures{
+		Values:             make(map[string]string, len(component.Component.Measures)),
+		Types:              make(map[string]string, len(component.Metrics)),
+		IgnoreSmallChanges: true,
+	}
+
+	for _, measure := range component.Component.Measures {
+		value := measure.Value
+		if strings.HasPrefix(measure.Metric, newCodeMetricPrefix) && measure.Period != nil && measure.Period.Value != "" {
+			value = measure.Period.Value
+		}
+
+		if value != "" {
+			measures.Values[measure.Metric] = value
+		}
+	}
+
+	for _, metric := range component.Metrics {
+		measures.Types[metric.Key] = metric.Type
+	}
+
+	return measures
+}
+
+// EvaluateQualityGate evaluates quality gate conditions against measures as the server
+// does after an analysis: a GT condition fails when the value is above the threshold and
+// an LT condition when it is below, ratings compare as numbers (1 for A), and conditions
+// on metrics without a value pass.
+//
+// The server compares the unrounded values it computed, while Measures.Component rounds
+// decimal values, so a value within rounding of the threshold may evaluate differently.
+func EvaluateQualityGate(conditions []QualityGateCondition, measures *QualityGateMeasures) (*QualityGateEvaluation, error) {
+	evaluation := &QualityGateEvaluation{
+		Status:     QualityGateStatusOK,
+		Conditions: make([]QualityGateConditionStatus, 0, len(conditions)),
+	}
+
+	for _, condition := range conditions {
+		status, err := evaluateCondition(condition, measures)
+		if err != nil {
+			return nil, fmt.Errorf("condition on %s: %w", condition.Metric, err)
+		}
+
+		if status.Status == QualityGateStatusError {
+			evaluation.Status = QualityGateStatusError
+		}
+
+		evaluation.Conditions = append(evaluation.Conditions, status)
+	}
+
+	return evaluation, nil
+}
+
+// evaluateCondition evaluates one condition.
+func evaluateCondition(condition QualityGateCondition, measures *QualityGateMeasures) (QualityGateConditionStatus, error) {
+	status := QualityGateConditionStatus{
+		ActualValue:    measures.Values[condition.Metric],
+		Comparator:     condition.Op,
+		ErrorThreshold: condition.Error,
+		MetricKey:      condition.Metric,
+		Status:         QualityGateStatusOK,
+	}
+
+	if condition.Op != QualityGateOperatorGreaterThan && condition.Op != QualityGateOperatorLessThan {
+		return status, fmt.Errorf("%w: operator %q", ErrUnsupportedCondition, condition.Op)
+	}
+
+	metricType := measures.Types[condition.Metric]
+	if _, comparable := comparableMetricTypes[metricType]; !comparable && metricType != "" {
+		return status, fmt.Errorf("%w: metric type %s", ErrUnsupportedCondition, metricType)
+	}
+
+	threshold, err := parseThreshold(condition.Error, metricType)
+	if err != nil {
+		return status, err
+	}
+
+	if status.ActualValue == "" || measures.smallChange(condition.Metric) {
+		return status, nil
+	}
+
+	value, err := parseMeasureValue(status.ActualValue)
+	if err != nil {
+		return status, fmt.Errorf("measure %q: %w", status.ActualValue, err)
+	}
+
+	if (condition.Op == QualityGateOperatorGreaterThan && value > threshold) ||
+		(condition.Op == QualityGateOperatorLessThan && value < threshold) {
+		status.Status = QualityGateStatusError
+	}
+
+	return status, nil
+}
+
+// smallChange reports whether the conditions on metric are skipped because the change
+// has fewer than 20 new lines.
+func (m *QualityGateMeasures) smallChange(metric string) bool {
+	if _, ignored := smallChangesetMetrics[metric]; !ignored || !m.IgnoreSmallChanges {
+		return false
+	}
+
+	newLines, err := strconv.ParseFloat(m.Values[MetricNewLines], 64)
+
+	return err == nil && newLines < smallChangesetLines
+}
+
+// parseThreshold parses the threshold of a condition, a whole number for the integer
+// metric types.
+func parseThreshold(threshold, metricType string) (float64, error) {
+	if _, integer := integerMetricTypes[metricType]; integer {
+		value, err := strconv.ParseInt(threshold, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: threshold %q is not a whole number", ErrUnsupportedCondition, threshold)
+		}
+
+		return float64(value), nil
+	}
+
+	value, err := strconv.ParseFloat(threshold, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: threshold %q is not a number", ErrUnsupportedCondition, threshold)
+	}
+
+	return value, nil
+}
+
+// parseMeasureValue parses a measure value, true and false counting as 1 and 0.
+func parseMeasureValue(value string) (float64, error) {
+	switch value {
+	case "true":
+		return 1, nil
+	case "false":
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: not a number", ErrInvalidValue)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,120 @@
+package sonar
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewQualityGateMeasures(t *testing.T) {
+	var component MeasuresComponent
+
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"component": {"key": "app", "measures": [
+			{"metric": "coverage", "value": "71.4"},
+			{"metric": "new_coverage", "period": {"value": "65.0"}},
+			{"metric": "new_violations", "value": "3", "period": {"value": "3"}},
+			{"metric": "new_lines", "period": {"value": "120"}},
+			{"metric": "new_duplicated_lines_density"}
+		]},
+		"metrics": [{"key": "coverage", "type": "PERCENT"}, {"key": "new_violations", "type": "INT"}]
+	}`), &component))
+
+	measures := NewQualityGateMeasures(&component)
+
+	assert.Equal(t, map[string]string{
+		"coverage":       "71.4",
+		"new_coverage":   "65.0",
+		"new_violations": "3",
+		"new_lines":      "120",
+	}, measures.Values)
+	assert.Equal(t, map[string]string{"coverage": "PERCENT", "new_violations": "INT"}, measures.Types)
+	assert.True(t, measures.IgnoreSmallChanges)
+}
+
+func TestEvaluateQualityGate(t *testing.T) {
+	conditions := []QualityGateCondition{
+		{Metric: "new_coverage", Op: "LT", Error: "80"},
+		{Metric: "new_violations", Op: "GT", Error: "0"},
+		{Metric: "new_reliability_rating", Op: "GT", Error: "1"},
+		{Metric: "new_security_hotspots_reviewed", Op: "LT", Error: "100"},
+	}
+	measures := &QualityGateMeasures{
+		Values: map[string]string{
+			"new_coverage":           "80.0",
+			"new_violations":         "2",
+			"new_reliability_rating": "3.0",
+			"new_lines":              "500",
+		},
+		Types: map[string]string{
+			"new_coverage":           "PERCENT",
+			"new_violations":         "INT",
+			"new_reliability_rating": "RATING",
+		},
+		IgnoreSmallChanges: true,
+	}
+
+	evaluation, err := EvaluateQualityGate(conditions, measures)
+	require.NoError(t, err)
+
+	assert.Equal(t, QualityGateStatusError, evaluation.Status)
+	assert.Equal(t, []QualityGateConditionStatus{
+		{MetricKey: "new_coverage", Comparator: "LT", ErrorThreshold: "80", ActualValue: "80.0", Status: "OK"},
+		{MetricKey: "new_violations", Comparator: "GT", ErrorThreshold: "0", ActualValue: "2", Status: "ERROR"},
+		{MetricKey: "new_reliability_rating", Comparator: "GT", ErrorThreshold: "1", ActualValue: "3.0", Status: "ERROR"},
+		{MetricKey: "new_security_hotspots_reviewed", Comparator: "LT", ErrorThreshold: "100", Status: "OK"},
+	}, evaluation.Conditions)
+	assert.Len(t, evaluation.Failed(), 2)
+
+	measures.Values["new_violations"] = "0"
+	measures.Values["new_reliability_rating"] = "1.0"
+
+	evaluation, err = EvaluateQualityGate(conditions, measures)
+	require.NoError(t, err)
+	assert.Equal(t, QualityGateStatusOK, evaluation.Status)
+	assert.Empty(t, evaluation.Failed())
+}
+
+func TestEvaluateQualityGate_SmallChanges(t *testing.T) {
+	conditions := []QualityGateCondition{
+		{Metric: "new_coverage", Op: "LT", Error: "80"},
+		{Metric: "new_violations", Op: "GT", Error: "0"},
+	}
+	measures := &QualityGateMeasures{
+		Values:             map[string]string{"new_coverage": "10.0", "new_violations": "1", "new_lines": "12"},
+		Types:              nil,
+		IgnoreSmallChanges: true,
+	}
+
+	evaluation, err := EvaluateQualityGate(conditions, measures)
+	require.NoError(t, err)
+	assert.Equal(t, "OK", evaluation.Conditions[0].Status)
+	assert.Equal(t, "ERROR", evaluation.Conditions[1].Status)
+
+	measures.IgnoreSmallChanges = false
+
+	evaluation, err = EvaluateQualityGate(conditions, measures)
+	require.NoError(t, err)
+	assert.Equal(t, "ERROR", evaluation.Conditions[0].Status)
+}
+
+func TestEvaluateQualityGate_Unsupported(t *testing.T) {
+	measures := &QualityGateMeasures{
+		Values:             map[string]string{"alert_status": "OK", "new_violations": "1"},
+		Types:              map[string]string{"alert_status": "LEVEL", "new_violations": "INT"},
+		IgnoreSmallChanges: true,
+	}
+
+	for name, condition := range map[string]QualityGateCondition{
+		"operator":  {Metric: "new_violations", Op: "EQ", Error: "0"},
+		"type":      {Metric: "alert_status", Op: "GT", Error: "0"},
+		"threshold": {Metric: "new_violations", Op: "GT", Error: "0.5"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := EvaluateQualityGate([]QualityGateCondition{condition}, measures)
+			require.ErrorIs(t, err, ErrUnsupportedCondition)
+		})
+	}
+}
@@ -325,26 +325,26 @@ type QualitygatesCreateConditionOptions struct {
 // QualitygatesDeleteConditionOptions contains options for deleting a condition.
 type QualitygatesDeleteConditionOptions struct {
 	// ID is the condition UUID (required).
-	ID string `url:"id,omitempty"`
+	ID string `url:"id,omitempty" cli:"positional"`
 }
 
 // QualitygatesUnassignOptions contains options for removing a project association.
 type QualitygatesUnassignOptions struct {
 	// ProjectKey is the project key (required).
-	ProjectKey string `url:"projectKey,omitempty"`
+	ProjectKey string `url:"projectKey,omitempty" cli:"positional"`
 }
 
 // QualitygatesDeleteOptions contains options for deleting a quality gate.
 type QualitygatesDeleteOptions struct {
 	// Name is the name of the quality gate to delete (required).
 	// Maximum length: 100 characters
-	Name string `url:"name,omitempty"`
+	Name string `url:"name,omitempty" cli:"positional"`
 }
 
 // QualitygatesGetByProjectOptions contains options for getting a project's quality gate.
 type QualitygatesGetByProjectOptions struct {
 	// Project is the project key (required).
-	Project string `url:"project,omitempty"`
+	Project string `url:"project,omitempty" cli:"positional"`
 }
 
 // QualitygatesProjectStatusOptions contains options for getting project status.
@@ -454,13 +454,13 @@ type QualitygatesAssignOptions struct {
 type QualitygatesSetDefaultOptions struct {
 	// Name is the name of the quality gate to set as default (required).
 	// Maximum length: 100 characters
-	Name string `url:"name,omitempty"`
+	Name string `url:"name,omitempty" cli:"positional"`
 }
 
 // QualitygatesShowOptions contains options for showing a quality gate.
 type QualitygatesShowOptions struct {
 	// Name is the name of the quality gate (required).
-	Name string `url:"name,omitempty"`
+	Name string `url:"name,omitempty" cli:"positional"`
 }
 
 // QualitygatesUpdateConditionOptions contains options for updating a condition.
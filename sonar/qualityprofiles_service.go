@@ -466,7 +466,7 @@ type QualityprofilesActivateRuleOptions struct {
 //nolint:govet // Field alignment is less important than logical grouping
 type QualityprofilesActivateRulesOptions struct {
 	// TargetKey is the quality profile key on which rules are activated (required).
-	TargetKey string `url:"targetKey,omitempty"`
+	TargetKey string `url:"targetKey,omitempty" cli:"positional"`
 	// Activation filters rules that are activated or deactivated on the selected quality profile.
 	Activation bool `url:"activation,omitempty"`
 	// ActiveImpactSeverities filters by activation software quality severities.
@@ -683,7 +683,7 @@ type QualityprofilesDeactivateRuleOptions struct {
 //nolint:govet // Field alignment is less important than logical grouping
 type QualityprofilesDeactivateRulesOptions struct {
 	// TargetKey is the quality profile key on which rules are deactivated (required).
-	TargetKey string `url:"targetKey,omitempty"`
+	TargetKey string `url:"targetKey,omitempty" cli:"positional"`
 	// Activation filters rules that are activated or deactivated on the selected quality profile.
 	Activation bool `url:"activation,omitempty"`
 	// ActiveImpactSeverities filters by activation software quality severities.
@@ -806,7 +806,7 @@ type QualityprofilesProjectsOptions struct {
 	PaginationArgs `url:",inline"`
 
 	// Key is the quality profile key (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 	// Query limits search to projects containing this string.
 	Query string `url:"q,omitempty"`
 	// Selected filters by selection status.
@@ -939,7 +939,7 @@ type QualityprofilesSetDefaultOptions struct {
 // QualityprofilesShowOptions contains options for showing a profile.
 type QualityprofilesShowOptions struct {
 	// Key is the quality profile key (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 	// CompareToSonarWay adds the number of missing rules from related Sonar way profile.
 	CompareToSonarWay bool `url:"compareToSonarWay,omitempty"`
 }
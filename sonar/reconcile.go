@@ -0,0 +1,436 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultReconcileBatchSize is the default number of rule changes ReconcileProfile
+	// sends concurrently.
+	DefaultReconcileBatchSize = 10
+
+	// RuleChangeActivate activates a rule that is not active on the profile.
+	RuleChangeActivate = "ACTIVATE"
+	// RuleChangeUpdate changes the severity, impacts, parameters or priority of an active
+	// rule. Updating an inherited rule makes it an override.
+	RuleChangeUpdate = "UPDATE"
+	// RuleChangeReset resets an override to the activation inherited from the parent.
+	RuleChangeReset = "RESET"
+	// RuleChangeDeactivate deactivates a rule activated on the profile itself.
+	RuleChangeDeactivate = "DEACTIVATE"
+)
+
+// ErrReconcileIncomplete is returned by ReconcileProfile when some rule changes failed.
+// The result tells which ones were applied.
+var ErrReconcileIncomplete = errors.New("quality profile reconciliation incomplete")
+
+// -----------------------------------------------------------------------------
+// Shared Types
+// -----------------------------------------------------------------------------
+
+// QualityprofilesRuleActivation is the desired activation of a rule in a quality profile.
+type QualityprofilesRuleActivation struct {
+	// Key is the rule key, such as java:S138.
+	Key string `json:"key"`
+	// Severity is the severity of the activation. An empty severity keeps the current one,
+	// or the rule default for a rule that is not active yet.
+	// Cannot be used at the same time as Impacts.
+	Severity string `json:"severity,omitempty"`
+	// Impacts are the severities of the activation per software quality, such as
+	// MAINTAINABILITY: HIGH. Empty impacts keep the current ones.
+	Impacts map[string]string `json:"impacts,omitempty"`
+	// Params are rule parameters. Parameters not listed keep their current values.
+	Params map[string]string `json:"params,omitempty"`
+	// Prioritized marks the rule as prioritized in the profile.
+	Prioritized bool `json:"prioritized,omitempty"`
+}
+
+// -----------------------------------------------------------------------------
+// Response Types
+// -----------------------------------------------------------------------------
+
+// QualityprofilesRuleChange is a change ReconcileProfile makes, or would make, to a rule
+// of the profile.
+type QualityprofilesRuleChange struct {
+	// Rule is the rule key.
+	Rule string `json:"rule"`
+	// Action is RuleChangeActivate, RuleChangeUpdate, RuleChangeReset or RuleChangeDeactivate.
+	Action string `json:"action"`
+	// Inherit is the inheritance of the current activation (NONE, INHERITED, OVERRIDES),
+	// empty for a rule that is not active.
+	Inherit string `json:"inherit,omitempty"`
+	// Fields are the attributes that change, with their current and desired values.
+	Fields []QualityprofilesRuleFieldChange `json:"fields,omitempty"`
+	// Applied reports whether the change was sent successfully.
+	Applied bool `json:"applied"`
+	// Error is the reason the change failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// QualityprofilesRuleFieldChange is an attribute of a rule activation that changes, such
+// as "severity" or "params.max".
+type QualityprofilesRuleFieldChange struct {
+	// Name is the attribute.
+	Name string `json:"name"`
+	// Before is the current value, empty if unset.
+	Before string `json:"before,omitempty"`
+	// After is the desired value, empty if unset.
+	After string `json:"after,omitempty"`
+}
+
+// QualityprofilesReconcileProfileResult is the report of ReconcileProfile.
+type QualityprofilesReconcileProfileResult struct {
+	// Key is the quality profile key.
+	Key string `json:"key"`
+	// DryRun reports whether the changes were only computed.
+	DryRun bool `json:"dryRun"`
+	// Changes are the rule changes: activations, updates, resets and deactivations, each
+	// sorted by rule key.
+	Changes []QualityprofilesRuleChange `json:"changes"`
+	// Unchanged is the number of desired rules that already match their activation.
+	Unchanged int `json:"unchanged"`
+	// Inherited are the undesired rules inherited from the parent profile, which cannot
+	// be deactivated on the profile itself.
+	Inherited []string `json:"inherited,omitempty"`
+	// Batches is the number of batches of changes sent.
+	Batches int `json:"batches"`
+}
+
+// -----------------------------------------------------------------------------
+// Option Types
+// -----------------------------------------------------------------------------
+
+// QualityprofilesReconcileProfileOptions contains parameters for the ReconcileProfile method.
+//
+//nolint:govet // fieldalignment: keeping logical field grouping for readability
+type QualityprofilesReconcileProfileOptions struct {
+	// Key is the quality profile key.
+	// This field is required.
+	Key string
+	// Rules are the rules that must be active on the profile. Active rules not listed are
+	// deactivated, or reset to their inherited activation.
+	Rules []QualityprofilesRuleActivation
+	// DryRun computes the changes without sending them.
+	DryRun bool
+	// BatchSize is the number of changes sent concurrently (default
+	// DefaultReconcileBatchSize). A batch starts once the previous one succeeded.
+	BatchSize int
+}
+
+// -----------------------------------------------------------------------------
+// Validation Functions
+// -----------------------------------------------------------------------------
+
+// ValidateReconcileProfileOpt validates the options for the ReconcileProfile method.
+func (s *QualityprofilesService) ValidateReconcileProfileOpt(opt *QualityprofilesReconcileProfileOptions) error {
+	if opt == nil {
+		return NewValidationError("QualityprofilesReconcileProfileOptions", "cannot be nil", ErrMissingRequired)
+	}
+
+	err := ValidateRequired(opt.Key, "Key")
+	if err != nil {
+		return err
+	}
+
+	if opt.BatchSize < 0 {
+		return NewValidationError("BatchSize", "must not be negative", ErrOutOfRange)
+	}
+
+	seen := make(map[string]bool, len(opt.Rules))
+
+	for _, rule := range opt.Rules {
+		if seen[rule.Key] {
+			return NewValidationError("Rules", fmt.Sprintf("rule %s is listed twice", rule.Key), ErrInvalidValue)
+		}
+
+		seen[rule.Key] = true
+
+		err = s.ValidateActivateRuleOpt(activateRuleOptions(opt.Key, rule, nil))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Service Methods
+// -----------------------------------------------------------------------------
+
+// ReconcileProfile makes the active rules of a quality profile match the desired list: it
+// activates the missing rules, updates the severity, impacts, parameters or priority of
+// those that differ, and deactivates the active rules not listed.
+//
+// The current activations are read with Rules.Search. Rules inherited from the parent
+// profile cannot be deactivated on the profile itself: undesired inherited rules are
+// reported in Inherited, and undesired overrides are reset to the inherited activation.
+// Updating an inherited rule makes it an override.
+//
+// With DryRun, the changes are only computed. Otherwise they are sent in batches of
+// BatchSize concurrent requests, and a batch with a failed change stops the
+// reconciliation: the result tells which changes were applied, and the error wraps
+// ErrReconcileIncomplete.
+func (s *QualityprofilesService) ReconcileProfile(ctx context.Context, opt *QualityprofilesReconcileProfileOptions) (*QualityprofilesReconcileProfileResult, *http.Response, error) {
+	err := s.ValidateReconcileProfileOpt(opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current, resp, err := s.client.Rules.SearchActivations(ctx, &RulesSearchActivationsOptions{Qprofile: opt.Key})
+	if err != nil {
+		return nil, resp, fmt.Errorf("failed to read the rules of quality profile %s: %w", opt.Key, err)
+	}
+
+	result, steps := planReconciliation(opt, current)
+	if opt.DryRun || len(steps) == 0 {
+		return result, resp, nil
+	}
+
+	batchSize := opt.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultReconcileBatchSize
+	}
+
+	for start := 0; start < len(steps); start += batchSize {
+		end := min(start+batchSize, len(steps))
+		result.Batches++
+
+		failed := s.applyBatch(ctx, opt.Key, steps[start:end], result.Changes[start:end])
+		if failed > 0 {
+			return result, resp, fmt.Errorf("%w: %d of %d changes to %s failed, %d not sent",
+				ErrReconcileIncomplete, failed, end-start, opt.Key, len(steps)-end)
+		}
+	}
+
+	return result, resp, nil
+}
+
+// applyBatch sends the changes of a batch concurrently, records their outcome and
+// returns the number of failures.
+func (s *QualityprofilesService) applyBatch(ctx context.Context, profileKey string, steps []reconcileStep, changes []QualityprofilesRuleChange) int {
+	var wg sync.WaitGroup
+
+	for i, step := range steps {
+		wg.Go(func() {
+			var err error
+
+			if step.options == nil {
+				_, err = s.DeactivateRule(ctx, &QualityprofilesDeactivateRuleOptions{Key: profileKey, Rule: step.change.Rule})
+			} else {
+				_, err = s.ActivateRule(ctx, step.options)
+			}
+			if err != nil {
+				changes[i].Error = err.Error()
+
+				return
+			}
+
+			changes[i].Applied = true
+		})
+	}
+
+	wg.Wait()
+
+	failed := 0
+
+	for _, change := range changes {
+		if !change.Applied {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// planReconciliation computes the changes reconciling the current activations with the
+// desired rules. The changes of the result and the steps applying them are in the same
+// order.
+func planReconciliation(opt *QualityprofilesReconcileProfileOptions, current map[string]RulesActivation) (*QualityprofilesReconcileProfileResult, []reconcileStep) {
+	result := &QualityprofilesReconcileProfileResult{ //nolint:exhaustruct // filled below
+		Key:     opt.Key,
+		DryRun:  opt.DryRun,
+		Changes: []QualityprofilesRuleChange{},
+	}
+
+	var activations, updates, resets, deactivations []reconcileStep
+
+	desired := make(map[string]bool, len(opt.Rules))
+
+	for _, rule := range sortedRules(opt.Rules) {
+		desired[rule.Key] = true
+
+		active, found := current[rule.Key]
+		if !found {
+			activations = append(activations, reconcileStep{
+				change:  QualityprofilesRuleChange{Rule: rule.Key, Action: RuleChangeActivate, Fields: activationFields(rule)}, //nolint:exhaustruct // outcome set on apply
+				options: activateRuleOptions(opt.Key, rule, nil),
+			})
+
+			continue
+		}
+
+		fields := ruleFieldChanges(active, rule)
+		if len(fields) == 0 {
+			result.Unchanged++
+
+			continue
+		}
+
+		updates = append(updates, reconcileStep{
+			change:  QualityprofilesRuleChange{Rule: rule.Key, Action: RuleChangeUpdate, Inherit: active.Inherit, Fields: fields}, //nolint:exhaustruct // outcome set on apply
+			options: activateRuleOptions(opt.Key, rule, &active),
+		})
+	}
+
+	for _, ruleKey := range slices.Sorted(maps.Keys(current)) {
+		if desired[ruleKey] {
+			continue
+		}
+
+		switch active := current[ruleKey]; active.Inherit {
+		case InheritanceTypeInherited:
+			result.Inherited = append(result.Inherited, ruleKey)
+		case InheritanceTypeOverrides:
+			resets = append(resets, reconcileStep{
+				change: QualityprofilesRuleChange{ //nolint:exhaustruct // outcome set on apply
+					Rule:    ruleKey,
+					Action:  RuleChangeReset,
+					Inherit: active.Inherit,
+					Fields:  []QualityprofilesRuleFieldChange{{Name: "inherit", Before: InheritanceTypeOverrides, After: InheritanceTypeInherited}},
+				},
+				options: &QualityprofilesActivateRuleOptions{Key: opt.Key, Rule: ruleKey, Reset: true}, //nolint:exhaustruct // reset to the parent activation
+			})
+		default:
+			deactivations = append(deactivations, reconcileStep{
+				change: QualityprofilesRuleChange{ //nolint:exhaustruct // outcome set on apply
+					Rule:    ruleKey,
+					Action:  RuleChangeDeactivate,
+					Inherit: active.Inherit,
+					Fields:  []QualityprofilesRuleFieldChange{{Name: "severity", Before: active.Severity, After: ""}},
+				},
+				options: nil,
+			})
+		}
+	}
+
+	steps := slices.Concat(activations, updates, resets, deactivations)
+
+	for _, step := range steps {
+		result.Changes = append(result.Changes, step.change)
+	}
+
+	return result, steps
+}
+
+// reconcileStep is a planned rule change and the options applying it.
+type reconcileStep struct {
+	change QualityprofilesRuleChange
+	// options activate, update or reset the rule; nil for a deactivation.
+	options *QualityprofilesActivateRuleOptions
+}
+
+// sortedRules returns the rules sorted by key.
+func sortedRules(rules []QualityprofilesRuleActivation) []QualityprofilesRuleActivation {
+	return slices.SortedFunc(slices.Values(rules), func(a, b QualityprofilesRuleActivation) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+}
+
+// activateRuleOptions returns the options activating a desired rule. For a rule already
+// active, attributes the rule does not set keep their current values.
+func activateRuleOptions(profileKey string, rule QualityprofilesRuleActivation, active *RulesActivation) *QualityprofilesActivateRuleOptions {
+	severity, params := rule.Severity, rule.Params
+
+	if active != nil {
+		params = activationParams(*active)
+		maps.Copy(params, rule.Params)
+
+		if severity == "" && len(rule.Impacts) == 0 {
+			severity = active.Severity
+		}
+	}
+
+	return &QualityprofilesActivateRuleOptions{ //nolint:exhaustruct // no reset
+		Key:             profileKey,
+		Rule:            rule.Key,
+		Severity:        severity,
+		Impacts:         rule.Impacts,
+		Params:          params,
+		PrioritizedRule: rule.Prioritized,
+	}
+}
+
+// activationFields returns the attributes a desired rule sets on activation.
+func activationFields(rule QualityprofilesRuleActivation) []QualityprofilesRuleFieldChange {
+	fields := fieldChange(nil, "severity", "", rule.Severity)
+
+	for _, quality := range slices.Sorted(maps.Keys(rule.Impacts)) {
+		fields = fieldChange(fields, "impacts."+quality, "", rule.Impacts[quality])
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(rule.Params)) {
+		fields = fieldChange(fields, "params."+name, "", rule.Params[name])
+	}
+
+	if rule.Prioritized {
+		fields = fieldChange(fields, "prioritized", "", strconv.FormatBool(true))
+	}
+
+	return fields
+}
+
+// ruleFieldChanges compares the attributes a desired rule sets with its activation.
+func ruleFieldChanges(active RulesActivation, rule QualityprofilesRuleActivation) []QualityprofilesRuleFieldChange {
+	var fields []QualityprofilesRuleFieldChange
+
+	if rule.Severity != "" {
+		fields = fieldChange(fields, "severity", active.Severity, rule.Severity)
+	}
+
+	impacts := make(map[string]string, len(active.Impacts))
+
+	for _, impact := range active.Impacts {
+		impacts[impact.SoftwareQuality] = impact.Severity
+	}
+
+	for _, quality := range slices.Sorted(maps.Keys(rule.Impacts)) {
+		fields = fieldChange(fields, "impacts."+quality, impacts[quality], rule.Impacts[quality])
+	}
+
+	params := activationParams(active)
+
+	for _, name := range slices.Sorted(maps.Keys(rule.Params)) {
+		fields = fieldChange(fields, "params."+name, params[name], rule.Params[name])
+	}
+
+	return fieldChange(fields, "prioritized", strconv.FormatBool(active.PrioritizedRule), strconv.FormatBool(rule.Prioritized))
+}
+
+// fieldChange appends the change of an attribute to fields when its values differ.
+func fieldChange(fields []QualityprofilesRuleFieldChange, name, before, after string) []QualityprofilesRuleFieldChange {
+	if before == after {
+		return fields
+	}
+
+	return append(fields, QualityprofilesRuleFieldChange{Name: name, Before: before, After: after})
+}
+
+// activationParams returns the parameters of an activation by name.
+func activationParams(active RulesActivation) map[string]string {
+	params := make(map[string]string, len(active.Params))
+
+	for _, param := range active.Params {
+		params[param.Key] = param.Value
+	}
+
+	return params
+}
@@ -0,0 +1,181 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reconcileServer serves the activations of profile P1 and records the rule changes,
+// failing the activation of the rules in failing.
+type reconcileServer struct {
+	mu      sync.Mutex
+	changes []string
+	failing []string
+}
+
+// handler returns the handler of the server.
+func (s *reconcileServer) handler(t *testing.T) http.HandlerFunc {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/rules/search" {
+			assert.Equal(t, "P1", r.URL.Query().Get("qprofile"))
+			assert.Equal(t, "true", r.URL.Query().Get("activation"))
+			_, _ = w.Write([]byte(`{"paging": {"pageIndex": 1, "pageSize": 500, "total": 5},
+				"rules": [{"key": "java:S1"}, {"key": "java:S2"}, {"key": "java:S3"}, {"key": "java:S4"}, {"key": "java:S5"}],
+				"actives": {
+					"java:S1": [{"qProfile": "P1", "inherit": "NONE", "severity": "MAJOR", "params": [{"key": "max", "value": "10"}, {"key": "min", "value": "1"}]}],
+					"java:S2": [{"qProfile": "P1", "inherit": "NONE", "severity": "MINOR"}],
+					"java:S3": [{"qProfile": "P1", "inherit": "INHERITED", "severity": "MAJOR"}],
+					"java:S4": [{"qProfile": "P1", "inherit": "OVERRIDES", "severity": "BLOCKER"}, {"qProfile": "P0", "severity": "MAJOR"}],
+					"java:S5": [{"qProfile": "P1", "inherit": "NONE", "severity": "MAJOR"}]
+				}}`))
+
+			return
+		}
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+
+		// Parameters are sent in no particular order.
+		params := strings.Split(r.FormValue("params"), ";")
+		slices.Sort(params)
+
+		rule := r.FormValue("rule")
+		change := r.URL.Path + " " + rule + " " + r.FormValue("severity") + " " + strings.Join(params, ";") + " " + r.FormValue("reset")
+
+		s.mu.Lock()
+		s.changes = append(s.changes, change)
+		s.mu.Unlock()
+
+		if slices.Contains(s.failing, rule) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": [{"msg": "Rule not found"}]}`))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// desiredRules updates S1, keeps S5 and activates S6: S2 is deactivated, S3 is inherited
+// and S4 is reset.
+func desiredRules() []QualityprofilesRuleActivation {
+	return []QualityprofilesRuleActivation{
+		{Key: "java:S6", Severity: "BLOCKER"},
+		{Key: "java:S1", Severity: "CRITICAL", Params: map[string]string{"max": "20"}},
+		{Key: "java:S5", Severity: "MAJOR"},
+	}
+}
+
+func TestQualityprofiles_ReconcileProfile_DryRun(t *testing.T) {
+	fake := &reconcileServer{} //nolint:exhaustruct // no failures
+	server := newTestServer(t, fake.handler(t))
+	client := newTestClient(t, server.url())
+
+	result, _, err := client.Qualityprofiles.ReconcileProfile(context.Background(), &QualityprofilesReconcileProfileOptions{
+		Key: "P1", Rules: desiredRules(), DryRun: true,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, fake.changes)
+
+	assert.Equal(t, &QualityprofilesReconcileProfileResult{
+		Key:    "P1",
+		DryRun: true,
+		Changes: []QualityprofilesRuleChange{
+			{Rule: "java:S6", Action: RuleChangeActivate, Fields: []QualityprofilesRuleFieldChange{{Name: "severity", After: "BLOCKER"}}},
+			{Rule: "java:S1", Action: RuleChangeUpdate, Inherit: InheritanceTypeNone, Fields: []QualityprofilesRuleFieldChange{
+				{Name: "severity", Before: "MAJOR", After: "CRITICAL"},
+				{Name: "params.max", Before: "10", After: "20"},
+			}},
+			{Rule: "java:S4", Action: RuleChangeReset, Inherit: InheritanceTypeOverrides, Fields: []QualityprofilesRuleFieldChange{
+				{Name: "inherit", Before: InheritanceTypeOverrides, After: InheritanceTypeInherited},
+			}},
+			{Rule: "java:S2", Action: RuleChangeDeactivate, Inherit: InheritanceTypeNone, Fields: []QualityprofilesRuleFieldChange{
+				{Name: "severity", Before: "MINOR"},
+			}},
+		},
+		Unchanged: 1,
+		Inherited: []string{"java:S3"},
+	}, result)
+}
+
+func TestQualityprofiles_ReconcileProfile_Apply(t *testing.T) {
+	fake := &reconcileServer{} //nolint:exhaustruct // no failures
+	server := newTestServer(t, fake.handler(t))
+	client := newTestClient(t, server.url())
+
+	result, _, err := client.Qualityprofiles.ReconcileProfile(context.Background(), &QualityprofilesReconcileProfileOptions{
+		Key: "P1", Rules: desiredRules(), BatchSize: 3,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Batches)
+
+	for _, change := range result.Changes {
+		assert.True(t, change.Applied, change.Rule)
+	}
+
+	// Parameters not listed keep their current values.
+	assert.ElementsMatch(t, []string{
+		"/qualityprofiles/activate_rule java:S6 BLOCKER  ",
+		"/qualityprofiles/activate_rule java:S1 CRITICAL max=20;min=1 ",
+		"/qualityprofiles/activate_rule java:S4   true",
+		"/qualityprofiles/deactivate_rule java:S2   ",
+	}, fake.changes)
+}
+
+func TestQualityprofiles_ReconcileProfile_Failure(t *testing.T) {
+	fake := &reconcileServer{failing: []string{"java:S6"}} //nolint:exhaustruct // no changes yet
+	server := newTestServer(t, fake.handler(t))
+	client := newTestClient(t, server.url())
+
+	result, _, err := client.Qualityprofiles.ReconcileProfile(context.Background(), &QualityprofilesReconcileProfileOptions{
+		Key: "P1", Rules: desiredRules(), BatchSize: 2,
+	})
+	require.ErrorIs(t, err, ErrReconcileIncomplete)
+	assert.Contains(t, err.Error(), "1 of 2 changes to P1 failed, 2 not sent")
+
+	assert.Equal(t, 1, result.Batches)
+	assert.False(t, result.Changes[0].Applied)
+	assert.Contains(t, result.Changes[0].Error, "Rule not found")
+	assert.True(t, result.Changes[1].Applied)
+	assert.False(t, result.Changes[2].Applied)
+	assert.Empty(t, result.Changes[2].Error)
+	assert.Len(t, fake.changes, 2)
+}
+
+func TestQualityprofiles_ValidateReconcileProfileOpt(t *testing.T) {
+	client := newLocalhostClient(t)
+
+	tests := []struct {
+		name string
+		opt  *QualityprofilesReconcileProfileOptions
+	}{
+		{name: "nil options", opt: nil},
+		{name: "missing key", opt: &QualityprofilesReconcileProfileOptions{}},                                 //nolint:exhaustruct // missing key
+		{name: "negative batch size", opt: &QualityprofilesReconcileProfileOptions{Key: "P1", BatchSize: -1}}, //nolint:exhaustruct // batch size only
+		{name: "duplicate rule", opt: &QualityprofilesReconcileProfileOptions{Key: "P1", Rules: []QualityprofilesRuleActivation{{Key: "java:S1"}, {Key: "java:S1"}}}}, //nolint:exhaustruct // rules only
+		{name: "severity and impacts", opt: &QualityprofilesReconcileProfileOptions{Key: "P1", Rules: []QualityprofilesRuleActivation{ //nolint:exhaustruct // rules only
+			{Key: "java:S1", Severity: "MAJOR", Impacts: map[string]string{"MAINTAINABILITY": "HIGH"}},
+		}}},
+		{name: "invalid severity", opt: &QualityprofilesReconcileProfileOptions{Key: "P1", Rules: []QualityprofilesRuleActivation{{Key: "java:S1", Severity: "SEVERE"}}}}, //nolint:exhaustruct // rules only
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := client.Qualityprofiles.ReconcileProfile(context.Background(), tt.opt)
+			require.Error(t, err)
+		})
+	}
+}
@@ -21,7 +21,7 @@ type RegulatoryReportsDownloadOptions struct {
 	// Branch is the branch key. Optional.
 	Branch string `url:"branch,omitempty"`
 	// Project is the project key. This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 }
 
 // -----------------------------------------------------------------------------
@@ -427,7 +427,7 @@ type RulesCreateOptions struct {
 // RulesDeleteOptions contains options for deleting a custom rule.
 type RulesDeleteOptions struct {
 	// Key is the unique identifier of the rule to be deleted (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 }
 
 // RulesListOptions contains options for listing rules.
@@ -569,10 +569,17 @@ type RulesSearchOptions struct {
 	Types []string `url:"types,omitempty,comma"`
 }
 
+// RulesSearchActivationsOptions contains options for listing the rule activations of a
+// quality profile.
+type RulesSearchActivationsOptions struct {
+	// Qprofile is the key of the quality profile (required).
+	Qprofile string `url:"qprofile,omitempty"`
+}
+
 // RulesShowOptions contains options for showing a specific rule.
 type RulesShowOptions struct {
 	// Key is the unique identifier of the rule to be retrieved (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 	// Actives determines whether to include the list of quality profiles where the rule is active.
 	Actives bool `url:"actives,omitempty"`
 }
@@ -593,7 +600,7 @@ type RulesUpdateOptions struct {
 	Impacts map[string]string `url:"impacts,omitempty"`
 	// Key is the unique identifier of the rule to be updated (required).
 	// Maximum length: 200 characters
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 	// MarkdownDescription is the Markdown-formatted description of the rule.
 	// Mandatory for custom and manual rules.
 	MarkdownDescription string `url:"markdownDescription,omitempty"`
@@ -1074,6 +1081,21 @@ func (s *RulesService) ValidateSearchOpt(opt *RulesSearchOptions) error {
 	return nil
 }
 
+// ValidateSearchActivationsOpt validates the options for listing the rule activations of
+// a quality profile.
+func (s *RulesService) ValidateSearchActivationsOpt(opt *RulesSearchActivationsOptions) error {
+	if opt == nil {
+		return NewValidationError("RulesSearchActivationsOption", "cannot be nil", ErrMissingRequired)
+	}
+
+	err := ValidateRequired(opt.Qprofile, "Qprofile")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ValidateShowOpt validates the options for showing a specific rule.
 func (s *RulesService) ValidateShowOpt(opt *RulesShowOptions) error {
 	if opt == nil {
@@ -1187,6 +1209,46 @@ func (s *RulesService) SearchAll(ctx context.Context, opt *RulesSearchOptions) (
 	})
 }
 
+// SearchActivations fetches all pages of the rules activated on a quality profile and
+// returns their activation on that profile, by rule key. SearchAll drops the activations.
+func (s *RulesService) SearchActivations(ctx context.Context, opt *RulesSearchActivationsOptions) (map[string]RulesActivation, *http.Response, error) {
+	err := s.ValidateSearchActivationsOpt(opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profileKey := opt.Qprofile
+	activations := make(map[string]RulesActivation)
+
+	search := &RulesSearchOptions{ //nolint:exhaustruct // only the activation filter is needed
+		Qprofile:   profileKey,
+		Activation: true,
+		Fields:     []string{"actives"},
+	}
+	search.PageSize = MaxPageSize
+
+	for page := int64(1); ; page++ {
+		search.Page = page
+
+		result, resp, err := s.Search(ctx, search)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for ruleKey, actives := range result.Actives {
+			for _, active := range actives {
+				if active.QProfile == profileKey {
+					activations[ruleKey] = active
+				}
+			}
+		}
+
+		if len(result.Rules) == 0 || page*search.PageSize >= result.Paging.Total {
+			return activations, resp, nil
+		}
+	}
+}
+
 // convertCreateOptForURL converts RulesCreateOptions to a URL-encodable format.
 func (s *RulesService) convertCreateOptForURL(opt *RulesCreateOptions) *rulesCreateURLOptions {
 	//nolint:exhaustruct // Only populate fields that have values
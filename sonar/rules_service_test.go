@@ -876,3 +876,38 @@ func TestRulesService_SearchAll(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestRulesService_SearchActivations(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var pages []string
+
+		server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "qp1", r.URL.Query().Get("qprofile"))
+			assert.Equal(t, "true", r.URL.Query().Get("activation"))
+			pages = append(pages, r.URL.Query().Get("p"))
+			w.Header().Set("Content-Type", "application/json")
+			if len(pages) == 1 {
+				_, _ = w.Write([]byte(`{"paging":{"pageIndex":1,"pageSize":500,"total":501},"rules":[{"key":"rule1"}],` +
+					`"actives":{"rule1":[{"qProfile":"qp1","severity":"MAJOR"},{"qProfile":"qp2","severity":"MINOR"}]}}`))
+			} else {
+				_, _ = w.Write([]byte(`{"paging":{"pageIndex":2,"pageSize":500,"total":501},"rules":[{"key":"rule2"}],` +
+					`"actives":{"rule2":[{"qProfile":"qp1","severity":"BLOCKER","inherit":"INHERITED"}]}}`))
+			}
+		})
+
+		client := newTestClient(t, server.URL)
+		activations, _, err := client.Rules.SearchActivations(context.Background(), &RulesSearchActivationsOptions{Qprofile: "qp1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, pages)
+		require.Len(t, activations, 2)
+		assert.Equal(t, "MAJOR", activations["rule1"].Severity)
+		assert.Equal(t, "BLOCKER", activations["rule2"].Severity)
+		assert.Equal(t, InheritanceTypeInherited, activations["rule2"].Inherit)
+	})
+
+	t.Run("missing profile key", func(t *testing.T) {
+		client := newLocalhostClient(t)
+		_, _, err := client.Rules.SearchActivations(context.Background(), &RulesSearchActivationsOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -81,7 +81,7 @@ type SecurityReportsDownloadOptions struct {
 	// Branch filters the results by branch. Optional.
 	Branch string `url:"branch,omitempty"`
 	// Project is the project key. This field is required.
-	Project string `url:"project"`
+	Project string `url:"project" cli:"positional"`
 
 	// Standards is the list of standards to include in the report. If
 	// omitted, all standards are included. Optional.
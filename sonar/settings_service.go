@@ -142,7 +142,7 @@ type SettingsResetOptions struct {
 	// Only keys for projects, applications, portfolios or subportfolios are accepted.
 	Component string `url:"component,omitempty"`
 	// Keys is the list of setting keys to reset (required).
-	Keys []string `url:"keys,omitempty,comma"`
+	Keys []string `url:"keys,omitempty,comma" cli:"positional"`
 }
 
 // JSONEncodedMap is a wrapper type for map[string]any that encodes as JSON when used in URL parameters.
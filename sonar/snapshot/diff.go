@@ -0,0 +1,326 @@
+package snapshot
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+// DifferenceType is the kind of a difference between two snapshots.
+type DifferenceType string
+
+const (
+	// DifferenceAdded is an object or value the second snapshot has and the first has not.
+	DifferenceAdded DifferenceType = "added"
+	// DifferenceRemoved is an object or value the first snapshot has and the second has not.
+	DifferenceRemoved DifferenceType = "removed"
+	// DifferenceChanged is a value that differs between the snapshots.
+	DifferenceChanged DifferenceType = "changed"
+)
+
+// Difference is a difference between two snapshots.
+type Difference struct {
+	// Section is the section of the object, such as SectionQualityGates.
+	Section string `json:"section" yaml:"section"`
+	// Object identifies the object, such as "java/Company Java" for a quality profile.
+	Object string `json:"object" yaml:"object"`
+	// Field is the part of the object that differs, such as "condition new_coverage".
+	// It is empty when the object itself was added or removed, or is a single value.
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+	// Type is the kind of difference.
+	Type DifferenceType `json:"type" yaml:"type"`
+	// From is the value in the first snapshot.
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+	// To is the value in the second snapshot.
+	To string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// Compare returns the differences between two snapshots, from the first to the second,
+// in quality gates and their conditions, quality profiles and their rule activations,
+// permission templates, global permissions, global settings and webhooks. Differences
+// are sorted by section, object and field.
+//
+// Rule activations are compared through the profile backups with sonar.DiffProfileBackups,
+// so that profiles of two instances can be compared; built-in profiles have none and only their parent and
+// default status are compared. Redacted secrets compare equal.
+func Compare(from, to *Snapshot) ([]Difference, error) {
+	d := &differ{differences: nil}
+
+	compareObjects(d, SectionQualityGates, from.QualityGates, to.QualityGates,
+		func(gate QualityGate) string { return gate.Name }, nil, d.qualityGate)
+
+	var err error
+
+	compareObjects(d, SectionQualityProfiles, from.QualityProfiles, to.QualityProfiles,
+		func(profile QualityProfile) string { return profile.Language + "/" + profile.Name }, nil,
+		func(object string, a, b QualityProfile) {
+			if err == nil {
+				err = d.qualityProfile(object, a, b)
+			}
+		})
+
+	if err != nil {
+		return nil, err
+	}
+
+	compareObjects(d, SectionPermissionTemplates, from.PermissionTemplates, to.PermissionTemplates,
+		func(template PermissionTemplate) string { return template.Name }, nil, d.permissionTemplate)
+
+	d.permissions(SectionGlobalPermissions, "", from.GlobalPermissions, to.GlobalPermissions)
+
+	compareObjects(d, SectionSettings, from.Settings, to.Settings,
+		func(setting Setting) string { return setting.Key }, settingText,
+		func(object string, a, b Setting) {
+			d.value(SectionSettings, object, "", settingText(a), settingText(b))
+		})
+
+	compareObjects(d, SectionWebhooks, from.Webhooks, to.Webhooks,
+		func(webhook Webhook) string { return webhook.Name },
+		func(webhook Webhook) string { return webhook.URL },
+		func(object string, a, b Webhook) {
+			d.value(SectionWebhooks, object, "url", a.URL, b.URL)
+			d.value(SectionWebhooks, object, "secret", a.Secret, b.Secret)
+		})
+
+	slices.SortStableFunc(d.differences, func(a, b Difference) int {
+		return cmp.Or(
+			cmp.Compare(sectionOrder(a.Section), sectionOrder(b.Section)),
+			strings.Compare(a.Object, b.Object),
+			strings.Compare(a.Field, b.Field),
+		)
+	})
+
+	return d.differences, nil
+}
+
+// comparedSections are the sections Compare compares, in the order of its result.
+//
+//nolint:gochecknoglobals // constant lookup table
+var comparedSections = []string{
+	SectionQualityGates,
+	SectionQualityProfiles,
+	SectionPermissionTemplates,
+	SectionGlobalPermissions,
+	SectionSettings,
+	SectionWebhooks,
+}
+
+// sectionOrder returns the position of a section in the result of Compare.
+func sectionOrder(section string) int {
+	return slices.Index(comparedSections, section)
+}
+
+// differ collects the differences between two snapshots.
+type differ struct {
+	differences []Difference
+}
+
+// add records a difference.
+func (d *differ) add(section, object, field string, diffType DifferenceType, from, to string) {
+	d.differences = append(d.differences, Difference{
+		Section: section,
+		Object:  object,
+		Field:   field,
+		Type:    diffType,
+		From:    from,
+		To:      to,
+	})
+}
+
+// value records the difference between two values of a field, if any. An empty value
+// stands for an absent one.
+func (d *differ) value(section, object, field, from, to string) {
+	switch {
+	case from == to:
+	case from == "":
+		d.add(section, object, field, DifferenceAdded, "", to)
+	case to == "":
+		d.add(section, object, field, DifferenceRemoved, from, "")
+	default:
+		d.add(section, object, field, DifferenceChanged, from, to)
+	}
+}
+
+// compareObjects records the objects of a section only one snapshot has, with the value
+// describe returns if it is not nil, and calls compare with the objects both have.
+func compareObjects[T any](d *differ, section string, from, to []T, key, describe func(T) string, compare func(object string, a, b T)) {
+	text := func(object T) string {
+		if describe == nil {
+			return ""
+		}
+
+		return describe(object)
+	}
+
+	byKey := make(map[string]T, len(from))
+
+	for _, object := range from {
+		byKey[key(object)] = object
+	}
+
+	seen := make(map[string]bool, len(to))
+
+	for _, object := range to {
+		name := key(object)
+		seen[name] = true
+
+		previous, found := byKey[name]
+		if !found {
+			d.add(section, name, "", DifferenceAdded, "", text(object))
+
+			continue
+		}
+
+		compare(name, previous, object)
+	}
+
+	for _, object := range from {
+		if name := key(object); !seen[name] {
+			d.add(section, name, "", DifferenceRemoved, text(object), "")
+		}
+	}
+}
+
+// qualityGate records the differences between two versions of a gate.
+func (d *differ) qualityGate(object string, a, b QualityGate) {
+	d.value(SectionQualityGates, object, "default", formatFlag(a.Default), formatFlag(b.Default))
+
+	conditions := func(gate QualityGate) map[string]string {
+		byMetric := make(map[string]string, len(gate.Conditions))
+
+		for _, condition := range gate.Conditions {
+			byMetric[condition.Metric] = condition.Op + " " + condition.Error
+		}
+
+		return byMetric
+	}
+
+	d.values(SectionQualityGates, object, "condition ", conditions(a), conditions(b))
+}
+
+// qualityProfile records the differences between two versions of a profile.
+func (d *differ) qualityProfile(object string, a, b QualityProfile) error {
+	d.value(SectionQualityProfiles, object, "parent", a.Parent, b.Parent)
+	d.value(SectionQualityProfiles, object, "default", formatFlag(a.Default), formatFlag(b.Default))
+
+	if a.Backup == "" || b.Backup == "" {
+		return nil
+	}
+
+	from, err := sonar.ParseProfileBackup(a.Backup)
+	if err != nil {
+		return fmt.Errorf("invalid backup of quality profile %s: %w", object, err)
+	}
+
+	to, err := sonar.ParseProfileBackup(b.Backup)
+	if err != nil {
+		return fmt.Errorf("invalid backup of quality profile %s: %w", object, err)
+	}
+
+	for _, difference := range sonar.DiffProfileBackups(from, to) {
+		field := difference.Field
+		if difference.Rule != "" {
+			field = strings.TrimSuffix("rule "+difference.Rule+" "+field, " ")
+		}
+
+		d.add(SectionQualityProfiles, object, field, DifferenceType(difference.Type), difference.From, difference.To)
+	}
+
+	return nil
+}
+
+// permissionTemplate records the differences between two versions of a template.
+func (d *differ) permissionTemplate(object string, a, b PermissionTemplate) {
+	d.value(SectionPermissionTemplates, object, "description", a.Description, b.Description)
+	d.value(SectionPermissionTemplates, object, "projectKeyPattern", a.ProjectKeyPattern, b.ProjectKeyPattern)
+	d.value(SectionPermissionTemplates, object, "default for", formatList(a.DefaultFor), formatList(b.DefaultFor))
+	d.value(SectionPermissionTemplates, object, "project creator", formatList(a.ProjectCreator), formatList(b.ProjectCreator))
+	d.permissions(SectionPermissionTemplates, object, a.Permissions, b.Permissions)
+}
+
+// permissions records the differences between the permissions granted to each group and
+// user. Global permissions have no object: the principals are the objects.
+func (d *differ) permissions(section, object string, a, b Permissions) {
+	grants := func(permissions Permissions) map[string]string {
+		byPrincipal := make(map[string]string, len(permissions.Groups)+len(permissions.Users))
+
+		for group, granted := range permissions.Groups {
+			byPrincipal["group "+group] = formatList(granted)
+		}
+
+		for user, granted := range permissions.Users {
+			byPrincipal["user "+user] = formatList(granted)
+		}
+
+		return byPrincipal
+	}
+
+	from, to := grants(a), grants(b)
+
+	if object != "" {
+		d.values(section, object, "", from, to)
+
+		return
+	}
+
+	for _, principal := range sortedUnion(from, to) {
+		d.value(section, principal, "", from[principal], to[principal])
+	}
+}
+
+// values records the differences between two sets of named values of an object, each
+// name prefixed to make the field.
+func (d *differ) values(section, object, prefix string, from, to map[string]string) {
+	for _, name := range sortedUnion(from, to) {
+		d.value(section, object, prefix+name, from[name], to[name])
+	}
+}
+
+// sortedUnion returns the keys of two maps, sorted.
+func sortedUnion(a, b map[string]string) []string {
+	keys := slices.Collect(maps.Keys(a))
+
+	for key := range b {
+		if _, found := a[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// settingText returns the value of a setting as text.
+func settingText(setting Setting) string {
+	switch {
+	case setting.FieldValues != nil:
+		text, _ := json.Marshal(setting.FieldValues) //nolint:errchkjson // maps of strings always encode
+
+		return string(text)
+	case setting.Values != nil:
+		return formatList(setting.Values)
+	default:
+		return setting.Value
+	}
+}
+
+// formatFlag returns "true" for a set flag and "" otherwise, so that a flag set on one
+// side only is reported as added or removed.
+func formatFlag(flag bool) string {
+	if flag {
+		return "true"
+	}
+
+	return ""
+}
+
+// formatList returns the values joined with commas.
+func formatList(values []string) string {
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,114 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backupWithRules returns a profile backup activating the given rules.
+func backupWithRules(rules string) string {
+	return `<?xml version='1.0' encoding='UTF-8'?><profile><name>Company</name><language>java</language><rules>` +
+		rules + `</rules></profile>`
+}
+
+func TestCompare(t *testing.T) {
+	from := &Snapshot{
+		QualityGates: []QualityGate{
+			{Name: "Strict", Default: true, Conditions: []Condition{
+				{Metric: "new_coverage", Op: "LT", Error: "80"},
+				{Metric: "new_violations", Op: "GT", Error: "0"},
+			}},
+			{Name: "Legacy"},
+		},
+		QualityProfiles: []QualityProfile{
+			{Name: "Company", Language: "java", Parent: "Sonar way", Backup: backupWithRules(`
+				<rule><repositoryKey>java</repositoryKey><key>S100</key><priority>MAJOR</priority>
+					<parameters><parameter><key>format</key><value>^[a-z]+$</value></parameter></parameters></rule>
+				<rule><repositoryKey>java</repositoryKey><key>S101</key><priority>MINOR</priority></rule>`)},
+			{Name: "Sonar way", Language: "java", BuiltIn: true},
+		},
+		PermissionTemplates: []PermissionTemplate{{
+			Name:        "Default template",
+			DefaultFor:  []string{"TRK"},
+			Permissions: Permissions{Groups: map[string][]string{"developers": {"codeviewer", "user"}}},
+		}},
+		GlobalPermissions: Permissions{
+			Groups: map[string][]string{"sonar-administrators": {"admin"}},
+			Users:  map[string][]string{"ci": {"scan"}},
+		},
+		Settings: []Setting{
+			{Key: "sonar.exclusions", Values: []string{"**/gen/**"}},
+			{Key: "sonar.auth.github.clientSecret.secured", Value: Redacted},
+		},
+		Webhooks: []Webhook{{Name: "CI", URL: "https://ci.example.com", Secret: Redacted}},
+	}
+
+	to := &Snapshot{
+		QualityGates: []QualityGate{
+			{Name: "Strict", Conditions: []Condition{
+				{Metric: "new_coverage", Op: "LT", Error: "90"},
+				{Metric: "new_duplicated_lines_density", Op: "GT", Error: "3"},
+			}},
+		},
+		QualityProfiles: []QualityProfile{
+			{Name: "Company", Language: "java", Parent: "Sonar way", Default: true, Backup: backupWithRules(`
+				<rule><repositoryKey>java</repositoryKey><key>S100</key><priority>CRITICAL</priority>
+					<impacts><impact><softwareQuality>MAINTAINABILITY</softwareQuality><severity>HIGH</severity></impact></impacts>
+					<parameters><parameter><key>format</key><value>^[a-zA-Z]+$</value></parameter></parameters></rule>
+				<rule><repositoryKey>java</repositoryKey><key>S102</key><priority>INFO</priority>
+					<parameters><parameter><key>max</key><value>10</value></parameter></parameters></rule>`)},
+			{Name: "Sonar way", Language: "java", BuiltIn: true},
+		},
+		PermissionTemplates: []PermissionTemplate{{
+			Name:           "Default template",
+			DefaultFor:     []string{"TRK"},
+			Permissions:    Permissions{Groups: map[string][]string{"developers": {"user"}}},
+			ProjectCreator: []string{"admin"},
+		}},
+		GlobalPermissions: Permissions{
+			Groups: map[string][]string{"sonar-administrators": {"admin"}},
+		},
+		Settings: []Setting{
+			{Key: "sonar.exclusions", Values: []string{"**/gen/**", "**/vendor/**"}},
+			{Key: "sonar.auth.github.clientSecret.secured", Value: Redacted},
+		},
+		Webhooks: []Webhook{{Name: "CI", URL: "https://ci.example.com"}},
+	}
+
+	differences, err := Compare(from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Difference{
+		{Section: SectionQualityGates, Object: "Legacy", Type: DifferenceRemoved},
+		{Section: SectionQualityGates, Object: "Strict", Field: "condition new_coverage", Type: DifferenceChanged, From: "LT 80", To: "LT 90"},
+		{Section: SectionQualityGates, Object: "Strict", Field: "condition new_duplicated_lines_density", Type: DifferenceAdded, To: "GT 3"},
+		{Section: SectionQualityGates, Object: "Strict", Field: "condition new_violations", Type: DifferenceRemoved, From: "GT 0"},
+		{Section: SectionQualityGates, Object: "Strict", Field: "default", Type: DifferenceRemoved, From: "true"},
+		{Section: SectionQualityProfiles, Object: "java/Company", Field: "default", Type: DifferenceAdded, To: "true"},
+		{Section: SectionQualityProfiles, Object: "java/Company", Field: "rule java:S100 impacts.MAINTAINABILITY", Type: DifferenceAdded, To: "HIGH"},
+		{Section: SectionQualityProfiles, Object: "java/Company", Field: "rule java:S100 params.format", Type: DifferenceChanged, From: "^[a-z]+$", To: "^[a-zA-Z]+$"},
+		{Section: SectionQualityProfiles, Object: "java/Company", Field: "rule java:S100 severity", Type: DifferenceChanged, From: "MAJOR", To: "CRITICAL"},
+		{Section: SectionQualityProfiles, Object: "java/Company", Field: "rule java:S101", Type: DifferenceRemoved, From: "MINOR"},
+		{Section: SectionQualityProfiles, Object: "java/Company", Field: "rule java:S102", Type: DifferenceAdded, To: "INFO max=10"},
+		{Section: SectionPermissionTemplates, Object: "Default template", Field: "group developers", Type: DifferenceChanged, From: "codeviewer,user", To: "user"},
+		{Section: SectionPermissionTemplates, Object: "Default template", Field: "project creator", Type: DifferenceAdded, To: "admin"},
+		{Section: SectionGlobalPermissions, Object: "user ci", Type: DifferenceRemoved, From: "scan"},
+		{Section: SectionSettings, Object: "sonar.exclusions", Type: DifferenceChanged, From: "**/gen/**", To: "**/gen/**,**/vendor/**"},
+		{Section: SectionWebhooks, Object: "CI", Field: "secret", Type: DifferenceRemoved, From: Redacted},
+	}, differences)
+
+	differences, err = Compare(from, from)
+	require.NoError(t, err)
+	assert.Empty(t, differences)
+}
+
+func TestCompare_InvalidBackup(t *testing.T) {
+	profiles := func(backup string) *Snapshot {
+		return &Snapshot{QualityProfiles: []QualityProfile{{Name: "Company", Language: "java", Backup: backup}}}
+	}
+
+	_, err := Compare(profiles(backupWithRules("")), profiles("<profile>"))
+	require.ErrorContains(t, err, "invalid backup of quality profile java/Company")
+}
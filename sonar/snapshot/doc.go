@@ -0,0 +1,26 @@
+// Package snapshot exports the configuration of a SonarQube instance.
+//
+// Export reads the quality gates, quality profiles, permission templates, global
+// permissions, groups and their members, global settings, global webhooks, ALM
+// settings, portfolios, applications and new code periods of an instance into a
+// typed Snapshot:
+//
+//	snap, err := snapshot.Export(ctx, client)
+//	err = snap.WriteDir("backup", snapshot.FormatYAML)
+//
+// WriteDir stores a snapshot as a directory with one YAML or JSON file per section,
+// and the backup of each custom quality profile as an XML file that
+// Qualityprofiles.Restore accepts. ReadDir reads such a directory back.
+//
+// # Secrets
+//
+// The server does not disclose secrets, and a snapshot never holds any: the values of
+// secured settings, webhook secrets and the credentials of ALM settings are replaced
+// with Redacted, which records that a secret is set and must be provided again when
+// the configuration is restored.
+//
+// # Editions
+//
+// Portfolios and applications only exist in the editions that support them. On other
+// editions these sections are left empty and listed in Instance.Skipped.
+package snapshot
@@ -0,0 +1,611 @@
+package snapshot
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+)
+
+const (
+	// SectionQualityGates is the quality gates section.
+	SectionQualityGates = "quality-gates"
+	// SectionQualityProfiles is the quality profiles section.
+	SectionQualityProfiles = "quality-profiles"
+	// SectionPermissionTemplates is the permission templates section.
+	SectionPermissionTemplates = "permission-templates"
+	// SectionGlobalPermissions is the global permissions section.
+	SectionGlobalPermissions = "global-permissions"
+	// SectionGroups is the groups section.
+	SectionGroups = "groups"
+	// SectionSettings is the global settings section.
+	SectionSettings = "settings"
+	// SectionWebhooks is the global webhooks section.
+	SectionWebhooks = "webhooks"
+	// SectionAlmSettings is the ALM settings section.
+	SectionAlmSettings = "alm-settings"
+	// SectionPortfolios is the portfolios section, skipped when the edition has none.
+	SectionPortfolios = "portfolios"
+	// SectionApplications is the applications section, skipped when the edition has none.
+	SectionApplications = "applications"
+	// SectionNewCodePeriods is the new code periods section.
+	SectionNewCodePeriods = "new-code-periods"
+)
+
+// exporter reads the sections of a snapshot.
+type exporter struct {
+	client     *sonar.Client
+	snapshot   *Snapshot
+	qualifiers []string
+}
+
+// Export reads the configuration of the instance the client is connected to. It needs
+// the Administer System permission. The first failed request stops the export.
+func Export(ctx context.Context, client *sonar.Client) (*Snapshot, error) {
+	if client == nil {
+		return nil, sonar.NewValidationError("client", "is required", sonar.ErrMissingRequired)
+	}
+
+	e := &exporter{client: client, snapshot: &Snapshot{}, qualifiers: nil} //nolint:exhaustruct // filled by the sections
+
+	sections := []func(context.Context) error{
+		e.instance,
+		e.qualityGates,
+		e.qualityProfiles,
+		e.permissionTemplates,
+		e.globalPermissions,
+		e.groups,
+		e.settings,
+		e.webhooks,
+		e.almSettings,
+		e.portfolios,
+		e.applications,
+		e.newCodePeriods,
+	}
+
+	for _, section := range sections {
+		err := section(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return e.snapshot, nil
+}
+
+// instance reads the version and edition of the server, and the qualifiers it supports.
+func (e *exporter) instance(ctx context.Context) error {
+	exportedAt := time.Now().UTC()
+
+	global, _, err := e.client.Navigation.Global(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the server version: %w", err)
+	}
+
+	e.qualifiers = global.Qualifiers
+	e.snapshot.Instance = Instance{Version: global.Version, Edition: global.Edition, ExportedAt: exportedAt, Skipped: nil}
+
+	return nil
+}
+
+// qualityGates reads the quality gates and their conditions.
+func (e *exporter) qualityGates(ctx context.Context) error {
+	list, _, err := e.client.Qualitygates.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list quality gates: %w", err)
+	}
+
+	gates := make([]QualityGate, 0, len(list.Qualitygates))
+
+	for _, gate := range list.Qualitygates {
+		show, _, err := e.client.Qualitygates.Show(ctx, &sonar.QualitygatesShowOptions{Name: gate.Name})
+		if err != nil {
+			return fmt.Errorf("failed to read quality gate %q: %w", gate.Name, err)
+		}
+
+		conditions := make([]Condition, 0, len(show.Conditions))
+
+		for _, condition := range show.Conditions {
+			conditions = append(conditions, Condition{Metric: condition.Metric, Op: condition.Op, Error: condition.Error})
+		}
+
+		slices.SortFunc(conditions, func(a, b Condition) int { return strings.Compare(a.Metric, b.Metric) })
+
+		gates = append(gates, QualityGate{Name: gate.Name, BuiltIn: gate.IsBuiltIn, Default: gate.IsDefault, Conditions: conditions})
+	}
+
+	slices.SortFunc(gates, func(a, b QualityGate) int { return strings.Compare(a.Name, b.Name) })
+	e.snapshot.QualityGates = gates
+
+	return nil
+}
+
+// qualityProfiles reads the quality profiles and the backups of the custom ones.
+func (e *exporter) qualityProfiles(ctx context.Context) error {
+	search, _, err := e.client.Qualityprofiles.Search(ctx, &sonar.QualityprofilesSearchOptions{}) //nolint:exhaustruct // every profile
+	if err != nil {
+		return fmt.Errorf("failed to search quality profiles: %w", err)
+	}
+
+	profiles := make([]QualityProfile, 0, len(search.Profiles))
+
+	for _, profile := range search.Profiles {
+		exported := QualityProfile{
+			Key:      profile.Key,
+			Name:     profile.Name,
+			Language: profile.Language,
+			Parent:   profile.ParentName,
+			BuiltIn:  profile.IsBuiltIn,
+			Default:  profile.IsDefault,
+			Backup:   "",
+		}
+
+		if !profile.IsBuiltIn {
+			backup, _, err := e.client.Qualityprofiles.Backup(ctx, &sonar.QualityprofilesBackupOptions{
+				Language:       profile.Language,
+				QualityProfile: profile.Name,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to back up quality profile %q (%s): %w", profile.Name, profile.Language, err)
+			}
+
+			exported.Backup = *backup
+		}
+
+		profiles = append(profiles, exported)
+	}
+
+	slices.SortFunc(profiles, func(a, b QualityProfile) int {
+		return cmp.Or(strings.Compare(a.Language, b.Language), strings.Compare(a.Name, b.Name))
+	})
+	e.snapshot.QualityProfiles = profiles
+
+	return nil
+}
+
+// permissionTemplates reads the permission templates and the permissions they grant.
+func (e *exporter) permissionTemplates(ctx context.Context) error {
+	permissions := e.client.Permissions
+
+	search, _, err := permissions.SearchTemplates(ctx, &sonar.PermissionsSearchTemplatesOptions{}) //nolint:exhaustruct // every template
+	if err != nil {
+		return fmt.Errorf("failed to search permission templates: %w", err)
+	}
+
+	defaults := make(map[string][]string, len(search.DefaultTemplates))
+
+	for _, template := range search.DefaultTemplates {
+		defaults[template.TemplateID] = append(defaults[template.TemplateID], template.Qualifier)
+	}
+
+	templates := make([]PermissionTemplate, 0, len(search.PermissionTemplates))
+
+	for _, template := range search.PermissionTemplates {
+		groups, _, err := permissions.TemplateGroupsAll(ctx, &sonar.PermissionsTemplateGroupsOptions{ //nolint:exhaustruct // every group
+			TemplateName: template.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read the groups of permission template %q: %w", template.Name, err)
+		}
+
+		users, _, err := permissions.TemplateUsersAll(ctx, &sonar.PermissionsTemplateUsersOptions{ //nolint:exhaustruct // every user
+			TemplateName: template.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read the users of permission template %q: %w", template.Name, err)
+		}
+
+		var creator []string
+
+		for _, permission := range template.Permissions {
+			if permission.WithProjectCreator {
+				creator = append(creator, permission.Key)
+			}
+		}
+
+		slices.Sort(creator)
+		slices.Sort(defaults[template.ID])
+
+		templates = append(templates, PermissionTemplate{
+			Name:              template.Name,
+			Description:       template.Description,
+			ProjectKeyPattern: template.ProjectKeyPattern,
+			DefaultFor:        defaults[template.ID],
+			Permissions: Permissions{
+				Groups: grants(groups, func(group sonar.PermissionsTemplateGroup) (string, []string) {
+					return group.Name, group.Permissions
+				}),
+				Users: grants(users, func(user sonar.PermissionsTemplateUser) (string, []string) {
+					return user.Login, user.Permissions
+				}),
+			},
+			ProjectCreator: creator,
+		})
+	}
+
+	slices.SortFunc(templates, func(a, b PermissionTemplate) int { return strings.Compare(a.Name, b.Name) })
+	e.snapshot.PermissionTemplates = templates
+
+	return nil
+}
+
+// globalPermissions reads the permissions granted on the instance.
+func (e *exporter) globalPermissions(ctx context.Context) error {
+	groups, _, err := e.client.Permissions.GroupsAll(ctx, &sonar.PermissionsGroupsOptions{}) //nolint:exhaustruct // every group
+	if err != nil {
+		return fmt.Errorf("failed to read global group permissions: %w", err)
+	}
+
+	users, _, err := e.client.Permissions.UsersAll(ctx, &sonar.PermissionsUsersOptions{}) //nolint:exhaustruct // every user
+	if err != nil {
+		return fmt.Errorf("failed to read global user permissions: %w", err)
+	}
+
+	e.snapshot.GlobalPermissions = Permissions{
+		Groups: grants(groups, func(group sonar.PermissionGroup) (string, []string) { return group.Name, group.Permissions }),
+		Users:  grants(users, func(user sonar.PermissionUser) (string, []string) { return user.Login, user.Permissions }),
+	}
+
+	return nil
+}
+
+// grants maps the principals that have permissions to their sorted permissions.
+func grants[T any](principals []T, permissionsOf func(T) (string, []string)) map[string][]string {
+	granted := make(map[string][]string)
+
+	for _, principal := range principals {
+		name, permissions := permissionsOf(principal)
+		if len(permissions) == 0 {
+			continue
+		}
+
+		granted[name] = slices.Sorted(slices.Values(permissions))
+	}
+
+	if len(granted) == 0 {
+		return nil
+	}
+
+	return granted
+}
+
+// groups reads the user groups and their members.
+func (e *exporter) groups(ctx context.Context) error {
+	userGroups := e.client.UserGroups
+
+	search, _, err := userGroups.SearchAll(ctx, &sonar.UserGroupsSearchOptions{}) //nolint:exhaustruct // every group
+	if err != nil {
+		return fmt.Errorf("failed to search groups: %w", err)
+	}
+
+	groups := make([]Group, 0, len(search))
+
+	for _, group := range search {
+		members, _, err := userGroups.UsersAll(ctx, &sonar.UserGroupsUsersOptions{ //nolint:exhaustruct // every member
+			Name:     group.Name,
+			Selected: "selected",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read the members of group %q: %w", group.Name, err)
+		}
+
+		logins := make([]string, 0, len(members))
+
+		for _, member := range members {
+			logins = append(logins, member.Login)
+		}
+
+		slices.Sort(logins)
+
+		groups = append(groups, Group{
+			Name:        group.Name,
+			Description: group.Description,
+			Default:     group.Default,
+			Managed:     group.Managed,
+			Members:     logins,
+		})
+	}
+
+	slices.SortFunc(groups, func(a, b Group) int { return strings.Compare(a.Name, b.Name) })
+	e.snapshot.Groups = groups
+
+	return nil
+}
+
+// settings reads the global settings set to a value other than their default.
+func (e *exporter) settings(ctx context.Context) error {
+	definitions, _, err := e.client.Settings.ListDefinitions(ctx, &sonar.SettingsListDefinitionsOptions{Component: ""})
+	if err != nil {
+		return fmt.Errorf("failed to list setting definitions: %w", err)
+	}
+
+	defaults := make(map[string]string, len(definitions.Definitions))
+
+	for _, definition := range definitions.Definitions {
+		defaults[definition.Key] = definition.DefaultValue
+	}
+
+	values, _, err := e.client.Settings.Values(ctx, &sonar.SettingsValuesOptions{Component: "", Keys: nil})
+	if err != nil {
+		return fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	settings := make([]Setting, 0, len(values.Settings)+len(values.SetSecuredSettings))
+
+	for _, value := range values.Settings {
+		defaultValue, defined := defaults[value.Key]
+		if value.Inherited || (defined && value.FieldValues == nil && settingValue(value) == defaultValue) {
+			continue
+		}
+
+		settings = append(settings, Setting{Key: value.Key, Value: value.Value, Values: value.Values, FieldValues: value.FieldValues})
+	}
+
+	for _, key := range values.SetSecuredSettings {
+		settings = append(settings, Setting{Key: key, Value: Redacted, Values: nil, FieldValues: nil})
+	}
+
+	slices.SortFunc(settings, func(a, b Setting) int { return strings.Compare(a.Key, b.Key) })
+	e.snapshot.Settings = settings
+
+	return nil
+}
+
+// settingValue formats the value of a setting like setting definitions format defaults.
+func settingValue(value sonar.SettingValue) string {
+	if value.Values != nil {
+		return strings.Join(value.Values, ",")
+	}
+
+	return value.Value
+}
+
+// webhooks reads the global webhooks.
+func (e *exporter) webhooks(ctx context.Context) error {
+	list, _, err := e.client.Webhooks.List(ctx, &sonar.WebhooksListOptions{}) //nolint:exhaustruct // global webhooks
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	webhooks := make([]Webhook, 0, len(list.Webhooks))
+
+	for _, webhook := range list.Webhooks {
+		exported := Webhook{Name: webhook.Name, URL: webhook.URL, Secret: ""}
+		if webhook.HasSecret {
+			exported.Secret = Redacted
+		}
+
+		webhooks = append(webhooks, exported)
+	}
+
+	slices.SortFunc(webhooks, func(a, b Webhook) int { return strings.Compare(a.Name, b.Name) })
+	e.snapshot.Webhooks = webhooks
+
+	return nil
+}
+
+// almSettings reads the DevOps platform integrations, without their secrets.
+func (e *exporter) almSettings(ctx context.Context) error {
+	definitions, _, err := e.client.AlmSettings.ListDefinitions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list ALM settings: %w", err)
+	}
+
+	settings := []AlmSetting{}
+
+	for _, azure := range definitions.Azure {
+		settings = append(settings, AlmSetting{ALM: "azure", Key: azure.Key, URL: azure.URL, Secret: Redacted}) //nolint:exhaustruct // Azure has no app
+	}
+
+	for _, bitbucket := range definitions.Bitbucket {
+		settings = append(settings, AlmSetting{ALM: "bitbucket", Key: bitbucket.Key, URL: bitbucket.URL, Secret: Redacted}) //nolint:exhaustruct // Bitbucket Server has no app
+	}
+
+	for _, cloud := range definitions.BitbucketCloud {
+		settings = append(settings, AlmSetting{ //nolint:exhaustruct // Bitbucket Cloud has no URL
+			ALM:       "bitbucketcloud",
+			Key:       cloud.Key,
+			ClientID:  cloud.ClientID,
+			Workspace: cloud.Workspace,
+			Secret:    Redacted,
+		})
+	}
+
+	for _, github := range definitions.Github {
+		settings = append(settings, AlmSetting{ //nolint:exhaustruct // GitHub has no workspace
+			ALM:      "github",
+			Key:      github.Key,
+			URL:      github.URL,
+			AppID:    github.AppID,
+			ClientID: github.ClientID,
+			Secret:   Redacted,
+		})
+	}
+
+	for _, gitlab := range definitions.Gitlab {
+		settings = append(settings, AlmSetting{ALM: "gitlab", Key: gitlab.Key, URL: gitlab.URL, Secret: Redacted}) //nolint:exhaustruct // GitLab has no app
+	}
+
+	slices.SortFunc(settings, func(a, b AlmSetting) int { return strings.Compare(a.Key, b.Key) })
+	e.snapshot.AlmSettings = settings
+
+	return nil
+}
+
+// supports reports whether the server supports components with the qualifier, and
+// records the section as skipped otherwise.
+func (e *exporter) supports(qualifier, section string) bool {
+	if slices.Contains(e.qualifiers, qualifier) {
+		return true
+	}
+
+	e.snapshot.Instance.Skipped = append(e.snapshot.Instance.Skipped, section)
+
+	return false
+}
+
+// portfolios reads the root portfolios, when the edition has them.
+func (e *exporter) portfolios(ctx context.Context) error {
+	e.snapshot.Portfolios = []Portfolio{}
+
+	if !e.supports(sonar.ProjectQualifierVW, SectionPortfolios) {
+		return nil
+	}
+
+	list, _, err := e.client.Views.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list portfolios: %w", err)
+	}
+
+	portfolios := make([]Portfolio, 0, len(list.Views))
+
+	for _, view := range list.Views {
+		details, _, err := e.client.Views.Show(ctx, &sonar.ViewsShowOptions{Key: view.Key})
+		if err != nil {
+			return fmt.Errorf("failed to read portfolio %q: %w", view.Key, err)
+		}
+
+		var subPortfolios []string
+
+		for _, sub := range details.SubViews {
+			subPortfolios = append(subPortfolios, cmp.Or(sub.OriginalKey, sub.Key))
+		}
+
+		slices.Sort(subPortfolios)
+
+		portfolios = append(portfolios, Portfolio{
+			Key:           details.Key,
+			Name:          details.Name,
+			Description:   details.Description,
+			Visibility:    details.Visibility,
+			SelectionMode: details.SelectionMode,
+			SubPortfolios: subPortfolios,
+		})
+	}
+
+	slices.SortFunc(portfolios, func(a, b Portfolio) int { return strings.Compare(a.Key, b.Key) })
+	e.snapshot.Portfolios = portfolios
+
+	return nil
+}
+
+// applications reads the applications and their projects, when the edition has them.
+func (e *exporter) applications(ctx context.Context) error {
+	e.snapshot.Applications = []Application{}
+
+	if !e.supports(sonar.ProjectQualifierAPP, SectionApplications) {
+		return nil
+	}
+
+	components, _, err := e.client.Projects.SearchAll(ctx, &sonar.ProjectsSearchOptions{ //nolint:exhaustruct // every application
+		Qualifiers: []string{sonar.ProjectQualifierAPP},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search applications: %w", err)
+	}
+
+	applications := make([]Application, 0, len(components))
+
+	for _, component := range components {
+		show, _, err := e.client.Applications.Show(ctx, &sonar.ApplicationsShowOptions{Application: component.Key, Branch: ""})
+		if err != nil {
+			return fmt.Errorf("failed to read application %q: %w", component.Key, err)
+		}
+
+		application := show.Application
+		projects := make([]string, 0, len(application.Projects))
+
+		for _, project := range application.Projects {
+			projects = append(projects, project.Key)
+		}
+
+		var branches []string
+
+		for _, branch := range application.Branches {
+			if !branch.IsMain {
+				branches = append(branches, branch.Name)
+			}
+		}
+
+		slices.Sort(projects)
+		slices.Sort(branches)
+
+		applications = append(applications, Application{
+			Key:         application.Key,
+			Name:        application.Name,
+			Description: application.Description,
+			Visibility:  application.Visibility,
+			Tags:        application.Tags,
+			Projects:    projects,
+			Branches:    branches,
+		})
+	}
+
+	slices.SortFunc(applications, func(a, b Application) int { return strings.Compare(a.Key, b.Key) })
+	e.snapshot.Applications = applications
+
+	return nil
+}
+
+// newCodePeriods reads the global new code period and those set on projects and
+// branches. Periods inherited from the instance or the project are left out.
+func (e *exporter) newCodePeriods(ctx context.Context) error {
+	periods := e.client.NewCodePeriods
+
+	global, _, err := periods.Show(ctx, &sonar.NewCodePeriodsShowOptions{Branch: "", Project: ""})
+	if err != nil {
+		return fmt.Errorf("failed to read the global new code period: %w", err)
+	}
+
+	exported := []NewCodePeriod{{Project: "", Branch: "", Type: global.Type, Value: global.Value}}
+
+	projects, _, err := e.client.Projects.SearchAll(ctx, &sonar.ProjectsSearchOptions{ //nolint:exhaustruct // every project
+		Qualifiers: []string{sonar.ProjectQualifierTRK},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search projects: %w", err)
+	}
+
+	keys := make([]string, 0, len(projects))
+
+	for _, project := range projects {
+		keys = append(keys, project.Key)
+	}
+
+	slices.Sort(keys)
+
+	for _, project := range keys {
+		own, _, err := periods.Show(ctx, &sonar.NewCodePeriodsShowOptions{Branch: "", Project: project})
+		if err != nil {
+			return fmt.Errorf("failed to read the new code period of project %q: %w", project, err)
+		}
+
+		if !own.Inherited {
+			exported = append(exported, NewCodePeriod{Project: project, Branch: "", Type: own.Type, Value: own.Value})
+		}
+
+		branches, _, err := periods.List(ctx, &sonar.NewCodePeriodsListOptions{Project: project})
+		if err != nil {
+			return fmt.Errorf("failed to list the new code periods of project %q: %w", project, err)
+		}
+
+		var branchPeriods []NewCodePeriod
+
+		for _, branch := range branches.NewCodePeriods {
+			if !branch.Inherited {
+				branchPeriods = append(branchPeriods, NewCodePeriod{Project: project, Branch: branch.BranchKey, Type: branch.Type, Value: branch.Value})
+			}
+		}
+
+		slices.SortFunc(branchPeriods, func(a, b NewCodePeriod) int { return strings.Compare(a.Branch, b.Branch) })
+		exported = append(exported, branchPeriods...)
+	}
+
+	e.snapshot.NewCodePeriods = exported
+
+	return nil
+}
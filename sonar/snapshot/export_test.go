@@ -0,0 +1,164 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/testutil"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackup is the backup of the custom quality profile of the test server.
+const testBackup = `<?xml version='1.0' encoding='UTF-8'?>
+<profile><name>Company Java</name><language>java</language><rules/></profile>`
+
+// testResponses are the responses of the test server, by path and, for endpoints
+// called once per object, by the value of the identifying parameter.
+//
+//nolint:gochecknoglobals // test fixture
+var testResponses = map[string]string{
+	"navigation/global": `{"version": "2025.1", "edition": "community", "qualifiers": ["TRK"]}`,
+	"qualitygates/list": `{"qualitygates": [{"name": "Strict"}, {"name": "Sonar way", "isBuiltIn": true, "isDefault": true}]}`,
+	"qualitygates/show?name=Sonar way": `{"name": "Sonar way", "conditions": [
+		{"id": "1", "metric": "new_coverage", "op": "LT", "error": "80"}]}`,
+	"qualitygates/show?name=Strict": `{"name": "Strict", "conditions": [
+		{"id": "2", "metric": "new_violations", "op": "GT", "error": "0"},
+		{"id": "3", "metric": "new_coverage", "op": "LT", "error": "90"}]}`,
+	"qualityprofiles/search": `{"profiles": [
+		{"key": "AX1", "name": "Sonar way", "language": "java", "isBuiltIn": true},
+		{"key": "AX2", "name": "Company Java", "language": "java", "parentName": "Sonar way", "isDefault": true}]}`,
+	"qualityprofiles/backup?qualityProfile=Company Java": testBackup,
+	"permissions/search_templates": `{
+		"defaultTemplates": [{"templateId": "T1", "qualifier": "TRK"}],
+		"permissionTemplates": [{"id": "T1", "name": "Default template",
+			"permissions": [{"key": "admin", "withProjectCreator": true}]}]}`,
+	"permissions/template_groups?templateName=Default template": `{"paging": {"total": 2}, "groups": [
+		{"name": "sonar-users", "permissions": ["user", "codeviewer"]},
+		{"name": "Anyone", "permissions": []}]}`,
+	"permissions/template_users?templateName=Default template": `{"paging": {"total": 0}, "users": []}`,
+	"permissions/groups":                          `{"paging": {"total": 1}, "groups": [{"name": "sonar-administrators", "permissions": ["admin"]}]}`,
+	"permissions/users":                           `{"paging": {"total": 1}, "users": [{"login": "ci", "permissions": ["scan", "provisioning"]}]}`,
+	"user_groups/search":                          `{"paging": {"total": 1}, "groups": [{"name": "sonar-administrators", "description": "Admins"}]}`,
+	"user_groups/users?name=sonar-administrators": `{"paging": {"total": 2}, "users": [{"login": "bob"}, {"login": "admin"}]}`,
+	"settings/list_definitions": `{"definitions": [
+		{"key": "sonar.exclusions", "multiValues": true},
+		{"key": "sonar.scm.disabled", "defaultValue": "false"}]}`,
+	"settings/values": `{"setSecuredSettings": ["sonar.auth.github.clientSecret.secured"], "settings": [
+		{"key": "sonar.scm.disabled", "value": "false"},
+		{"key": "sonar.exclusions", "values": ["**/gen/**"]},
+		{"key": "sonar.core.serverBaseURL", "value": "https://sonar.example.com"},
+		{"key": "sonar.lf.logoUrl", "value": "https://example.com/logo.png", "inherited": true}]}`,
+	"webhooks/list":                     `{"webhooks": [{"key": "W1", "name": "CI", "url": "https://ci.example.com", "hasSecret": true}]}`,
+	"alm_settings/list_definitions":     `{"github": [{"key": "GitHub", "url": "https://api.github.com", "appId": "12", "clientId": "abc"}]}`,
+	"new_code_periods/show":             `{"type": "PREVIOUS_VERSION", "inherited": false}`,
+	"new_code_periods/show?project=app": `{"projectKey": "app", "type": "NUMBER_OF_DAYS", "value": "30"}`,
+	"new_code_periods/show?project=lib": `{"projectKey": "lib", "type": "PREVIOUS_VERSION", "inherited": true}`,
+	"new_code_periods/list?project=app": `{"newCodePeriods": [
+		{"projectKey": "app", "branchKey": "main", "type": "NUMBER_OF_DAYS", "value": "30", "inherited": true},
+		{"projectKey": "app", "branchKey": "release", "type": "REFERENCE_BRANCH", "value": "main"}]}`,
+	"new_code_periods/list?project=lib": `{"newCodePeriods": []}`,
+	"projects/search?qualifiers=TRK":    `{"paging": {"total": 2}, "components": [{"key": "lib"}, {"key": "app"}]}`,
+}
+
+// identifyingParams are the parameters that select the response of the test server.
+//
+//nolint:gochecknoglobals // test fixture
+var identifyingParams = []string{"name", "qualityProfile", "templateName", "project", "qualifiers"}
+
+func TestExport(t *testing.T) {
+	snap, err := Export(t.Context(), testutil.NewClient(t, testResponses, identifyingParams...))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025.1", snap.Instance.Version)
+	assert.Equal(t, []string{SectionPortfolios, SectionApplications}, snap.Instance.Skipped)
+	assert.False(t, snap.Instance.ExportedAt.IsZero())
+
+	assert.Equal(t, []QualityGate{
+		{Name: "Sonar way", BuiltIn: true, Default: true, Conditions: []Condition{{Metric: "new_coverage", Op: "LT", Error: "80"}}},
+		{Name: "Strict", Conditions: []Condition{
+			{Metric: "new_coverage", Op: "LT", Error: "90"},
+			{Metric: "new_violations", Op: "GT", Error: "0"},
+		}},
+	}, snap.QualityGates)
+
+	assert.Equal(t, []QualityProfile{
+		{Key: "AX2", Name: "Company Java", Language: "java", Parent: "Sonar way", Default: true, Backup: testBackup},
+		{Key: "AX1", Name: "Sonar way", Language: "java", BuiltIn: true},
+	}, snap.QualityProfiles)
+
+	assert.Equal(t, []PermissionTemplate{{
+		Name:           "Default template",
+		DefaultFor:     []string{sonar.ProjectQualifierTRK},
+		Permissions:    Permissions{Groups: map[string][]string{"sonar-users": {"codeviewer", "user"}}},
+		ProjectCreator: []string{"admin"},
+	}}, snap.PermissionTemplates)
+
+	assert.Equal(t, Permissions{
+		Groups: map[string][]string{"sonar-administrators": {"admin"}},
+		Users:  map[string][]string{"ci": {"provisioning", "scan"}},
+	}, snap.GlobalPermissions)
+
+	assert.Equal(t, []Group{{Name: "sonar-administrators", Description: "Admins", Members: []string{"admin", "bob"}}}, snap.Groups)
+
+	assert.Equal(t, []Setting{
+		{Key: "sonar.auth.github.clientSecret.secured", Value: Redacted},
+		{Key: "sonar.core.serverBaseURL", Value: "https://sonar.example.com"},
+		{Key: "sonar.exclusions", Values: []string{"**/gen/**"}},
+	}, snap.Settings, "defaults and inherited values are left out")
+
+	assert.Equal(t, []Webhook{{Name: "CI", URL: "https://ci.example.com", Secret: Redacted}}, snap.Webhooks)
+	assert.Equal(t, []AlmSetting{
+		{ALM: "github", Key: "GitHub", URL: "https://api.github.com", AppID: "12", ClientID: "abc", Secret: Redacted},
+	}, snap.AlmSettings)
+	assert.Empty(t, snap.Portfolios)
+	assert.Empty(t, snap.Applications)
+
+	assert.Equal(t, []NewCodePeriod{
+		{Type: sonar.NewCodePeriodTypePreviousVersion},
+		{Project: "app", Type: sonar.NewCodePeriodTypeNumberOfDays, Value: "30"},
+		{Project: "app", Branch: "release", Type: sonar.NewCodePeriodTypeReferenceBranch, Value: "main"},
+	}, snap.NewCodePeriods)
+}
+
+func TestExport_PortfoliosAndApplications(t *testing.T) {
+	responses := map[string]string{
+		"navigation/global":              `{"version": "2025.1", "edition": "enterprise", "qualifiers": ["TRK", "VW", "APP"]}`,
+		"views/list":                     `{"views": [{"key": "all", "name": "All"}]}`,
+		"views/show":                     `{"key": "all", "name": "All", "selectionMode": "MANUAL", "subViews": [{"key": "all:team", "originalKey": "team"}]}`,
+		"projects/search?qualifiers=APP": `{"paging": {"total": 1}, "components": [{"key": "shop"}]}`,
+		"applications/show": `{"application": {"key": "shop", "name": "Shop", "tags": ["web"],
+			"projects": [{"key": "shop-ui"}, {"key": "shop-api"}],
+			"branches": [{"name": "main", "isMain": true}, {"name": "next"}]}}`,
+	}
+
+	for path, body := range testResponses {
+		if _, found := responses[path]; !found {
+			responses[path] = body
+		}
+	}
+
+	snap, err := Export(t.Context(), testutil.NewClient(t, responses, identifyingParams...))
+	require.NoError(t, err)
+
+	assert.Empty(t, snap.Instance.Skipped)
+	assert.Equal(t, []Portfolio{{Key: "all", Name: "All", SelectionMode: "MANUAL", SubPortfolios: []string{"team"}}}, snap.Portfolios)
+	assert.Equal(t, []Application{{
+		Key:      "shop",
+		Name:     "Shop",
+		Tags:     []string{"web"},
+		Projects: []string{"shop-api", "shop-ui"},
+		Branches: []string{"next"},
+	}}, snap.Applications)
+}
+
+func TestExport_Error(t *testing.T) {
+	responses := map[string]string{"navigation/global": testResponses["navigation/global"]}
+
+	_, err := Export(t.Context(), testutil.NewClient(t, responses, identifyingParams...))
+	require.ErrorContains(t, err, "failed to list quality gates")
+	assert.True(t, sonar.IsNotFound(err))
+
+	_, err = Export(t.Context(), nil)
+	require.ErrorIs(t, err, sonar.ErrMissingRequired)
+}
@@ -0,0 +1,232 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the format of the files of a snapshot directory.
+type Format string
+
+const (
+	// FormatYAML writes YAML files.
+	FormatYAML Format = "yaml"
+	// FormatJSON writes JSON files.
+	FormatJSON Format = "json"
+)
+
+const (
+	// backupsDir is the directory of the quality profile backups, one subdirectory per
+	// language.
+	backupsDir = "quality-profiles"
+	// instanceFile is the name, without extension, of the file every snapshot directory has.
+	instanceFile = "instance"
+	// dirPermissions are the permissions of the directories WriteDir creates.
+	dirPermissions = 0o750
+	// filePermissions are the permissions of the files WriteDir writes.
+	filePermissions = 0o600
+)
+
+// ErrNotSnapshot is returned by ReadDir for a directory WriteDir did not write.
+var ErrNotSnapshot = errors.New("not a snapshot directory")
+
+// section is a file of a snapshot directory and the part of the snapshot it holds.
+type section struct {
+	name  string
+	value any
+}
+
+// sections returns the files of a snapshot directory.
+func (s *Snapshot) sections() []section {
+	return []section{
+		{name: instanceFile, value: &s.Instance},
+		{name: SectionQualityGates, value: &s.QualityGates},
+		{name: SectionQualityProfiles, value: &s.QualityProfiles},
+		{name: SectionPermissionTemplates, value: &s.PermissionTemplates},
+		{name: SectionGlobalPermissions, value: &s.GlobalPermissions},
+		{name: SectionGroups, value: &s.Groups},
+		{name: SectionSettings, value: &s.Settings},
+		{name: SectionWebhooks, value: &s.Webhooks},
+		{name: SectionAlmSettings, value: &s.AlmSettings},
+		{name: SectionPortfolios, value: &s.Portfolios},
+		{name: SectionApplications, value: &s.Applications},
+		{name: SectionNewCodePeriods, value: &s.NewCodePeriods},
+	}
+}
+
+// WriteDir writes the snapshot to dir, creating it if needed: one file per section in
+// the format, and the backup of each custom quality profile in
+// quality-profiles/<language>/<name>.xml. The files of a previous snapshot in dir are
+// replaced.
+func (s *Snapshot) WriteDir(dir string, format Format) error {
+	other := FormatJSON
+
+	switch format {
+	case FormatYAML:
+	case FormatJSON:
+		other = FormatYAML
+	default:
+		return sonar.NewValidationError("format", fmt.Sprintf("must be %s or %s", FormatYAML, FormatJSON), sonar.ErrInvalidValue)
+	}
+
+	err := os.RemoveAll(filepath.Join(dir, backupsDir))
+	if err != nil {
+		return fmt.Errorf("failed to remove previous profile backups: %w", err)
+	}
+
+	err = os.MkdirAll(dir, dirPermissions)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	for _, section := range s.sections() {
+		data, err := marshal(section.value, format)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", section.name, err)
+		}
+
+		err = os.WriteFile(filepath.Join(dir, section.name+"."+string(format)), data, filePermissions)
+		if err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+
+		err = os.Remove(filepath.Join(dir, section.name+"."+string(other)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove previous snapshot file: %w", err)
+		}
+	}
+
+	for _, profile := range s.QualityProfiles {
+		if profile.Backup == "" {
+			continue
+		}
+
+		path := backupPath(dir, profile)
+
+		err = os.MkdirAll(filepath.Dir(path), dirPermissions)
+		if err != nil {
+			return fmt.Errorf("failed to create profile backup directory: %w", err)
+		}
+
+		err = os.WriteFile(path, []byte(profile.Backup), filePermissions)
+		if err != nil {
+			return fmt.Errorf("failed to write profile backup: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReadDir reads a snapshot WriteDir wrote to dir, in either format.
+func ReadDir(dir string) (*Snapshot, error) {
+	format := FormatYAML
+
+	_, err := os.Stat(filepath.Join(dir, instanceFile+"."+string(FormatYAML)))
+	if errors.Is(err, fs.ErrNotExist) {
+		format = FormatJSON
+
+		_, err = os.Stat(filepath.Join(dir, instanceFile+"."+string(FormatJSON)))
+	}
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s has no %s file", ErrNotSnapshot, dir, instanceFile)
+		}
+
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	snapshot := &Snapshot{} //nolint:exhaustruct // filled by the files
+
+	for _, section := range snapshot.sections() {
+		path := filepath.Join(dir, section.name+"."+string(format))
+
+		data, err := os.ReadFile(path) //nolint:gosec // reading the snapshot the caller points to is the purpose
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+
+		err = unmarshal(data, section.value, format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	for i, profile := range snapshot.QualityProfiles {
+		if profile.BuiltIn {
+			continue
+		}
+
+		backup, err := os.ReadFile(backupPath(dir, profile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the backup of quality profile %q (%s): %w", profile.Name, profile.Language, err)
+		}
+
+		snapshot.QualityProfiles[i].Backup = string(backup)
+	}
+
+	return snapshot, nil
+}
+
+// backupPath returns the path of the backup of a profile in a snapshot directory.
+func backupPath(dir string, profile QualityProfile) string {
+	return filepath.Join(dir, backupsDir, url.PathEscape(profile.Language), url.PathEscape(profile.Name)+".xml")
+}
+
+// marshal encodes a section in the format.
+func marshal(value any, format Format) ([]byte, error) {
+	if format == FormatJSON {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		return append(data, '\n'), nil
+	}
+
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2) //nolint:mnd // conventional YAML indentation
+
+	err := encoder.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// unmarshal decodes a section in the format, rejecting unknown fields.
+func unmarshal(data []byte, value any, format Format) error {
+	if format == FormatJSON {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		err := decoder.Decode(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse snapshot: %w", err)
+		}
+
+		return nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boxboxjason/sonarqube-client-go/v2/internal/testutil"
+	"github.com/boxboxjason/sonarqube-client-go/v2/sonar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_WriteDir(t *testing.T) {
+	snap, err := Export(t.Context(), testutil.NewClient(t, testResponses, identifyingParams...))
+	require.NoError(t, err)
+
+	for _, format := range []Format{FormatYAML, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "snapshot")
+
+			require.NoError(t, snap.WriteDir(dir, format))
+
+			backup, err := os.ReadFile(filepath.Join(dir, "quality-profiles", "java", "Company%20Java.xml"))
+			require.NoError(t, err)
+			assert.Equal(t, testBackup, string(backup))
+			assert.NoFileExists(t, filepath.Join(dir, "quality-profiles", "java", "Sonar%20way.xml"), "built-in profiles are not backed up")
+			assert.FileExists(t, filepath.Join(dir, "quality-gates."+string(format)))
+
+			read, err := ReadDir(dir)
+			require.NoError(t, err)
+			assert.True(t, snap.Instance.ExportedAt.Equal(read.Instance.ExportedAt))
+
+			read.Instance.ExportedAt = snap.Instance.ExportedAt
+			assert.Equal(t, snap, read)
+		})
+	}
+}
+
+func TestSnapshot_WriteDir_Replaces(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "quality-profiles", "java", "Old.xml")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0o750))
+	require.NoError(t, os.WriteFile(stale, []byte("<profile/>"), 0o600))
+
+	snap := &Snapshot{QualityGates: []QualityGate{{Name: "Strict"}}}
+	require.NoError(t, snap.WriteDir(dir, FormatJSON))
+	require.NoError(t, snap.WriteDir(dir, FormatYAML))
+
+	assert.NoFileExists(t, stale)
+	assert.NoFileExists(t, filepath.Join(dir, "quality-gates.json"), "files of the other format are removed")
+
+	read, err := ReadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, snap.QualityGates, read.QualityGates)
+
+	require.ErrorIs(t, snap.WriteDir(dir, "xml"), sonar.ErrInvalidValue)
+}
+
+func TestReadDir_Errors(t *testing.T) {
+	_, err := ReadDir(t.TempDir())
+	require.ErrorIs(t, err, ErrNotSnapshot)
+
+	dir := t.TempDir()
+	require.NoError(t, (&Snapshot{}).WriteDir(dir, FormatYAML))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "webhooks.yaml"), []byte("- {name: CI, hook: x}\n"), 0o600))
+
+	_, err = ReadDir(dir)
+	require.ErrorContains(t, err, "field hook not found")
+
+	require.NoError(t, (&Snapshot{QualityProfiles: []QualityProfile{{Name: "A", Language: "java"}}}).WriteDir(dir, FormatYAML))
+
+	_, err = ReadDir(dir)
+	require.ErrorContains(t, err, `failed to read the backup of quality profile "A" (java)`)
+}
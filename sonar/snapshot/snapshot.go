@@ -0,0 +1,215 @@
+package snapshot
+
+import "time"
+
+// Redacted replaces the secrets the server does not disclose.
+const Redacted = "(redacted)"
+
+// Snapshot is the configuration of a SonarQube instance.
+type Snapshot struct {
+	// Instance describes the exported instance.
+	Instance Instance `json:"instance" yaml:"instance"`
+	// QualityGates are the quality gates, sorted by name.
+	QualityGates []QualityGate `json:"qualityGates" yaml:"qualityGates"`
+	// QualityProfiles are the quality profiles, sorted by language and name.
+	QualityProfiles []QualityProfile `json:"qualityProfiles" yaml:"qualityProfiles"`
+	// PermissionTemplates are the permission templates, sorted by name.
+	PermissionTemplates []PermissionTemplate `json:"permissionTemplates" yaml:"permissionTemplates"`
+	// GlobalPermissions are the permissions granted on the instance.
+	GlobalPermissions Permissions `json:"globalPermissions" yaml:"globalPermissions"`
+	// Groups are the user groups, sorted by name.
+	Groups []Group `json:"groups" yaml:"groups"`
+	// Settings are the global settings whose value differs from the default, sorted by key.
+	Settings []Setting `json:"settings" yaml:"settings"`
+	// Webhooks are the global webhooks, sorted by name.
+	Webhooks []Webhook `json:"webhooks" yaml:"webhooks"`
+	// AlmSettings are the DevOps platform integrations, sorted by key.
+	AlmSettings []AlmSetting `json:"almSettings" yaml:"almSettings"`
+	// Portfolios are the root portfolios, sorted by key.
+	Portfolios []Portfolio `json:"portfolios" yaml:"portfolios"`
+	// Applications are the applications, sorted by key.
+	Applications []Application `json:"applications" yaml:"applications"`
+	// NewCodePeriods are the global new code period, then the periods set on projects
+	// and branches, sorted by project and branch.
+	NewCodePeriods []NewCodePeriod `json:"newCodePeriods" yaml:"newCodePeriods"`
+}
+
+// Instance describes the exported instance.
+type Instance struct {
+	// Version is the version of the server.
+	Version string `json:"version" yaml:"version"`
+	// Edition is the edition of the server, such as community or enterprise.
+	Edition string `json:"edition,omitempty" yaml:"edition,omitempty"`
+	// ExportedAt is the time the export started.
+	ExportedAt time.Time `json:"exportedAt" yaml:"exportedAt"`
+	// Skipped are the sections the edition of the server does not support.
+	Skipped []string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+// QualityGate is a quality gate and its conditions.
+type QualityGate struct {
+	// Name is the name of the gate.
+	Name string `json:"name" yaml:"name"`
+	// BuiltIn is set on the gates provided by the server.
+	BuiltIn bool `json:"builtIn,omitempty" yaml:"builtIn,omitempty"`
+	// Default is set on the default gate.
+	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
+	// Conditions are the conditions of the gate, sorted by metric.
+	Conditions []Condition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+}
+
+// Condition is a quality gate condition.
+type Condition struct {
+	// Metric is the key of the metric the condition is on.
+	Metric string `json:"metric" yaml:"metric"`
+	// Op is the operator, LT or GT.
+	Op string `json:"op" yaml:"op"`
+	// Error is the error threshold.
+	Error string `json:"error" yaml:"error"`
+}
+
+// QualityProfile is a quality profile.
+type QualityProfile struct {
+	// Key is the key of the profile on the exported instance.
+	Key string `json:"key" yaml:"key"`
+	// Name is the name of the profile.
+	Name string `json:"name" yaml:"name"`
+	// Language is the language of the profile.
+	Language string `json:"language" yaml:"language"`
+	// Parent is the name of the profile it inherits from, if any.
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	// BuiltIn is set on the profiles provided by the server.
+	BuiltIn bool `json:"builtIn,omitempty" yaml:"builtIn,omitempty"`
+	// Default is set on the default profile of the language.
+	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
+	// Backup is the XML backup of the profile, as returned by Qualityprofiles.Backup.
+	// Built-in profiles are not backed up. WriteDir stores backups in their own files.
+	Backup string `json:"-" yaml:"-"`
+}
+
+// PermissionTemplate is a permission template and the permissions it grants.
+type PermissionTemplate struct {
+	// Name is the name of the template.
+	Name string `json:"name" yaml:"name"`
+	// Description is the description of the template.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// ProjectKeyPattern is the regular expression of the project keys the template
+	// applies to.
+	ProjectKeyPattern string `json:"projectKeyPattern,omitempty" yaml:"projectKeyPattern,omitempty"`
+	// DefaultFor are the qualifiers, such as TRK, the template is the default for.
+	DefaultFor []string `json:"defaultFor,omitempty" yaml:"defaultFor,omitempty"`
+	// Permissions are the permissions the template grants.
+	Permissions Permissions `json:"permissions" yaml:"permissions"`
+	// ProjectCreator are the permissions the template grants the creator of a project.
+	ProjectCreator []string `json:"projectCreator,omitempty" yaml:"projectCreator,omitempty"`
+}
+
+// Permissions are permissions granted to groups and users.
+type Permissions struct {
+	// Groups maps group names to their sorted permissions.
+	Groups map[string][]string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	// Users maps user logins to their sorted permissions.
+	Users map[string][]string `json:"users,omitempty" yaml:"users,omitempty"`
+}
+
+// Group is a user group and its members.
+type Group struct {
+	// Name is the name of the group.
+	Name string `json:"name" yaml:"name"`
+	// Description is the description of the group.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Default is set on the group every user belongs to.
+	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
+	// Managed is set on the groups provisioned by an external system.
+	Managed bool `json:"managed,omitempty" yaml:"managed,omitempty"`
+	// Members are the sorted logins of the members of the group.
+	Members []string `json:"members,omitempty" yaml:"members,omitempty"`
+}
+
+// Setting is the value of a global setting.
+type Setting struct {
+	// Key is the setting key.
+	Key string `json:"key" yaml:"key"`
+	// Value is the value of a single-value setting, or Redacted for a secured setting.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Values are the values of a multi-value setting.
+	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+	// FieldValues are the values of a property set setting.
+	FieldValues []map[string]string `json:"fieldValues,omitempty" yaml:"fieldValues,omitempty"`
+}
+
+// Webhook is a global webhook.
+type Webhook struct {
+	// Name is the name of the webhook.
+	Name string `json:"name" yaml:"name"`
+	// URL is the URL the webhook posts to.
+	URL string `json:"url" yaml:"url"`
+	// Secret is Redacted when the webhook has a secret.
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+// AlmSetting is the integration with a DevOps platform instance.
+type AlmSetting struct {
+	// ALM is the platform: azure, bitbucket, bitbucketcloud, github or gitlab.
+	ALM string `json:"alm" yaml:"alm"`
+	// Key is the key of the setting.
+	Key string `json:"key" yaml:"key"`
+	// URL is the API URL of the platform, if it has one.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// AppID is the GitHub App ID.
+	AppID string `json:"appId,omitempty" yaml:"appId,omitempty"`
+	// ClientID is the OAuth client ID of GitHub and Bitbucket Cloud.
+	ClientID string `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	// Workspace is the Bitbucket Cloud workspace.
+	Workspace string `json:"workspace,omitempty" yaml:"workspace,omitempty"`
+	// Secret is always Redacted: the token, private key or client secret of the
+	// integration must be provided again on restore.
+	Secret string `json:"secret" yaml:"secret"`
+}
+
+// Portfolio is a root portfolio.
+type Portfolio struct {
+	// Key is the key of the portfolio.
+	Key string `json:"key" yaml:"key"`
+	// Name is the name of the portfolio.
+	Name string `json:"name" yaml:"name"`
+	// Description is the description of the portfolio.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Visibility is public or private.
+	Visibility string `json:"visibility,omitempty" yaml:"visibility,omitempty"`
+	// SelectionMode is how the projects of the portfolio are selected.
+	SelectionMode string `json:"selectionMode,omitempty" yaml:"selectionMode,omitempty"`
+	// SubPortfolios are the keys of the direct sub-portfolios.
+	SubPortfolios []string `json:"subPortfolios,omitempty" yaml:"subPortfolios,omitempty"`
+}
+
+// Application is an application and its projects.
+type Application struct {
+	// Key is the key of the application.
+	Key string `json:"key" yaml:"key"`
+	// Name is the name of the application.
+	Name string `json:"name" yaml:"name"`
+	// Description is the description of the application.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Visibility is public or private.
+	Visibility string `json:"visibility,omitempty" yaml:"visibility,omitempty"`
+	// Tags are the tags of the application.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Projects are the sorted keys of the projects of the application.
+	Projects []string `json:"projects,omitempty" yaml:"projects,omitempty"`
+	// Branches are the sorted names of the branches of the application, other than the
+	// main one.
+	Branches []string `json:"branches,omitempty" yaml:"branches,omitempty"`
+}
+
+// NewCodePeriod is the definition of new code of the instance, a project or a branch.
+type NewCodePeriod struct {
+	// Project is the project key, empty for the global period.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+	// Branch is the branch name, empty for the global or a project period.
+	Branch string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	// Type is the type of the period, such as NUMBER_OF_DAYS.
+	Type string `json:"type" yaml:"type"`
+	// Value is the value of the period, such as a number of days or a branch name.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+}
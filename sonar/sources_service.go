@@ -112,13 +112,13 @@ type SourcesIndexOptions struct {
 // SourcesIssueSnippetsOptions represents options for getting issue snippets.
 type SourcesIssueSnippetsOptions struct {
 	// IssueKey is the issue key (required).
-	IssueKey string `url:"issueKey,omitempty"`
+	IssueKey string `url:"issueKey,omitempty" cli:"positional"`
 }
 
 // SourcesLinesOptions represents options for getting source file lines.
 type SourcesLinesOptions struct {
 	// Key is the file key (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 	// Branch is the branch key (optional).
 	Branch string `url:"branch,omitempty"`
 	// PullRequest is the pull request identifier (optional).
@@ -132,7 +132,7 @@ type SourcesLinesOptions struct {
 // SourcesRawOptions represents options for getting raw source file content.
 type SourcesRawOptions struct {
 	// Key is the file key (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 	// Branch is the branch key (optional).
 	Branch string `url:"branch,omitempty"`
 	// PullRequest is the pull request identifier (optional).
@@ -142,7 +142,7 @@ type SourcesRawOptions struct {
 // SourcesScmOptions represents options for getting SCM data.
 type SourcesScmOptions struct {
 	// Key is the file key (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 	// CommitsByLine indicates whether to group commits by line (optional, default: false).
 	CommitsByLine bool `url:"commits_by_line,omitempty"`
 	// From is the starting line number (optional, default: 1).
@@ -154,7 +154,7 @@ type SourcesScmOptions struct {
 // SourcesShowOptions represents options for showing source file content.
 type SourcesShowOptions struct {
 	// Key is the file key (required).
-	Key string `url:"key,omitempty"`
+	Key string `url:"key,omitempty" cli:"positional"`
 	// From is the starting line number (optional, default: 1).
 	From int64 `url:"from,omitempty"`
 	// To is the ending line number (optional, default: end of file).
@@ -116,7 +116,7 @@ type UserGroupsAddUserOptions struct {
 	// Login is the user login (optional - for internal accounts).
 	Login string `url:"login,omitempty"`
 	// Name is the group name (required).
-	Name string `url:"name,omitempty"`
+	Name string `url:"name,omitempty" cli:"positional"`
 }
 
 // UserGroupsCreateOptions represents options for creating a group.
@@ -133,7 +133,7 @@ type UserGroupsCreateOptions struct {
 // UserGroupsDeleteOptions represents options for deleting a group.
 type UserGroupsDeleteOptions struct {
 	// Name is the group name (required).
-	Name string `url:"name,omitempty"`
+	Name string `url:"name,omitempty" cli:"positional"`
 }
 
 // UserGroupsRemoveUserOptions represents options for removing a user from a group.
@@ -141,7 +141,7 @@ type UserGroupsRemoveUserOptions struct {
 	// Login is the user login (optional - for internal accounts).
 	Login string `url:"login,omitempty"`
 	// Name is the group name (required).
-	Name string `url:"name,omitempty"`
+	Name string `url:"name,omitempty" cli:"positional"`
 }
 
 // UserGroupsSearchOptions represents options for searching groups.
@@ -163,7 +163,7 @@ type UserGroupsSearchOptions struct {
 // UserGroupsUpdateOptions represents options for updating a group.
 type UserGroupsUpdateOptions struct {
 	// CurrentName is the current name of the group to update (required).
-	CurrentName string `url:"currentName,omitempty"`
+	CurrentName string `url:"currentName,omitempty" cli:"positional"`
 	// Description is the new optional description for the group.
 	// Maximum length: 200 characters.
 	Description string `url:"description,omitempty"`
@@ -180,7 +180,7 @@ type UserGroupsUsersOptions struct {
 	PaginationArgs
 
 	// Name is the group name (required).
-	Name string `url:"name,omitempty"`
+	Name string `url:"name,omitempty" cli:"positional"`
 	// Query limits search to names or logins that contain the supplied string.
 	Query string `url:"q,omitempty"`
 	// Selected filters by selection status.
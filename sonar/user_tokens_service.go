@@ -122,7 +122,7 @@ type UserTokensRevokeOptions struct {
 	Login string `url:"login,omitempty"`
 	// Name is the token name.
 	// This field is required.
-	Name string `url:"name"`
+	Name string `url:"name" cli:"positional"`
 }
 
 // UserTokensSearchOptions contains parameters for the Search method.
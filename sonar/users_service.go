@@ -335,7 +335,7 @@ type UsersUpdate struct {
 type UsersAnonymizeOptions struct {
 	// Login is the user login.
 	// This field is required.
-	Login string `url:"login"`
+	Login string `url:"login" cli:"positional"`
 }
 
 // UsersChangePasswordOptions contains parameters for the ChangePassword method.
@@ -377,7 +377,7 @@ type UsersCreateOptions struct {
 type UsersDeactivateOptions struct {
 	// Login is the user login.
 	// This field is required.
-	Login string `url:"login"`
+	Login string `url:"login" cli:"positional"`
 	// Anonymize specifies whether to anonymize the user in addition to deactivating.
 	Anonymize bool `url:"anonymize,omitempty"`
 }
@@ -401,7 +401,7 @@ type UsersGroupsOptions struct {
 
 	// Login is the user login.
 	// This field is required.
-	Login string `url:"login"`
+	Login string `url:"login" cli:"positional"`
 	// Query is a limit search to group names that contain the supplied string.
 	Query string `url:"q,omitempty"`
 	// Selected filters the selection status.
@@ -464,7 +464,7 @@ type UsersUpdateOptions struct {
 	Email string `url:"email,omitempty"`
 	// Login is the user login.
 	// This field is required.
-	Login string `url:"login"`
+	Login string `url:"login" cli:"positional"`
 	// Name is the user's new display name.
 	Name string `url:"name,omitempty"`
 	// ScmAccounts is the list of SCM accounts.
@@ -179,7 +179,7 @@ type ViewsCreateOptions struct {
 // ViewsDeleteOptions contains parameters for the Delete method.
 type ViewsDeleteOptions struct {
 	// Key is the portfolio key. This field is required.
-	Key string `url:"key"`
+	Key string `url:"key" cli:"positional"`
 }
 
 // ViewsSearchOptions contains parameters for the Search method.
@@ -199,7 +199,7 @@ type ViewsSearchOptions struct {
 // ViewsShowOptions contains parameters for the Show method.
 type ViewsShowOptions struct {
 	// Key is the portfolio key. This field is required.
-	Key string `url:"key"`
+	Key string `url:"key" cli:"positional"`
 }
 
 // ViewsUpdateOptions contains parameters for the Update method.
@@ -286,7 +286,7 @@ type ViewsProjectsOptions struct {
 	PaginationArgs
 
 	// Key is the portfolio key. This field is required.
-	Key string `url:"key"`
+	Key string `url:"key" cli:"positional"`
 	// Query limits results to projects whose key contains this value.
 	Query string `url:"query,omitempty"`
 	// Selected filters on selected, deselected or all projects.
@@ -296,13 +296,13 @@ type ViewsProjectsOptions struct {
 // ViewsApplicationsOptions contains parameters for the Applications method.
 type ViewsApplicationsOptions struct {
 	// Portfolio is the portfolio key. This field is required.
-	Portfolio string `url:"portfolio"`
+	Portfolio string `url:"portfolio" cli:"positional"`
 }
 
 // ViewsSubViewsOptions contains parameters for the SubPortfolios method.
 type ViewsSubViewsOptions struct {
 	// Portfolio is the portfolio key. This field is required.
-	Portfolio string `url:"portfolio"`
+	Portfolio string `url:"portfolio" cli:"positional"`
 }
 
 // ViewsProjectsStatusOptions contains parameters for the ProjectsStatus method.
@@ -312,7 +312,7 @@ type ViewsProjectsStatusOptions struct {
 	PaginationArgs
 
 	// Portfolio is the portfolio key. This field is required.
-	Portfolio string `url:"portfolio"`
+	Portfolio string `url:"portfolio" cli:"positional"`
 	// Status filters projects by quality gate status.
 	Status string `url:"status,omitempty"`
 }
@@ -362,13 +362,13 @@ type ViewsRemoveApplicationBranchOptions struct {
 // ViewsSetManualModeOptions contains parameters for the SetManualMode method.
 type ViewsSetManualModeOptions struct {
 	// Portfolio is the portfolio key. This field is required.
-	Portfolio string `url:"portfolio"`
+	Portfolio string `url:"portfolio" cli:"positional"`
 }
 
 // ViewsSetNoneModeOptions contains parameters for the SetNoneMode method.
 type ViewsSetNoneModeOptions struct {
 	// Portfolio is the portfolio key. This field is required.
-	Portfolio string `url:"portfolio"`
+	Portfolio string `url:"portfolio" cli:"positional"`
 }
 
 // ViewsSetRegexpModeOptions contains parameters for the SetRegexpMode method.
@@ -386,7 +386,7 @@ type ViewsSetRemainingProjectsModeOptions struct {
 	// Branch selects a branch in all matched projects instead of using main branches.
 	Branch string `url:"branch,omitempty"`
 	// Portfolio is the portfolio key. This field is required.
-	Portfolio string `url:"portfolio"`
+	Portfolio string `url:"portfolio" cli:"positional"`
 }
 
 // ViewsSetTagsModeOptions contains parameters for the SetTagsMode method.
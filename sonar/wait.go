@@ -0,0 +1,288 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultWaitInitialInterval is the default delay before the second poll of a wait.
+	DefaultWaitInitialInterval = time.Second
+	// DefaultWaitMaxInterval is the default cap on the delay between two polls of a wait.
+	DefaultWaitMaxInterval = 10 * time.Second
+
+	// QualityGateStatusOK is the status of a passed quality gate.
+	QualityGateStatusOK = "OK"
+	// QualityGateStatusWarn is the status of a quality gate passed with warnings (older servers).
+	QualityGateStatusWarn = "WARN"
+	// QualityGateStatusError is the status of a failed quality gate.
+	QualityGateStatusError = "ERROR"
+	// QualityGateStatusNone is the status of a project without a computed quality gate.
+	QualityGateStatusNone = "NONE"
+)
+
+var (
+	// ErrTaskFailed is returned by WaitForTask and WaitForQualityGate when the Compute
+	// Engine task ends FAILED or CANCELED.
+	ErrTaskFailed = errors.New("compute engine task failed")
+	// ErrTaskWarnings is returned by WaitForTask and WaitForQualityGate when the task
+	// succeeded with analysis warnings and WaitOptions.FailOnWarnings is set.
+	ErrTaskWarnings = errors.New("compute engine task reported analysis warnings")
+)
+
+// -----------------------------------------------------------------------------
+// Shared Types
+// -----------------------------------------------------------------------------
+
+// WaitOptions configures how WaitForTask and WaitForQualityGate poll a Compute Engine
+// task. The delay between polls starts at InitialInterval and doubles up to MaxInterval.
+//
+//nolint:govet // fieldalignment: keeping logical field grouping for readability
+type WaitOptions struct {
+	// InitialInterval is the delay before the second poll (default DefaultWaitInitialInterval).
+	// It is capped by MaxInterval.
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between two polls (default DefaultWaitMaxInterval).
+	MaxInterval time.Duration
+	// Timeout bounds the whole wait. Zero leaves the wait bounded by the context only.
+	Timeout time.Duration
+	// FailOnWarnings treats analysis warnings of a successful task as a failure.
+	FailOnWarnings bool
+}
+
+// validate checks that no duration is negative.
+func (o *WaitOptions) validate() error {
+	durations := []struct {
+		value time.Duration
+		field string
+	}{
+		{value: o.InitialInterval, field: "InitialInterval"},
+		{value: o.MaxInterval, field: "MaxInterval"},
+		{value: o.Timeout, field: "Timeout"},
+	}
+
+	for _, duration := range durations {
+		if duration.value < 0 {
+			return NewValidationError(duration.field, "must not be negative", ErrOutOfRange)
+		}
+	}
+
+	return nil
+}
+
+// intervals returns the initial and maximum poll intervals, with defaults applied.
+func (o *WaitOptions) intervals() (time.Duration, time.Duration) {
+	maxInterval := o.MaxInterval
+	if maxInterval == 0 {
+		maxInterval = DefaultWaitMaxInterval
+	}
+
+	initial := o.InitialInterval
+	if initial == 0 {
+		initial = DefaultWaitInitialInterval
+	}
+
+	return min(initial, maxInterval), maxInterval
+}
+
+// -----------------------------------------------------------------------------
+// Response Types
+// -----------------------------------------------------------------------------
+
+// CeTaskResult is the last state of a Compute Engine task awaited by WaitForTask.
+type CeTaskResult struct {
+	// Task is the last polled state of the task, including its error message, error
+	// stacktrace and analysis warnings.
+	Task CeTask `json:"task,omitzero"`
+	// Polls is the number of times the task was polled.
+	Polls int `json:"polls,omitempty"`
+}
+
+// Done reports whether the task reached a final status (SUCCESS, FAILED or CANCELED).
+func (r *CeTaskResult) Done() bool {
+	switch r.Task.Status {
+	case TaskStatusSuccess, TaskStatusFailed, TaskStatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasWarnings reports whether the analysis reported warnings.
+func (r *CeTaskResult) HasWarnings() bool {
+	return len(r.Task.Warnings) > 0 || r.Task.WarningCount > 0
+}
+
+// err returns the error matching the final state of the task, if any.
+func (r *CeTaskResult) err(failOnWarnings bool) error {
+	switch {
+	case r.Task.Status == TaskStatusFailed || r.Task.Status == TaskStatusCanceled:
+		return fmt.Errorf("%w: task %s ended with status %s: %s", ErrTaskFailed, r.Task.ID, r.Task.Status, r.Task.ErrorMessage)
+	case failOnWarnings && r.HasWarnings():
+		return fmt.Errorf("%w: task %s: %d warnings", ErrTaskWarnings, r.Task.ID, max(int64(len(r.Task.Warnings)), r.Task.WarningCount))
+	default:
+		return nil
+	}
+}
+
+// QualityGateWaitResult is the outcome of WaitForQualityGate.
+type QualityGateWaitResult struct {
+	// Task is the last polled state of the Compute Engine task.
+	Task CeTask `json:"task,omitzero"`
+	// ProjectStatus is the quality gate status of the analysis the task produced. It is
+	// empty when the task did not succeed.
+	ProjectStatus QualityGateProjectStatus `json:"projectStatus,omitzero"`
+}
+
+// Passed reports whether the quality gate passed (OK, or WARN on older servers).
+func (r *QualityGateWaitResult) Passed() bool {
+	return r.ProjectStatus.Status == QualityGateStatusOK || r.ProjectStatus.Status == QualityGateStatusWarn
+}
+
+// -----------------------------------------------------------------------------
+// Option Types
+// -----------------------------------------------------------------------------
+
+// CeWaitForTaskOptions contains parameters for the WaitForTask method.
+type CeWaitForTaskOptions struct {
+	WaitOptions
+
+	// TaskID is the ID of the task, e.g. the ceTaskId of a scanner's report-task.txt.
+	// This field is required.
+	TaskID string
+}
+
+// QualitygatesWaitForQualityGateOptions contains parameters for the WaitForQualityGate method.
+type QualitygatesWaitForQualityGateOptions struct {
+	WaitOptions
+
+	// TaskID is the ID of the analysis report task, e.g. the ceTaskId of a scanner's
+	// report-task.txt.
+	// This field is required.
+	TaskID string
+}
+
+// -----------------------------------------------------------------------------
+// Validation Functions
+// -----------------------------------------------------------------------------
+
+// ValidateWaitForTaskOpt validates the options for the WaitForTask method.
+func (s *CeService) ValidateWaitForTaskOpt(opt *CeWaitForTaskOptions) error {
+	if opt == nil {
+		return NewValidationError("CeWaitForTaskOptions", "cannot be nil", ErrMissingRequired)
+	}
+
+	err := ValidateRequired(opt.TaskID, "TaskID")
+	if err != nil {
+		return err
+	}
+
+	return opt.validate()
+}
+
+// ValidateWaitForQualityGateOpt validates the options for the WaitForQualityGate method.
+func (s *QualitygatesService) ValidateWaitForQualityGateOpt(opt *QualitygatesWaitForQualityGateOptions) error {
+	if opt == nil {
+		return NewValidationError("QualitygatesWaitForQualityGateOptions", "cannot be nil", ErrMissingRequired)
+	}
+
+	err := ValidateRequired(opt.TaskID, "TaskID")
+	if err != nil {
+		return err
+	}
+
+	return opt.validate()
+}
+
+// -----------------------------------------------------------------------------
+// Service Methods
+// -----------------------------------------------------------------------------
+
+// WaitForTask polls a Compute Engine task, with exponential backoff, until it ends
+// SUCCESS, FAILED or CANCELED, the timeout expires or the context is done.
+//
+// The task is polled with its stacktrace and warnings, so the result carries the error
+// message and stacktrace of a failed task. A FAILED or CANCELED task returns the result
+// together with an error wrapping ErrTaskFailed; with FailOnWarnings, a successful task
+// with analysis warnings returns an error wrapping ErrTaskWarnings. When the wait times
+// out, or a later poll fails, the last polled state is returned with the error.
+func (s *CeService) WaitForTask(ctx context.Context, opt *CeWaitForTaskOptions) (*CeTaskResult, *http.Response, error) {
+	err := s.ValidateWaitForTaskOpt(opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opt.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, opt.Timeout)
+		defer cancel()
+	}
+
+	interval, maxInterval := opt.intervals()
+	taskOpt := &CeTaskOptions{ID: opt.TaskID, AdditionalFields: []string{TaskFieldStacktrace, TaskFieldWarnings}}
+	result := new(CeTaskResult)
+
+	for {
+		details, resp, err := s.Task(ctx, taskOpt)
+		if err != nil && result.Polls == 0 {
+			return nil, resp, err
+		}
+
+		if err != nil {
+			return result, resp, err
+		}
+
+		result.Task = details.Task
+		result.Polls++
+
+		if result.Done() {
+			return result, resp, result.err(opt.FailOnWarnings)
+		}
+
+		if !sleepContext(ctx, interval) {
+			return result, resp, fmt.Errorf("task %s still %s: %w", opt.TaskID, result.Task.Status, ctx.Err())
+		}
+
+		interval = min(interval*retryBackoffBase, maxInterval)
+	}
+}
+
+// WaitForQualityGate waits for an analysis report task with WaitForTask, then returns
+// the quality gate status of the analysis it produced.
+//
+// Task failures (and warnings, with FailOnWarnings) return the result, holding the last
+// state of the task, together with the WaitForTask error. A failed quality gate is not an
+// error: use QualityGateWaitResult.Passed or the status itself.
+func (s *QualitygatesService) WaitForQualityGate(ctx context.Context, opt *QualitygatesWaitForQualityGateOptions) (*QualityGateWaitResult, *http.Response, error) {
+	err := s.ValidateWaitForQualityGateOpt(opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	task, resp, err := s.client.Ce.WaitForTask(ctx, &CeWaitForTaskOptions{WaitOptions: opt.WaitOptions, TaskID: opt.TaskID})
+	if task == nil {
+		return nil, resp, err
+	}
+
+	result := &QualityGateWaitResult{Task: task.Task} //nolint:exhaustruct // the project status is set below
+	if err != nil {
+		return result, resp, err
+	}
+
+	if task.Task.AnalysisID == "" {
+		return result, resp, fmt.Errorf("task %s of type %s produced no analysis", task.Task.ID, task.Task.Type)
+	}
+
+	status, resp, err := s.ProjectStatus(ctx, &QualitygatesProjectStatusOptions{AnalysisID: task.Task.AnalysisID}) //nolint:exhaustruct // the analysis identifies the gate status
+	if err != nil {
+		return result, resp, err
+	}
+
+	result.ProjectStatus = status.ProjectStatus
+
+	return result, resp, nil
+}
@@ -0,0 +1,171 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastWait polls without delay.
+var fastWait = WaitOptions{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond} //nolint:exhaustruct,gochecknoglobals // test fixture
+
+// taskHandler serves ce/task responses in sequence, repeating the last one.
+func taskHandler(t *testing.T, polls *int, responses ...string) http.HandlerFunc {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ce/task", r.URL.Path)
+		assert.Equal(t, "T1", r.URL.Query().Get("id"))
+		assert.Equal(t, "stacktrace,warnings", r.URL.Query().Get("additionalFields"))
+
+		response := responses[min(*polls, len(responses)-1)]
+		*polls++
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}
+}
+
+func TestCe_WaitForTask(t *testing.T) {
+	polls := 0
+	server := newTestServer(t, taskHandler(t, &polls,
+		`{"task": {"id": "T1", "status": "PENDING"}}`,
+		`{"task": {"id": "T1", "status": "IN_PROGRESS"}}`,
+		`{"task": {"id": "T1", "status": "SUCCESS", "analysisId": "A1"}}`,
+	))
+	client := newTestClient(t, server.url())
+
+	result, resp, err := client.Ce.WaitForTask(context.Background(), &CeWaitForTaskOptions{WaitOptions: fastWait, TaskID: "T1"})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	assert.Equal(t, 3, result.Polls)
+	assert.True(t, result.Done())
+	assert.Equal(t, "A1", result.Task.AnalysisID)
+}
+
+func TestCe_WaitForTask_Failed(t *testing.T) {
+	polls := 0
+	server := newTestServer(t, taskHandler(t, &polls,
+		`{"task": {"id": "T1", "status": "FAILED", "errorMessage": "boom", "errorStacktrace": "at Foo.bar()"}}`,
+	))
+	client := newTestClient(t, server.url())
+
+	result, _, err := client.Ce.WaitForTask(context.Background(), &CeWaitForTaskOptions{WaitOptions: fastWait, TaskID: "T1"})
+	require.ErrorIs(t, err, ErrTaskFailed)
+	assert.Contains(t, err.Error(), "ended with status FAILED: boom")
+
+	require.NotNil(t, result)
+	assert.Equal(t, "at Foo.bar()", result.Task.ErrorStacktrace)
+}
+
+func TestCe_WaitForTask_Warnings(t *testing.T) {
+	polls := 0
+	server := newTestServer(t, taskHandler(t, &polls,
+		`{"task": {"id": "T1", "status": "SUCCESS", "warningCount": 1, "warnings": ["SCM provider autodetection failed"]}}`,
+	))
+	client := newTestClient(t, server.url())
+
+	result, _, err := client.Ce.WaitForTask(context.Background(), &CeWaitForTaskOptions{WaitOptions: fastWait, TaskID: "T1"})
+	require.NoError(t, err)
+	assert.True(t, result.HasWarnings())
+
+	strict := fastWait
+	strict.FailOnWarnings = true
+
+	result, _, err = client.Ce.WaitForTask(context.Background(), &CeWaitForTaskOptions{WaitOptions: strict, TaskID: "T1"})
+	require.ErrorIs(t, err, ErrTaskWarnings)
+	assert.Equal(t, []string{"SCM provider autodetection failed"}, result.Task.Warnings)
+}
+
+func TestCe_WaitForTask_Timeout(t *testing.T) {
+	polls := 0
+	server := newTestServer(t, taskHandler(t, &polls, `{"task": {"id": "T1", "status": "PENDING"}}`))
+	client := newTestClient(t, server.url())
+
+	opts := fastWait
+	opts.Timeout = 20 * time.Millisecond
+
+	result, _, err := client.Ce.WaitForTask(context.Background(), &CeWaitForTaskOptions{WaitOptions: opts, TaskID: "T1"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NotNil(t, result)
+	assert.False(t, result.Done())
+	assert.Equal(t, TaskStatusPending, result.Task.Status)
+
+	// A context deadline bounds the wait as well.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, _, err = client.Ce.WaitForTask(ctx, &CeWaitForTaskOptions{WaitOptions: fastWait, TaskID: "T1"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCe_WaitForTask_Validation(t *testing.T) {
+	client := newTestClient(t, "http://localhost/")
+
+	_, _, err := client.Ce.WaitForTask(context.Background(), nil)
+	require.ErrorIs(t, err, ErrMissingRequired)
+
+	_, _, err = client.Ce.WaitForTask(context.Background(), &CeWaitForTaskOptions{})
+	require.ErrorIs(t, err, ErrMissingRequired)
+
+	_, _, err = client.Ce.WaitForTask(context.Background(), &CeWaitForTaskOptions{WaitOptions: WaitOptions{Timeout: -time.Second}, TaskID: "T1"})
+	require.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestWaitOptions_Intervals(t *testing.T) {
+	initial, maxInterval := (&WaitOptions{}).intervals()
+	assert.Equal(t, DefaultWaitInitialInterval, initial)
+	assert.Equal(t, DefaultWaitMaxInterval, maxInterval)
+
+	initial, maxInterval = (&WaitOptions{MaxInterval: 100 * time.Millisecond}).intervals()
+	assert.Equal(t, 100*time.Millisecond, initial)
+	assert.Equal(t, 100*time.Millisecond, maxInterval)
+}
+
+func TestQualitygates_WaitForQualityGate(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/ce/task":
+			_, _ = w.Write([]byte(`{"task": {"id": "T1", "status": "SUCCESS", "analysisId": "A1", "componentKey": "my-app"}}`))
+		case "/qualitygates/project_status":
+			assert.Equal(t, "A1", r.URL.Query().Get("analysisId"))
+
+			_, _ = w.Write([]byte(`{"projectStatus": {"status": "ERROR", "conditions": [{"status": "ERROR", "metricKey": "new_coverage"}]}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	client := newTestClient(t, server.url())
+
+	result, _, err := client.Qualitygates.WaitForQualityGate(context.Background(), &QualitygatesWaitForQualityGateOptions{WaitOptions: fastWait, TaskID: "T1"})
+	require.NoError(t, err)
+
+	assert.False(t, result.Passed())
+	assert.Equal(t, "my-app", result.Task.ComponentKey)
+	assert.Equal(t, QualityGateStatusError, result.ProjectStatus.Status)
+	assert.Len(t, result.ProjectStatus.Conditions, 1)
+}
+
+func TestQualitygates_WaitForQualityGate_TaskFailed(t *testing.T) {
+	polls := 0
+	server := newTestServer(t, taskHandler(t, &polls, `{"task": {"id": "T1", "status": "CANCELED"}}`))
+	client := newTestClient(t, server.url())
+
+	result, _, err := client.Qualitygates.WaitForQualityGate(context.Background(), &QualitygatesWaitForQualityGateOptions{WaitOptions: fastWait, TaskID: "T1"})
+	require.ErrorIs(t, err, ErrTaskFailed)
+
+	require.NotNil(t, result)
+	assert.Equal(t, TaskStatusCanceled, result.Task.Status)
+	assert.Empty(t, result.ProjectStatus.Status)
+
+	_, _, err = client.Qualitygates.WaitForQualityGate(context.Background(), nil)
+	require.ErrorIs(t, err, ErrMissingRequired)
+}
@@ -125,7 +125,7 @@ type WebhooksCreateOptions struct {
 type WebhooksDeleteOptions struct {
 	// Webhook is the key of the webhook to delete (required).
 	// Maximum length: 40 characters.
-	Webhook string `url:"webhook,omitempty"`
+	Webhook string `url:"webhook,omitempty" cli:"positional"`
 }
 
 // WebhooksDeliveriesOptions represents options for listing webhook deliveries.
@@ -149,7 +149,7 @@ type WebhooksDeliveriesOptions struct {
 // WebhooksDeliveryOptions represents options for getting a single delivery.
 type WebhooksDeliveryOptions struct {
 	// DeliveryID is the unique identifier of the delivery (required).
-	DeliveryID string `url:"deliveryId,omitempty"`
+	DeliveryID string `url:"deliveryId,omitempty" cli:"positional"`
 }
 
 // WebhooksListOptions represents options for listing webhooks.